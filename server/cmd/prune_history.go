@@ -0,0 +1,53 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tengge1/shadoweditor/server"
+	prunehistory "github.com/tengge1/shadoweditor/server/tools/prune_history"
+)
+
+// pruneHistoryCmd prunes scene history to the retention policy.
+var pruneHistoryCmd = &cobra.Command{
+	Use:   "prune-history",
+	Short: "Prune scene history",
+	Long:  `Delete scene history versions that the [history] section of config.toml does not keep.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := RunPruneHistory(); err != nil {
+			fmt.Println(err.Error())
+		}
+	},
+}
+
+func init() {
+	pruneHistoryCmd.Flags().BoolVar(&embedded, "embedded", false, "prune the data stored in files under public_dir/data")
+	AddCommand(pruneHistoryCmd)
+}
+
+// RunPruneHistory read the config file, and prune scene history.
+func RunPruneHistory() error {
+	if err := server.Create(cfgFile); err != nil {
+		return err
+	}
+	if embedded {
+		server.Config.Database.Type = server.FileStorage
+	}
+
+	result, err := prunehistory.Prune(server.Config.History)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%v versions of %v scenes are deleted, %v documents, %v bytes reclaimed.\n",
+		result.Versions, result.Scenes, result.Documents, result.Bytes)
+	return nil
+}
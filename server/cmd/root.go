@@ -20,7 +20,7 @@ var (
 		Use:   "ShadowEditor",
 		Short: "3D scene editor based on three.js, golang and mongodb",
 		Long: `ShadowEditor is a 3D scene editor based on three.js, golang and mongodb.
-This application uses mongodb to store data.`,
+This application uses mongodb or sqlite to store data.`,
 	}
 )
 
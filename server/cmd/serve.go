@@ -18,6 +18,9 @@ import (
 	"github.com/tengge1/shadoweditor/server"
 )
 
+// embedded means the server stores data in files instead of mongo.
+var embedded bool
+
 // serveCmd launch the shadow editor server.
 var serveCmd = &cobra.Command{
 	Use:     "serve",
@@ -33,6 +36,7 @@ var serveCmd = &cobra.Command{
 }
 
 func init() {
+	serveCmd.Flags().BoolVar(&embedded, "embedded", false, "store data in files under public_dir/data without mongo")
 	AddCommand(serveCmd)
 }
 
@@ -40,12 +44,20 @@ func init() {
 func RunServe() error {
 	// Read config file `./config.toml`.
 	if _, err := os.Stat(cfgFile); os.IsNotExist(err) {
-		return fmt.Errorf("cannot find config file: %v", cfgFile)
-	}
-
-	err := server.Create(cfgFile)
-	if err != nil {
-		return err
+		if !embedded {
+			return fmt.Errorf("cannot find config file: %v", cfgFile)
+		}
+		// embedded mode can run with the default config.
+		if err := server.CreateEmbedded("."); err != nil {
+			return err
+		}
+	} else {
+		if err := server.Create(cfgFile); err != nil {
+			return err
+		}
+		if embedded {
+			server.Config.Database.Type = server.FileStorage
+		}
 	}
 
 	server.Start()
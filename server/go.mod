@@ -7,10 +7,13 @@ require (
 	github.com/dgrijalva/jwt-go v3.2.0+incompatible
 	github.com/dimfeld/httptreemux v5.0.1+incompatible
 	github.com/google/go-cmp v0.5.6 // indirect
+	github.com/gorilla/websocket v1.4.2
 	github.com/inconshreveable/mousetrap v1.0.0
 	github.com/json-iterator/go v1.1.11
 	github.com/klauspost/compress v1.10.5 // indirect
+	github.com/mattn/go-sqlite3 v1.14.8
 	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
+	github.com/modern-go/reflect2 v1.0.2 // indirect
 	github.com/mozillazg/go-pinyin v0.17.0
 	github.com/otiai10/copy v1.1.1
 	github.com/sirupsen/logrus v1.8.1
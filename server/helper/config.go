@@ -49,10 +49,16 @@ func GetConfig(path string) (config *ConfigModel, err error) {
 		)
 	}
 
+	// sqlite database file is saved in `./data` by default.
+	if config.Database.File == "" {
+		config.Database.File = fmt.Sprintf("./data/%v.db", config.Database.Database)
+	}
+
 	// In windows system, path separator "/" should be replace with "\\".
 	if strings.HasPrefix(runtime.GOOS, "windows") {
 		config.Path.PublicDir = strings.ReplaceAll(config.Path.PublicDir, "/", "\\")
 		config.Path.LogDir = strings.ReplaceAll(config.Path.LogDir, "/", "\\")
+		config.Database.File = strings.ReplaceAll(config.Database.File, "/", "\\")
 	}
 
 	return
@@ -66,6 +72,8 @@ type ConfigModel struct {
 	Upload    UploadConfigModel    `toml:"upload"`
 	Path      PathConfigModel      `toml:"path"`
 	Log       LogConfigModel       `toml:"log"`
+	History   HistoryConfigModel   `toml:"history"`
+	Export    ExportConfigModel    `toml:"export"`
 }
 
 // ServerConfigModel is the server config section in `config.toml`.
@@ -84,6 +92,8 @@ type DatabaseConfigModel struct {
 	User     string `toml:"user"`
 	Password string `toml:"password"`
 	Database string `toml:"database"`
+	// File is the database file path when type is sqlite.
+	File string `toml:"file"`
 
 	// Connection should not read from config.toml.
 	Connection string
@@ -99,6 +109,15 @@ type AuthorityConfigModel struct {
 // UploadConfigModel is the upload config section in `config.toml`.
 type UploadConfigModel struct {
 	MaxSize int64 `toml:"max_size"`
+	// MaxUnzipSize is the max total uncompressed size of an uploaded zip file. 0 means no limit.
+	MaxUnzipSize int64 `toml:"max_unzip_size"`
+	// MaxUnzipFiles is the max entry count of an uploaded zip file. 0 means no limit.
+	MaxUnzipFiles int `toml:"max_unzip_files"`
+	// MaxChunkedSize is the max file size of a chunked upload. 0 means no limit.
+	MaxChunkedSize int64 `toml:"max_chunked_size"`
+	// MaxGeometrySize is the max size of a mesh file that its counts, bounds and
+	// thumbnail are read from, because it is read into memory. 0 means no limit.
+	MaxGeometrySize int64 `toml:"max_geometry_size"`
 }
 
 // PathConfigModel is the authority path section in `config.toml`.
@@ -111,3 +130,19 @@ type PathConfigModel struct {
 type LogConfigModel struct {
 	File string `toml:"file"`
 }
+
+// HistoryConfigModel is the scene history retention policy section in `config.toml`.
+type HistoryConfigModel struct {
+	// KeepVersions is how many latest history versions of a scene are kept. 0 means no limit.
+	KeepVersions int `toml:"keep_versions"`
+	// DailyAfterDays means only one version per day is kept for versions older than
+	// these days. 0 means disabled.
+	DailyAfterDays int `toml:"daily_after_days"`
+}
+
+// ExportConfigModel is the export config section in `config.toml`.
+type ExportConfigModel struct {
+	// TempExpires is how many minutes exported files are kept in the temp dir, and chunked
+	// uploads are kept without changes. 0 means forever.
+	TempExpires int `toml:"temp_expires"`
+}
@@ -115,6 +115,26 @@ func TestConfig(t *testing.T) {
 		if config.Upload.MaxSize != maxSize {
 			t.Errorf("expect %v, got %v", maxSize, config.Upload.MaxSize)
 		}
+
+		maxUnzipSize := upload.GetInt64("max_unzip_size")
+		if config.Upload.MaxUnzipSize != maxUnzipSize {
+			t.Errorf("expect %v, got %v", maxUnzipSize, config.Upload.MaxUnzipSize)
+		}
+
+		maxUnzipFiles := upload.GetInt("max_unzip_files")
+		if config.Upload.MaxUnzipFiles != maxUnzipFiles {
+			t.Errorf("expect %v, got %v", maxUnzipFiles, config.Upload.MaxUnzipFiles)
+		}
+
+		maxChunkedSize := upload.GetInt64("max_chunked_size")
+		if config.Upload.MaxChunkedSize != maxChunkedSize {
+			t.Errorf("expect %v, got %v", maxChunkedSize, config.Upload.MaxChunkedSize)
+		}
+
+		maxGeometrySize := upload.GetInt64("max_geometry_size")
+		if config.Upload.MaxGeometrySize != maxGeometrySize {
+			t.Errorf("expect %v, got %v", maxGeometrySize, config.Upload.MaxGeometrySize)
+		}
 	}
 
 	// path section
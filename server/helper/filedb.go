@@ -0,0 +1,398 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package helper
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// fileDBExt is the extension of collection files.
+const fileDBExt = ".bson"
+
+// NewFileDB create a document storage that saves collections to files in dir.
+func NewFileDB(dir string) (*FileDB, error) {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, err
+		}
+	}
+	return &FileDB{
+		Dir:         dir,
+		collections: map[string][]bson.Raw{},
+	}, nil
+}
+
+// FileDB is a document storage that needs no database server. It is used when the
+// server runs in embedded mode.
+//
+// Each collection is saved to a file named `<collectionName>.bson` in Dir, which
+// contains the bson documents one after another, like the output of mongodump. A
+// collection is read into memory when it is first used, and it is written back to
+// the file after each change. Filters, updates and options are mongo style, and
+// they are evaluated in memory, see query.go.
+type FileDB struct {
+	Dir         string
+	collections map[string][]bson.Raw
+	mutex       sync.Mutex
+}
+
+// fileDBPath returns the file path of a collection.
+func (f *FileDB) fileDBPath(name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid collection name: %v", name)
+	}
+	return filepath.Join(f.Dir, name+fileDBExt), nil
+}
+
+// load returns the documents of a collection, and reads the file if needed.
+// The mutex must be held.
+func (f *FileDB) load(name string) ([]bson.Raw, bool, error) {
+	if docs, ok := f.collections[name]; ok {
+		return docs, true, nil
+	}
+	path, err := f.fileDBPath(name)
+	if err != nil {
+		return nil, false, err
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	docs := []bson.Raw{}
+	for len(data) > 0 {
+		if len(data) < 5 {
+			return nil, false, fmt.Errorf("%v is corrupted", path)
+		}
+		length := int(binary.LittleEndian.Uint32(data))
+		if length < 5 || length > len(data) {
+			return nil, false, fmt.Errorf("%v is corrupted", path)
+		}
+		docs = append(docs, bson.Raw(data[:length]))
+		data = data[length:]
+	}
+	f.collections[name] = docs
+	return docs, true, nil
+}
+
+// save writes the documents of a collection to the file. The mutex must be held.
+func (f *FileDB) save(name string, docs []bson.Raw) error {
+	path, err := f.fileDBPath(name)
+	if err != nil {
+		return err
+	}
+
+	var buffer bytes.Buffer
+	for _, doc := range docs {
+		buffer.Write(doc)
+	}
+
+	// write a temp file and rename it, so that a crash never leaves a half written collection.
+	temp := path + ".tmp"
+	if err := ioutil.WriteFile(temp, buffer.Bytes(), 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(temp, path); err != nil {
+		return err
+	}
+	f.collections[name] = docs
+	return nil
+}
+
+// Close drops the collections read into memory. Every change is already written to
+// the files, so nothing is lost.
+func (f *FileDB) Close() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.collections = map[string][]bson.Raw{}
+	return nil
+}
+
+// ListCollectionNames list collectionNames of database.
+func (f *FileDB) ListCollectionNames() (collectionNames []string, err error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	files, err := ioutil.ReadDir(f.Dir)
+	if err != nil {
+		return nil, err
+	}
+	collectionNames = []string{}
+	for _, file := range files {
+		if !file.IsDir() && strings.HasSuffix(file.Name(), fileDBExt) {
+			collectionNames = append(collectionNames, strings.TrimSuffix(file.Name(), fileDBExt))
+		}
+	}
+	sort.Strings(collectionNames)
+	return collectionNames, nil
+}
+
+// CollectionExists determine whether a collection existed.
+func (f *FileDB) CollectionExists(name string) (existed bool, err error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	_, existed, err = f.load(name)
+	return
+}
+
+// CreateCollection create a new collection.
+func (f *FileDB) CreateCollection(name string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	_, existed, err := f.load(name)
+	if err != nil || existed {
+		return err
+	}
+	return f.save(name, []bson.Raw{})
+}
+
+// DropCollection drop a collection.
+func (f *FileDB) DropCollection(name string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	path, err := f.fileDBPath(name)
+	if err != nil {
+		return err
+	}
+	delete(f.collections, name)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// InsertOne insert one document to a collection.
+func (f *FileDB) InsertOne(collectionName string, document interface{}) (*mongo.InsertOneResult, error) {
+	result, err := f.InsertMany(collectionName, []interface{}{document})
+	if err != nil {
+		return nil, err
+	}
+	return &mongo.InsertOneResult{InsertedID: result.InsertedIDs[0]}, nil
+}
+
+// InsertMany insert many documents to a collection.
+func (f *FileDB) InsertMany(collectionName string, documents []interface{}) (*mongo.InsertManyResult, error) {
+	if len(documents) == 0 {
+		return nil, fmt.Errorf("must provide at least one element in input slice")
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	docs, _, err := f.load(collectionName)
+	if err != nil {
+		return nil, err
+	}
+
+	newDocs := make([]bson.Raw, len(docs), len(docs)+len(documents))
+	copy(newDocs, docs)
+	ids := []interface{}{}
+	for _, document := range documents {
+		raw, id, err := newDocument(document)
+		if err != nil {
+			return nil, err
+		}
+		newDocs = append(newDocs, raw)
+		ids = append(ids, id)
+	}
+
+	if err := f.save(collectionName, newDocs); err != nil {
+		return nil, err
+	}
+	return &mongo.InsertManyResult{InsertedIDs: ids}, nil
+}
+
+// find returns the documents that match the filter. The mutex must be held.
+func (f *FileDB) find(collectionName string, filter interface{}, sort interface{}, skip, limit int64) ([]int, []bson.Raw, error) {
+	docs, _, err := f.load(collectionName)
+	if err != nil {
+		return nil, nil, err
+	}
+	indexes, err := queryDocuments(docs, filter, sort, skip, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	return indexes, docs, nil
+}
+
+// Count get documents count of a collection.
+func (f *FileDB) Count(collectionName string, filter interface{}) (int64, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	indexes, _, err := f.find(collectionName, filter, nil, 0, 0)
+	return int64(len(indexes)), err
+}
+
+// FindOne find one document from a collection.
+func (f *FileDB) FindOne(collectionName string, filter interface{}, result interface{}, opts ...*options.FindOneOptions) (find bool, err error) {
+	opt := options.MergeFindOneOptions(opts...)
+	var skip int64
+	if opt.Skip != nil {
+		skip = *opt.Skip
+	}
+
+	f.mutex.Lock()
+	indexes, docs, err := f.find(collectionName, filter, opt.Sort, skip, 1)
+	f.mutex.Unlock()
+
+	if err != nil {
+		return false, err
+	}
+	if len(indexes) == 0 {
+		return false, nil
+	}
+	if err := bson.Unmarshal(docs[indexes[0]], result); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// FindMany find many documents in the collection.
+func (f *FileDB) FindMany(collectionName string, filter interface{}, results interface{}, opts ...*options.FindOptions) (err error) {
+	opt := options.MergeFindOptions(opts...)
+	var skip, limit int64
+	if opt.Skip != nil {
+		skip = *opt.Skip
+	}
+	if opt.Limit != nil {
+		limit = *opt.Limit
+	}
+
+	f.mutex.Lock()
+	indexes, docs, err := f.find(collectionName, filter, opt.Sort, skip, limit)
+	f.mutex.Unlock()
+
+	if err != nil {
+		return err
+	}
+	matched := make([]bson.Raw, 0, len(indexes))
+	for _, index := range indexes {
+		matched = append(matched, docs[index])
+	}
+	return decodeDocuments(matched, results)
+}
+
+// FindAll find all the documents in the collection.
+func (f *FileDB) FindAll(collectionName string, results interface{}, opts ...*options.FindOptions) (err error) {
+	return f.FindMany(collectionName, bson.M{}, results, opts...)
+}
+
+// update updates documents that match the filter. It updates one document at most
+// when limit is 1.
+func (f *FileDB) update(collectionName string, filter interface{}, update interface{}, limit int64) (*mongo.UpdateResult, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	indexes, docs, err := f.find(collectionName, filter, nil, 0, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := mongo.UpdateResult{}
+	newDocs := make([]bson.Raw, len(docs))
+	copy(newDocs, docs)
+	for _, index := range indexes {
+		data, err := updateDocument(docs[index], update)
+		if err != nil {
+			return nil, err
+		}
+		result.MatchedCount++
+		if bytes.Equal(data, docs[index]) {
+			continue
+		}
+		newDocs[index] = data
+		result.ModifiedCount++
+	}
+
+	if result.ModifiedCount > 0 {
+		if err := f.save(collectionName, newDocs); err != nil {
+			return nil, err
+		}
+	}
+	return &result, nil
+}
+
+// UpdateOne update one document.
+func (f *FileDB) UpdateOne(collectionName string, filter interface{}, update interface{}) (*mongo.UpdateResult, error) {
+	return f.update(collectionName, filter, update, 1)
+}
+
+// UpdateMany update many documents in the collection.
+func (f *FileDB) UpdateMany(collectionName string, filter interface{}, update interface{}) (*mongo.UpdateResult, error) {
+	return f.update(collectionName, filter, update, 0)
+}
+
+// UpdateAll update all the documents in the collection.
+func (f *FileDB) UpdateAll(collectionName string, update interface{}) (*mongo.UpdateResult, error) {
+	return f.UpdateMany(collectionName, bson.M{}, update)
+}
+
+// delete deletes documents that match the filter. It deletes one document at most
+// when limit is 1.
+func (f *FileDB) delete(collectionName string, filter interface{}, limit int64) (*mongo.DeleteResult, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	indexes, docs, err := f.find(collectionName, filter, nil, 0, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(indexes) == 0 {
+		return &mongo.DeleteResult{}, nil
+	}
+
+	deleted := map[int]bool{}
+	for _, index := range indexes {
+		deleted[index] = true
+	}
+	newDocs := make([]bson.Raw, 0, len(docs)-len(indexes))
+	for i, doc := range docs {
+		if !deleted[i] {
+			newDocs = append(newDocs, doc)
+		}
+	}
+
+	if err := f.save(collectionName, newDocs); err != nil {
+		return nil, err
+	}
+	return &mongo.DeleteResult{DeletedCount: int64(len(indexes))}, nil
+}
+
+// DeleteOne delete one document from the collection.
+func (f *FileDB) DeleteOne(collectionName string, filter interface{}) (*mongo.DeleteResult, error) {
+	return f.delete(collectionName, filter, 1)
+}
+
+// DeleteMany delete many documents from the collection.
+func (f *FileDB) DeleteMany(collectionName string, filter interface{}) (*mongo.DeleteResult, error) {
+	return f.delete(collectionName, filter, 0)
+}
+
+// DeleteAll delete all documents from the collection.
+func (f *FileDB) DeleteAll(collectionName string) (*mongo.DeleteResult, error) {
+	return f.DeleteMany(collectionName, bson.M{})
+}
@@ -0,0 +1,111 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package helper
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestFileDB(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := NewFileDB(dir)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	collectionName := "PersonTest"
+
+	// InsertOne and InsertMany
+	if _, err := db.InsertOne(collectionName, Person{"xiaoqiang", 30}); err != nil {
+		t.Error(err)
+	}
+	persons := []interface{}{
+		Person{"xiaoming", 10},
+		Person{"xiaoli", 20},
+	}
+	if _, err = db.InsertMany(collectionName, persons); err != nil {
+		t.Error(err)
+	}
+
+	// UpdateOne
+	update := bson.M{
+		"$set": bson.M{
+			"age": 40,
+		},
+	}
+	if _, err := db.UpdateOne(collectionName, bson.M{"name": "xiaoqiang"}, update); err != nil {
+		t.Error(err)
+	}
+
+	// DeleteOne
+	if _, err := db.DeleteOne(collectionName, bson.M{"name": "xiaoming"}); err != nil {
+		t.Error(err)
+	}
+
+	// reopen the storage, and documents should be read from the file
+	db, err = NewFileDB(dir)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	results := []Person{}
+	opts := options.FindOptions{
+		Sort: bson.M{
+			"age": 1,
+		},
+	}
+	if err := db.FindAll(collectionName, &results, &opts); err != nil {
+		t.Error(err)
+	}
+	if len(results) != 2 {
+		t.Errorf("expect 2, got %v", len(results))
+		return
+	}
+	if results[0].Name != "xiaoli" || results[1].Age != 40 {
+		t.Errorf("expect [xiaoli 20] [xiaoqiang 40], got %v", results)
+	}
+
+	// ListCollectionNames
+	names, err := db.ListCollectionNames()
+	if err != nil {
+		t.Error(err)
+	}
+	if len(names) != 1 || names[0] != collectionName {
+		t.Errorf("expect [%v], got %v", collectionName, names)
+	}
+
+	// DropCollection
+	if err := db.DropCollection(collectionName); err != nil {
+		t.Error(err)
+	}
+	existed, err := db.CollectionExists(collectionName)
+	if err != nil {
+		t.Error(err)
+	}
+	if existed {
+		t.Errorf("expect false, got %v", existed)
+	}
+
+	// invalid collection name
+	if _, err := db.InsertOne("../PersonTest", Person{"xiaoqiang", 30}); err == nil {
+		t.Errorf("expect error, got nil")
+	}
+}
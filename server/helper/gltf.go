@@ -0,0 +1,363 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package helper
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// glTF 2.0 constants, see https://github.com/KhronosGroup/glTF/tree/master/specification/2.0
+const (
+	// GLTFByte is the component type of int8.
+	GLTFByte = 5120
+	// GLTFUnsignedByte is the component type of uint8.
+	GLTFUnsignedByte = 5121
+	// GLTFShort is the component type of int16.
+	GLTFShort = 5122
+	// GLTFUnsignedShort is the component type of uint16.
+	GLTFUnsignedShort = 5123
+	// GLTFUnsignedInt is the component type of uint32.
+	GLTFUnsignedInt = 5125
+	// GLTFFloat is the component type of float32.
+	GLTFFloat = 5126
+
+	// GLTFArrayBuffer is the target of vertex attributes.
+	GLTFArrayBuffer = 34962
+	// GLTFElementArrayBuffer is the target of indices.
+	GLTFElementArrayBuffer = 34963
+)
+
+const (
+	glbMagic     = 0x46546C67 // glTF
+	glbVersion   = 2
+	glbChunkJSON = 0x4E4F534A // JSON
+	glbChunkBIN  = 0x004E4942 // BIN
+)
+
+// GLTF is a glTF 2.0 document.
+type GLTF struct {
+	Asset          GLTFAsset              `json:"asset"`
+	Scene          *int                   `json:"scene,omitempty"`
+	Scenes         []GLTFScene            `json:"scenes,omitempty"`
+	Nodes          []GLTFNode             `json:"nodes,omitempty"`
+	Meshes         []GLTFMesh             `json:"meshes,omitempty"`
+	Materials      []GLTFMaterial         `json:"materials,omitempty"`
+	Textures       []GLTFTexture          `json:"textures,omitempty"`
+	Images         []GLTFImage            `json:"images,omitempty"`
+	Samplers       []GLTFSampler          `json:"samplers,omitempty"`
+	Accessors      []GLTFAccessor         `json:"accessors,omitempty"`
+	BufferViews    []GLTFBufferView       `json:"bufferViews,omitempty"`
+	Buffers        []GLTFBuffer           `json:"buffers,omitempty"`
+	ExtensionsUsed []string               `json:"extensionsUsed,omitempty"`
+	Extensions     map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// GLTFAsset is the metadata of a glTF document.
+type GLTFAsset struct {
+	Version   string `json:"version"`
+	Generator string `json:"generator,omitempty"`
+}
+
+// GLTFScene is a set of root nodes.
+type GLTFScene struct {
+	Name  string `json:"name,omitempty"`
+	Nodes []int  `json:"nodes,omitempty"`
+}
+
+// GLTFNode is a node in the node hierarchy.
+type GLTFNode struct {
+	Name        string                 `json:"name,omitempty"`
+	Children    []int                  `json:"children,omitempty"`
+	Mesh        *int                   `json:"mesh,omitempty"`
+	Camera      *int                   `json:"camera,omitempty"`
+	Matrix      []float64              `json:"matrix,omitempty"`
+	Translation []float64              `json:"translation,omitempty"`
+	Rotation    []float64              `json:"rotation,omitempty"`
+	Scale       []float64              `json:"scale,omitempty"`
+	Extensions  map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// GLTFMesh is a set of primitives to be rendered.
+type GLTFMesh struct {
+	Name       string          `json:"name,omitempty"`
+	Primitives []GLTFPrimitive `json:"primitives"`
+}
+
+// GLTFPrimitive is geometry to be rendered with the given material.
+type GLTFPrimitive struct {
+	Attributes map[string]int `json:"attributes"`
+	Indices    *int           `json:"indices,omitempty"`
+	Material   *int           `json:"material,omitempty"`
+	Mode       *int           `json:"mode,omitempty"`
+}
+
+// GLTFMaterial is the material appearance of a primitive.
+type GLTFMaterial struct {
+	Name                 string                 `json:"name,omitempty"`
+	PBRMetallicRoughness *GLTFPBR               `json:"pbrMetallicRoughness,omitempty"`
+	NormalTexture        *GLTFTextureInfo       `json:"normalTexture,omitempty"`
+	OcclusionTexture     *GLTFTextureInfo       `json:"occlusionTexture,omitempty"`
+	EmissiveTexture      *GLTFTextureInfo       `json:"emissiveTexture,omitempty"`
+	EmissiveFactor       []float64              `json:"emissiveFactor,omitempty"`
+	AlphaMode            string                 `json:"alphaMode,omitempty"`
+	AlphaCutoff          *float64               `json:"alphaCutoff,omitempty"`
+	DoubleSided          bool                   `json:"doubleSided,omitempty"`
+	Extensions           map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// GLTFPBR is the metallic-roughness material model.
+type GLTFPBR struct {
+	BaseColorFactor          []float64        `json:"baseColorFactor,omitempty"`
+	BaseColorTexture         *GLTFTextureInfo `json:"baseColorTexture,omitempty"`
+	MetallicFactor           *float64         `json:"metallicFactor,omitempty"`
+	RoughnessFactor          *float64         `json:"roughnessFactor,omitempty"`
+	MetallicRoughnessTexture *GLTFTextureInfo `json:"metallicRoughnessTexture,omitempty"`
+}
+
+// GLTFTextureInfo is a reference to a texture.
+type GLTFTextureInfo struct {
+	Index    int `json:"index"`
+	TexCoord int `json:"texCoord,omitempty"`
+}
+
+// GLTFTexture is a texture and its sampler.
+type GLTFTexture struct {
+	Name    string `json:"name,omitempty"`
+	Sampler *int   `json:"sampler,omitempty"`
+	Source  *int   `json:"source,omitempty"`
+}
+
+// GLTFImage is image data used to create a texture.
+type GLTFImage struct {
+	Name       string `json:"name,omitempty"`
+	URI        string `json:"uri,omitempty"`
+	MimeType   string `json:"mimeType,omitempty"`
+	BufferView *int   `json:"bufferView,omitempty"`
+}
+
+// GLTFSampler is the filtering and wrapping modes of a texture.
+type GLTFSampler struct {
+	MagFilter int `json:"magFilter,omitempty"`
+	MinFilter int `json:"minFilter,omitempty"`
+	WrapS     int `json:"wrapS,omitempty"`
+	WrapT     int `json:"wrapT,omitempty"`
+}
+
+// GLTFAccessor is a typed view into a buffer view.
+type GLTFAccessor struct {
+	BufferView    *int      `json:"bufferView,omitempty"`
+	ByteOffset    int       `json:"byteOffset,omitempty"`
+	ComponentType int       `json:"componentType"`
+	Normalized    bool      `json:"normalized,omitempty"`
+	Count         int       `json:"count"`
+	Type          string    `json:"type"`
+	Max           []float64 `json:"max,omitempty"`
+	Min           []float64 `json:"min,omitempty"`
+}
+
+// GLTFBufferView is a view into a buffer.
+type GLTFBufferView struct {
+	Buffer     int `json:"buffer"`
+	ByteOffset int `json:"byteOffset,omitempty"`
+	ByteLength int `json:"byteLength"`
+	ByteStride int `json:"byteStride,omitempty"`
+	Target     int `json:"target,omitempty"`
+}
+
+// GLTFBuffer is a buffer of binary data.
+type GLTFBuffer struct {
+	ByteLength int    `json:"byteLength"`
+	URI        string `json:"uri,omitempty"`
+}
+
+// WriteGLB writes a glTF document and its binary buffer as a .glb file.
+func WriteGLB(w io.Writer, doc *GLTF, bin []byte) error {
+	jsonData, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	// chunks are aligned to 4 bytes, json is padded with spaces and bin with zeros.
+	for len(jsonData)%4 != 0 {
+		jsonData = append(jsonData, ' ')
+	}
+	binData := bin
+	for len(binData)%4 != 0 {
+		binData = append(binData, 0)
+	}
+
+	length := 12 + 8 + len(jsonData)
+	if len(binData) > 0 {
+		length += 8 + len(binData)
+	}
+
+	header := []uint32{glbMagic, glbVersion, uint32(length), uint32(len(jsonData)), glbChunkJSON}
+	if err := binary.Write(w, binary.LittleEndian, header); err != nil {
+		return err
+	}
+	if _, err := w.Write(jsonData); err != nil {
+		return err
+	}
+	if len(binData) == 0 {
+		return nil
+	}
+	if err := binary.Write(w, binary.LittleEndian, []uint32{uint32(len(binData)), glbChunkBIN}); err != nil {
+		return err
+	}
+	_, err = w.Write(binData)
+	return err
+}
+
+// ReadGLB reads a .glb file, and returns the glTF document and its binary buffer.
+func ReadGLB(data []byte) (*GLTF, []byte, error) {
+	reader := bytes.NewReader(data)
+	header := [3]uint32{}
+	if err := binary.Read(reader, binary.LittleEndian, &header); err != nil {
+		return nil, nil, fmt.Errorf("invalid glb header")
+	}
+	if header[0] != glbMagic {
+		return nil, nil, fmt.Errorf("invalid glb magic")
+	}
+	if header[1] != glbVersion {
+		return nil, nil, fmt.Errorf("glb version %v is not supported", header[1])
+	}
+	if int(header[2]) > len(data) {
+		return nil, nil, fmt.Errorf("glb is truncated")
+	}
+	data = data[:header[2]]
+
+	var doc *GLTF
+	var bin []byte
+	for offset := 12; offset+8 <= len(data); {
+		length := int(binary.LittleEndian.Uint32(data[offset:]))
+		chunkType := binary.LittleEndian.Uint32(data[offset+4:])
+		offset += 8
+		if length < 0 || offset+length > len(data) {
+			return nil, nil, fmt.Errorf("glb chunk is truncated")
+		}
+		chunk := data[offset : offset+length]
+		offset += length
+
+		switch chunkType {
+		case glbChunkJSON:
+			doc = &GLTF{}
+			if err := json.Unmarshal(chunk, doc); err != nil {
+				return nil, nil, err
+			}
+		case glbChunkBIN:
+			if bin == nil {
+				bin = chunk
+			}
+		}
+	}
+	if doc == nil {
+		return nil, nil, fmt.Errorf("glb has no json chunk")
+	}
+	return doc, bin, nil
+}
+
+// ReadGLTF reads a .gltf or .glb file, and returns the glTF document and its buffers.
+// Buffers are the glb binary chunk or base64 data urls, and other buffers are read
+// from the files in dir. When dir is empty, only self-contained files are allowed.
+// maxSize limits the total size of the buffer files, 0 means no limit.
+func ReadGLTF(data []byte, glb bool, dir string, maxSize int64) (*GLTF, [][]byte, error) {
+	var doc *GLTF
+	buffers := [][]byte{}
+
+	if glb {
+		var bin []byte
+		var err error
+		if doc, bin, err = ReadGLB(data); err != nil {
+			return nil, nil, err
+		}
+		buffers = append(buffers, bin)
+	} else {
+		doc = &GLTF{}
+		if err := json.Unmarshal(data, doc); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var size int64
+	for i, buffer := range doc.Buffers {
+		if buffer.URI == "" {
+			if i >= len(buffers) || buffers[i] == nil {
+				return nil, nil, fmt.Errorf("buffer %v has no data", i)
+			}
+			continue
+		}
+
+		var data []byte
+		var err error
+		if strings.HasPrefix(buffer.URI, "data:") {
+			data, _, err = DecodeDataURL(buffer.URI)
+		} else if dir == "" {
+			err = fmt.Errorf("external file %v is not supported, please upload a .glb file", buffer.URI)
+		} else {
+			limit := int64(0)
+			if maxSize > 0 {
+				if limit = maxSize - size; limit <= 0 {
+					return nil, nil, fmt.Errorf("buffer %v is too large", buffer.URI)
+				}
+			}
+			data, err = readGLTFFile(buffer.URI, dir, limit)
+			size += int64(len(data))
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if i < len(buffers) {
+			buffers[i] = data
+		} else {
+			buffers = append(buffers, data)
+		}
+	}
+	return doc, buffers, nil
+}
+
+// readGLTFFile reads a file relative to the glTF file, and limit is its max size, 0
+// means no limit. Files outside the dir are not allowed.
+func readGLTFFile(uri, dir string, limit int64) ([]byte, error) {
+	name, err := url.PathUnescape(uri)
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, filepath.Base(filepath.FromSlash(name)))
+	if limit != 0 {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if info.Size() > limit {
+			return nil, fmt.Errorf("buffer %v is too large", uri)
+		}
+	}
+	return ioutil.ReadFile(path)
+}
+
+// DecodeDataURL decodes a base64 data url, such as `data:image/png;base64,...`, and
+// returns the data and its mime type.
+func DecodeDataURL(url string) ([]byte, string, error) {
+	comma := strings.Index(url, ",")
+	if !strings.HasPrefix(url, "data:") || comma < 0 || !strings.HasSuffix(url[:comma], ";base64") {
+		return nil, "", fmt.Errorf("invalid data url")
+	}
+	mimeType := strings.TrimSuffix(strings.TrimPrefix(url[:comma], "data:"), ";base64")
+	data, err := base64.StdEncoding.DecodeString(url[comma+1:])
+	return data, mimeType, err
+}
@@ -0,0 +1,52 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package helper
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestGLB(t *testing.T) {
+	doc := GLTF{
+		Asset: GLTFAsset{
+			Version: "2.0",
+		},
+		Nodes: []GLTFNode{{
+			Name: "Box",
+		}},
+		Buffers: []GLTFBuffer{{
+			ByteLength: 3,
+		}},
+	}
+
+	buf := bytes.Buffer{}
+	if err := WriteGLB(&buf, &doc, []byte{1, 2, 3}); err != nil {
+		t.Error(err)
+		return
+	}
+	if buf.Len()%4 != 0 {
+		t.Errorf("expect aligned to 4 bytes, got %v", buf.Len())
+	}
+
+	result, bin, err := ReadGLB(buf.Bytes())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if result.Asset.Version != "2.0" || len(result.Nodes) != 1 || result.Nodes[0].Name != "Box" {
+		t.Errorf("unexpected document: %v", result)
+	}
+	if !bytes.Equal(bin[:3], []byte{1, 2, 3}) {
+		t.Errorf("expect [1 2 3], got %v", bin)
+	}
+
+	if _, _, err := ReadGLB([]byte("not a glb file")); err == nil {
+		t.Error("expect an error")
+	}
+}
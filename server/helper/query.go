@@ -0,0 +1,758 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package helper
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// This file evaluates mongo style filters, updates and sorts in memory. It is used by
+// the storages that keep documents on their own, such as SQLite, so that handlers can
+// query them in the same way as mongo.
+//
+// Supported filter operators: $and, $or, $nor, $eq, $ne, $gt, $gte, $lt, $lte, $in,
+// $nin, $exists, $regex (with $options), $not and $size.
+//
+// Supported update operators: $set, $unset, $inc and $push. An update without operators
+// replaces the whole document but keeps its `_id`.
+
+// newDocument marshals a document to bson, and adds an `_id` if it has not one like mongo.
+func newDocument(document interface{}) (bson.Raw, interface{}, error) {
+	raw, err := bson.Marshal(document)
+	if err != nil {
+		return nil, nil, err
+	}
+	if value, err := bson.Raw(raw).LookupErr("_id"); err == nil {
+		var id interface{}
+		if err := value.Unmarshal(&id); err != nil {
+			return nil, nil, err
+		}
+		return raw, id, nil
+	}
+
+	doc := bson.D{}
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return nil, nil, err
+	}
+	id := primitive.NewObjectID()
+	doc = append(bson.D{{Key: "_id", Value: id}}, doc...)
+
+	raw, err = bson.Marshal(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return raw, id, nil
+}
+
+// decodeDocuments decodes bson documents to results, which must be a pointer to a slice.
+func decodeDocuments(docs []bson.Raw, results interface{}) error {
+	value := reflect.ValueOf(results)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("results argument must be a pointer to a slice, but was a %v", value.Kind())
+	}
+	slice := value.Elem().Slice(0, 0)
+	for _, doc := range docs {
+		elem := reflect.New(slice.Type().Elem())
+		if err := bson.Unmarshal(doc, elem.Interface()); err != nil {
+			return err
+		}
+		slice = reflect.Append(slice, elem.Elem())
+	}
+	value.Elem().Set(slice)
+	return nil
+}
+
+// toM converts a filter or an update to bson.M.
+func toM(value interface{}) (bson.M, error) {
+	if value == nil {
+		return bson.M{}, nil
+	}
+	raw, err := bson.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	result := bson.M{}
+	if err := bson.Unmarshal(raw, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// toD converts a sort specification to bson.D, so that the order of keys is kept.
+func toD(value interface{}) (bson.D, error) {
+	if value == nil {
+		return bson.D{}, nil
+	}
+	raw, err := bson.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	result := bson.D{}
+	if err := bson.Unmarshal(raw, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// asMap returns a document value as bson.M.
+func asMap(value interface{}) (bson.M, bool) {
+	switch v := value.(type) {
+	case primitive.M:
+		return v, true
+	case primitive.D:
+		return v.Map(), true
+	case map[string]interface{}:
+		return bson.M(v), true
+	}
+	return nil, false
+}
+
+// asArray returns an array value as primitive.A.
+func asArray(value interface{}) (primitive.A, bool) {
+	switch v := value.(type) {
+	case primitive.A:
+		return v, true
+	case []interface{}:
+		return primitive.A(v), true
+	}
+	return nil, false
+}
+
+// lookupPath gets a value from a document by a dotted path, such as `metadata.generator`.
+// When the path goes through an array of documents, it returns the values of every element.
+func lookupPath(doc interface{}, path string) (interface{}, bool) {
+	current := doc
+	parts := strings.Split(path, ".")
+	for i, part := range parts {
+		if m, ok := asMap(current); ok {
+			value, ok := m[part]
+			if !ok {
+				return nil, false
+			}
+			current = value
+			continue
+		}
+		arr, ok := asArray(current)
+		if !ok {
+			return nil, false
+		}
+		if index, err := strconv.Atoi(part); err == nil {
+			if index < 0 || index >= len(arr) {
+				return nil, false
+			}
+			current = arr[index]
+			continue
+		}
+		rest := strings.Join(parts[i:], ".")
+		values := primitive.A{}
+		for _, item := range arr {
+			if value, ok := lookupPath(item, rest); ok {
+				values = append(values, value)
+			}
+		}
+		return values, len(values) > 0
+	}
+	return current, true
+}
+
+// matchFilter reports whether a document satisfies a mongo style filter.
+func matchFilter(doc bson.M, filter bson.M) (bool, error) {
+	for key, condition := range filter {
+		var ok bool
+		var err error
+		switch key {
+		case "$and", "$or", "$nor":
+			ok, err = matchLogical(doc, key, condition)
+		default:
+			if strings.HasPrefix(key, "$") {
+				return false, fmt.Errorf("unknown top level operator: %v", key)
+			}
+			value, exists := lookupPath(doc, key)
+			ok, err = matchCondition(value, exists, condition)
+		}
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// matchLogical evaluates $and, $or and $nor.
+func matchLogical(doc bson.M, operator string, condition interface{}) (bool, error) {
+	filters, ok := asArray(condition)
+	if !ok || len(filters) == 0 {
+		return false, fmt.Errorf("%v must be a nonempty array", operator)
+	}
+	for _, item := range filters {
+		filter, ok := asMap(item)
+		if !ok {
+			return false, fmt.Errorf("%v entries must be documents", operator)
+		}
+		matched, err := matchFilter(doc, filter)
+		if err != nil {
+			return false, err
+		}
+		switch {
+		case operator == "$and" && !matched:
+			return false, nil
+		case operator == "$or" && matched:
+			return true, nil
+		case operator == "$nor" && matched:
+			return false, nil
+		}
+	}
+	return operator != "$or", nil
+}
+
+// isOperatorExpression reports whether a condition is like `{"$ne": -1}`.
+func isOperatorExpression(condition interface{}) (bson.M, bool) {
+	m, ok := asMap(condition)
+	if !ok || len(m) == 0 {
+		return nil, false
+	}
+	for key := range m {
+		if !strings.HasPrefix(key, "$") {
+			return nil, false
+		}
+	}
+	return m, true
+}
+
+// matchCondition evaluates the condition of one field.
+func matchCondition(value interface{}, exists bool, condition interface{}) (bool, error) {
+	expression, ok := isOperatorExpression(condition)
+	if !ok {
+		if !exists {
+			// like mongo, `{"field": null}` also matches documents without the field
+			return condition == nil, nil
+		}
+		return matchEqual(value, condition), nil
+	}
+	if _, ok := expression["$options"]; ok {
+		if _, ok := expression["$regex"]; !ok {
+			return false, fmt.Errorf("$options needs a $regex")
+		}
+	}
+	for operator, operand := range expression {
+		matched, err := matchOperator(value, exists, operator, operand, expression)
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// matchOperator evaluates one query operator on a field value.
+func matchOperator(value interface{}, exists bool, operator string, operand interface{}, expression bson.M) (bool, error) {
+	switch operator {
+	case "$eq":
+		return exists && matchEqual(value, operand), nil
+	case "$ne":
+		return !exists || !matchEqual(value, operand), nil
+	case "$gt", "$gte", "$lt", "$lte":
+		if !exists {
+			return false, nil
+		}
+		return matchAny(value, func(item interface{}) bool {
+			result, ok := compareValues(item, operand)
+			if !ok {
+				return false
+			}
+			switch operator {
+			case "$gt":
+				return result > 0
+			case "$gte":
+				return result >= 0
+			case "$lt":
+				return result < 0
+			}
+			return result <= 0
+		}), nil
+	case "$in", "$nin":
+		candidates, ok := asArray(operand)
+		if !ok {
+			return false, fmt.Errorf("%v needs an array", operator)
+		}
+		found := false
+		for _, candidate := range candidates {
+			if (exists && matchEqual(value, candidate)) || (!exists && candidate == nil) {
+				found = true
+				break
+			}
+		}
+		return found == (operator == "$in"), nil
+	case "$exists":
+		return exists == truthy(operand), nil
+	case "$regex":
+		if !exists {
+			return false, nil
+		}
+		re, err := compileRegex(operand, expression["$options"])
+		if err != nil {
+			return false, err
+		}
+		return matchAny(value, func(item interface{}) bool {
+			str, ok := item.(string)
+			return ok && re.MatchString(str)
+		}), nil
+	case "$options":
+		return true, nil
+	case "$not":
+		matched, err := matchCondition(value, exists, operand)
+		return !matched, err
+	case "$size":
+		arr, ok := asArray(value)
+		if !ok {
+			return false, nil
+		}
+		size, ok := toFloat(operand)
+		return ok && float64(len(arr)) == size, nil
+	}
+	return false, fmt.Errorf("unknown operator: %v", operator)
+}
+
+// matchAny reports whether a value or any of its elements satisfies fn.
+func matchAny(value interface{}, fn func(item interface{}) bool) bool {
+	if fn(value) {
+		return true
+	}
+	if arr, ok := asArray(value); ok {
+		for _, item := range arr {
+			if fn(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchEqual reports whether a value equals the expected one. Like mongo, an array
+// matches when the array itself or any of its elements equals the expected value.
+func matchEqual(value, expected interface{}) bool {
+	return matchAny(value, func(item interface{}) bool {
+		result, ok := compareValues(item, expected)
+		return ok && result == 0
+	})
+}
+
+// truthy converts the operand of $exists to bool.
+func truthy(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case nil:
+		return false
+	}
+	if number, ok := toFloat(value); ok {
+		return number != 0
+	}
+	return true
+}
+
+// compileRegex compiles the operand of $regex with the flags in $options.
+func compileRegex(pattern, options interface{}) (*regexp.Regexp, error) {
+	expr := ""
+	flags := ""
+	switch v := pattern.(type) {
+	case string:
+		expr = v
+	case primitive.Regex:
+		expr = v.Pattern
+		flags = v.Options
+	default:
+		return nil, fmt.Errorf("$regex has to be a string")
+	}
+	if str, ok := options.(string); ok {
+		flags += str
+	}
+	prefix := ""
+	for _, flag := range flags {
+		switch flag {
+		case 'i', 'm', 's':
+			prefix += string(flag)
+		}
+	}
+	if prefix != "" {
+		expr = "(?" + prefix + ")" + expr
+	}
+	return regexp.Compile(expr)
+}
+
+// toFloat converts a bson number to float64.
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// typeOrder returns the mongo comparison order of bson types.
+func typeOrder(value interface{}) int {
+	if _, ok := toFloat(value); ok {
+		return 2
+	}
+	if _, ok := asMap(value); ok {
+		return 4
+	}
+	if _, ok := asArray(value); ok {
+		return 5
+	}
+	switch value.(type) {
+	case nil, primitive.Null, primitive.Undefined:
+		return 1
+	case string, primitive.Symbol:
+		return 3
+	case primitive.Binary, []byte:
+		return 6
+	case primitive.ObjectID:
+		return 7
+	case bool:
+		return 8
+	case primitive.DateTime:
+		return 9
+	case primitive.Timestamp:
+		return 10
+	case primitive.Regex:
+		return 11
+	}
+	return 12
+}
+
+// compareValues compares two bson values. Like mongo query operators, it returns false
+// when they cannot be ordered, such as values of different types.
+func compareValues(a, b interface{}) (int, bool) {
+	order := typeOrder(a)
+	if order != typeOrder(b) {
+		return 0, false
+	}
+	switch order {
+	case 1:
+		return 0, true
+	case 2:
+		x, _ := toFloat(a)
+		y, _ := toFloat(b)
+		return compareFloat(x, y), true
+	case 3:
+		return strings.Compare(fmt.Sprint(a), fmt.Sprint(b)), true
+	case 7:
+		x, y := a.(primitive.ObjectID), b.(primitive.ObjectID)
+		return bytes.Compare(x[:], y[:]), true
+	case 8:
+		x, y := a.(bool), b.(bool)
+		if x == y {
+			return 0, true
+		}
+		if !x {
+			return -1, true
+		}
+		return 1, true
+	case 9:
+		return compareFloat(float64(a.(primitive.DateTime)), float64(b.(primitive.DateTime))), true
+	case 4:
+		x, _ := asMap(a)
+		y, _ := asMap(b)
+		if len(x) != len(y) {
+			return 0, false
+		}
+		for key, value := range x {
+			other, ok := y[key]
+			if !ok {
+				return 0, false
+			}
+			if result, ok := compareValues(value, other); !ok || result != 0 {
+				return 0, false
+			}
+		}
+		return 0, true
+	case 5:
+		x, _ := asArray(a)
+		y, _ := asArray(b)
+		for i := 0; i < len(x) && i < len(y); i++ {
+			result, ok := compareValues(x[i], y[i])
+			if !ok || result != 0 {
+				return result, ok
+			}
+		}
+		return compareFloat(float64(len(x)), float64(len(y))), true
+	}
+	if reflect.DeepEqual(a, b) {
+		return 0, true
+	}
+	return 0, false
+}
+
+// sortValues compares two bson values for sorting, and values of different types are
+// ordered by their types like mongo.
+func sortValues(a, b interface{}) int {
+	orderA, orderB := typeOrder(a), typeOrder(b)
+	if orderA != orderB {
+		return compareFloat(float64(orderA), float64(orderB))
+	}
+	result, _ := compareValues(a, b)
+	return result
+}
+
+// compareFloat compares two float64 numbers.
+func compareFloat(x, y float64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+// applyUpdate applies a mongo style update to a document.
+func applyUpdate(doc bson.M, update bson.M) (bson.M, error) {
+	isReplacement := true
+	for key := range update {
+		if strings.HasPrefix(key, "$") {
+			isReplacement = false
+			break
+		}
+	}
+	if isReplacement {
+		if id, ok := doc["_id"]; ok {
+			update["_id"] = id
+		}
+		return update, nil
+	}
+
+	for operator, fields := range update {
+		values, ok := asMap(fields)
+		if !ok {
+			return nil, fmt.Errorf("modifier %v allowed for objects only", operator)
+		}
+		for path, value := range values {
+			if path == "_id" {
+				return nil, fmt.Errorf("performing an update on the path '_id' would modify the immutable field '_id'")
+			}
+			var err error
+			switch operator {
+			case "$set":
+				err = setPath(doc, path, value)
+			case "$unset":
+				unsetPath(doc, path)
+			case "$inc":
+				err = incPath(doc, path, value)
+			case "$push":
+				err = pushPath(doc, path, value)
+			default:
+				err = fmt.Errorf("unknown modifier: %v", operator)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return doc, nil
+}
+
+// setPath sets a value at a dotted path, creating the parent documents if needed.
+func setPath(doc bson.M, path string, value interface{}) error {
+	parts := strings.Split(path, ".")
+	var current interface{} = doc
+	for i, part := range parts {
+		last := i == len(parts)-1
+		if m, ok := asMap(current); ok {
+			if last {
+				m[part] = value
+				return nil
+			}
+			next, ok := m[part]
+			if !ok || next == nil {
+				next = bson.M{}
+				m[part] = next
+			}
+			current = next
+			continue
+		}
+		arr, ok := asArray(current)
+		index, err := strconv.Atoi(part)
+		if !ok || err != nil || index < 0 || index >= len(arr) {
+			return fmt.Errorf("cannot create field '%v' in path '%v'", part, path)
+		}
+		if last {
+			arr[index] = value
+			return nil
+		}
+		current = arr[index]
+	}
+	return nil
+}
+
+// unsetPath removes the value at a dotted path.
+func unsetPath(doc bson.M, path string) {
+	index := strings.LastIndex(path, ".")
+	if index == -1 {
+		delete(doc, path)
+		return
+	}
+	if parent, ok := lookupPath(doc, path[:index]); ok {
+		if m, ok := asMap(parent); ok {
+			delete(m, path[index+1:])
+		}
+	}
+}
+
+// incPath increases the number at a dotted path.
+func incPath(doc bson.M, path string, value interface{}) error {
+	delta, ok := toFloat(value)
+	if !ok {
+		return fmt.Errorf("cannot increment with non-numeric argument: %v", path)
+	}
+	current, exists := lookupPath(doc, path)
+	if !exists {
+		return setPath(doc, path, value)
+	}
+	switch v := current.(type) {
+	case int32:
+		if d, ok := value.(int32); ok {
+			return setPath(doc, path, v+d)
+		}
+		if d, ok := value.(int64); ok {
+			return setPath(doc, path, int64(v)+d)
+		}
+	case int64:
+		if d, ok := value.(int32); ok {
+			return setPath(doc, path, v+int64(d))
+		}
+		if d, ok := value.(int64); ok {
+			return setPath(doc, path, v+d)
+		}
+	}
+	number, ok := toFloat(current)
+	if !ok {
+		return fmt.Errorf("cannot apply $inc to a value of non-numeric type: %v", path)
+	}
+	return setPath(doc, path, number+delta)
+}
+
+// pushPath appends a value to the array at a dotted path.
+func pushPath(doc bson.M, path string, value interface{}) error {
+	current, exists := lookupPath(doc, path)
+	if !exists || current == nil {
+		return setPath(doc, path, primitive.A{value})
+	}
+	arr, ok := asArray(current)
+	if !ok {
+		return fmt.Errorf("the field '%v' must be an array", path)
+	}
+	return setPath(doc, path, append(arr, value))
+}
+
+// queryItem is a document being queried, and index is its position in the input.
+type queryItem struct {
+	index int
+	doc   bson.M
+}
+
+// sortItems sorts documents by a mongo style sort specification, such as `{"UpdateTime": -1}`.
+func sortItems(items []queryItem, spec bson.D) {
+	if len(spec) == 0 {
+		return
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		for _, item := range spec {
+			direction, _ := toFloat(item.Value)
+			a, _ := lookupPath(items[i].doc, item.Key)
+			b, _ := lookupPath(items[j].doc, item.Key)
+			result := sortValues(a, b)
+			if result == 0 {
+				continue
+			}
+			if direction < 0 {
+				return result > 0
+			}
+			return result < 0
+		}
+		return false
+	})
+}
+
+// queryDocuments filters, sorts, skips and limits bson documents in memory. It returns
+// the indexes of the matched documents in docs.
+func queryDocuments(docs []bson.Raw, filter interface{}, sortSpec interface{}, skip, limit int64) ([]int, error) {
+	conditions, err := toM(filter)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := toD(sortSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []queryItem{}
+	for i, raw := range docs {
+		doc := bson.M{}
+		if err := bson.Unmarshal(raw, &doc); err != nil {
+			return nil, err
+		}
+		ok, err := matchFilter(doc, conditions)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			items = append(items, queryItem{i, doc})
+		}
+	}
+
+	sortItems(items, spec)
+
+	if skip > 0 {
+		if skip > int64(len(items)) {
+			skip = int64(len(items))
+		}
+		items = items[skip:]
+	}
+	if limit > 0 && limit < int64(len(items)) {
+		items = items[:limit]
+	}
+
+	indexes := make([]int, 0, len(items))
+	for _, item := range items {
+		indexes = append(indexes, item.index)
+	}
+	return indexes, nil
+}
+
+// updateDocument applies a mongo style update to a bson document.
+func updateDocument(raw bson.Raw, update interface{}) (bson.Raw, error) {
+	changes, err := toM(update)
+	if err != nil {
+		return nil, err
+	}
+	doc := bson.M{}
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	doc, err = applyUpdate(doc, changes)
+	if err != nil {
+		return nil, err
+	}
+	return bson.Marshal(doc)
+}
@@ -0,0 +1,121 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package helper
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestQueryDocuments(t *testing.T) {
+	id := primitive.NewObjectID()
+	docs := []bson.Raw{}
+	for _, doc := range []bson.M{
+		{"ID": id, "Name": "Scene1", "UserID": "1", "Version": 3, "metadata": bson.M{"generator": "OptionsSerializer"}},
+		{"Name": "scene2", "IsPublic": true, "Version": 1},
+		{"Name": "Mesh", "Status": -1, "Tags": bson.A{"a", "b"}},
+		{"Name": "Map", "Version": "v2"},
+	} {
+		raw, _, err := newDocument(doc)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		docs = append(docs, raw)
+	}
+
+	tests := []struct {
+		filter bson.M
+		expect int
+	}{
+		{bson.M{}, 4},
+		{bson.M{"ID": id}, 1},
+		{bson.M{"metadata.generator": "OptionsSerializer"}, 1},
+		{bson.M{"Status": bson.M{"$ne": -1}}, 3},
+		{bson.M{"UserID": bson.M{"$exists": 0}}, 3},
+		{bson.M{"Name": bson.M{"$regex": "^scene", "$options": "i"}}, 2},
+		{bson.M{"Version": bson.M{"$gt": 1}}, 1},
+		// values of different types do not match comparisons, like mongo
+		{bson.M{"Version": bson.M{"$lt": "z"}}, 1},
+		{bson.M{"Version": bson.M{"$gte": primitive.NewObjectID()}}, 0},
+		{bson.M{"Tags": "b"}, 1},
+		{bson.M{"$or": bson.A{bson.M{"UserID": "1"}, bson.M{"IsPublic": true}}}, 2},
+		{bson.M{"$and": bson.A{bson.M{"Version": bson.M{"$in": bson.A{1, 3}}}, bson.M{"IsPublic": true}}}, 1},
+	}
+
+	for _, test := range tests {
+		indexes, err := queryDocuments(docs, test.filter, nil, 0, 0)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if len(indexes) != test.expect {
+			t.Errorf("%v: expect %v, got %v", test.filter, test.expect, len(indexes))
+		}
+	}
+
+	// sort
+	indexes, err := queryDocuments(docs, bson.M{"Version": bson.M{"$exists": 1}}, bson.M{"Version": 1}, 0, 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	// numbers are sorted before strings
+	if len(indexes) != 3 || indexes[0] != 1 || indexes[1] != 0 || indexes[2] != 3 {
+		t.Errorf("expect [1 0 3], got %v", indexes)
+	}
+}
+
+func TestUpdateDocument(t *testing.T) {
+	raw, id, err := newDocument(bson.M{"Name": "Scene1", "Version": 1, "Category": "1"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"Name":         "Scene2",
+			"metadata.url": "/Upload",
+		},
+		"$unset": bson.M{
+			"Category": 1,
+		},
+		"$inc": bson.M{
+			"Version": 1,
+		},
+	}
+	raw, err = updateDocument(raw, update)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	doc := bson.M{}
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		t.Error(err)
+		return
+	}
+	if doc["_id"] != id {
+		t.Errorf("expect %v, got %v", id, doc["_id"])
+	}
+	if doc["Name"] != "Scene2" {
+		t.Errorf("expect Scene2, got %v", doc["Name"])
+	}
+	if doc["Version"] != int32(2) {
+		t.Errorf("expect 2, got %v", doc["Version"])
+	}
+	if _, ok := doc["Category"]; ok {
+		t.Errorf("expect Category to be removed")
+	}
+	if url, _ := lookupPath(doc, "metadata.url"); url != "/Upload" {
+		t.Errorf("expect /Upload, got %v", url)
+	}
+}
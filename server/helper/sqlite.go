@@ -1,5 +1,3 @@
-// +build ignore
-
 // Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
 // Use of this source code is governed by a MIT-style
 // license that can be found in the LICENSE file.
@@ -12,8 +10,12 @@ package helper
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 
 	_ "github.com/mattn/go-sqlite3" // sqlite3 driver for go using database/sql
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // NewSQLite create a new sqlite connection.
@@ -27,10 +29,19 @@ func NewSQLite(path string) (*SQLite, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	// sqlite allows only one writer at a time, and more connections
+	// will cause `database is locked` error.
+	db.SetMaxOpenConns(1)
+
 	return &SQLite{db}, nil
 }
 
 // SQLite is a SQLite client.
+//
+// Besides executing sql, it can be used as a document storage like Mongo. Each collection
+// is a table, and each document is saved as bson in a row. Filters, updates and options
+// are mongo style, and they are evaluated in memory, see query.go.
 type SQLite struct {
 	DB *sql.DB
 }
@@ -74,3 +85,305 @@ func (s SQLite) Close() error {
 	}
 	return s.DB.Close()
 }
+
+// sqliteTable quotes a collection name to use as a table name.
+func sqliteTable(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// ListCollectionNames list collectionNames of database.
+func (s SQLite) ListCollectionNames() (collectionNames []string, err error) {
+	rows, err := s.Query("select name from sqlite_master where type='table' and name not like 'sqlite_%' order by name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	collectionNames = []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		collectionNames = append(collectionNames, name)
+	}
+	return collectionNames, rows.Err()
+}
+
+// CollectionExists determine whether a collection existed.
+func (s SQLite) CollectionExists(name string) (existed bool, err error) {
+	row, err := s.QueryRow("select count(*) from sqlite_master where type='table' and name=?", name)
+	if err != nil {
+		return false, err
+	}
+	var count int
+	if err := row.Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// CreateCollection create a new collection.
+func (s SQLite) CreateCollection(name string) error {
+	_, err := s.Exec(fmt.Sprintf("create table if not exists %v (id integer primary key autoincrement, data blob not null)", sqliteTable(name)))
+	return err
+}
+
+// DropCollection drop a collection.
+func (s SQLite) DropCollection(name string) error {
+	_, err := s.Exec(fmt.Sprintf("drop table if exists %v", sqliteTable(name)))
+	return err
+}
+
+// InsertOne insert one document to a collection.
+func (s SQLite) InsertOne(collectionName string, document interface{}) (*mongo.InsertOneResult, error) {
+	result, err := s.InsertMany(collectionName, []interface{}{document})
+	if err != nil {
+		return nil, err
+	}
+	return &mongo.InsertOneResult{InsertedID: result.InsertedIDs[0]}, nil
+}
+
+// InsertMany insert many documents to a collection.
+func (s SQLite) InsertMany(collectionName string, documents []interface{}) (*mongo.InsertManyResult, error) {
+	if len(documents) == 0 {
+		return nil, fmt.Errorf("must provide at least one element in input slice")
+	}
+	if err := s.CreateCollection(collectionName); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := fmt.Sprintf("insert into %v (data) values (?)", sqliteTable(collectionName))
+	ids := []interface{}{}
+	for _, document := range documents {
+		raw, id, err := newDocument(document)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(query, []byte(raw)); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &mongo.InsertManyResult{InsertedIDs: ids}, nil
+}
+
+// sqliteQuerier is implemented by both *sql.DB and *sql.Tx.
+type sqliteQuerier interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// sqliteRows reads all the documents of a collection with their row ids.
+func sqliteRows(q sqliteQuerier, collectionName string) (ids []int64, docs []bson.Raw, err error) {
+	var count int
+	row := q.QueryRow("select count(*) from sqlite_master where type='table' and name=?", collectionName)
+	if err := row.Scan(&count); err != nil || count == 0 {
+		return nil, nil, err
+	}
+
+	rows, err := q.Query(fmt.Sprintf("select id, data from %v order by id", sqliteTable(collectionName)))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var data []byte
+		if err := rows.Scan(&id, &data); err != nil {
+			return nil, nil, err
+		}
+		ids = append(ids, id)
+		docs = append(docs, bson.Raw(data))
+	}
+	return ids, docs, rows.Err()
+}
+
+// sqliteFind finds the row ids and documents that match the filter.
+func sqliteFind(q sqliteQuerier, collectionName string, filter interface{}, sort interface{}, skip, limit int64) ([]int64, []bson.Raw, error) {
+	ids, docs, err := sqliteRows(q, collectionName)
+	if err != nil {
+		return nil, nil, err
+	}
+	indexes, err := queryDocuments(docs, filter, sort, skip, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	matchedIDs := make([]int64, 0, len(indexes))
+	matchedDocs := make([]bson.Raw, 0, len(indexes))
+	for _, index := range indexes {
+		matchedIDs = append(matchedIDs, ids[index])
+		matchedDocs = append(matchedDocs, docs[index])
+	}
+	return matchedIDs, matchedDocs, nil
+}
+
+// Count get documents count of a collection.
+func (s SQLite) Count(collectionName string, filter interface{}) (int64, error) {
+	if s.DB == nil {
+		return 0, fmt.Errorf("db is not created")
+	}
+	ids, _, err := sqliteFind(s.DB, collectionName, filter, nil, 0, 0)
+	return int64(len(ids)), err
+}
+
+// FindOne find one document from a collection.
+func (s SQLite) FindOne(collectionName string, filter interface{}, result interface{}, opts ...*options.FindOneOptions) (find bool, err error) {
+	opt := options.MergeFindOneOptions(opts...)
+	var skip int64
+	if opt.Skip != nil {
+		skip = *opt.Skip
+	}
+
+	if s.DB == nil {
+		return false, fmt.Errorf("db is not created")
+	}
+	_, docs, err := sqliteFind(s.DB, collectionName, filter, opt.Sort, skip, 1)
+	if err != nil {
+		return false, err
+	}
+	if len(docs) == 0 {
+		return false, nil
+	}
+	if err := bson.Unmarshal(docs[0], result); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// FindMany find many documents in the collection.
+func (s SQLite) FindMany(collectionName string, filter interface{}, results interface{}, opts ...*options.FindOptions) (err error) {
+	opt := options.MergeFindOptions(opts...)
+	var skip, limit int64
+	if opt.Skip != nil {
+		skip = *opt.Skip
+	}
+	if opt.Limit != nil {
+		limit = *opt.Limit
+	}
+
+	if s.DB == nil {
+		return fmt.Errorf("db is not created")
+	}
+	_, docs, err := sqliteFind(s.DB, collectionName, filter, opt.Sort, skip, limit)
+	if err != nil {
+		return err
+	}
+	return decodeDocuments(docs, results)
+}
+
+// FindAll find all the documents in the collection.
+func (s SQLite) FindAll(collectionName string, results interface{}, opts ...*options.FindOptions) (err error) {
+	return s.FindMany(collectionName, bson.M{}, results, opts...)
+}
+
+// sqliteUpdate updates documents that match the filter. It updates one document at most
+// when limit is 1.
+func (s SQLite) sqliteUpdate(collectionName string, filter interface{}, update interface{}, limit int64) (*mongo.UpdateResult, error) {
+	if s.DB == nil {
+		return nil, fmt.Errorf("db is not created")
+	}
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	ids, docs, err := sqliteFind(tx, collectionName, filter, nil, 0, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("update %v set data=? where id=?", sqliteTable(collectionName))
+	result := mongo.UpdateResult{}
+	for i, doc := range docs {
+		data, err := updateDocument(doc, update)
+		if err != nil {
+			return nil, err
+		}
+		result.MatchedCount++
+		if string(data) == string(doc) {
+			continue
+		}
+		if _, err := tx.Exec(query, []byte(data), ids[i]); err != nil {
+			return nil, err
+		}
+		result.ModifiedCount++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// UpdateOne update one document.
+func (s SQLite) UpdateOne(collectionName string, filter interface{}, update interface{}) (*mongo.UpdateResult, error) {
+	return s.sqliteUpdate(collectionName, filter, update, 1)
+}
+
+// UpdateMany update many documents in the collection.
+func (s SQLite) UpdateMany(collectionName string, filter interface{}, update interface{}) (*mongo.UpdateResult, error) {
+	return s.sqliteUpdate(collectionName, filter, update, 0)
+}
+
+// UpdateAll update all the documents in the collection.
+func (s SQLite) UpdateAll(collectionName string, update interface{}) (*mongo.UpdateResult, error) {
+	return s.UpdateMany(collectionName, bson.M{}, update)
+}
+
+// sqliteDelete deletes documents that match the filter. It deletes one document at most
+// when limit is 1.
+func (s SQLite) sqliteDelete(collectionName string, filter interface{}, limit int64) (*mongo.DeleteResult, error) {
+	if s.DB == nil {
+		return nil, fmt.Errorf("db is not created")
+	}
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	ids, _, err := sqliteFind(tx, collectionName, filter, nil, 0, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("delete from %v where id=?", sqliteTable(collectionName))
+	for _, id := range ids {
+		if _, err := tx.Exec(query, id); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &mongo.DeleteResult{DeletedCount: int64(len(ids))}, nil
+}
+
+// DeleteOne delete one document from the collection.
+func (s SQLite) DeleteOne(collectionName string, filter interface{}) (*mongo.DeleteResult, error) {
+	return s.sqliteDelete(collectionName, filter, 1)
+}
+
+// DeleteMany delete many documents from the collection.
+func (s SQLite) DeleteMany(collectionName string, filter interface{}) (*mongo.DeleteResult, error) {
+	return s.sqliteDelete(collectionName, filter, 0)
+}
+
+// DeleteAll delete all documents from the collection.
+func (s SQLite) DeleteAll(collectionName string) (*mongo.DeleteResult, error) {
+	return s.DeleteMany(collectionName, bson.M{})
+}
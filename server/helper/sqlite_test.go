@@ -1,5 +1,3 @@
-// +build ignore
-
 // Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
 // Use of this source code is governed by a MIT-style
 // license that can be found in the LICENSE file.
@@ -10,11 +8,25 @@
 package helper
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func TestSQLite(t *testing.T) {
-	client, err := NewSQLite("./test.db")
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	client, err := NewSQLite(filepath.Join(dir, "test.db"))
 	if err != nil {
 		t.Error(err)
 		return
@@ -49,6 +61,7 @@ func TestSQLite(t *testing.T) {
 		}
 		t.Logf("name: %v, age: %v", name, age)
 	}
+	rows.Close()
 
 	_, err = client.Exec("drop table test")
 	if err != nil {
@@ -56,3 +69,138 @@ func TestSQLite(t *testing.T) {
 		return
 	}
 }
+
+func TestSQLiteDocuments(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := NewSQLite(filepath.Join(dir, "test.db"))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer db.Close()
+
+	collectionName := "PersonTest"
+
+	// FindMany on a collection that does not exist
+	results := []Person{}
+	if err := db.FindAll(collectionName, &results); err != nil {
+		t.Error(err)
+	}
+	if len(results) != 0 {
+		t.Errorf("expect 0, got %v", len(results))
+	}
+
+	// InsertOne
+	result, err := db.InsertOne(collectionName, Person{"xiaoqiang", 30})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if _, ok := result.InsertedID.(primitive.ObjectID); !ok {
+		t.Errorf("expect ObjectID, got %T", result.InsertedID)
+	}
+
+	// InsertMany
+	persons := []interface{}{
+		Person{"xiaoming", 10},
+		Person{"xiaoli", 20},
+	}
+	if _, err = db.InsertMany(collectionName, persons); err != nil {
+		t.Error(err)
+	}
+
+	// CollectionExists
+	existed, err := db.CollectionExists(collectionName)
+	if err != nil {
+		t.Error(err)
+	}
+	if !existed {
+		t.Errorf("expect true, got %v", existed)
+	}
+
+	// FindOne
+	person := Person{}
+	find, err := db.FindOne(collectionName, bson.M{"name": "xiaoqiang"}, &person)
+	if err != nil {
+		t.Error(err)
+	}
+	if !find || person.Age != 30 {
+		t.Errorf("expect xiaoqiang 30, got %v %v", person.Name, person.Age)
+	}
+
+	// FindMany with sort, skip and limit
+	skip, limit := int64(1), int64(1)
+	opts := options.FindOptions{
+		Sort: bson.M{
+			"age": -1,
+		},
+		Skip:  &skip,
+		Limit: &limit,
+	}
+	if err := db.FindMany(collectionName, bson.M{}, &results, &opts); err != nil {
+		t.Error(err)
+	}
+	if len(results) != 1 || results[0].Name != "xiaoli" {
+		t.Errorf("expect [xiaoli], got %v", results)
+	}
+
+	// Count
+	count, err := db.Count(collectionName, bson.M{"age": bson.M{"$gte": 20}})
+	if err != nil {
+		t.Error(err)
+	}
+	if count != 2 {
+		t.Errorf("expect 2, got %v", count)
+	}
+
+	// UpdateOne
+	update := bson.M{
+		"$set": bson.M{
+			"age": 40,
+		},
+	}
+	updateResult, err := db.UpdateOne(collectionName, bson.M{"name": "xiaoqiang"}, update)
+	if err != nil {
+		t.Error(err)
+	}
+	if updateResult.ModifiedCount != 1 {
+		t.Errorf("expect 1, got %v", updateResult.ModifiedCount)
+	}
+	db.FindOne(collectionName, bson.M{"name": "xiaoqiang"}, &person)
+	if person.Age != 40 {
+		t.Errorf("expect 40, got %v", person.Age)
+	}
+
+	// DeleteMany
+	deleteResult, err := db.DeleteMany(collectionName, bson.M{"age": bson.M{"$lt": 30}})
+	if err != nil {
+		t.Error(err)
+	}
+	if deleteResult.DeletedCount != 2 {
+		t.Errorf("expect 2, got %v", deleteResult.DeletedCount)
+	}
+
+	// ListCollectionNames
+	names, err := db.ListCollectionNames()
+	if err != nil {
+		t.Error(err)
+	}
+	if len(names) != 1 || names[0] != collectionName {
+		t.Errorf("expect [%v], got %v", collectionName, names)
+	}
+
+	// DropCollection
+	if err := db.DropCollection(collectionName); err != nil {
+		t.Error(err)
+	}
+	existed, _ = db.CollectionExists(collectionName)
+	if existed {
+		t.Errorf("expect false, got %v", existed)
+	}
+}
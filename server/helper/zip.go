@@ -13,6 +13,7 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 )
@@ -47,7 +48,12 @@ func Zip(dir, path string) error {
 		if path == dir { // The first path of filepath.Walk is always the root.
 			return nil
 		}
-		targetPath := strings.TrimLeft(path, dir)
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		// zip entries always use forward slashes.
+		targetPath := filepath.ToSlash(rel)
 		if info.IsDir() { // dir
 			if _, err := w.Create(targetPath + "/"); err != nil {
 				return err
@@ -77,42 +83,109 @@ func Zip(dir, path string) error {
 	return nil
 }
 
-// UnZip decompresses all files to a directory.
-func UnZip(filename, dir string) error {
+// UnZip decompresses all files to a directory. maxSize is the max total
+// uncompressed size, and maxFiles is the max entry count, 0 means no limit.
+// Entries that are symlinks or outside the directory are rejected.
+func UnZip(filename, dir string, maxSize int64, maxFiles int) error {
 	r, err := zip.OpenReader(filename)
 	if err != nil {
 		return err
 	}
 	defer r.Close()
 
-	for _, f := range r.File {
-		name := f.Name
-		info := f.FileInfo()
-		targetPath := filepath.Join(dir, name)
-		if info.IsDir() { // dir
-			if _, err := os.Stat(targetPath); os.IsNotExist(err) {
-				// IMPORTANT: info.Mode() return wrong mode when f is a dir.
-				// So, DO NOT use info.Mode() to os.MkdirAll.
-				if err := os.MkdirAll(targetPath, 0755); err != nil {
-					return err
-				}
-			}
-		} else { // file
-			reader, err := f.Open()
-			if err != nil {
-				return err
-			}
-			defer reader.Close()
-			writer, err := os.OpenFile(targetPath, os.O_CREATE|os.O_RDWR|os.O_TRUNC, info.Mode())
-			if err != nil {
-				return err
-			}
-			defer writer.Close()
-			if _, err := io.Copy(writer, reader); err != nil {
+	if maxFiles > 0 && len(r.File) > maxFiles {
+		return fmt.Errorf("zip file has more than %v entries", maxFiles)
+	}
+
+	// check all the entries before writing any file
+	targetPaths := make([]string, len(r.File))
+	var totalSize uint64
+	for i, f := range r.File {
+		targetPath, err := unZipPath(f.Name, dir)
+		if err != nil {
+			return err
+		}
+		mode := f.Mode()
+		if mode&os.ModeSymlink != 0 {
+			return fmt.Errorf("zip entry %v is a symlink", f.Name)
+		}
+		if !mode.IsDir() && !mode.IsRegular() {
+			return fmt.Errorf("zip entry %v is not a regular file", f.Name)
+		}
+		totalSize += f.UncompressedSize64
+		if maxSize > 0 && totalSize > uint64(maxSize) {
+			return fmt.Errorf("zip file is larger than %v bytes when uncompressed", maxSize)
+		}
+		targetPaths[i] = targetPath
+	}
+
+	// the sizes in the headers may be wrong, so count the written bytes too.
+	remain := maxSize
+	for i, f := range r.File {
+		targetPath := targetPaths[i]
+		if f.FileInfo().IsDir() { // dir
+			// IMPORTANT: info.Mode() return wrong mode when f is a dir.
+			// So, DO NOT use info.Mode() to os.MkdirAll.
+			if err := os.MkdirAll(targetPath, 0755); err != nil {
 				return err
 			}
+			continue
+		}
+		// file, some zip files have no dir entries.
+		if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+			return err
+		}
+		limit := int64(-1)
+		if maxSize > 0 {
+			limit = remain + 1
+		}
+		written, err := unZipFile(f, targetPath, limit)
+		if err != nil {
+			return err
+		}
+		remain -= written
+		if maxSize > 0 && remain < 0 {
+			return fmt.Errorf("zip file is larger than %v bytes when uncompressed", maxSize)
 		}
 	}
 
 	return nil
 }
+
+// unZipPath returns the path that a zip entry is decompressed to, and returns an
+// error if the entry is outside dir.
+func unZipPath(name, dir string) (string, error) {
+	// some zip tools on windows use backslashes.
+	slashName := strings.ReplaceAll(name, "\\", "/")
+	if path.IsAbs(slashName) || filepath.VolumeName(filepath.FromSlash(slashName)) != "" {
+		return "", fmt.Errorf("zip entry %v is outside the target dir", name)
+	}
+	for _, element := range strings.Split(slashName, "/") {
+		if element == ".." {
+			return "", fmt.Errorf("zip entry %v is outside the target dir", name)
+		}
+	}
+	return filepath.Join(dir, filepath.FromSlash(slashName)), nil
+}
+
+// unZipFile writes a zip entry to a file, and returns the written size. At most
+// limit bytes are written, -1 means no limit.
+func unZipFile(f *zip.File, targetPath string, limit int64) (int64, error) {
+	reader, err := f.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+
+	writer, err := os.OpenFile(targetPath, os.O_CREATE|os.O_RDWR|os.O_TRUNC, f.Mode().Perm()|0600)
+	if err != nil {
+		return 0, err
+	}
+	defer writer.Close()
+
+	var source io.Reader = reader
+	if limit >= 0 {
+		source = io.LimitReader(reader, limit)
+	}
+	return io.Copy(writer, source)
+}
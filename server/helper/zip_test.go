@@ -8,8 +8,11 @@
 package helper
 
 import (
+	"archive/zip"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -38,16 +41,106 @@ func TestZip(t *testing.T) {
 		t.Error(err)
 	}
 
+	// entry names should be relative to the source dir
+	reader, err := zip.OpenReader(destPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range reader.File {
+		if strings.HasPrefix(f.Name, "/") || strings.Contains(f.Name, "\\") {
+			t.Errorf("unexpected zip entry name: %v", f.Name)
+		}
+	}
+	reader.Close()
+
 	// unzip
 	unzipDir, err := ioutil.TempDir("", "")
 	if err != nil {
 		t.Error(err)
 	}
 
-	if err := UnZip(destPath, unzipDir); err != nil {
+	if err := UnZip(destPath, unzipDir, 0, 0); err != nil {
 		t.Error(err)
 	}
 
 	// check unzip files
 	checkTestTree(unzipDir, t)
 }
+
+func TestUnZipUnsafe(t *testing.T) {
+	// createZip creates a zip file with the entries, and returns its path.
+	createZip := func(headers ...*zip.FileHeader) string {
+		file, err := ioutil.TempFile(os.TempDir(), "*.zip")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+		w := zip.NewWriter(file)
+		for _, header := range headers {
+			writer, err := w.CreateHeader(header)
+			if err != nil {
+				t.Fatal(err)
+			}
+			writer.Write([]byte("0123456789"))
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return file.Name()
+	}
+
+	symlink := &zip.FileHeader{Name: "link"}
+	symlink.SetMode(os.ModeSymlink | 0777)
+
+	tests := []struct {
+		name     string
+		headers  []*zip.FileHeader
+		maxSize  int64
+		maxFiles int
+		err      string
+	}{
+		{"parent", []*zip.FileHeader{{Name: "../evil.txt"}}, 0, 0, "outside"},
+		{"nested parent", []*zip.FileHeader{{Name: "a/../../evil.txt"}}, 0, 0, "outside"},
+		{"backslash parent", []*zip.FileHeader{{Name: "..\\evil.txt"}}, 0, 0, "outside"},
+		{"absolute", []*zip.FileHeader{{Name: "/evil.txt"}}, 0, 0, "outside"},
+		{"symlink", []*zip.FileHeader{symlink}, 0, 0, "symlink"},
+		{"too many entries", []*zip.FileHeader{{Name: "a.txt"}, {Name: "b.txt"}}, 0, 1, "entries"},
+		{"too large", []*zip.FileHeader{{Name: "a.txt"}, {Name: "b.txt"}}, 15, 0, "larger"},
+	}
+
+	for _, test := range tests {
+		zipPath := createZip(test.headers...)
+		defer os.Remove(zipPath)
+
+		parent, err := ioutil.TempDir("", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(parent)
+		dir := filepath.Join(parent, "target")
+
+		err = UnZip(zipPath, dir, test.maxSize, test.maxFiles)
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%v: expect error with %v, got %v", test.name, test.err, err)
+		}
+		if _, err := os.Stat(filepath.Join(parent, "evil.txt")); !os.IsNotExist(err) {
+			t.Errorf("%v: file is written outside the target dir", test.name)
+		}
+	}
+
+	// files within the limits, and entries without dir entries
+	zipPath := createZip(&zip.FileHeader{Name: "a/b.txt"}, &zip.FileHeader{Name: "c.txt"})
+	defer os.Remove(zipPath)
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := UnZip(zipPath, dir, 20, 2); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, "a", "b.txt"))
+	if err != nil || string(data) != "0123456789" {
+		t.Errorf("expect 0123456789, got %v, %v", string(data), err)
+	}
+}
@@ -47,7 +47,9 @@ import (
 	// Register `github.com/tengge1/shadoweditor/server/tools`
 	_ "github.com/tengge1/shadoweditor/server/tools/backup_database" // backup_database api
 	_ "github.com/tengge1/shadoweditor/server/tools/clean_scenes"    // clean_scenes api
+	_ "github.com/tengge1/shadoweditor/server/tools/clean_uploads"   // clean_uploads api
 	_ "github.com/tengge1/shadoweditor/server/tools/plugin"          // plugin api
+	_ "github.com/tengge1/shadoweditor/server/tools/prune_history"   // prune_history api
 	_ "github.com/tengge1/shadoweditor/server/tools/typeface"        // typeface api
 
 	// Register `github.com/tengge1/shadoweditor/server/upload`
@@ -0,0 +1,495 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/tengge1/shadoweditor/helper"
+)
+
+// AssetCollectionNames are the collections of uploaded files that scenes may reference.
+// Each asset is stored in its own `SavePath` dir under the public dir.
+var AssetCollectionNames = []string{
+	MeshCollectionName,
+	MapCollectionName,
+	AudioCollectionName,
+	AnimationCollectionName,
+	VideoCollectionName,
+	FileCollectionName,
+}
+
+// usageCollectionNames are the collections whose `Data` may reference assets.
+var usageCollectionNames = []string{
+	PrefabCollectionName,
+	MaterialCollectionName,
+	CharacterCollectionName,
+	ParticleCollectionName,
+}
+
+// AssetUsageModel is a scene, prefab, material or character that references an asset.
+type AssetUsageModel struct {
+	// The collection, such as `_Scene` and `_Prefab`
+	CollectionName string
+	// ID
+	ID string
+	// Name
+	Name string
+	// Old versions of scenes and branches that reference the asset
+	Versions []int `json:",omitempty"`
+}
+
+// materialMaps are the texture properties of three.js materials.
+var materialMaps = []string{
+	"alphaMap",
+	"aoMap",
+	"bumpMap",
+	"displacementMap",
+	"emissiveMap",
+	"envMap",
+	"lightMap",
+	"map",
+	"metalnessMap",
+	"normalMap",
+	"roughnessMap",
+}
+
+// SceneURLs returns the urls of files referenced by scene docs, such as textures,
+// server models, MMD animations and audios. Base64 urls are ignored.
+func SceneURLs(docs []bson.M) []string {
+	urls := []string{}
+
+	for _, doc := range docs {
+		metadata, _ := toMap(doc["metadata"])
+		generator, _ := metadata["generator"].(string)
+
+		switch generator {
+		case "ServerObject": // server model
+			userData, _ := toMap(doc["userData"])
+			urls = append(urls, urlOf(userData)) // model files
+
+			if val, ok := toMap(userData["Animation"]); ok { // MMD animation
+				urls = append(urls, urlOf(val))
+			}
+			if val, ok := toMap(userData["CameraAnimation"]); ok { // MMD camara animation
+				urls = append(urls, urlOf(val))
+			}
+			if val, ok := toMap(userData["Audio"]); ok { // MMD audio
+				urls = append(urls, urlOf(val))
+			}
+		case "SceneSerializer": // scene
+			if background, ok := toMap(doc["background"]); ok { // texture or cube texture
+				urls = append(urls, textureURLs(background)...)
+			}
+		case "MeshSerializer", "SpriteSerializer": // mesh
+			if materials, ok := toArray(doc["material"]); ok {
+				for _, material := range materials {
+					if val, ok := toMap(material); ok {
+						urls = append(urls, MaterialURLs(val)...)
+					}
+				}
+			} else if material, ok := toMap(doc["material"]); ok {
+				urls = append(urls, MaterialURLs(material)...)
+			}
+		case "AudioSerializer":
+			userData, _ := toMap(doc["userData"])
+			urls = append(urls, urlOf(userData))
+		}
+	}
+
+	return splitURLs(urls)
+}
+
+// MaterialURLs returns the urls of textures referenced by a material, including
+// textures in shader uniforms.
+func MaterialURLs(material bson.M) []string {
+	textures := []bson.M{}
+	for _, name := range materialMaps {
+		if val, ok := toMap(material[name]); ok {
+			textures = append(textures, val)
+		}
+	}
+	// texture in uniforms
+	if uniforms, ok := toMap(material["uniforms"]); ok {
+		for _, val := range uniforms {
+			uniform, ok := toMap(val)
+			if !ok || uniform["type"] != "t" {
+				continue
+			}
+			if texture, ok := toMap(uniform["value"]); ok {
+				textures = append(textures, texture)
+			}
+		}
+	}
+
+	urls := []string{}
+	for _, texture := range textures {
+		urls = append(urls, textureURLs(texture)...)
+	}
+	return splitURLs(urls)
+}
+
+// AssetURLs returns the urls of files referenced by an asset, whose `Url` is a string
+// or a cube texture doc.
+func AssetURLs(asset bson.M) []string {
+	if url, ok := asset["Url"].(string); ok {
+		return splitURLs([]string{url})
+	}
+	urls := []string{}
+	if cube, ok := toMap(asset["Url"]); ok {
+		for _, val := range cube {
+			if url, ok := val.(string); ok {
+				urls = append(urls, url)
+			}
+		}
+	}
+	return splitURLs(urls)
+}
+
+// FindAsset returns the collection name and the doc of the asset that a url belongs
+// to. Assets are the docs of `AssetCollectionNames`.
+func FindAsset(assets map[string][]bson.M, url string) (string, bson.M, bool) {
+	for _, collectionName := range AssetCollectionNames {
+		for _, asset := range assets[collectionName] {
+			savePath, _ := asset["SavePath"].(string)
+			if savePath != "" && strings.HasPrefix(url, strings.TrimSuffix(savePath, "/")+"/") {
+				return collectionName, asset, true
+			}
+		}
+	}
+	return "", nil, false
+}
+
+// LoadAssets returns all the docs of `AssetCollectionNames`, grouped by collection name.
+func LoadAssets(db Storage) (map[string][]bson.M, error) {
+	assets := map[string][]bson.M{}
+	for _, collectionName := range AssetCollectionNames {
+		docs := []bson.M{}
+		if err := db.FindAll(collectionName, &docs); err != nil {
+			return nil, err
+		}
+		assets[collectionName] = docs
+	}
+	return assets, nil
+}
+
+// DataURLs returns the urls referenced by the `Data` of prefabs, materials and characters.
+// Data is a json string or a decoded doc. Arrays are treated as scene docs, and objects
+// are treated as a scene doc or a material.
+func DataURLs(data interface{}) []string {
+	if str, ok := data.(string); ok {
+		var obj interface{}
+		if err := json.Unmarshal([]byte(str), &obj); err != nil {
+			return []string{}
+		}
+		data = obj
+	}
+
+	if array, ok := toArray(data); ok {
+		docs := []bson.M{}
+		for _, item := range array {
+			if doc, ok := toMap(item); ok {
+				docs = append(docs, doc)
+			}
+		}
+		return SceneURLs(docs)
+	}
+	if doc, ok := toMap(data); ok {
+		return splitURLs(append(SceneURLs([]bson.M{doc}), MaterialURLs(doc)...))
+	}
+	return []string{}
+}
+
+// WhereUsed returns the scenes, prefabs, materials and characters that reference
+// files in the `SavePath` dir of an asset. Old versions of scenes and branches, tags
+// and published snapshots are checked too, because restoring, merging and share links
+// need their files.
+func WhereUsed(db Storage, savePath string) ([]AssetUsageModel, error) {
+	list := []AssetUsageModel{}
+	if strings.TrimSuffix(savePath, "/") == "" {
+		return list, nil
+	}
+
+	prefix := strings.TrimSuffix(savePath, "/") + "/"
+	uses := func(urls []string) bool {
+		for _, url := range urls {
+			if strings.HasPrefix(url, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+	// usedVersions returns whether the latest data of a scene or a branch uses the
+	// asset, and the versions in its history that use it.
+	usedVersions := func(collectionName string) (bool, []int, error) {
+		docs := []bson.M{}
+		if err := db.FindAll(collectionName, &docs); err != nil {
+			return false, nil, err
+		}
+		history := []bson.M{}
+		if err := db.FindAll(collectionName+HistorySuffix, &history); err != nil {
+			return false, nil, err
+		}
+		versions := map[int][]bson.M{}
+		for _, doc := range history {
+			version := int(toNumber(doc[VersionField], -1))
+			versions[version] = append(versions[version], doc)
+		}
+		list := []int{}
+		for version, docs := range versions {
+			if uses(SceneURLs(docs)) {
+				list = append(list, version)
+			}
+		}
+		sort.Ints(list)
+		return uses(SceneURLs(docs)), list, nil
+	}
+
+	scenes := []bson.M{}
+	if err := db.FindAll(SceneCollectionName, &scenes); err != nil {
+		return nil, err
+	}
+	for _, scene := range scenes {
+		collectionName, _ := scene["CollectionName"].(string)
+		if collectionName == "" {
+			continue
+		}
+		used, versions, err := usedVersions(collectionName)
+		if err != nil {
+			return nil, err
+		}
+		if !used && len(versions) == 0 {
+			continue
+		}
+		usage := newAssetUsage(SceneCollectionName, scene)
+		usage.Versions = versions
+		list = append(list, usage)
+
+		// tags of the versions
+		current := int(toNumber(scene["Version"], 0))
+		tags := []bson.M{}
+		if err := db.FindMany(SceneTagCollectionName, bson.M{"SceneID": scene["ID"]}, &tags); err != nil {
+			return nil, err
+		}
+		for _, tag := range tags {
+			version := int(toNumber(tag["Version"], -1))
+			if (used && version == current) || containsInt(versions, version) {
+				list = append(list, newAssetUsage(SceneTagCollectionName, tag))
+			}
+		}
+	}
+
+	branches := []bson.M{}
+	if err := db.FindAll(SceneBranchCollectionName, &branches); err != nil {
+		return nil, err
+	}
+	for _, branch := range branches {
+		collectionName, _ := branch["CollectionName"].(string)
+		if collectionName == "" {
+			continue
+		}
+		used, versions, err := usedVersions(collectionName)
+		if err != nil {
+			return nil, err
+		}
+		if used || len(versions) > 0 {
+			usage := newAssetUsage(SceneBranchCollectionName, branch)
+			usage.Versions = versions
+			list = append(list, usage)
+		}
+	}
+
+	// published snapshots have no name, and they are named by their tokens.
+	publishes := []bson.M{}
+	if err := db.FindAll(ScenePublishCollectionName, &publishes); err != nil {
+		return nil, err
+	}
+	for _, publish := range publishes {
+		collectionName, _ := publish["CollectionName"].(string)
+		if collectionName == "" {
+			continue
+		}
+		docs := []bson.M{}
+		if err := db.FindAll(collectionName, &docs); err != nil {
+			return nil, err
+		}
+		if uses(SceneURLs(docs)) {
+			usage := newAssetUsage(ScenePublishCollectionName, publish)
+			usage.Name, _ = publish["Token"].(string)
+			list = append(list, usage)
+		}
+	}
+
+	for _, collectionName := range usageCollectionNames {
+		docs := []bson.M{}
+		if err := db.FindAll(collectionName, &docs); err != nil {
+			return nil, err
+		}
+		for _, doc := range docs {
+			if uses(DataURLs(doc["Data"])) {
+				list = append(list, newAssetUsage(collectionName, doc))
+			}
+		}
+	}
+
+	return list, nil
+}
+
+// HandleWhereUsed writes the usages of an asset, see WhereUsed. The asset is found by
+// `ID` in the form, and idField is the field that the asset is saved with, such as `ID`.
+func HandleWhereUsed(w http.ResponseWriter, r *http.Request, collectionName, idField string) {
+	r.ParseForm()
+	id, err := primitive.ObjectIDFromHex(strings.TrimSpace(r.FormValue("ID")))
+	if err != nil {
+		helper.WriteJSON(w, Result{
+			Code: 300,
+			Msg:  "ID is not allowed.",
+		})
+		return
+	}
+
+	db, err := DB()
+	if err != nil {
+		helper.WriteJSON(w, Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	filter := bson.M{
+		idField: id,
+	}
+
+	doc := bson.M{}
+	find, _ := db.FindOne(collectionName, filter, &doc)
+
+	if !find {
+		helper.WriteJSON(w, Result{
+			Code: 300,
+			Msg:  "The asset is not existed!",
+		})
+		return
+	}
+
+	savePath, _ := doc["SavePath"].(string)
+	list, err := WhereUsed(db, savePath)
+	if err != nil {
+		helper.WriteJSON(w, Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	helper.WriteJSON(w, Result{
+		Code: 200,
+		Msg:  "Get Successfully!",
+		Data: list,
+	})
+}
+
+func newAssetUsage(collectionName string, doc bson.M) AssetUsageModel {
+	usage := AssetUsageModel{
+		CollectionName: collectionName,
+	}
+	if id, ok := doc["ID"].(primitive.ObjectID); ok {
+		usage.ID = id.Hex()
+	} else if id, ok := doc["_id"].(primitive.ObjectID); ok {
+		usage.ID = id.Hex()
+	}
+	usage.Name, _ = doc["Name"].(string)
+	return usage
+}
+
+// textureURLs returns the image urls of a texture or a cube texture.
+func textureURLs(texture bson.M) []string {
+	urls := []string{}
+	if images, ok := toArray(texture["image"]); ok { // cube texture
+		for _, val := range images {
+			if image, ok := toMap(val); ok {
+				urls = append(urls, srcOf(image))
+			}
+		}
+	} else if image, ok := toMap(texture["image"]); ok {
+		urls = append(urls, srcOf(image))
+	}
+	return urls
+}
+
+// splitURLs splits urls joined by `;` (LOL has multiple urls), and removes base64 urls
+// and duplicates.
+func splitURLs(urls []string) []string {
+	result := []string{}
+	exists := map[string]bool{}
+	for _, url := range urls {
+		for _, item := range strings.Split(url, ";") {
+			if !strings.HasPrefix(item, "/") || exists[item] {
+				continue
+			}
+			exists[item] = true
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func urlOf(doc bson.M) string {
+	url, _ := doc["Url"].(string)
+	return url
+}
+
+func srcOf(doc bson.M) string {
+	src, _ := doc["src"].(string)
+	return src
+}
+
+// toMap converts a nested doc decoded from bson to bson.M.
+func toMap(value interface{}) (bson.M, bool) {
+	switch val := value.(type) {
+	case bson.M:
+		return val, true
+	case primitive.D:
+		return val.Map(), true
+	case map[string]interface{}:
+		return val, true
+	}
+	return nil, false
+}
+
+// toArray converts a nested array decoded from bson to bson.A.
+func toArray(value interface{}) (bson.A, bool) {
+	switch val := value.(type) {
+	case bson.A:
+		return val, true
+	case []interface{}:
+		return val, true
+	case []string:
+		list := bson.A{}
+		for _, i := range val {
+			list = append(list, i)
+		}
+		return list, true
+	}
+	return nil, false
+}
+
+func containsInt(list []int, value int) bool {
+	for _, i := range list {
+		if i == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package server
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSceneURLs(t *testing.T) {
+	// decode into bson.A, so nested docs are primitive.D.
+	docs := bson.A{}
+	err := bson.UnmarshalExtJSON([]byte(`{"docs":[
+		{"metadata":{"generator":"SceneSerializer"},"background":{"metadata":{"generator":"CubeTextureSerializer"},
+			"image":[{"src":"/Upload/Texture/1/px.png"},{"src":"/Upload/Texture/1/nx.png"}]}},
+		{"metadata":{"generator":"ServerObject"},"userData":{"Url":"/Upload/Model/1/a.lol;/Upload/Model/1/b.lol","Audio":{"Url":"/Upload/Audio/1/a.mp3"}}},
+		{"metadata":{"generator":"MeshSerializer"},"material":{"map":{"image":{"src":"data:image/png;base64,AAAA"}},
+			"uniforms":{"tex":{"type":"t","value":{"image":{"src":"/Upload/Texture/2/a.png"}}}}}},
+		{"metadata":{"generator":"SpriteSerializer"},"material":{"map":{"image":{"src":"/Upload/Texture/2/a.png"}}}}
+	]}`), false, &struct{ Docs *bson.A }{&docs})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list := []bson.M{}
+	for _, doc := range docs {
+		val, _ := toMap(doc)
+		list = append(list, val)
+	}
+
+	expected := []string{
+		"/Upload/Texture/1/px.png",
+		"/Upload/Texture/1/nx.png",
+		"/Upload/Model/1/a.lol",
+		"/Upload/Model/1/b.lol",
+		"/Upload/Audio/1/a.mp3",
+		"/Upload/Texture/2/a.png",
+	}
+	if urls := SceneURLs(list); !reflect.DeepEqual(urls, expected) {
+		t.Errorf("expect %v, got %v", expected, urls)
+	}
+}
+
+func TestFindAsset(t *testing.T) {
+	assets := map[string][]bson.M{
+		MeshCollectionName: {{"Name": "lol", "SavePath": "/Upload/Model/1"}},
+		MapCollectionName:  {{"Name": "map", "SavePath": "/Upload/Texture/1"}},
+	}
+	if name, asset, ok := FindAsset(assets, "/Upload/Model/1/sub/a.lol"); !ok || name != MeshCollectionName || asset["Name"] != "lol" {
+		t.Errorf("expect lol, got %v %v", name, asset)
+	}
+	if _, _, ok := FindAsset(assets, "/Upload/Texture/10/a.png"); ok {
+		t.Errorf("expect no asset")
+	}
+}
+
+func TestWhereUsed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := CreateEmbedded(dir); err != nil {
+		t.Fatal(err)
+	}
+	db, err := DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mesh := bson.M{"metadata": bson.M{"generator": "ServerObject"}, "userData": bson.M{"Url": "/Upload/Model/1/a.obj"}}
+	other := bson.M{"metadata": bson.M{"generator": "ServerObject"}, "userData": bson.M{"Url": "/Upload/Model/2/b.obj"}}
+
+	// the latest version of the scene no longer uses the model, but version 1 and its tag do.
+	sceneID := primitive.NewObjectID()
+	db.InsertOne(SceneCollectionName, bson.M{"ID": sceneID, "Name": "Scene1", "CollectionName": "Scene1", "Version": 2})
+	db.InsertOne("Scene1", other)
+	db.InsertOne("Scene1"+HistorySuffix, bson.M{"userData": mesh["userData"], "metadata": mesh["metadata"], VersionField: 1})
+	db.InsertOne(SceneTagCollectionName, bson.M{"ID": primitive.NewObjectID(), "SceneID": sceneID, "Name": "v1", "Version": 1})
+	db.InsertOne(SceneTagCollectionName, bson.M{"ID": primitive.NewObjectID(), "SceneID": sceneID, "Name": "v2", "Version": 2})
+
+	db.InsertOne(SceneBranchCollectionName, bson.M{"ID": primitive.NewObjectID(), "Name": "Branch1", "CollectionName": "Branch1"})
+	db.InsertOne("Branch1", mesh)
+	db.InsertOne(ScenePublishCollectionName, bson.M{"ID": primitive.NewObjectID(), "Token": "token1", "CollectionName": "Publishtoken1"})
+	db.InsertOne("Publishtoken1", mesh)
+
+	list, err := WhereUsed(db, "/Upload/Model/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []AssetUsageModel{
+		{CollectionName: SceneCollectionName, Name: "Scene1", Versions: []int{1}},
+		{CollectionName: SceneTagCollectionName, Name: "v1"},
+		{CollectionName: SceneBranchCollectionName, Name: "Branch1", Versions: []int{}},
+		{CollectionName: ScenePublishCollectionName, Name: "token1"},
+	}
+	for i := range list {
+		list[i].ID = ""
+	}
+	if !reflect.DeepEqual(list, expected) {
+		t.Errorf("expect %v, got %v", expected, list)
+	}
+
+	// assets without a save path are used by nothing
+	if list, err := WhereUsed(db, ""); err != nil || len(list) != 0 {
+		t.Errorf("expect no usages, got %v %v", list, err)
+	}
+}
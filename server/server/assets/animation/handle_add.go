@@ -30,11 +30,18 @@ func init() {
 
 // Add upload an animation.
 func Add(w http.ResponseWriter, r *http.Request) {
-	r.ParseMultipartForm(server.Config.Upload.MaxSize)
-	files := r.MultipartForm.File
+	// files are in the form, or are completed chunked uploads.
+	files, err := server.FormFiles(r)
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
 
 	// check upload file
-	if len(files) != 1 {
+	if len(files) != 1 || files["file"] == nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
 			Msg:  "Please select an file.",
@@ -42,10 +49,10 @@ func Add(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	file := files["file"][0]
+	file := files["file"]
 	fileName := file.Filename
 	fileSize := file.Size
-	fileType := file.Header.Get("Content-Type")
+	fileType := file.ContentType
 	fileExt := filepath.Ext(fileName)
 	fileNameWithoutExt := strings.TrimRight(fileName, fileExt)
 
@@ -91,8 +98,17 @@ func Add(w http.ResponseWriter, r *http.Request) {
 	defer source.Close()
 
 	io.Copy(target, source)
+	target.Close()
 
-	helper.UnZip(targetPath, physicalPath)
+	upload := server.Config.Upload
+	if err := helper.UnZip(targetPath, physicalPath, upload.MaxUnzipSize, upload.MaxUnzipFiles); err != nil {
+		os.RemoveAll(physicalPath)
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  fmt.Sprintf("Invalid zip file: %v", err),
+		})
+		return
+	}
 
 	os.RemoveAll(tempPath)
 
@@ -129,7 +145,7 @@ func Add(w http.ResponseWriter, r *http.Request) {
 	// save to mongo
 	pinyin := helper.ConvertToPinYin(fileNameWithoutExt)
 
-	db, err := server.Mongo()
+	db, err := server.DB()
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
@@ -162,7 +178,18 @@ func Add(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	db.InsertOne(server.AnimationCollectionName, doc)
+	if _, err := db.InsertOne(server.AnimationCollectionName, doc); err != nil {
+		server.RemoveAssetFiles(db, doc)
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	// the chunked uploads are kept until the asset is stored, so that a failed add
+	// does not need to upload the files again.
+	server.RemoveUploads(files)
 
 	helper.WriteJSON(w, server.Result{
 		Code: 200,
@@ -12,6 +12,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
 	"testing"
 	"time"
 
@@ -19,8 +20,22 @@ import (
 	"github.com/tengge1/shadoweditor/server"
 )
 
+// testDir is the directory that tests store data in, so that tests need no mongo.
+var testDir string
+
+func TestMain(m *testing.M) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		panic(err)
+	}
+	testDir = dir
+	code := m.Run()
+	os.RemoveAll(testDir)
+	os.Exit(code)
+}
+
 func TestAnimationList(t *testing.T) {
-	server.Create("../config.toml")
+	server.CreateEmbedded(testDir)
 
 	ts := httptest.NewServer(http.HandlerFunc(List))
 	defer ts.Close()
@@ -42,7 +57,7 @@ func TestAnimationList(t *testing.T) {
 }
 
 func TestAnimationAdd(t *testing.T) {
-	server.Create("../config.toml")
+	server.CreateEmbedded(testDir)
 
 	ts := httptest.NewServer(http.HandlerFunc(Add))
 	defer ts.Close()
@@ -69,7 +84,7 @@ func TestAnimationAdd(t *testing.T) {
 }
 
 func TestAnimationEdit(t *testing.T) {
-	server.Create("../config.toml")
+	server.CreateEmbedded(testDir)
 
 	ts := httptest.NewServer(http.HandlerFunc(Edit))
 	defer ts.Close()
@@ -97,7 +112,7 @@ func TestAnimationEdit(t *testing.T) {
 }
 
 func TestAnimationDelete(t *testing.T) {
-	server.Create("../config.toml")
+	server.CreateEmbedded(testDir)
 
 	ts := httptest.NewServer(http.HandlerFunc(Delete))
 	defer ts.Close()
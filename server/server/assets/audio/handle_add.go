@@ -8,10 +8,7 @@
 package audio
 
 import (
-	"fmt"
-	"io"
 	"net/http"
-	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -29,11 +26,18 @@ func init() {
 
 // Add upload an audio.
 func Add(w http.ResponseWriter, r *http.Request) {
-	r.ParseMultipartForm(server.Config.Upload.MaxSize)
-	files := r.MultipartForm.File
+	// files are in the form, or are completed chunked uploads.
+	files, err := server.FormFiles(r)
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
 
 	// check upload file
-	if len(files) != 1 {
+	if len(files) != 1 || files["file"] == nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
 			Msg:  "Please select an file.",
@@ -41,10 +45,10 @@ func Add(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	file := files["file"][0]
+	file := files["file"]
 	fileName := file.Filename
 	fileSize := file.Size
-	fileType := file.Header.Get("Content-Type")
+	fileType := file.ContentType
 	fileExt := filepath.Ext(fileName)
 	fileNameWithoutExt := strings.TrimRight(fileName, fileExt)
 
@@ -56,18 +60,7 @@ func Add(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// save file
-	now := time.Now()
-
-	savePath := fmt.Sprintf("/Upload/Audio/%v", helper.TimeToString(now, "yyyyMMddHHmmss"))
-	physicalPath := server.MapPath(savePath)
-
-	if _, err := os.Stat(physicalPath); os.IsNotExist(err) {
-		os.MkdirAll(physicalPath, 0755)
-	}
-
-	targetPath := fmt.Sprintf("%v/%v", physicalPath, fileName)
-	target, err := os.Create(targetPath)
+	db, err := server.DB()
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
@@ -75,7 +68,9 @@ func Add(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
-	defer target.Close()
+
+	// save file, and files with the same content share storage.
+	now := time.Now()
 
 	source, err := file.Open()
 	if err != nil {
@@ -87,12 +82,7 @@ func Add(w http.ResponseWriter, r *http.Request) {
 	}
 	defer source.Close()
 
-	io.Copy(target, source)
-
-	// save to mongo
-	pinyin := helper.ConvertToPinYin(fileNameWithoutExt)
-
-	db, err := server.Mongo()
+	blob, err := server.AddBlob(db, source, fileName, nil)
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
@@ -101,7 +91,10 @@ func Add(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	url := fmt.Sprintf("%v/%v", savePath, fileName)
+	// save to mongo
+	pinyin := helper.ConvertToPinYin(fileNameWithoutExt)
+
+	url := blob.URL()
 
 	doc := bson.M{
 		"ID":          primitive.NewObjectID(),
@@ -112,7 +105,8 @@ func Add(w http.ResponseWriter, r *http.Request) {
 		"FirstPinYin": pinyin.FirstPinYin,
 		"Name":        fileNameWithoutExt,
 		"SaveName":    fileName,
-		"SavePath":    savePath,
+		"SavePath":    blob.SavePath,
+		"Blobs":       []string{blob.Hash},
 		"TotalPinYin": pinyin.TotalPinYin,
 		"Type":        Unknown,
 		"Url":         url,
@@ -129,7 +123,18 @@ func Add(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	db.InsertOne(server.AudioCollectionName, doc)
+	if _, err := db.InsertOne(server.AudioCollectionName, doc); err != nil {
+		server.RemoveAssetFiles(db, doc)
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	// the chunked uploads are kept until the asset is stored, so that a failed add
+	// does not need to upload the files again.
+	server.RemoveUploads(files)
 
 	helper.WriteJSON(w, server.Result{
 		Code: 200,
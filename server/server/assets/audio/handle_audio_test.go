@@ -11,13 +11,28 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"testing"
 
 	"github.com/tengge1/shadoweditor/server"
 )
 
+// testDir is the directory that tests store data in, so that tests need no mongo.
+var testDir string
+
+func TestMain(m *testing.M) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		panic(err)
+	}
+	testDir = dir
+	code := m.Run()
+	os.RemoveAll(testDir)
+	os.Exit(code)
+}
+
 func TestAudioList(t *testing.T) {
-	server.Create("../config.toml")
+	server.CreateEmbedded(testDir)
 
 	ts := httptest.NewServer(http.HandlerFunc(List))
 	defer ts.Close()
@@ -11,13 +11,34 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
 	"testing"
 
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
 	"github.com/tengge1/shadoweditor/server"
+	"github.com/tengge1/shadoweditor/server/thumbnail"
 )
 
+// testDir is the directory that tests store data in, so that tests need no mongo.
+var testDir string
+
+func TestMain(m *testing.M) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		panic(err)
+	}
+	testDir = dir
+	code := m.Run()
+	os.RemoveAll(testDir)
+	os.Exit(code)
+}
+
 func TestCharacterList(t *testing.T) {
-	server.Create("../config.toml")
+	server.CreateEmbedded(testDir)
 
 	ts := httptest.NewServer(http.HandlerFunc(List))
 	defer ts.Close()
@@ -37,3 +58,26 @@ func TestCharacterList(t *testing.T) {
 
 	t.Log(string(bytes))
 }
+
+func TestCharacterThumbnail(t *testing.T) {
+	server.CreateEmbedded(testDir)
+
+	id := primitive.NewObjectID()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(url.Values{
+		"ID":   {id.Hex()},
+		"Name": {"TestCharacterThumbnail"},
+		"Data": {`{"metadata":{"generator":"MeshSerializer"},"uuid":"1",` +
+			`"geometry":{"type":"BoxBufferGeometry","parameters":{"width":1,"height":1,"depth":1}}}`},
+	}.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	Save(httptest.NewRecorder(), req)
+
+	thumbnail.Wait()
+
+	db, _ := server.DB()
+	doc := bson.M{}
+	db.FindOne(server.CharacterCollectionName, bson.M{"ID": id}, &doc)
+	if url, _ := doc["Thumbnail"].(string); !thumbnail.IsRendered(url) {
+		t.Errorf("expect a rendered thumbnail, got %v", doc["Thumbnail"])
+	}
+}
@@ -33,7 +33,7 @@ func Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	db, err := server.Mongo()
+	db, err := server.DB()
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
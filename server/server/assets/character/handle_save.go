@@ -17,6 +17,8 @@ import (
 
 	"github.com/tengge1/shadoweditor/helper"
 	"github.com/tengge1/shadoweditor/server"
+	"github.com/tengge1/shadoweditor/server/assets/scene"
+	"github.com/tengge1/shadoweditor/server/thumbnail"
 )
 
 func init() {
@@ -42,7 +44,7 @@ func Save(w http.ResponseWriter, r *http.Request) {
 
 	data := strings.TrimSpace(r.FormValue("Data"))
 
-	db, err := server.Mongo()
+	db, err := server.DB()
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
@@ -91,6 +93,10 @@ func Save(w http.ResponseWriter, r *http.Request) {
 		db.UpdateOne(server.CharacterCollectionName, filter, update)
 	}
 
+	thumbnail.Update(server.CharacterCollectionName, id, func() ([]thumbnail.Mesh, error) {
+		return scene.DataMeshes(db, server.CharacterCollectionName, id)
+	})
+
 	helper.WriteJSON(w, server.Result{
 		Code: 200,
 		Msg:  "Saved successfully!",
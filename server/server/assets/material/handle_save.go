@@ -44,7 +44,7 @@ func Save(w http.ResponseWriter, r *http.Request) {
 	data := strings.TrimSpace(r.FormValue("Data"))
 	thumbnail := strings.TrimSpace(r.FormValue("Thumbnail"))
 
-	db, err := server.Mongo()
+	db, err := server.DB()
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
@@ -0,0 +1,167 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package mesh
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/tengge1/shadoweditor/three"
+)
+
+// Geometry is the triangles of a mesh file. Node transforms of the file are
+// applied to the positions.
+type Geometry struct {
+	// Vertex positions
+	Positions []three.Vector3
+	// Triangle vertex indices into positions, three for each triangle
+	Indices []int
+	// Material count
+	Materials int
+	// Texture count
+	Textures int
+}
+
+// Info is the metadata of a mesh file.
+type Info struct {
+	// Vertex count
+	VertexCount int
+	// Triangle count
+	TriangleCount int
+	// Material count
+	MaterialCount int
+	// Texture count
+	TextureCount int
+	// Min point of the axis-aligned bounds, nil when the mesh has no vertices
+	BoundsMin []float64
+	// Max point of the axis-aligned bounds, nil when the mesh has no vertices
+	BoundsMax []float64
+}
+
+// CanReadGeometry returns whether ReadGeometry supports a mesh type.
+func CanReadGeometry(meshType Type) bool {
+	switch meshType {
+	case Obj, Stl, Ply, Gltf, Glb:
+		return true
+	}
+	return false
+}
+
+// ReadGeometry reads an obj, stl, ply, gltf or glb file. Files that the mesh file
+// references, such as mtl files and gltf buffers, are read from the same directory.
+// Files are read into memory, so a file or gltf buffers larger than maxSize are not
+// read, and 0 means no limit. Callers hold three.Mutex.
+func ReadGeometry(path string, maxSize int64) (*Geometry, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if maxSize > 0 && info.Size() > maxSize {
+		return nil, fmt.Errorf("%v is too large to read", filepath.Base(path))
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Dir(path)
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".obj":
+		return readOBJ(data, dir)
+	case ".stl":
+		return readSTL(data)
+	case ".ply":
+		return readPLY(data)
+	case ".gltf":
+		return readGLTF(data, dir, false, maxSize)
+	case ".glb":
+		return readGLTF(data, dir, true, maxSize)
+	}
+	return nil, fmt.Errorf("%v is not supported", filepath.Ext(path))
+}
+
+// Info returns the counts and bounds of a geometry.
+func (g *Geometry) Info() Info {
+	info := Info{
+		VertexCount:   len(g.Positions),
+		TriangleCount: len(g.Indices) / 3,
+		MaterialCount: g.Materials,
+		TextureCount:  g.Textures,
+	}
+	if len(g.Positions) > 0 {
+		box := three.Box3{}
+		box.SetFromPoints(g.Positions)
+		info.BoundsMin = []float64{box.Min.X, box.Min.Y, box.Min.Z}
+		info.BoundsMax = []float64{box.Max.X, box.Max.Y, box.Max.Z}
+	}
+	return info
+}
+
+// setInfo saves mesh info to a _Mesh doc.
+func setInfo(doc bson.M, info Info) {
+	doc["VertexCount"] = info.VertexCount
+	doc["TriangleCount"] = info.TriangleCount
+	doc["MaterialCount"] = info.MaterialCount
+	doc["TextureCount"] = info.TextureCount
+	if info.BoundsMin != nil {
+		doc["BoundsMin"] = info.BoundsMin
+		doc["BoundsMax"] = info.BoundsMax
+	}
+}
+
+// toInt converts a number of a doc to int, and meshes uploaded before have no counts.
+func toInt(value interface{}) int {
+	switch number := value.(type) {
+	case int32:
+		return int(number)
+	case int64:
+		return int(number)
+	case int:
+		return number
+	case float64:
+		return int(number)
+	}
+	return 0
+}
+
+// toFloats converts an array of a doc to []float64.
+func toFloats(value interface{}) []float64 {
+	array, ok := value.(primitive.A)
+	if !ok {
+		return nil
+	}
+	list := []float64{}
+	for _, item := range array {
+		number, _ := item.(float64)
+		list = append(list, number)
+	}
+	return list
+}
+
+// addPolygon adds a polygon to the geometry as a triangle fan.
+func (g *Geometry) addPolygon(indices []int) {
+	for i := 2; i < len(indices); i++ {
+		g.Indices = append(g.Indices, indices[0], indices[i-1], indices[i])
+	}
+}
+
+// checkIndices returns an error if an index is out of the positions.
+func (g *Geometry) checkIndices() error {
+	for _, index := range g.Indices {
+		if index < 0 || index >= len(g.Positions) {
+			return fmt.Errorf("vertex index %v is out of range", index)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,281 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package mesh
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/tengge1/shadoweditor/helper"
+	"github.com/tengge1/shadoweditor/three"
+)
+
+// gltfComponents is the component count of glTF accessor types.
+var gltfComponents = map[string]int{
+	"SCALAR": 1,
+	"VEC2":   2,
+	"VEC3":   3,
+	"VEC4":   4,
+	"MAT2":   4,
+	"MAT3":   9,
+	"MAT4":   16,
+}
+
+// gltfReader reads the triangles of the meshes of a glTF scene.
+type gltfReader struct {
+	doc      *helper.GLTF
+	buffers  [][]byte
+	geometry *Geometry
+	// visited nodes, a node has only one parent, so that invalid files that reuse
+	// nodes or have cycles do not take exponential time.
+	visited map[int]bool
+}
+
+// readGLTF reads a .gltf or .glb file. External buffers are read from dir, and their
+// total size is limited by maxSize.
+func readGLTF(data []byte, dir string, glb bool, maxSize int64) (*Geometry, error) {
+	doc, buffers, err := helper.ReadGLTF(data, glb, dir, maxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	r := gltfReader{
+		doc:     doc,
+		buffers: buffers,
+		geometry: &Geometry{
+			Materials: len(doc.Materials),
+			Textures:  len(doc.Textures),
+		},
+		visited: map[int]bool{},
+	}
+
+	if len(doc.Scenes) == 0 { // a library of meshes
+		for i := range doc.Meshes {
+			if err := r.addMesh(i, three.NewMatrix4()); err != nil {
+				return nil, err
+			}
+		}
+		return r.geometry, nil
+	}
+
+	scene := 0
+	if doc.Scene != nil {
+		scene = *doc.Scene
+	}
+	if scene < 0 || scene >= len(doc.Scenes) {
+		return nil, fmt.Errorf("scene %v does not exist", scene)
+	}
+	for _, node := range doc.Scenes[scene].Nodes {
+		if err := r.addNode(node, three.NewMatrix4()); err != nil {
+			return nil, err
+		}
+	}
+	return r.geometry, nil
+}
+
+// addNode adds the meshes of a node and its children in world space.
+func (r *gltfReader) addNode(index int, parent *three.Matrix4) error {
+	if index < 0 || index >= len(r.doc.Nodes) {
+		return fmt.Errorf("node %v does not exist", index)
+	}
+	if r.visited[index] {
+		return fmt.Errorf("node %v has more than one parent", index)
+	}
+	r.visited[index] = true
+
+	node := r.doc.Nodes[index]
+	local := three.NewMatrix4()
+	if len(node.Matrix) == 16 {
+		local.FromArray(node.Matrix, 0)
+	} else {
+		position := three.NewVector3(0, 0, 0)
+		quaternion := three.NewQuaternion(0, 0, 0, 1)
+		scale := three.NewVector3(1, 1, 1)
+		if len(node.Translation) == 3 {
+			position.Set(node.Translation[0], node.Translation[1], node.Translation[2])
+		}
+		if len(node.Rotation) == 4 {
+			quaternion.Set(node.Rotation[0], node.Rotation[1], node.Rotation[2], node.Rotation[3])
+		}
+		if len(node.Scale) == 3 {
+			scale.Set(node.Scale[0], node.Scale[1], node.Scale[2])
+		}
+		local.Compose(*position, *quaternion, *scale)
+	}
+	world := three.NewMatrix4().MultiplyMatrices(*parent, *local)
+
+	if node.Mesh != nil {
+		if err := r.addMesh(*node.Mesh, world); err != nil {
+			return err
+		}
+	}
+	for _, child := range node.Children {
+		if err := r.addNode(child, world); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addMesh adds the primitives of a mesh.
+func (r *gltfReader) addMesh(index int, world *three.Matrix4) error {
+	if index < 0 || index >= len(r.doc.Meshes) {
+		return fmt.Errorf("mesh %v does not exist", index)
+	}
+	for i, primitive := range r.doc.Meshes[index].Primitives {
+		position, ok := primitive.Attributes["POSITION"]
+		if !ok {
+			continue
+		}
+		values, components, err := r.readAccessor(position)
+		if err != nil {
+			return fmt.Errorf("mesh %v primitive %v: %v", index, i, err)
+		}
+		if components != 3 {
+			return fmt.Errorf("mesh %v primitive %v: position is not VEC3", index, i)
+		}
+
+		start := len(r.geometry.Positions)
+		count := len(values) / 3
+		for j := 0; j < count; j++ {
+			vertex := three.NewVector3(values[j*3], values[j*3+1], values[j*3+2])
+			vertex.ApplyMatrix4(*world)
+			r.geometry.Positions = append(r.geometry.Positions, *vertex)
+		}
+
+		indices := make([]int, count)
+		for j := range indices {
+			indices[j] = j
+		}
+		if primitive.Indices != nil {
+			values, _, err := r.readAccessor(*primitive.Indices)
+			if err != nil {
+				return fmt.Errorf("mesh %v primitive %v: %v", index, i, err)
+			}
+			indices = make([]int, len(values))
+			for j, value := range values {
+				if value < 0 || int(value) >= count {
+					return fmt.Errorf("mesh %v primitive %v: index %v is out of range", index, i, value)
+				}
+				indices[j] = int(value)
+			}
+		}
+
+		mode := 4 // TRIANGLES
+		if primitive.Mode != nil {
+			mode = *primitive.Mode
+		}
+		r.addTriangles(mode, start, indices)
+	}
+	return nil
+}
+
+// addTriangles adds the triangles of a primitive. Points and lines have no triangles.
+func (r *gltfReader) addTriangles(mode, start int, indices []int) {
+	g := r.geometry
+	switch mode {
+	case 4: // TRIANGLES
+		for i := 0; i+2 < len(indices); i += 3 {
+			g.Indices = append(g.Indices, start+indices[i], start+indices[i+1], start+indices[i+2])
+		}
+	case 5: // TRIANGLE_STRIP
+		for i := 2; i < len(indices); i++ {
+			if i%2 == 0 {
+				g.Indices = append(g.Indices, start+indices[i-2], start+indices[i-1], start+indices[i])
+			} else {
+				g.Indices = append(g.Indices, start+indices[i-1], start+indices[i-2], start+indices[i])
+			}
+		}
+	case 6: // TRIANGLE_FAN
+		for i := 2; i < len(indices); i++ {
+			g.Indices = append(g.Indices, start+indices[0], start+indices[i-1], start+indices[i])
+		}
+	}
+}
+
+// readAccessor returns the values of an accessor, and its component count.
+func (r *gltfReader) readAccessor(index int) ([]float64, int, error) {
+	if index < 0 || index >= len(r.doc.Accessors) {
+		return nil, 0, fmt.Errorf("accessor %v does not exist", index)
+	}
+	accessor := r.doc.Accessors[index]
+	components, ok := gltfComponents[accessor.Type]
+	if !ok {
+		return nil, 0, fmt.Errorf("accessor type %v is not supported", accessor.Type)
+	}
+	size := 0
+	switch accessor.ComponentType {
+	case helper.GLTFByte, helper.GLTFUnsignedByte:
+		size = 1
+	case helper.GLTFShort, helper.GLTFUnsignedShort:
+		size = 2
+	case helper.GLTFUnsignedInt, helper.GLTFFloat:
+		size = 4
+	default:
+		return nil, 0, fmt.Errorf("component type %v is not supported", accessor.ComponentType)
+	}
+	if accessor.Count < 0 {
+		return nil, 0, fmt.Errorf("accessor %v has invalid count", index)
+	}
+	if accessor.BufferView == nil { // sparse accessors
+		return nil, 0, fmt.Errorf("accessor %v has no buffer view", index)
+	}
+	if *accessor.BufferView < 0 || *accessor.BufferView >= len(r.doc.BufferViews) {
+		return nil, 0, fmt.Errorf("buffer view %v does not exist", *accessor.BufferView)
+	}
+	view := r.doc.BufferViews[*accessor.BufferView]
+	if view.Buffer < 0 || view.Buffer >= len(r.buffers) || view.ByteOffset < 0 || view.ByteLength < 0 ||
+		view.ByteOffset+view.ByteLength > len(r.buffers[view.Buffer]) {
+		return nil, 0, fmt.Errorf("buffer view %v is out of buffer", *accessor.BufferView)
+	}
+	data := r.buffers[view.Buffer][view.ByteOffset : view.ByteOffset+view.ByteLength]
+	stride := components * size
+	if view.ByteStride > 0 {
+		stride = view.ByteStride
+	}
+	if accessor.Count > 0 && (accessor.ByteOffset < 0 || accessor.ByteOffset+(accessor.Count-1)*stride+components*size > len(data)) {
+		return nil, 0, fmt.Errorf("accessor %v is out of buffer view", index)
+	}
+
+	values := make([]float64, 0, accessor.Count*components)
+	for i := 0; i < accessor.Count; i++ {
+		for j := 0; j < components; j++ {
+			b := data[accessor.ByteOffset+i*stride+j*size:]
+			var value float64
+			switch accessor.ComponentType {
+			case helper.GLTFByte:
+				value = float64(int8(b[0]))
+				if accessor.Normalized {
+					value = math.Max(value/127, -1)
+				}
+			case helper.GLTFUnsignedByte:
+				value = float64(b[0])
+				if accessor.Normalized {
+					value /= 255
+				}
+			case helper.GLTFShort:
+				value = float64(int16(binary.LittleEndian.Uint16(b)))
+				if accessor.Normalized {
+					value = math.Max(value/32767, -1)
+				}
+			case helper.GLTFUnsignedShort:
+				value = float64(binary.LittleEndian.Uint16(b))
+				if accessor.Normalized {
+					value /= 65535
+				}
+			case helper.GLTFUnsignedInt:
+				value = float64(binary.LittleEndian.Uint32(b))
+			case helper.GLTFFloat:
+				value = float64(math.Float32frombits(binary.LittleEndian.Uint32(b)))
+			}
+			values = append(values, value)
+		}
+	}
+	return values, components, nil
+}
@@ -9,10 +9,8 @@ package mesh
 
 import (
 	"fmt"
-	"io"
 	"io/ioutil"
 	"net/http"
-	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -22,6 +20,8 @@ import (
 
 	"github.com/tengge1/shadoweditor/helper"
 	"github.com/tengge1/shadoweditor/server"
+	"github.com/tengge1/shadoweditor/server/thumbnail"
+	"github.com/tengge1/shadoweditor/three"
 )
 
 func init() {
@@ -30,11 +30,18 @@ func init() {
 
 // Add upload a mesh.
 func Add(w http.ResponseWriter, r *http.Request) {
-	r.ParseMultipartForm(server.Config.Upload.MaxSize)
-	files := r.MultipartForm.File
+	// files are in the form, or are completed chunked uploads.
+	files, err := server.FormFiles(r)
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
 
 	// check upload file
-	if len(files) != 1 {
+	if len(files) != 1 || files["file"] == nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
 			Msg:  "Please select an file.",
@@ -42,10 +49,10 @@ func Add(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	file := files["file"][0]
+	file := files["file"]
 	fileName := file.Filename
 	fileSize := file.Size
-	fileType := file.Header.Get("Content-Type")
+	fileType := file.ContentType
 	fileExt := filepath.Ext(fileName)
 	fileNameWithoutExt := strings.TrimRight(fileName, fileExt)
 
@@ -57,20 +64,7 @@ func Add(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// save file
-	now := time.Now()
-
-	savePath := fmt.Sprintf("/Upload/Model/%v", helper.TimeToString(now, "yyyyMMddHHmmss"))
-	physicalPath := server.MapPath(savePath)
-
-	tempPath := filepath.Join(physicalPath, "temp")
-
-	if _, err := os.Stat(tempPath); os.IsNotExist(err) {
-		os.MkdirAll(tempPath, 0755)
-	}
-
-	targetPath := filepath.Join(tempPath, fileName)
-	target, err := os.Create(targetPath)
+	db, err := server.DB()
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
@@ -78,7 +72,9 @@ func Add(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
-	defer target.Close()
+
+	// save file, and zip files with the same content share storage.
+	now := time.Now()
 
 	source, err := file.Open()
 	if err != nil {
@@ -90,11 +86,23 @@ func Add(w http.ResponseWriter, r *http.Request) {
 	}
 	defer source.Close()
 
-	io.Copy(target, source)
-
-	helper.UnZip(targetPath, physicalPath)
+	upload := server.Config.Upload
+	blob, err := server.AddBlob(db, source, fileName, func(file, dir string) error {
+		if err := helper.UnZip(file, dir, upload.MaxUnzipSize, upload.MaxUnzipFiles); err != nil {
+			return fmt.Errorf("invalid zip file: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
 
-	os.RemoveAll(tempPath)
+	savePath := blob.SavePath
+	physicalPath := server.MapPath(savePath)
 
 	// justify file type
 	entryFileName := ""
@@ -102,6 +110,7 @@ func Add(w http.ResponseWriter, r *http.Request) {
 
 	infos, err := ioutil.ReadDir(physicalPath)
 	if err != nil {
+		server.ReleaseBlob(db, blob.Hash)
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
 			Msg:  err.Error(),
@@ -254,6 +263,7 @@ func Add(w http.ResponseWriter, r *http.Request) {
 				}
 			}
 			if lanim == "" {
+				server.ReleaseBlob(db, blob.Hash)
 				helper.WriteJSON(w, server.Result{
 					Code: 300,
 					Msg:  "lanim file is not uploaded!",
@@ -261,6 +271,7 @@ func Add(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 			if ltexture == "" {
+				server.ReleaseBlob(db, blob.Hash)
 				helper.WriteJSON(w, server.Result{
 					Code: 300,
 					Msg:  "png file is not uploaded!",
@@ -277,7 +288,7 @@ func Add(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if meshType == Unknown {
-		os.RemoveAll(physicalPath)
+		server.ReleaseBlob(db, blob.Hash)
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
 			Msg:  "Unknown file type!",
@@ -288,15 +299,6 @@ func Add(w http.ResponseWriter, r *http.Request) {
 	// save to mongo
 	pinyin := helper.ConvertToPinYin(fileNameWithoutExt)
 
-	db, err := server.Mongo()
-	if err != nil {
-		helper.WriteJSON(w, server.Result{
-			Code: 300,
-			Msg:  err.Error(),
-		})
-		return
-	}
-
 	doc := bson.M{
 		"ID":          primitive.NewObjectID(),
 		"AddTime":     now,
@@ -307,12 +309,26 @@ func Add(w http.ResponseWriter, r *http.Request) {
 		"Name":        fileNameWithoutExt,
 		"SaveName":    fileName,
 		"SavePath":    savePath,
+		"Blobs":       []string{blob.Hash},
 		"Thumbnail":   "",
 		"TotalPinYin": pinyin.TotalPinYin,
 		"Type":        meshType,
 		"Url":         entryFileName,
 	}
 
+	// read the counts and bounds, and models that can not be read are still saved.
+	var geometry *Geometry
+	if CanReadGeometry(meshType) {
+		entryPath := filepath.Join(physicalPath, filepath.Base(entryFileName))
+		three.Mutex.Lock()
+		if geometry, err = ReadGeometry(entryPath, server.Config.Upload.MaxGeometrySize); err != nil {
+			server.Logger.Warnf("read mesh %v: %v", entryFileName, err)
+		} else {
+			setInfo(doc, geometry.Info())
+		}
+		three.Mutex.Unlock()
+	}
+
 	if server.Config.Authority.Enabled {
 		user, _ := server.GetCurrentUser(r)
 
@@ -321,7 +337,28 @@ func Add(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	db.InsertOne(server.MeshCollectionName, doc)
+	if _, err := db.InsertOne(server.MeshCollectionName, doc); err != nil {
+		server.RemoveAssetFiles(db, doc)
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	// the chunked uploads are kept until the asset is stored, so that a failed add
+	// does not need to upload the files again.
+	server.RemoveUploads(files)
+
+	if geometry != nil {
+		thumbnail.Update(server.MeshCollectionName, doc["ID"].(primitive.ObjectID), func() ([]thumbnail.Mesh, error) {
+			return []thumbnail.Mesh{{
+				Positions: geometry.Positions,
+				Indices:   geometry.Indices,
+				Color:     thumbnail.DefaultColor,
+			}}, nil
+		})
+	}
 
 	helper.WriteJSON(w, server.Result{
 		Code: 200,
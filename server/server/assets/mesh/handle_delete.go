@@ -9,7 +9,7 @@ package mesh
 
 import (
 	"net/http"
-	"os"
+	"strconv"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -22,7 +22,7 @@ func init() {
 	server.Handle(http.MethodPost, "/api/Mesh/Delete", Delete, server.DeleteMesh)
 }
 
-// Delete delete a mesh.
+// Delete delete a mesh. A mesh that is still used is not deleted unless Force is true.
 func Delete(w http.ResponseWriter, r *http.Request) {
 	r.ParseForm()
 	id, err := primitive.ObjectIDFromHex(r.FormValue("ID"))
@@ -34,7 +34,7 @@ func Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	db, err := server.Mongo()
+	db, err := server.DB()
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
@@ -58,9 +58,38 @@ func Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	path := doc["SavePath"].(string)
-	physicalPath := server.MapPath(path)
-	os.RemoveAll(physicalPath)
+	// refuse to delete an asset that is still referenced, unless Force is true. Files
+	// that other assets share are kept, so the references do not break.
+	force, _ := strconv.ParseBool(r.FormValue("Force"))
+	shared, err := server.AssetFilesShared(db, doc)
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+	if savePath, _ := doc["SavePath"].(string); !force && !shared && savePath != "" {
+		list, err := server.WhereUsed(db, savePath)
+		if err != nil {
+			helper.WriteJSON(w, server.Result{
+				Code: 300,
+				Msg:  err.Error(),
+			})
+			return
+		}
+		if len(list) > 0 {
+			helper.WriteJSON(w, server.Result{
+				Code: 302,
+				Msg:  "The asset is still used, and Force is required to delete it.",
+				Data: list,
+			})
+			return
+		}
+	}
+
+	// files shared with other assets are kept
+	server.RemoveAssetFiles(db, doc)
 
 	db.DeleteOne(server.MeshCollectionName, filter)
 
@@ -35,7 +35,7 @@ func Download(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	db, err := server.Mongo()
+	db, err := server.DB()
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
@@ -27,7 +27,7 @@ func init() {
 func List(w http.ResponseWriter, r *http.Request) {
 	r.ParseForm()
 
-	db, err := server.Mongo()
+	db, err := server.DB()
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
@@ -109,6 +109,12 @@ func List(w http.ResponseWriter, r *http.Request) {
 			// UpdateTime:   doc["UpdateTime"].(primitive.DateTime).Time(),
 			Thumbnail: thumbnail,
 		}
+		info.VertexCount = toInt(doc["VertexCount"])
+		info.TriangleCount = toInt(doc["TriangleCount"])
+		info.MaterialCount = toInt(doc["MaterialCount"])
+		info.TextureCount = toInt(doc["TextureCount"])
+		info.BoundsMin = toFloats(doc["BoundsMin"])
+		info.BoundsMax = toFloats(doc["BoundsMax"])
 
 		list = append(list, info)
 	}
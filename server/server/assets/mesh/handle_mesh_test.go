@@ -8,16 +8,46 @@
 package mesh
 
 import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
 	"io/ioutil"
+	"math"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/tengge1/shadoweditor/helper"
 	"github.com/tengge1/shadoweditor/server"
+	"github.com/tengge1/shadoweditor/server/thumbnail"
 )
 
+// testDir is the directory that tests store data in, so that tests need no mongo.
+var testDir string
+
+func TestMain(m *testing.M) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		panic(err)
+	}
+	testDir = dir
+	code := m.Run()
+	thumbnail.Wait()
+	os.RemoveAll(testDir)
+	os.Exit(code)
+}
+
 func TestMeshList(t *testing.T) {
-	server.Create("../config.toml")
+	server.CreateEmbedded(testDir)
 
 	ts := httptest.NewServer(http.HandlerFunc(List))
 	defer ts.Close()
@@ -37,3 +67,269 @@ func TestMeshList(t *testing.T) {
 
 	t.Log(string(bytes))
 }
+
+func TestMeshWhereUsed(t *testing.T) {
+	server.CreateEmbedded(testDir)
+	db, err := server.DB()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	id := primitive.NewObjectID()
+	db.InsertOne(server.MeshCollectionName, bson.M{
+		"_id":      id,
+		"ID":       id,
+		"Name":     "TestMeshWhereUsed",
+		"SavePath": "/Upload/Model/20200101000000",
+		"Type":     string(Obj),
+		"Url":      "/Upload/Model/20200101000000/model.obj",
+	})
+	db.InsertOne(server.PrefabCollectionName, bson.M{
+		"ID":   primitive.NewObjectID(),
+		"Name": "TestMeshWhereUsed",
+		"Data": `[{"metadata":{"generator":"ServerObject"},"userData":{"Url":"/Upload/Model/20200101000000/model.obj"}}]`,
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/?ID="+id.Hex(), nil)
+	rec := httptest.NewRecorder()
+	WhereUsed(rec, req)
+
+	result := struct {
+		server.Result
+		Data []server.AssetUsageModel
+	}{}
+	if err := helper.FromJSON(rec.Body.Bytes(), &result); err != nil {
+		t.Error(err)
+		return
+	}
+	if result.Code != 200 || len(result.Data) != 1 || result.Data[0].CollectionName != server.PrefabCollectionName {
+		t.Errorf("expect a prefab, got %v", rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(url.Values{"ID": {id.Hex()}}.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec = httptest.NewRecorder()
+	Delete(rec, req)
+
+	if err := helper.FromJSON(rec.Body.Bytes(), &result); err != nil {
+		t.Error(err)
+		return
+	}
+	if result.Code != 302 {
+		t.Errorf("expect 302, got %v: %v", result.Code, result.Msg)
+	}
+}
+
+func TestMeshAdd(t *testing.T) {
+	server.CreateEmbedded(testDir)
+
+	// a zip of a unit cube and its materials
+	var zipData bytes.Buffer
+	writer := zip.NewWriter(&zipData)
+	files := map[string]string{
+		"cube.obj": "mtllib cube.mtl\n" +
+			"v 0 0 0\nv 1 0 0\nv 1 1 0\nv 0 1 0\nv 0 0 1\nv 1 0 1\nv 1 1 1\nv 0 1 1\n" +
+			"usemtl red\nf 1 2 3 4\nf 5 6 7 8\nf 1 2 6 5\n" +
+			"usemtl blue\nf 2/1 3/2 7/3 6/4\nf 3//1 4//2 8//3 7//4\nf -8 -4 -1 -5\n",
+		"cube.mtl": "newmtl red\nmap_Kd red.png\nnewmtl blue\nmap_Kd -s 1 1 1 blue.png\nbump blue.png\n",
+	}
+	for name, content := range files {
+		file, _ := writer.Create(name)
+		file.Write([]byte(content))
+	}
+	writer.Close()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, _ := form.CreateFormFile("file", "TestMeshAdd.zip")
+	part.Write(zipData.Bytes())
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	rec := httptest.NewRecorder()
+	Add(rec, req)
+
+	result := server.Result{}
+	helper.FromJSON(rec.Body.Bytes(), &result)
+	if result.Code != 200 {
+		t.Errorf("expect 200, got %v", rec.Body.String())
+		return
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	rec = httptest.NewRecorder()
+	List(rec, req)
+
+	// AddTime can not be decoded, so the fields are decoded without it.
+	list := struct {
+		server.Result
+		Data []struct {
+			Name          string
+			Type          string
+			VertexCount   int
+			TriangleCount int
+			MaterialCount int
+			TextureCount  int
+			BoundsMin     []float64
+			BoundsMax     []float64
+		}
+	}{}
+	helper.FromJSON(rec.Body.Bytes(), &list)
+	for _, model := range list.Data {
+		if model.Name != "TestMeshAdd" {
+			continue
+		}
+		if model.Type != string(Obj) || model.VertexCount != 8 || model.TriangleCount != 12 ||
+			model.MaterialCount != 2 || model.TextureCount != 2 {
+			t.Errorf("expect 8 vertices, 12 triangles, 2 materials and 2 textures, got %v", model)
+		}
+		if !reflect.DeepEqual(model.BoundsMin, []float64{0, 0, 0}) || !reflect.DeepEqual(model.BoundsMax, []float64{1, 1, 1}) {
+			t.Errorf("expect bounds of a unit cube, got %v %v", model.BoundsMin, model.BoundsMax)
+		}
+
+		// the thumbnail is rendered in the background
+		thumbnail.Wait()
+		db, _ := server.DB()
+		doc := bson.M{}
+		db.FindOne(server.MeshCollectionName, bson.M{"Name": "TestMeshAdd"}, &doc)
+		if url, _ := doc["Thumbnail"].(string); !strings.HasPrefix(url, "/Upload/Thumbnail/") {
+			t.Errorf("expect a rendered thumbnail, got %v", doc["Thumbnail"])
+		}
+		return
+	}
+	t.Errorf("expect TestMeshAdd in list, got %v", rec.Body.String())
+}
+
+func TestMeshAddUnsafeZip(t *testing.T) {
+	server.CreateEmbedded(testDir)
+
+	var zipData bytes.Buffer
+	writer := zip.NewWriter(&zipData)
+	file, _ := writer.Create("../../evil.obj")
+	file.Write([]byte("v 0 0 0\n"))
+	writer.Close()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, _ := form.CreateFormFile("file", "TestMeshAddUnsafeZip.zip")
+	part.Write(zipData.Bytes())
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	rec := httptest.NewRecorder()
+	Add(rec, req)
+
+	result := server.Result{}
+	helper.FromJSON(rec.Body.Bytes(), &result)
+	if result.Code != 300 || !strings.Contains(result.Msg, "outside") {
+		t.Errorf("expect 300 with path error, got %v", rec.Body.String())
+	}
+	if _, err := os.Stat(server.MapPath("/Upload/evil.obj")); !os.IsNotExist(err) {
+		t.Errorf("expect no file outside the model dir")
+	}
+}
+
+func TestReadGeometry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	// a triangle, translated by the node in the glb file
+	var bin bytes.Buffer
+	for _, value := range []float32{0, 0, 0, 1, 0, 0, 0, 2, 3} {
+		binary.Write(&bin, binary.LittleEndian, value)
+	}
+	zero := 0
+	doc := &helper.GLTF{
+		Asset:  helper.GLTFAsset{Version: "2.0"},
+		Scene:  &zero,
+		Scenes: []helper.GLTFScene{{Nodes: []int{0}}},
+		Nodes:  []helper.GLTFNode{{Mesh: &zero, Translation: []float64{10, 0, 0}}},
+		Meshes: []helper.GLTFMesh{{Primitives: []helper.GLTFPrimitive{{
+			Attributes: map[string]int{"POSITION": 0},
+		}}}},
+		Materials:   []helper.GLTFMaterial{{Name: "default"}},
+		Accessors:   []helper.GLTFAccessor{{BufferView: &zero, ComponentType: helper.GLTFFloat, Count: 3, Type: "VEC3"}},
+		BufferViews: []helper.GLTFBufferView{{ByteLength: bin.Len()}},
+		Buffers:     []helper.GLTFBuffer{{ByteLength: bin.Len()}},
+	}
+	var glb bytes.Buffer
+	if err := helper.WriteGLB(&glb, doc, bin.Bytes()); err != nil {
+		t.Error(err)
+		return
+	}
+
+	// the same triangle in binary stl
+	var stl bytes.Buffer
+	stl.Write(make([]byte, 80))
+	binary.Write(&stl, binary.LittleEndian, uint32(1))
+	for _, value := range []float32{0, 0, 1, 0, 0, 0, 1, 0, 0, 0, 2, 3} {
+		binary.Write(&stl, binary.LittleEndian, math.Float32bits(value))
+	}
+	stl.Write([]byte{0, 0})
+
+	// two triangles in binary ply
+	var ply bytes.Buffer
+	ply.WriteString("ply\nformat binary_little_endian 1.0\ncomment TextureFile a.png\n" +
+		"element vertex 4\nproperty float x\nproperty float y\nproperty float z\n" +
+		"element face 1\nproperty list uchar int vertex_indices\nend_header\n")
+	for _, value := range []float32{0, 0, 0, 1, 0, 0, 1, 2, 0, 0, 2, 3} {
+		binary.Write(&ply, binary.LittleEndian, value)
+	}
+	ply.WriteByte(4)
+	for _, index := range []int32{0, 1, 2, 3} {
+		binary.Write(&ply, binary.LittleEndian, index)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		info Info
+	}{
+		{"a.glb", glb.Bytes(), Info{3, 1, 1, 0, []float64{10, 0, 0}, []float64{11, 2, 3}}},
+		{"b.stl", stl.Bytes(), Info{3, 1, 0, 0, []float64{0, 0, 0}, []float64{1, 2, 3}}},
+		{"c.stl", []byte("solid c\nfacet normal 0 0 1\nouter loop\nvertex 0 0 0\nvertex 1 0 0\nvertex 0 2 3\nendloop\nendfacet\nendsolid c\n"),
+			Info{3, 1, 0, 0, []float64{0, 0, 0}, []float64{1, 2, 3}}},
+		{"d.ply", ply.Bytes(), Info{4, 2, 0, 1, []float64{0, 0, 0}, []float64{1, 2, 3}}},
+		{"e.ply", []byte("ply\nformat ascii 1.0\nelement vertex 3\nproperty float x\nproperty float y\nproperty float z\n" +
+			"element face 1\nproperty list uchar int vertex_index\nend_header\n0 0 0\n1 0 0\n0 2 3\n3 0 1 2\n"),
+			Info{3, 1, 0, 0, []float64{0, 0, 0}, []float64{1, 2, 3}}},
+	}
+	for _, test := range tests {
+		path := filepath.Join(dir, test.name)
+		ioutil.WriteFile(path, test.data, 0755)
+		geometry, err := ReadGeometry(path, 0)
+		if err != nil {
+			t.Errorf("%v: %v", test.name, err)
+			continue
+		}
+		if info := geometry.Info(); !reflect.DeepEqual(info, test.info) {
+			t.Errorf("%v: expect %v, got %v", test.name, test.info, info)
+		}
+	}
+
+	// files larger than the max size are not read
+	if _, err := ReadGeometry(filepath.Join(dir, "a.glb"), 10); err == nil {
+		t.Errorf("expect error, got nil")
+	}
+
+	// nodes that are reused are refused, rather than visited exponential times.
+	doc.Nodes = []helper.GLTFNode{}
+	for i := 0; i < 64; i++ {
+		doc.Nodes = append(doc.Nodes, helper.GLTFNode{Mesh: &zero, Children: []int{i + 1, i + 1}})
+	}
+	doc.Nodes = append(doc.Nodes, helper.GLTFNode{Mesh: &zero})
+	glb.Reset()
+	helper.WriteGLB(&glb, doc, bin.Bytes())
+	path := filepath.Join(dir, "f.glb")
+	ioutil.WriteFile(path, glb.Bytes(), 0755)
+	if _, err := ReadGeometry(path, 0); err == nil {
+		t.Errorf("expect error, got nil")
+	}
+}
@@ -0,0 +1,23 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package mesh
+
+import (
+	"net/http"
+
+	"github.com/tengge1/shadoweditor/server"
+)
+
+func init() {
+	server.Handle(http.MethodGet, "/api/Mesh/WhereUsed", WhereUsed, server.ListMesh)
+}
+
+// WhereUsed returns the scenes, prefabs, materials and characters that reference a mesh.
+func WhereUsed(w http.ResponseWriter, r *http.Request) {
+	server.HandleWhereUsed(w, r, server.MeshCollectionName, "_id")
+}
@@ -41,4 +41,16 @@ type Model struct {
 	AddTime time.Time
 	// Thumbnail
 	Thumbnail string
+	// Vertex Count
+	VertexCount int
+	// Triangle Count
+	TriangleCount int
+	// Material Count
+	MaterialCount int
+	// Texture Count
+	TextureCount int
+	// Min point of the bounds, null if unknown
+	BoundsMin []float64
+	// Max point of the bounds, null if unknown
+	BoundsMax []float64
 }
@@ -0,0 +1,114 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package mesh
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/tengge1/shadoweditor/three"
+)
+
+// readOBJ reads the vertices and faces of an obj file, and the materials and
+// textures of its mtl files.
+func readOBJ(data []byte, dir string) (*Geometry, error) {
+	g := &Geometry{}
+	materials := map[string]bool{}
+	textures := map[string]bool{}
+	libraries := []string{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "v":
+			if len(fields) < 4 {
+				return nil, fmt.Errorf("line %v: invalid vertex", line)
+			}
+			position := [3]float64{}
+			for i := range position {
+				value, err := strconv.ParseFloat(fields[i+1], 64)
+				if err != nil {
+					return nil, fmt.Errorf("line %v: %v", line, err)
+				}
+				position[i] = value
+			}
+			g.Positions = append(g.Positions, *three.NewVector3(position[0], position[1], position[2]))
+		case "f":
+			indices := make([]int, 0, len(fields)-1)
+			for _, field := range fields[1:] {
+				// v, v/vt, v//vn or v/vt/vn
+				if slash := strings.Index(field, "/"); slash >= 0 {
+					field = field[:slash]
+				}
+				index, err := strconv.Atoi(field)
+				if err != nil {
+					return nil, fmt.Errorf("line %v: %v", line, err)
+				}
+				if index < 0 { // relative to the end of the vertices
+					index += len(g.Positions)
+				} else {
+					index--
+				}
+				indices = append(indices, index)
+			}
+			g.addPolygon(indices)
+		case "usemtl":
+			if len(fields) > 1 {
+				materials[fields[1]] = true
+			}
+		case "mtllib":
+			libraries = append(libraries, fields[1:]...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := g.checkIndices(); err != nil {
+		return nil, err
+	}
+
+	for _, library := range libraries {
+		data, err := ioutil.ReadFile(filepath.Join(dir, filepath.Base(library)))
+		if err != nil { // the mtl file is not uploaded
+			continue
+		}
+		readMTL(data, materials, textures)
+	}
+	g.Materials = len(materials)
+	g.Textures = len(textures)
+
+	return g, nil
+}
+
+// readMTL adds the material names and texture files of a mtl file.
+func readMTL(data []byte, materials, textures map[string]bool) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		switch keyword := strings.ToLower(fields[0]); {
+		case keyword == "newmtl":
+			materials[fields[1]] = true
+		case strings.HasPrefix(keyword, "map_") || keyword == "bump" || keyword == "disp" || keyword == "norm" || keyword == "decal":
+			// texture options, such as `-s 1 1 1`, are before the file name.
+			textures[fields[len(fields)-1]] = true
+		}
+	}
+}
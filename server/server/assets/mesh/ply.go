@@ -0,0 +1,221 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package mesh
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/tengge1/shadoweditor/three"
+)
+
+// plyElement is an element declared in the header of a ply file, such as `vertex`.
+type plyElement struct {
+	Name       string
+	Count      int
+	Properties []plyProperty
+}
+
+// plyProperty is a property of a ply element. List properties have a count type.
+type plyProperty struct {
+	Name      string
+	Type      string
+	CountType string
+}
+
+// plyValueReader reads a value of a ply type.
+type plyValueReader func(valueType string) (float64, error)
+
+// readPLY reads an ascii or binary ply file.
+func readPLY(data []byte) (*Geometry, error) {
+	end := bytes.Index(data, []byte("end_header"))
+	if !bytes.HasPrefix(data, []byte("ply")) || end < 0 {
+		return nil, fmt.Errorf("invalid ply file")
+	}
+	header := string(data[:end])
+	body := data[end+len("end_header"):]
+	if bytes.HasPrefix(body, []byte("\r\n")) {
+		body = body[2:]
+	} else if bytes.HasPrefix(body, []byte("\n")) {
+		body = body[1:]
+	}
+
+	g := &Geometry{}
+	format := ""
+	elements := []*plyElement{}
+	for _, line := range strings.Split(header, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "format":
+			if len(fields) > 1 {
+				format = fields[1]
+			}
+		case "comment":
+			if len(fields) > 2 && fields[1] == "TextureFile" {
+				g.Textures++
+			}
+		case "element":
+			if len(fields) < 3 {
+				return nil, fmt.Errorf("invalid ply element: %v", line)
+			}
+			count, err := strconv.Atoi(fields[2])
+			if err != nil || count < 0 {
+				return nil, fmt.Errorf("invalid ply element: %v", line)
+			}
+			elements = append(elements, &plyElement{Name: fields[1], Count: count})
+		case "property":
+			if len(elements) == 0 {
+				return nil, fmt.Errorf("ply property has no element")
+			}
+			element := elements[len(elements)-1]
+			if len(fields) == 5 && fields[1] == "list" {
+				element.Properties = append(element.Properties, plyProperty{Name: fields[4], Type: fields[3], CountType: fields[2]})
+			} else if len(fields) == 3 {
+				element.Properties = append(element.Properties, plyProperty{Name: fields[2], Type: fields[1]})
+			} else {
+				return nil, fmt.Errorf("invalid ply property: %v", line)
+			}
+		}
+	}
+
+	var read plyValueReader
+	switch format {
+	case "ascii":
+		read = asciiPLYReader(body)
+	case "binary_little_endian":
+		read = binaryPLYReader(body, binary.LittleEndian)
+	case "binary_big_endian":
+		read = binaryPLYReader(body, binary.BigEndian)
+	default:
+		return nil, fmt.Errorf("ply format %v is not supported", format)
+	}
+
+	for _, element := range elements {
+		for i := 0; i < element.Count; i++ {
+			if err := readPLYElement(g, element, read); err != nil {
+				return nil, fmt.Errorf("ply %v %v: %v", element.Name, i, err)
+			}
+		}
+	}
+	if err := g.checkIndices(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// readPLYElement reads an element, and adds it to the geometry if it is a vertex
+// or a face.
+func readPLYElement(g *Geometry, element *plyElement, read plyValueReader) error {
+	position := [3]float64{}
+	for _, property := range element.Properties {
+		if property.CountType == "" {
+			value, err := read(property.Type)
+			if err != nil {
+				return err
+			}
+			switch property.Name {
+			case "x":
+				position[0] = value
+			case "y":
+				position[1] = value
+			case "z":
+				position[2] = value
+			}
+			continue
+		}
+
+		count, err := read(property.CountType)
+		if err != nil {
+			return err
+		}
+		if count < 0 {
+			return fmt.Errorf("invalid list count %v", count)
+		}
+		indices := []int{}
+		for i := 0; i < int(count); i++ {
+			value, err := read(property.Type)
+			if err != nil {
+				return err
+			}
+			indices = append(indices, int(value))
+		}
+		if element.Name == "face" && (property.Name == "vertex_indices" || property.Name == "vertex_index") {
+			g.addPolygon(indices)
+		}
+	}
+	if element.Name == "vertex" {
+		g.Positions = append(g.Positions, *three.NewVector3(position[0], position[1], position[2]))
+	}
+	return nil
+}
+
+// asciiPLYReader reads values separated by white spaces.
+func asciiPLYReader(body []byte) plyValueReader {
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Split(bufio.ScanWords)
+	return func(valueType string) (float64, error) {
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return 0, err
+			}
+			return 0, io.ErrUnexpectedEOF
+		}
+		return strconv.ParseFloat(scanner.Text(), 64)
+	}
+}
+
+// binaryPLYReader reads values in a byte order.
+func binaryPLYReader(body []byte, order binary.ByteOrder) plyValueReader {
+	reader := bytes.NewReader(body)
+	return func(valueType string) (float64, error) {
+		var err error
+		switch valueType {
+		case "char", "int8":
+			var value int8
+			err = binary.Read(reader, order, &value)
+			return float64(value), err
+		case "uchar", "uint8":
+			var value uint8
+			err = binary.Read(reader, order, &value)
+			return float64(value), err
+		case "short", "int16":
+			var value int16
+			err = binary.Read(reader, order, &value)
+			return float64(value), err
+		case "ushort", "uint16":
+			var value uint16
+			err = binary.Read(reader, order, &value)
+			return float64(value), err
+		case "int", "int32":
+			var value int32
+			err = binary.Read(reader, order, &value)
+			return float64(value), err
+		case "uint", "uint32":
+			var value uint32
+			err = binary.Read(reader, order, &value)
+			return float64(value), err
+		case "float", "float32":
+			var value float32
+			err = binary.Read(reader, order, &value)
+			return float64(value), err
+		case "double", "float64":
+			var value float64
+			err = binary.Read(reader, order, &value)
+			return value, err
+		}
+		return 0, fmt.Errorf("ply type %v is not supported", valueType)
+	}
+}
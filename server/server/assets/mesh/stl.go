@@ -0,0 +1,94 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package mesh
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/tengge1/shadoweditor/three"
+)
+
+// stlHeaderSize is the size of the header and triangle count of binary stl files.
+const stlHeaderSize = 84
+
+// stlTriangleSize is the size of a triangle of binary stl files.
+const stlTriangleSize = 50
+
+// readSTL reads an ascii or binary stl file. Binary files may also start with
+// `solid`, so the size is checked first.
+func readSTL(data []byte) (*Geometry, error) {
+	if len(data) >= stlHeaderSize {
+		count := int(binary.LittleEndian.Uint32(data[80:]))
+		if len(data) == stlHeaderSize+count*stlTriangleSize {
+			return readBinarySTL(data[stlHeaderSize:], count), nil
+		}
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("solid")) {
+		return readASCIISTL(data)
+	}
+	return nil, fmt.Errorf("invalid stl file")
+}
+
+func readBinarySTL(data []byte, count int) *Geometry {
+	g := &Geometry{}
+	for i := 0; i < count; i++ {
+		// normal, three vertices and attribute byte count
+		triangle := data[i*stlTriangleSize:]
+		for j := 1; j <= 3; j++ {
+			x := math.Float32frombits(binary.LittleEndian.Uint32(triangle[j*12:]))
+			y := math.Float32frombits(binary.LittleEndian.Uint32(triangle[j*12+4:]))
+			z := math.Float32frombits(binary.LittleEndian.Uint32(triangle[j*12+8:]))
+			g.Indices = append(g.Indices, len(g.Positions))
+			g.Positions = append(g.Positions, *three.NewVector3(float64(x), float64(y), float64(z)))
+		}
+	}
+	return g
+}
+
+func readASCIISTL(data []byte) (*Geometry, error) {
+	g := &Geometry{}
+	polygon := []int{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "outer":
+			polygon = polygon[:0]
+		case "vertex":
+			if len(fields) < 4 {
+				return nil, fmt.Errorf("line %v: invalid vertex", line)
+			}
+			position := [3]float64{}
+			for i := range position {
+				value, err := strconv.ParseFloat(fields[i+1], 64)
+				if err != nil {
+					return nil, fmt.Errorf("line %v: %v", line, err)
+				}
+				position[i] = value
+			}
+			polygon = append(polygon, len(g.Positions))
+			g.Positions = append(g.Positions, *three.NewVector3(position[0], position[1], position[2]))
+		case "endloop":
+			g.addPolygon(polygon)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
@@ -42,7 +42,7 @@ func Save(w http.ResponseWriter, r *http.Request) {
 
 	data := strings.TrimSpace(r.FormValue("Data"))
 
-	db, err := server.Mongo()
+	db, err := server.DB()
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
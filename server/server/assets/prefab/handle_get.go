@@ -33,7 +33,7 @@ func Get(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
-	db, err := server.Mongo()
+	db, err := server.DB()
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
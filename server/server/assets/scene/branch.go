@@ -0,0 +1,244 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package scene
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/tengge1/shadoweditor/helper"
+	"github.com/tengge1/shadoweditor/server"
+)
+
+// A branch is stored like a scene: its data is in CollectionName, and its history is
+// in CollectionName + `_history`. Version 0 of a branch is a copy of the scene version
+// it is forked from, so the fork point is kept as long as the branch exists.
+
+// newBranchCollectionName returns a collection name for a new branch.
+func newBranchCollectionName(db server.Storage, now time.Time) (string, error) {
+	name := "Branch" + helper.TimeToString(now, "yyyyMMddHHmmss")
+	for i := 1; ; i++ {
+		count, err := db.Count(server.SceneBranchCollectionName, bson.M{
+			"CollectionName": name,
+		})
+		if err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return name, nil
+		}
+		name = fmt.Sprintf("Branch%v_%v", helper.TimeToString(now, "yyyyMMddHHmmss"), i)
+	}
+}
+
+// findBranch returns a branch of a scene by name.
+func findBranch(db server.Storage, sceneID primitive.ObjectID, name string) (bson.M, bool, error) {
+	filter := bson.M{
+		"SceneID": sceneID,
+		"Name":    name,
+	}
+	doc := bson.M{}
+	find, err := db.FindOne(server.SceneBranchCollectionName, filter, &doc)
+	return doc, find, err
+}
+
+// deleteBranch deletes a branch and its data.
+func deleteBranch(db server.Storage, branch bson.M) error {
+	collectionName := branch["CollectionName"].(string)
+	if err := db.DropCollection(collectionName); err != nil {
+		return err
+	}
+	if err := db.DropCollection(collectionName + server.HistorySuffix); err != nil {
+		return err
+	}
+	_, err := db.DeleteOne(server.SceneBranchCollectionName, bson.M{
+		"ID": branch["ID"],
+	})
+	return err
+}
+
+// deleteBranches deletes all the branches of a scene.
+func deleteBranches(db server.Storage, sceneID primitive.ObjectID) error {
+	branches := []bson.M{}
+	if err := db.FindMany(server.SceneBranchCollectionName, bson.M{"SceneID": sceneID}, &branches); err != nil {
+		return err
+	}
+	for _, branch := range branches {
+		if err := deleteBranch(db, branch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeScene merges the changes of a branch since base into the scene by object
+// uuid. Fields changed on only one side are taken from that side, and fields changed
+// differently on both sides are reported as conflicts. The merged data is only
+// meaningful when there are no conflicts.
+func mergeScene(base, scene, branch []bson.M) ([]bson.M, []ConflictModel) {
+	baseObjects := map[string]bson.M{}
+	for _, doc := range base {
+		baseObjects[objectKey(doc)] = doc
+	}
+	sceneObjects := map[string]bson.M{}
+	for _, doc := range scene {
+		sceneObjects[objectKey(doc)] = doc
+	}
+	branchObjects := map[string]bson.M{}
+	for _, doc := range branch {
+		branchObjects[objectKey(doc)] = doc
+	}
+
+	merged := []bson.M{}
+	conflicts := []ConflictModel{}
+
+	for _, doc := range scene {
+		key := objectKey(doc)
+		old, inBase := baseObjects[key]
+		other, inBranch := branchObjects[key]
+
+		switch {
+		case !inBranch && !inBase: // added in scene
+			merged = append(merged, doc)
+		case !inBranch: // removed in branch
+			if isModified(old, doc) {
+				conflicts = append(conflicts, newConflict(doc, old, doc, nil))
+				merged = append(merged, doc)
+			}
+		default:
+			if !inBase {
+				old = bson.M{}
+			}
+			object := newObjectDiff(doc)
+			merged = append(merged, mergeFields(object, "", old, doc, other, &conflicts))
+		}
+	}
+
+	for _, doc := range branch {
+		key := objectKey(doc)
+		if _, ok := sceneObjects[key]; ok {
+			continue
+		}
+		old, inBase := baseObjects[key]
+		if !inBase { // added in branch
+			merged = append(merged, doc)
+			continue
+		}
+		if isModified(old, doc) { // removed in scene
+			conflicts = append(conflicts, newConflict(doc, old, nil, doc))
+		}
+	}
+
+	for _, doc := range merged {
+		delete(doc, "_id")
+		delete(doc, server.VersionField)
+	}
+
+	return merged, conflicts
+}
+
+// mergeFields merges the fields of an object, and appends conflicts.
+func mergeFields(object ObjectDiffModel, prefix string, base, scene, branch bson.M, conflicts *[]ConflictModel) bson.M {
+	keys := []string{}
+	for _, doc := range []bson.M{base, scene, branch} {
+		for key := range doc {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	result := bson.M{}
+	for i, key := range keys {
+		if i > 0 && keys[i-1] == key {
+			continue
+		}
+		if prefix == "" && (key == "_id" || key == server.VersionField) {
+			continue
+		}
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		baseValue, baseOK := base[key]
+		sceneValue, sceneOK := scene[key]
+		branchValue, branchOK := branch[key]
+
+		switch {
+		case sameValue(sceneValue, sceneOK, branchValue, branchOK): // same on both sides
+			if sceneOK {
+				result[key] = sceneValue
+			}
+		case sameValue(baseValue, baseOK, branchValue, branchOK): // changed in scene
+			if sceneOK {
+				result[key] = sceneValue
+			}
+		case sameValue(baseValue, baseOK, sceneValue, sceneOK): // changed in branch
+			if branchOK {
+				result[key] = branchValue
+			}
+		default: // changed on both sides
+			baseDoc, baseIsDoc := baseValue.(bson.M)
+			sceneDoc, sceneIsDoc := sceneValue.(bson.M)
+			branchDoc, branchIsDoc := branchValue.(bson.M)
+			if !baseOK {
+				baseDoc, baseIsDoc = bson.M{}, true
+			}
+			if baseIsDoc && sceneIsDoc && branchIsDoc && !isVector(sceneDoc) && !isVector(branchDoc) {
+				result[key] = mergeFields(object, path, baseDoc, sceneDoc, branchDoc, conflicts)
+				continue
+			}
+			*conflicts = append(*conflicts, ConflictModel{
+				UUID:   object.UUID,
+				Name:   object.Name,
+				Type:   object.Type,
+				Path:   path,
+				Base:   baseValue,
+				Scene:  sceneValue,
+				Branch: branchValue,
+			})
+			if sceneOK {
+				result[key] = sceneValue
+			}
+		}
+	}
+	return result
+}
+
+// sameValue returns whether two optional values are the same.
+func sameValue(a interface{}, aOK bool, b interface{}, bOK bool) bool {
+	if !aOK || !bOK {
+		return aOK == bOK
+	}
+	return equalValues(a, b)
+}
+
+// isModified returns whether an object has changed.
+func isModified(from, to bson.M) bool {
+	changes := []FieldDiffModel{}
+	diffFields("", from, to, &changes)
+	return len(changes) > 0
+}
+
+// newConflict creates a conflict of an object that is removed on one side and
+// modified on the other side.
+func newConflict(doc bson.M, base, scene, branch interface{}) ConflictModel {
+	object := newObjectDiff(doc)
+	return ConflictModel{
+		UUID:   object.UUID,
+		Name:   object.Name,
+		Type:   object.Type,
+		Base:   base,
+		Scene:  scene,
+		Branch: branch,
+	}
+}
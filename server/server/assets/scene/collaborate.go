@@ -0,0 +1,597 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package scene
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/tengge1/shadoweditor/helper"
+	"github.com/tengge1/shadoweditor/server"
+)
+
+const (
+	// writeWait is the time allowed to write a message to a collaborator.
+	writeWait = 10 * time.Second
+	// pongWait is the time allowed to read the next pong from a collaborator.
+	pongWait = 60 * time.Second
+	// pingPeriod sends pings to collaborators with this period, and it must be less than pongWait.
+	pingPeriod = pongWait * 9 / 10
+)
+
+var (
+	// persistInterval is how often the data of a collaborating scene is saved.
+	persistInterval = 30 * time.Second
+	// rooms are the scenes that are being collaborated, and the key is scene ID.
+	rooms = map[primitive.ObjectID]*room{}
+	// roomsMutex protects rooms.
+	roomsMutex sync.Mutex
+)
+
+// collaborateMessage is the message between the server and collaborators.
+//
+// Collaborators send `add`, `remove`, `set` and `select` messages, and the server
+// broadcasts them to other collaborators of the scene. The server also sends
+// `presence` when collaborators join, leave or select objects, `reload` when the
+// scene is saved by others outside the collaboration, `lock` when others lock the
+// scene so that changes are not saved, and `error`.
+type collaborateMessage struct {
+	// Message Type
+	Type string
+	// Object UUID
+	UUID string `json:",omitempty"`
+	// Serialized object to add
+	Object map[string]interface{} `json:",omitempty"`
+	// JSON path of the property to set, such as `position` or `material.color`
+	Path string `json:",omitempty"`
+	// Property value to set
+	Value interface{} `json:",omitempty"`
+	// ID of the user who sends the message
+	UserID string `json:",omitempty"`
+	// The user who sends the message
+	Username string `json:",omitempty"`
+	// Collaborators of the scene, for presence
+	Users []CollaboratorModel `json:",omitempty"`
+	// Scene version, for reload
+	Version int `json:",omitempty"`
+	// Operations that are not able to apply to the latest scene, for reload
+	Rejected []collaborateMessage `json:",omitempty"`
+	// Error message
+	Msg string `json:",omitempty"`
+}
+
+// CollaboratorModel is a user who is editing a scene.
+type CollaboratorModel struct {
+	// Connection ID, a user may edit the scene in several pages.
+	ID int
+	// User ID
+	UserID string
+	// Username
+	Username string
+	// The uuid of the selected object
+	Selected string
+}
+
+// collaborator is a websocket connection to a scene.
+type collaborator struct {
+	CollaboratorModel
+	conn *websocket.Conn
+	send chan []byte
+}
+
+// room holds the data of a scene that is being collaborated.
+type room struct {
+	// The _Scene document that the data is based on.
+	doc bson.M
+	// Serialized objects, and the key is returned by objectKey.
+	objects map[string]bson.M
+	// Object keys in the saved order.
+	keys []string
+	// Whether objects have changed since last save.
+	dirty bool
+	// Operations since last save, they are applied again when others save the scene.
+	ops []collaborateMessage
+	// The user who changed objects last.
+	userID string
+	// Collaborators who are editing the scene.
+	collaborators map[*collaborator]bool
+	// The connection ID of the next collaborator.
+	nextID int
+	// done is closed when the last collaborator leaves.
+	done  chan struct{}
+	mutex sync.Mutex
+}
+
+// joinRoom adds a collaborator to the room of a scene, and loads the scene if no
+// one is editing it.
+func joinRoom(id primitive.ObjectID, c *collaborator) (*room, error) {
+	roomsMutex.Lock()
+	defer roomsMutex.Unlock()
+
+	r, ok := rooms[id]
+	if !ok {
+		r = &room{
+			collaborators: map[*collaborator]bool{},
+			done:          make(chan struct{}),
+		}
+		if err := r.load(id); err != nil {
+			return nil, err
+		}
+		rooms[id] = r
+		go r.run()
+	}
+
+	r.mutex.Lock()
+	r.nextID++
+	c.ID = r.nextID
+	r.collaborators[c] = true
+	r.mutex.Unlock()
+
+	r.broadcastPresence()
+	return r, nil
+}
+
+// leave removes a collaborator from the room. The room is saved and closed when
+// the last collaborator leaves.
+func (r *room) leave(c *collaborator) {
+	roomsMutex.Lock()
+	defer roomsMutex.Unlock()
+
+	r.mutex.Lock()
+	if _, ok := r.collaborators[c]; ok {
+		delete(r.collaborators, c)
+		close(c.send)
+	}
+	empty := len(r.collaborators) == 0
+	r.mutex.Unlock()
+
+	if !empty {
+		r.broadcastPresence()
+		return
+	}
+
+	// save before the room is removed, so that the next collaborator loads the latest data.
+	r.persist()
+	delete(rooms, r.doc["ID"].(primitive.ObjectID))
+	close(r.done)
+}
+
+// load reads the latest data of a scene. The mutex must be held or the room is
+// not shared yet.
+func (r *room) load(id primitive.ObjectID) error {
+	db, err := server.DB()
+	if err != nil {
+		return err
+	}
+
+	doc := bson.M{}
+	find, err := db.FindOne(server.SceneCollectionName, bson.M{"ID": id}, &doc)
+	if err != nil {
+		return err
+	}
+	if !find {
+		return fmt.Errorf("the scene is not existed")
+	}
+
+	docs, err := server.LoadSceneVersion(db, doc, -1)
+	if err != nil {
+		return err
+	}
+
+	r.doc = doc
+	r.objects = map[string]bson.M{}
+	r.keys = []string{}
+	r.dirty = false
+	r.ops = nil
+	for _, i := range docs {
+		delete(i, "_id")
+		key := objectKey(i)
+		if _, ok := r.objects[key]; !ok {
+			r.keys = append(r.keys, key)
+		}
+		r.objects[key] = i
+	}
+	return nil
+}
+
+// run saves the room periodically until it is closed.
+func (r *room) run() {
+	ticker := time.NewTicker(persistInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.persist()
+		case <-r.done:
+			return
+		}
+	}
+}
+
+// persist saves the objects as a new version of the scene, just like Save does.
+// If others have locked the scene, nothing is saved and collaborators are told. If
+// the scene has been saved by others, the operations are applied to the latest
+// data again, and collaborators are asked to reload.
+func (r *room) persist() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if !r.dirty {
+		return
+	}
+
+	db, err := server.DB()
+	if err != nil {
+		server.Logger.Error(err)
+		return
+	}
+
+	// the scene may be locked after the room is opened.
+	id := r.doc["ID"].(primitive.ObjectID)
+	doc := bson.M{}
+	find, err := db.FindOne(server.SceneCollectionName, bson.M{"ID": id}, &doc)
+	if err != nil {
+		server.Logger.Error(err)
+		return
+	}
+	if !find {
+		server.Logger.Errorf("collaborated scene %v is not existed", id.Hex())
+		return
+	}
+	if err := checkUserLock(r.userID, doc); err != nil {
+		msg := collaborateMessage{
+			Type: "lock",
+			Msg:  err.Error(),
+		}
+		msg.UserID, _, _ = getLock(doc)
+		if user, _ := server.GetUser(msg.UserID); user != nil {
+			msg.Username = user.Username
+		}
+		r.broadcast(nil, msg)
+		return
+	}
+
+	version, err := r.save(db)
+	if err != errConflict {
+		if err != nil {
+			server.Logger.Error(err)
+		}
+		return
+	}
+
+	rejected, err := r.rebase()
+	if err != nil {
+		server.Logger.Error(err)
+		return
+	}
+	msg := collaborateMessage{
+		Type:     "reload",
+		Version:  int(r.doc["Version"].(int32)),
+		Rejected: rejected,
+	}
+	if userID, ok := r.doc["UpdateUserID"].(string); ok && userID != "" {
+		msg.UserID = userID
+		if user, _ := server.GetUser(userID); user != nil {
+			msg.Username = user.Username
+		}
+	}
+	if r.dirty {
+		// a conflict again leaves the operations to the next save.
+		if version, err = r.save(db); err != nil {
+			server.Logger.Error(err)
+			return
+		}
+		msg.Version = version
+	}
+	r.broadcast(nil, msg)
+}
+
+// save saves the objects as a new version of the scene. The mutex must be held.
+func (r *room) save(db server.Storage) (int, error) {
+	data := make([]interface{}, 0, len(r.keys))
+	for _, key := range r.keys {
+		data = append(data, r.objects[key])
+	}
+
+	version, err := saveData(db, server.SceneCollectionName, r.doc, data, r.userID)
+	if err != nil {
+		return 0, err
+	}
+
+	r.doc["Version"] = int32(version)
+	r.dirty = false
+	r.ops = nil
+	updateThumbnail(db, r.doc["ID"].(primitive.ObjectID))
+	return version, nil
+}
+
+// rebase reloads the latest data of the scene, and applies the operations that are
+// not saved to it again. It returns the operations that are not able to apply, such
+// as setting an object that others have removed. The mutex must be held.
+func (r *room) rebase() ([]collaborateMessage, error) {
+	ops := r.ops
+	if err := r.load(r.doc["ID"].(primitive.ObjectID)); err != nil {
+		return nil, err
+	}
+
+	rejected := []collaborateMessage{}
+	for _, op := range ops {
+		if err := r.applyOp(&op); err != nil {
+			op.Msg = err.Error()
+			rejected = append(rejected, op)
+			continue
+		}
+		r.ops = append(r.ops, op)
+	}
+	r.dirty = len(r.ops) > 0
+	return rejected, nil
+}
+
+// apply applies an operation of a collaborator, and broadcasts it to others.
+func (r *room) apply(c *collaborator, msg collaborateMessage) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if msg.Type == "select" {
+		c.Selected = msg.UUID
+		r.broadcast(nil, r.presence())
+		return nil
+	}
+
+	msg.UserID = c.UserID
+	msg.Username = c.Username
+	if err := r.applyOp(&msg); err != nil {
+		return err
+	}
+
+	r.dirty = true
+	r.userID = c.UserID
+	r.ops = append(r.ops, msg)
+
+	r.broadcast(c, msg)
+	return nil
+}
+
+// applyOp applies an `add`, `remove` or `set` operation to the objects. The mutex
+// must be held.
+func (r *room) applyOp(msg *collaborateMessage) error {
+	switch msg.Type {
+	case "add":
+		if msg.Object == nil {
+			return fmt.Errorf("object is not allowed to be empty")
+		}
+		obj := bson.M{}
+		if err := normalize(msg.Object, &obj); err != nil {
+			return err
+		}
+		uuid, _ := obj["uuid"].(string)
+		if uuid == "" || (msg.UUID != "" && msg.UUID != uuid) {
+			return fmt.Errorf("uuid is not allowed")
+		}
+		delete(obj, "_id")
+		if _, ok := r.objects[uuid]; !ok {
+			r.keys = append(r.keys, uuid)
+		}
+		r.objects[uuid] = obj
+		msg.UUID = uuid
+	case "remove":
+		if _, ok := r.objects[msg.UUID]; !ok || msg.UUID == "" {
+			return fmt.Errorf("object %v is not existed", msg.UUID)
+		}
+		delete(r.objects, msg.UUID)
+		for i, key := range r.keys {
+			if key == msg.UUID {
+				r.keys = append(r.keys[:i], r.keys[i+1:]...)
+				break
+			}
+		}
+	case "set":
+		obj, ok := r.objects[msg.UUID]
+		if !ok || msg.UUID == "" {
+			return fmt.Errorf("object %v is not existed", msg.UUID)
+		}
+		if err := setProperty(obj, msg.Path, msg.Value); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown message type: %v", msg.Type)
+	}
+	return nil
+}
+
+// presence returns the presence message of the room. The mutex must be held.
+func (r *room) presence() collaborateMessage {
+	msg := collaborateMessage{
+		Type:  "presence",
+		Users: []CollaboratorModel{},
+	}
+	for c := range r.collaborators {
+		msg.Users = append(msg.Users, c.CollaboratorModel)
+	}
+	return msg
+}
+
+// broadcastPresence tells all collaborators who are editing the scene.
+func (r *room) broadcastPresence() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.broadcast(nil, r.presence())
+}
+
+// broadcast sends a message to collaborators except the sender. Collaborators
+// that are too slow to receive messages are disconnected, so that they reload
+// the scene when they connect again. The mutex must be held.
+func (r *room) broadcast(sender *collaborator, msg collaborateMessage) {
+	data, err := helper.ToJSON(msg)
+	if err != nil {
+		server.Logger.Error(err)
+		return
+	}
+	for c := range r.collaborators {
+		if c == sender {
+			continue
+		}
+		select {
+		case c.send <- data:
+		default:
+			c.conn.Close()
+		}
+	}
+}
+
+// normalize converts a json value to bson, so that numbers and nested documents
+// are the same as the data read from the database.
+func normalize(value interface{}, result interface{}) error {
+	data, err := bson.Marshal(bson.M{"value": value})
+	if err != nil {
+		return err
+	}
+	wrapper := bson.M{}
+	if err := bson.Unmarshal(data, &wrapper); err != nil {
+		return err
+	}
+	switch v := result.(type) {
+	case *bson.M:
+		doc, ok := wrapper["value"].(bson.M)
+		if !ok {
+			return fmt.Errorf("object is not allowed")
+		}
+		*v = doc
+	case *interface{}:
+		*v = wrapper["value"]
+	}
+	return nil
+}
+
+// setProperty sets a property of a serialized object by its json path, and a nil
+// value removes the property.
+func setProperty(obj bson.M, path string, value interface{}) error {
+	if path == "" || path == "uuid" || path == "_id" {
+		return fmt.Errorf("path is not allowed: %v", path)
+	}
+
+	var val interface{}
+	if err := normalize(value, &val); err != nil {
+		return err
+	}
+
+	keys := strings.Split(path, ".")
+	doc := obj
+	for _, key := range keys[:len(keys)-1] {
+		if key == "" {
+			return fmt.Errorf("path is not allowed: %v", path)
+		}
+		child, ok := doc[key]
+		if !ok || child == nil {
+			child = bson.M{}
+			doc[key] = child
+		}
+		next, ok := child.(bson.M)
+		if !ok {
+			return fmt.Errorf("path is not allowed: %v", path)
+		}
+		doc = next
+	}
+
+	key := keys[len(keys)-1]
+	if key == "" {
+		return fmt.Errorf("path is not allowed: %v", path)
+	}
+	if val == nil {
+		delete(doc, key)
+	} else {
+		doc[key] = val
+	}
+	return nil
+}
+
+// read reads operations from the collaborator until the connection is closed.
+func (c *collaborator) read(r *room) {
+	defer func() {
+		r.leave(c)
+		c.conn.Close()
+	}()
+
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		c.conn.SetReadDeadline(time.Now().Add(pongWait))
+		return nil
+	})
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		msg := collaborateMessage{}
+		if err := helper.FromJSON(data, &msg); err != nil {
+			c.sendError(r, err)
+			continue
+		}
+		if err := r.apply(c, msg); err != nil {
+			c.sendError(r, err)
+		}
+	}
+}
+
+// sendError tells the collaborator that an operation is not allowed.
+func (c *collaborator) sendError(r *room, err error) {
+	data, _ := helper.ToJSON(collaborateMessage{
+		Type: "error",
+		Msg:  err.Error(),
+	})
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.collaborators[c]; !ok {
+		return
+	}
+	select {
+	case c.send <- data:
+	default:
+		c.conn.Close()
+	}
+}
+
+// write writes messages to the collaborator, and pings it periodically.
+func (c *collaborator) write() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case data, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := c.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
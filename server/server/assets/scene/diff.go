@@ -0,0 +1,237 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package scene
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// vectorKeys are the keys of vectors, eulers and quaternions. A document that only
+// has these keys is compared as a whole, so that a moved object reports `position`
+// rather than `position.x`.
+var vectorKeys = map[string]bool{
+	"x":     true,
+	"y":     true,
+	"z":     true,
+	"w":     true,
+	"order": true,
+}
+
+// DiffModel is the difference between two scene versions.
+type DiffModel struct {
+	// From Version, -1 means the latest version
+	From int
+	// To Version, -1 means the latest version
+	To int
+	// Objects that only exist in To
+	Added []ObjectDiffModel
+	// Objects that only exist in From
+	Removed []ObjectDiffModel
+	// Objects that exist in both versions but have changed
+	Modified []ObjectDiffModel
+}
+
+// ObjectDiffModel is an added, removed or modified object.
+type ObjectDiffModel struct {
+	// UUID
+	UUID string
+	// Name
+	Name string
+	// Type
+	Type string
+	// Changed fields, only for modified objects
+	Changes []FieldDiffModel `json:",omitempty"`
+}
+
+// FieldDiffModel is a changed field of an object.
+type FieldDiffModel struct {
+	// JSON path of the field, such as `position` or `material.color`
+	Path string
+	// Value in From, nil when the field is added
+	From interface{}
+	// Value in To, nil when the field is removed
+	To interface{}
+}
+
+// diffScene compares the serialized objects of two scene versions by uuid.
+func diffScene(from, to []bson.M) DiffModel {
+	result := DiffModel{
+		Added:    []ObjectDiffModel{},
+		Removed:  []ObjectDiffModel{},
+		Modified: []ObjectDiffModel{},
+	}
+
+	fromObjects := map[string]bson.M{}
+	for _, doc := range from {
+		fromObjects[objectKey(doc)] = doc
+	}
+	toObjects := map[string]bson.M{}
+	for _, doc := range to {
+		toObjects[objectKey(doc)] = doc
+	}
+
+	for _, doc := range to {
+		old, ok := fromObjects[objectKey(doc)]
+		if !ok {
+			result.Added = append(result.Added, newObjectDiff(doc))
+			continue
+		}
+		changes := []FieldDiffModel{}
+		diffFields("", old, doc, &changes)
+		if len(changes) > 0 {
+			object := newObjectDiff(doc)
+			object.Changes = changes
+			result.Modified = append(result.Modified, object)
+		}
+	}
+
+	for _, doc := range from {
+		if _, ok := toObjects[objectKey(doc)]; !ok {
+			result.Removed = append(result.Removed, newObjectDiff(doc))
+		}
+	}
+
+	return result
+}
+
+// objectKey returns the key that objects of two versions are matched by. Documents
+// without uuid, such as scene options, are matched by their serializer.
+func objectKey(doc bson.M) string {
+	if uuid, ok := doc["uuid"].(string); ok && uuid != "" {
+		return uuid
+	}
+	if metadata, ok := doc["metadata"].(bson.M); ok {
+		return fmt.Sprintf("metadata:%v", metadata["generator"])
+	}
+	return ""
+}
+
+// newObjectDiff creates an ObjectDiffModel from a serialized object.
+func newObjectDiff(doc bson.M) ObjectDiffModel {
+	object := ObjectDiffModel{}
+	object.UUID, _ = doc["uuid"].(string)
+	object.Name, _ = doc["name"].(string)
+	object.Type, _ = doc["type"].(string)
+	if object.Type == "" {
+		if metadata, ok := doc["metadata"].(bson.M); ok {
+			object.Type, _ = metadata["generator"].(string)
+		}
+	}
+	return object
+}
+
+// diffFields appends the changed fields of two documents to changes, and the
+// paths are sorted.
+func diffFields(prefix string, from, to bson.M, changes *[]FieldDiffModel) {
+	keys := []string{}
+	for key := range from {
+		keys = append(keys, key)
+	}
+	for key := range to {
+		if _, ok := from[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if prefix == "" && (key == "_id" || key == "_version") {
+			continue
+		}
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		fromValue, fromOK := from[key]
+		toValue, toOK := to[key]
+		fromDoc, fromIsDoc := fromValue.(bson.M)
+		toDoc, toIsDoc := toValue.(bson.M)
+
+		if fromOK && toOK && fromIsDoc && toIsDoc && !isVector(fromDoc) && !isVector(toDoc) {
+			diffFields(path, fromDoc, toDoc, changes)
+			continue
+		}
+		if fromOK && toOK && equalValues(fromValue, toValue) {
+			continue
+		}
+		*changes = append(*changes, FieldDiffModel{
+			Path: path,
+			From: fromValue,
+			To:   toValue,
+		})
+	}
+}
+
+// isVector returns whether a document is a vector, an euler or a quaternion.
+func isVector(doc bson.M) bool {
+	if len(doc) == 0 {
+		return false
+	}
+	for key := range doc {
+		if !vectorKeys[key] {
+			return false
+		}
+	}
+	return true
+}
+
+// equalValues compares two bson values. Numbers are compared by value, because
+// the same number may be saved as int32 in one version and double in another.
+func equalValues(a, b interface{}) bool {
+	if x, ok := toFloat(a); ok {
+		y, ok := toFloat(b)
+		return ok && x == y
+	}
+
+	switch x := a.(type) {
+	case bson.M:
+		y, ok := b.(bson.M)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for key, value := range x {
+			other, ok := y[key]
+			if !ok || !equalValues(value, other) {
+				return false
+			}
+		}
+		return true
+	case bson.A:
+		y, ok := b.(bson.A)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !equalValues(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// toFloat converts a bson number to float64.
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	}
+	return 0, false
+}
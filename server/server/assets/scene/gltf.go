@@ -0,0 +1,655 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package scene
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/jpeg" // decode jpeg size
+	_ "image/png"  // decode png size
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/tengge1/shadoweditor/helper"
+	"github.com/tengge1/shadoweditor/three"
+)
+
+// gltfImage is an image embedded in a glTF file.
+type gltfImage struct {
+	Name     string
+	Data     []byte
+	MimeType string
+	Width    int
+	Height   int
+	// Url is set when the image is saved.
+	URL string
+}
+
+// gltfWrapModes maps glTF wrapping modes to three.js.
+var gltfWrapModes = map[int]int{
+	10497: 1000, // RepeatWrapping
+	33071: 1001, // ClampToEdgeWrapping
+	33648: 1002, // MirroredRepeatWrapping
+}
+
+// gltfFilters maps glTF texture filters to three.js.
+var gltfFilters = map[int]int{
+	9728: 1003, // NearestFilter
+	9729: 1006, // LinearFilter
+	9984: 1004, // NearestMipmapNearestFilter
+	9985: 1007, // LinearMipmapNearestFilter
+	9986: 1005, // NearestMipmapLinearFilter
+	9987: 1008, // LinearMipmapLinearFilter
+}
+
+// readGLTF reads a .glb file or a self-contained .gltf file, and returns the glTF
+// document and its images. Buffers and images of .gltf files must be data urls.
+func readGLTF(fileName string, data []byte) (*helper.GLTF, []gltfImage, error) {
+	doc, buffers, err := helper.ReadGLTF(data, strings.HasSuffix(strings.ToLower(fileName), ".glb"), "", 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if doc.Asset.Version != "" && !strings.HasPrefix(doc.Asset.Version, "2.") {
+		return nil, nil, fmt.Errorf("glTF %v is not supported", doc.Asset.Version)
+	}
+	if len(doc.Scenes) == 0 {
+		return nil, nil, fmt.Errorf("the glTF file has no scene")
+	}
+
+	images := []gltfImage{}
+	for i, img := range doc.Images {
+		item := gltfImage{
+			Name:     img.Name,
+			MimeType: img.MimeType,
+		}
+		if img.BufferView != nil {
+			if *img.BufferView < 0 || *img.BufferView >= len(doc.BufferViews) {
+				return nil, nil, fmt.Errorf("image %v has no buffer view", i)
+			}
+			view := doc.BufferViews[*img.BufferView]
+			if view.Buffer < 0 || view.Buffer >= len(buffers) || view.ByteOffset < 0 || view.ByteLength < 0 ||
+				view.ByteOffset+view.ByteLength > len(buffers[view.Buffer]) {
+				return nil, nil, fmt.Errorf("image %v is out of buffer", i)
+			}
+			item.Data = buffers[view.Buffer][view.ByteOffset : view.ByteOffset+view.ByteLength]
+		} else {
+			if !strings.HasPrefix(img.URI, "data:") {
+				return nil, nil, fmt.Errorf("external file %v is not supported, please upload a .glb file", img.URI)
+			}
+			data, mimeType, err := helper.DecodeDataURL(img.URI)
+			if err != nil {
+				return nil, nil, err
+			}
+			item.Data = data
+			item.MimeType = mimeType
+		}
+
+		config, format, err := image.DecodeConfig(bytes.NewReader(item.Data))
+		if err != nil {
+			return nil, nil, fmt.Errorf("image %v: %v", i, err)
+		}
+		item.MimeType = "image/" + format
+		item.Width = config.Width
+		item.Height = config.Height
+		images = append(images, item)
+	}
+
+	return doc, images, nil
+}
+
+// gltfImporter converts a glTF document to serialized scene objects. The glTF file is
+// loaded as a server object, and the objects in it are saved with the uuids that the
+// client assigns to them, so that they are edited object by object like native
+// objects. Geometries are loaded from the file, and materials are saved.
+type gltfImporter struct {
+	doc    *helper.GLTF
+	images []gltfImage
+	docs   []interface{}
+	// nodes that have been added, a node has only one parent.
+	visited map[int]bool
+}
+
+// build returns the docs of a scene that has the model as a server object. model has
+// the ID, Name, Type and Url of the mesh asset.
+func (g *gltfImporter) build(name string, model bson.M) []interface{} {
+	g.docs = []interface{}{}
+	g.visited = map[int]bool{}
+
+	sceneUUID := three.GenerateUUID()
+	cameraUUID := three.GenerateUUID()
+	rootUUID := three.GenerateUUID()
+
+	sceneIndex := 0
+	if g.doc.Scene != nil && *g.doc.Scene >= 0 && *g.doc.Scene < len(g.doc.Scenes) {
+		sceneIndex = *g.doc.Scene
+	}
+	children := bson.A{}
+	for _, node := range g.doc.Scenes[sceneIndex].Nodes {
+		if node >= 0 && node < len(g.doc.Nodes) && !g.visited[node] {
+			children = append(children, g.addNode(node, rootUUID))
+		}
+	}
+
+	root := newObject3D("ServerObject", "Group", rootUUID, model["Name"].(string), sceneUUID, nil)
+	root["children"] = childUUIDs(children)
+	root["userData"] = bson.M{
+		"ID":        model["ID"],
+		"Name":      model["Name"],
+		"Type":      model["Type"],
+		"Url":       model["Url"],
+		"Server":    true,
+		"_children": children,
+	}
+
+	camera := newObject3D("PerspectiveCameraSerializer", "PerspectiveCamera", cameraUUID, "DefaultCamera", "", nil)
+	position := three.NewVector3(0, 5, 10)
+	matrix := three.NewMatrix4()
+	matrix.LookAt(*position, *three.NewVector3(0, 0, 0), *three.NewVector3(0, 1, 0))
+	quaternion := three.NewQuaternion(0, 0, 0, 1)
+	quaternion.SetFromRotationMatrix(*matrix)
+	setTransform(camera, position, quaternion, three.NewVector3(1, 1, 1))
+	camera["aspect"] = 1
+	camera["far"] = 1000
+	camera["filmGauge"] = 35
+	camera["filmOffset"] = 0
+	camera["focus"] = 10
+	camera["fov"] = 50
+	camera["near"] = 0.1
+	camera["view"] = nil
+	camera["zoom"] = 1
+
+	scene := newObject3D("SceneSerializer", "Scene", sceneUUID, name, "", nil)
+	scene["children"] = bson.A{rootUUID}
+	scene["background"] = nil
+	scene["fog"] = nil
+	scene["overrideMaterial"] = nil
+	scene["userData"] = bson.M{
+		"children": bson.A{
+			bson.M{
+				"uuid":     rootUUID,
+				"children": children,
+			},
+		},
+	}
+
+	options := bson.M{
+		"metadata": bson.M{
+			"generator": "OptionsSerializer",
+		},
+		"saveChild":    true,
+		"saveMaterial": true,
+	}
+
+	return append([]interface{}{options, camera, scene, root}, g.docs...)
+}
+
+// addNode adds the objects that GLTFLoader creates for a node, and returns the
+// hierarchy of their uuids. A node with a mesh, a camera or a light is that object,
+// and a node with more than one of them is a group of them.
+func (g *gltfImporter) addNode(index int, parent string) bson.M {
+	g.visited[index] = true
+	node := g.doc.Nodes[index]
+	uuid := three.GenerateUUID()
+	entry := newChild(uuid)
+
+	light, hasLight := g.lightOf(node)
+	mesh := -1
+	if node.Mesh != nil && *node.Mesh >= 0 && *node.Mesh < len(g.doc.Meshes) {
+		mesh = *node.Mesh
+	}
+	count := 0
+	if mesh >= 0 {
+		count++
+	}
+	if node.Camera != nil {
+		count++
+	}
+	if hasLight {
+		count++
+	}
+
+	switch {
+	case count > 1:
+		doc := newObject3D("GroupSerializer", "Group", uuid, node.Name, parent, &node)
+		attachments := bson.A{}
+		if mesh >= 0 {
+			attachments = append(attachments, g.addMesh(mesh, three.GenerateUUID(), uuid, nil))
+		}
+		if node.Camera != nil {
+			attachments = append(attachments, newChild(three.GenerateUUID()))
+		}
+		if hasLight {
+			attachments = append(attachments, newLight(light))
+		}
+		entry["children"] = attachments
+		doc["children"] = childUUIDs(attachments)
+		g.docs = append(g.docs, doc)
+	case mesh >= 0:
+		entry = g.addMesh(mesh, uuid, parent, &node)
+	case count == 1: // camera or light, they are loaded from the file.
+		if hasLight {
+			entry = newLight(light)
+			entry["uuid"] = uuid
+		}
+	default:
+		g.docs = append(g.docs, newObject3D("Object3DSerializer", "Object3D", uuid, node.Name, parent, &node))
+	}
+
+	children := entry["children"].(bson.A)
+	for _, child := range node.Children {
+		if child >= 0 && child < len(g.doc.Nodes) && !g.visited[child] {
+			children = append(children, g.addNode(child, uuid))
+		}
+	}
+	entry["children"] = children
+	g.setChildren(uuid, children)
+
+	return entry
+}
+
+// addMesh adds the objects of a mesh. A mesh with one primitive is a three.js mesh,
+// and a mesh with more primitives is a group of meshes. node is nil when the mesh is
+// not the node itself.
+func (g *gltfImporter) addMesh(index int, uuid, parent string, node *helper.GLTFNode) bson.M {
+	mesh := g.doc.Meshes[index]
+	name := mesh.Name
+	if name == "" {
+		name = fmt.Sprintf("mesh_%v", index)
+	}
+	if node != nil && node.Name != "" {
+		name = node.Name
+	}
+
+	if len(mesh.Primitives) == 1 {
+		g.docs = append(g.docs, g.newPrimitive(mesh.Primitives[0], uuid, parent, name, node))
+		return newChild(uuid)
+	}
+
+	group := newObject3D("GroupSerializer", "Group", uuid, name, parent, node)
+	children := bson.A{}
+	g.docs = append(g.docs, group)
+	for _, primitive := range mesh.Primitives {
+		child := three.GenerateUUID()
+		g.docs = append(g.docs, g.newPrimitive(primitive, child, uuid, name, nil))
+		children = append(children, newChild(child))
+	}
+	group["children"] = childUUIDs(children)
+
+	entry := newChild(uuid)
+	entry["children"] = children
+	return entry
+}
+
+// newPrimitive returns the doc of a mesh primitive. Lines and points are saved as
+// Object3D, so that their materials are not replaced by mesh materials.
+func (g *gltfImporter) newPrimitive(primitive helper.GLTFPrimitive, uuid, parent, name string, node *helper.GLTFNode) bson.M {
+	mode := 4 // TRIANGLES
+	if primitive.Mode != nil {
+		mode = *primitive.Mode
+	}
+
+	switch mode {
+	case 0:
+		return newObject3D("Object3DSerializer", "Points", uuid, name, parent, node)
+	case 1:
+		return newObject3D("Object3DSerializer", "LineSegments", uuid, name, parent, node)
+	case 2:
+		return newObject3D("Object3DSerializer", "LineLoop", uuid, name, parent, node)
+	case 3:
+		return newObject3D("Object3DSerializer", "Line", uuid, name, parent, node)
+	}
+
+	doc := newObject3D("MeshSerializer", "Mesh", uuid, name, parent, node)
+	doc["drawMode"] = 0
+	doc["geometry"] = bson.M{
+		"metadata": bson.M{
+			"generator": "BufferGeometrySerializer",
+		},
+		"type":            "BufferGeometry",
+		"uuid":            three.GenerateUUID(),
+		"name":            "",
+		"groups":          bson.A{},
+		"morphAttributes": bson.M{},
+		"userData":        bson.M{},
+	}
+	doc["material"] = g.newMaterial(primitive.Material)
+	return doc
+}
+
+// newMaterial returns the doc of a glTF material. GLTFLoader uses MeshStandardMaterial,
+// and MeshBasicMaterial for unlit materials.
+func (g *gltfImporter) newMaterial(index *int) bson.M {
+	material := helper.GLTFMaterial{}
+	if index != nil && *index >= 0 && *index < len(g.doc.Materials) {
+		material = g.doc.Materials[*index]
+	}
+	_, unlit := material.Extensions["KHR_materials_unlit"]
+
+	generator, materialType := "MeshStandardMaterialSerializer", "MeshStandardMaterial"
+	if unlit {
+		generator, materialType = "MeshBasicMaterialSerializer", "MeshBasicMaterial"
+	}
+
+	doc := bson.M{
+		"metadata": bson.M{
+			"generator": generator,
+		},
+		"type":                materialType,
+		"uuid":                three.GenerateUUID(),
+		"name":                material.Name,
+		"alphaTest":           0,
+		"blendDst":            205, // OneMinusSrcAlphaFactor
+		"blendDstAlpha":       nil,
+		"blendEquation":       100, // AddEquation
+		"blendEquationAlpha":  nil,
+		"blendSrc":            204, // SrcAlphaFactor
+		"blendSrcAlpha":       nil,
+		"blending":            1, // NormalBlending
+		"clipIntersection":    false,
+		"clipShadow":          false,
+		"clippingPlanes":      nil,
+		"colorWrite":          true,
+		"depthFunc":           3, // LessEqualDepth
+		"depthTest":           true,
+		"depthWrite":          true,
+		"dithering":           false,
+		"flatShading":         false,
+		"fog":                 true,
+		"lights":              !unlit,
+		"morphNormals":        false,
+		"morphTargets":        false,
+		"opacity":             1,
+		"polygonOffset":       false,
+		"polygonOffsetFactor": 0,
+		"polygonOffsetUnits":  0,
+		"precision":           nil,
+		"premultipliedAlpha":  false,
+		"shadowSide":          nil,
+		"side":                0, // FrontSide
+		"skinning":            false,
+		"transparent":         false,
+		"vertexColors":        false,
+		"visible":             true,
+		"wireframe":           false,
+		"wireframeLinecap":    "round",
+		"wireframeLinejoin":   "round",
+		"wireframeLinewidth":  1,
+		"userData":            bson.M{},
+		"alphaMap":            nil,
+		"aoMap":               nil,
+		"aoMapIntensity":      1,
+		"envMap":              nil,
+		"lightMap":            nil,
+		"lightMapIntensity":   1,
+		"map":                 nil,
+		"refractionRatio":     0.98,
+		"specularMap":         nil,
+	}
+	if !unlit {
+		doc["bumpMap"] = nil
+		doc["bumpScale"] = 1
+		doc["displacementMap"] = nil
+		doc["displacementBias"] = 0
+		doc["displacementScale"] = 1
+		doc["emissive"] = 0
+		doc["emissiveIntensity"] = 1
+		doc["emissiveMap"] = nil
+		doc["envMapIntensity"] = 1
+		doc["metalness"] = 1
+		doc["metalnessMap"] = nil
+		doc["normalMap"] = nil
+		doc["normalMapType"] = 0 // TangentSpaceNormalMap
+		doc["normalScale"] = bson.M{"x": 1, "y": 1}
+		doc["roughness"] = 1
+		doc["roughnessMap"] = nil
+	}
+
+	color := []float64{1, 1, 1, 1}
+	if pbr := material.PBRMetallicRoughness; pbr != nil {
+		if len(pbr.BaseColorFactor) == 4 {
+			color = pbr.BaseColorFactor
+		}
+		doc["map"] = g.newTexture(pbr.BaseColorTexture, true)
+		if !unlit {
+			if pbr.MetallicFactor != nil {
+				doc["metalness"] = *pbr.MetallicFactor
+			}
+			if pbr.RoughnessFactor != nil {
+				doc["roughness"] = *pbr.RoughnessFactor
+			}
+			// blue channel is metalness, and green channel is roughness.
+			doc["metalnessMap"] = g.newTexture(pbr.MetallicRoughnessTexture, false)
+			doc["roughnessMap"] = g.newTexture(pbr.MetallicRoughnessTexture, false)
+		}
+	}
+	doc["color"] = toHex(color)
+	doc["opacity"] = color[3]
+
+	switch material.AlphaMode {
+	case "BLEND":
+		doc["transparent"] = true
+		doc["depthWrite"] = false
+	case "MASK":
+		doc["alphaTest"] = 0.5
+		if material.AlphaCutoff != nil {
+			doc["alphaTest"] = *material.AlphaCutoff
+		}
+	}
+	if material.DoubleSided {
+		doc["side"] = 2 // DoubleSide
+	}
+
+	if !unlit {
+		doc["normalMap"] = g.newTexture(material.NormalTexture, false)
+		doc["aoMap"] = g.newTexture(material.OcclusionTexture, false)
+		doc["emissiveMap"] = g.newTexture(material.EmissiveTexture, true)
+		if len(material.EmissiveFactor) == 3 {
+			doc["emissive"] = toHex(material.EmissiveFactor)
+		}
+	}
+
+	return doc
+}
+
+// newTexture returns the doc of a texture, or nil when the texture is not found.
+// Colors of base color and emissive textures are in sRGB.
+func (g *gltfImporter) newTexture(info *helper.GLTFTextureInfo, sRGB bool) interface{} {
+	if info == nil || info.Index < 0 || info.Index >= len(g.doc.Textures) {
+		return nil
+	}
+	texture := g.doc.Textures[info.Index]
+	if texture.Source == nil || *texture.Source < 0 || *texture.Source >= len(g.images) {
+		return nil
+	}
+	img := g.images[*texture.Source]
+
+	sampler := helper.GLTFSampler{}
+	if texture.Sampler != nil && *texture.Sampler >= 0 && *texture.Sampler < len(g.doc.Samplers) {
+		sampler = g.doc.Samplers[*texture.Sampler]
+	}
+	mapValue := func(values map[int]int, key, def int) int {
+		if value, ok := values[key]; ok {
+			return value
+		}
+		return def
+	}
+
+	encoding := 3000 // LinearEncoding
+	if sRGB {
+		encoding = 3001 // sRGBEncoding
+	}
+
+	return bson.M{
+		"metadata": bson.M{
+			"generator": "TextureSerializer",
+		},
+		"uuid":            three.GenerateUUID(),
+		"name":            texture.Name,
+		"anisotropy":      1,
+		"center":          bson.M{"x": 0, "y": 0},
+		"encoding":        encoding,
+		"flipY":           false,
+		"format":          1023, // RGBAFormat
+		"generateMipmaps": true,
+		"image": bson.M{
+			"tagName": "img",
+			"src":     img.URL,
+			"width":   img.Width,
+			"height":  img.Height,
+		},
+		"isTexture":        true,
+		"magFilter":        mapValue(gltfFilters, sampler.MagFilter, 1006),
+		"mapping":          300, // UVMapping
+		"matrixAutoUpdate": true,
+		"minFilter":        mapValue(gltfFilters, sampler.MinFilter, 1008),
+		"mipmaps":          bson.A{},
+		"offset":           bson.M{"x": 0, "y": 0},
+		"premultiplyAlpha": false,
+		"repeat":           bson.M{"x": 1, "y": 1},
+		"rotation":         0,
+		"type":             1009, // UnsignedByteType
+		"unpackAlignment":  4,
+		"version":          0,
+		"wrapS":            mapValue(gltfWrapModes, sampler.WrapS, 1000),
+		"wrapT":            mapValue(gltfWrapModes, sampler.WrapT, 1000),
+	}
+}
+
+// lightOf returns the type of the KHR_lights_punctual light of a node.
+func (g *gltfImporter) lightOf(node helper.GLTFNode) (string, bool) {
+	ext, ok := node.Extensions["KHR_lights_punctual"].(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	index, ok := ext["light"].(float64)
+	if !ok {
+		return "", false
+	}
+	lights, _ := g.doc.Extensions["KHR_lights_punctual"].(map[string]interface{})
+	list, _ := lights["lights"].([]interface{})
+	if int(index) < 0 || int(index) >= len(list) {
+		return "", false
+	}
+	light, _ := list[int(index)].(map[string]interface{})
+	lightType, _ := light["type"].(string)
+	return lightType, true
+}
+
+// setChildren sets the children of the doc of an object.
+func (g *gltfImporter) setChildren(uuid string, children bson.A) {
+	for _, doc := range g.docs {
+		if doc.(bson.M)["uuid"] == uuid {
+			doc.(bson.M)["children"] = childUUIDs(children)
+			return
+		}
+	}
+}
+
+// newLight returns the hierarchy of a light loaded from the file. Directional lights
+// and spot lights have a target.
+func newLight(lightType string) bson.M {
+	entry := newChild(three.GenerateUUID())
+	if lightType == "directional" || lightType == "spot" {
+		entry["children"] = bson.A{newChild(three.GenerateUUID())}
+	}
+	return entry
+}
+
+// newChild returns an item of the hierarchy of a server object.
+func newChild(uuid string) bson.M {
+	return bson.M{
+		"uuid":     uuid,
+		"children": bson.A{},
+	}
+}
+
+// childUUIDs returns the uuids of the items of a hierarchy.
+func childUUIDs(children bson.A) bson.A {
+	uuids := bson.A{}
+	for _, child := range children {
+		uuids = append(uuids, child.(bson.M)["uuid"])
+	}
+	return uuids
+}
+
+// newObject3D returns the doc of an object, and its transform is read from the node.
+func newObject3D(generator, objectType, uuid, name, parent string, node *helper.GLTFNode) bson.M {
+	doc := bson.M{
+		"metadata": bson.M{
+			"generator": generator,
+			"type":      "Object",
+			"version":   "0.0.1",
+		},
+		"castShadow":       false,
+		"children":         bson.A{},
+		"frustumCulled":    true,
+		"isObject3D":       true,
+		"layers":           bson.M{"mask": 1},
+		"matrixAutoUpdate": true,
+		"name":             name,
+		"parent":           parent,
+		"receiveShadow":    false,
+		"renderOrder":      0,
+		"type":             objectType,
+		"up":               bson.M{"x": 0, "y": 1, "z": 0},
+		"userData":         bson.M{},
+		"uuid":             uuid,
+		"visible":          true,
+	}
+
+	position := three.NewVector3(0, 0, 0)
+	quaternion := three.NewQuaternion(0, 0, 0, 1)
+	scale := three.NewVector3(1, 1, 1)
+	if node != nil {
+		if len(node.Matrix) == 16 {
+			three.NewMatrix4().FromArray(node.Matrix, 0).Decompose(position, quaternion, scale)
+		} else {
+			if len(node.Translation) == 3 {
+				position.Set(node.Translation[0], node.Translation[1], node.Translation[2])
+			}
+			if len(node.Rotation) == 4 {
+				quaternion.Set(node.Rotation[0], node.Rotation[1], node.Rotation[2], node.Rotation[3])
+			}
+			if len(node.Scale) == 3 {
+				scale.Set(node.Scale[0], node.Scale[1], node.Scale[2])
+			}
+		}
+	}
+	setTransform(doc, position, quaternion, scale)
+
+	return doc
+}
+
+// setTransform sets the position, rotation, quaternion and scale of an object.
+func setTransform(doc bson.M, position *three.Vector3, quaternion *three.Quaternion, scale *three.Vector3) {
+	rotation := three.NewEuler(0, 0, 0, "XYZ")
+	rotation.SetFromQuaternion(*quaternion, "XYZ", false)
+
+	doc["position"] = bson.M{"x": position.X, "y": position.Y, "z": position.Z}
+	doc["quaternion"] = bson.M{"x": quaternion.X(), "y": quaternion.Y(), "z": quaternion.Z(), "w": quaternion.W()}
+	doc["rotation"] = bson.M{"x": rotation.X(), "y": rotation.Y(), "z": rotation.Z(), "order": "XYZ"}
+	doc["scale"] = bson.M{"x": scale.X, "y": scale.Y, "z": scale.Z}
+}
+
+// toHex converts `[r, g, b]` from 0 to 1 to a hex color, such as 0xffffff.
+func toHex(color []float64) int {
+	hex := 0
+	for _, c := range color[:3] {
+		value := int(c*255 + 0.5)
+		if value < 0 {
+			value = 0
+		} else if value > 255 {
+			value = 255
+		}
+		hex = hex<<8 | value
+	}
+	return hex
+}
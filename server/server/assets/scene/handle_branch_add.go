@@ -0,0 +1,158 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package scene
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/tengge1/shadoweditor/helper"
+	"github.com/tengge1/shadoweditor/server"
+)
+
+func init() {
+	server.Handle(http.MethodPost, "/api/Scene/BranchAdd", BranchAdd, server.SaveScene)
+}
+
+// BranchAdd fork a scene version into a new branch. Version is a version number or
+// a tag name, and the latest version is forked when it is empty.
+func BranchAdd(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	id, err := primitive.ObjectIDFromHex(strings.TrimSpace(r.FormValue("ID")))
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "ID is not allowed.",
+		})
+		return
+	}
+	name := strings.TrimSpace(r.FormValue("Name"))
+	if name == "" {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "Name is not allowed to be empty.",
+		})
+		return
+	}
+
+	db, err := server.DB()
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	doc := bson.M{}
+	find, _ := db.FindOne(server.SceneCollectionName, bson.M{"ID": id}, &doc)
+
+	if !find {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "The scene is not existed!",
+		})
+		return
+	}
+
+	if !canModify(r, doc) {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "Permission denied.",
+		})
+		return
+	}
+
+	if _, find, _ := findBranch(db, id, name); find {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "The name is already existed.",
+		})
+		return
+	}
+
+	version, err := server.ParseSceneVersion(db, id, r.FormValue("Version"))
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+	if version == -1 {
+		version = 0
+		if doc["Version"] != nil {
+			version = int(doc["Version"].(int32))
+		}
+	}
+
+	docs, err := server.LoadSceneVersion(db, doc, version)
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	now := time.Now()
+	collectionName, err := newBranchCollectionName(db, now)
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	userID := ""
+	if server.Config.Authority.Enabled {
+		user, _ := server.GetCurrentUser(r)
+
+		if user != nil {
+			userID = user.ID
+		}
+	}
+
+	branch := bson.M{
+		"ID":             primitive.NewObjectID(),
+		"SceneID":        id,
+		"Name":           name,
+		"CollectionName": collectionName,
+		"Version":        0,
+		"ForkVersion":    version,
+		"MergeBase":      0,
+		"CreateTime":     now,
+		"UpdateTime":     now,
+	}
+	if userID != "" {
+		branch["UserID"] = userID
+		branch["UpdateUserID"] = userID
+	}
+	db.InsertOne(server.SceneBranchCollectionName, branch)
+
+	data := []interface{}{}
+	for _, i := range docs {
+		delete(i, "_id")
+		delete(i, server.VersionField)
+		data = append(data, i)
+	}
+	db.DeleteAll(collectionName)
+	if len(data) > 0 {
+		db.InsertMany(collectionName, data)
+	}
+
+	helper.WriteJSON(w, server.Result{
+		Code: 200,
+		Msg:  "Saved successfully!",
+	})
+}
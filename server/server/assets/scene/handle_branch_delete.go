@@ -0,0 +1,87 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package scene
+
+import (
+	"net/http"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/tengge1/shadoweditor/helper"
+	"github.com/tengge1/shadoweditor/server"
+)
+
+func init() {
+	server.Handle(http.MethodPost, "/api/Scene/BranchDelete", BranchDelete, server.SaveScene)
+}
+
+// BranchDelete delete a branch of a scene and its data.
+func BranchDelete(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	id, err := primitive.ObjectIDFromHex(strings.TrimSpace(r.FormValue("ID")))
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "ID is not allowed.",
+		})
+		return
+	}
+	name := strings.TrimSpace(r.FormValue("Name"))
+
+	db, err := server.DB()
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	doc := bson.M{}
+	find, _ := db.FindOne(server.SceneCollectionName, bson.M{"ID": id}, &doc)
+
+	if !find {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "The scene is not existed!",
+		})
+		return
+	}
+
+	if !canModify(r, doc) {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "Permission denied.",
+		})
+		return
+	}
+
+	branch, find, _ := findBranch(db, id, name)
+	if !find {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "The branch is not existed!",
+		})
+		return
+	}
+
+	if err := deleteBranch(db, branch); err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	helper.WriteJSON(w, server.Result{
+		Code: 200,
+		Msg:  "Delete successfully!",
+	})
+}
@@ -0,0 +1,100 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package scene
+
+import (
+	"net/http"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/tengge1/shadoweditor/helper"
+	"github.com/tengge1/shadoweditor/server"
+)
+
+func init() {
+	server.Handle(http.MethodGet, "/api/Scene/BranchList", BranchList, server.None)
+}
+
+// BranchList returns the branches of a scene.
+func BranchList(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	id, err := primitive.ObjectIDFromHex(strings.TrimSpace(r.FormValue("ID")))
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "ID is not allowed.",
+		})
+		return
+	}
+
+	db, err := server.DB()
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	filter := bson.M{
+		"SceneID": id,
+	}
+	opts := options.FindOptions{
+		Sort: bson.M{
+			"CreateTime": 1,
+		},
+	}
+	docs := []bson.M{}
+	if err := db.FindMany(server.SceneBranchCollectionName, filter, &docs, &opts); err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	list := []BranchModel{}
+	for _, doc := range docs {
+		branch := BranchModel{
+			ID:      doc["ID"].(primitive.ObjectID).Hex(),
+			SceneID: id.Hex(),
+		}
+		branch.Name, _ = doc["Name"].(string)
+		branch.CollectionName, _ = doc["CollectionName"].(string)
+		if version, ok := doc["Version"].(int32); ok {
+			branch.Version = int(version)
+		}
+		if forkVersion, ok := doc["ForkVersion"].(int32); ok {
+			branch.ForkVersion = int(forkVersion)
+		}
+		if mergeBase, ok := doc["MergeBase"].(int32); ok {
+			branch.MergeBase = int(mergeBase)
+		}
+		if createTime, ok := doc["CreateTime"].(primitive.DateTime); ok {
+			branch.CreateTime = createTime.Time()
+		}
+		if updateTime, ok := doc["UpdateTime"].(primitive.DateTime); ok {
+			branch.UpdateTime = updateTime.Time()
+		}
+		if userID, ok := doc["UserID"].(string); ok && userID != "" {
+			if user, _ := server.GetUser(userID); user != nil {
+				branch.Username = user.Username
+			}
+		}
+		list = append(list, branch)
+	}
+
+	helper.WriteJSON(w, server.Result{
+		Code: 200,
+		Msg:  "Get Successfully!",
+		Data: list,
+	})
+}
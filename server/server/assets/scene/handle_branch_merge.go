@@ -0,0 +1,191 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package scene
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/tengge1/shadoweditor/helper"
+	"github.com/tengge1/shadoweditor/server"
+)
+
+func init() {
+	server.Handle(http.MethodPost, "/api/Scene/BranchMerge", BranchMerge, server.SaveScene)
+}
+
+// BranchMerge merge a branch back into its scene with a three-way merge against the
+// last common version. When both sides changed the same field differently, nothing
+// is saved and the conflicts are returned with code 302.
+func BranchMerge(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	id, err := primitive.ObjectIDFromHex(strings.TrimSpace(r.FormValue("ID")))
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "ID is not allowed.",
+		})
+		return
+	}
+	name := strings.TrimSpace(r.FormValue("Name"))
+
+	db, err := server.DB()
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	filter := bson.M{
+		"ID": id,
+	}
+	doc := bson.M{}
+	find, _ := db.FindOne(server.SceneCollectionName, filter, &doc)
+
+	if !find {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "The scene is not existed!",
+		})
+		return
+	}
+
+	if !canModify(r, doc) {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "Permission denied.",
+		})
+		return
+	}
+	if err := checkLock(r, doc); err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	branch, find, _ := findBranch(db, id, name)
+	if !find {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "The branch is not existed!",
+		})
+		return
+	}
+
+	mergeBase := 0
+	if branch["MergeBase"] != nil {
+		mergeBase = int(branch["MergeBase"].(int32))
+	}
+	branchVersion := 0
+	if branch["Version"] != nil {
+		branchVersion = int(branch["Version"].(int32))
+	}
+
+	base, err := server.LoadSceneVersion(db, branch, mergeBase)
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+	theirs, err := server.LoadSceneVersion(db, branch, -1)
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+	ours, err := server.LoadSceneVersion(db, doc, -1)
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	merged, conflicts := mergeScene(base, ours, theirs)
+
+	result := mergeResult{}
+	result.ID = id.Hex()
+	result.Conflicts = conflicts
+
+	if len(conflicts) > 0 {
+		result.Code = 302
+		result.Msg = "There are conflicts between the scene and the branch."
+		if version, ok := doc["Version"].(int32); ok {
+			result.Version = int(version)
+		}
+		helper.WriteJSON(w, result)
+		return
+	}
+
+	userID := ""
+	if server.Config.Authority.Enabled {
+		user, _ := server.GetCurrentUser(r)
+
+		if user != nil {
+			userID = user.ID
+		}
+	}
+
+	data := []interface{}{}
+	for _, i := range merged {
+		data = append(data, i)
+	}
+
+	version, err := saveData(db, server.SceneCollectionName, doc, data, userID)
+	if err == errConflict {
+		doc = bson.M{}
+		db.FindOne(server.SceneCollectionName, filter, &doc)
+		writeConflict(w, doc)
+		return
+	}
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	// the merged branch version is the new common version of the scene and the branch.
+	update := bson.M{
+		"$set": bson.M{
+			"MergeBase":  branchVersion,
+			"UpdateTime": time.Now(),
+		},
+	}
+	db.UpdateOne(server.SceneBranchCollectionName, bson.M{"ID": branch["ID"]}, update)
+
+	updateThumbnail(db, id)
+
+	result.Code = 200
+	result.Msg = "Merged successfully!"
+	result.Version = version
+
+	helper.WriteJSON(w, result)
+}
+
+// mergeResult is the result of merging a branch.
+type mergeResult struct {
+	server.Result
+	ID        string
+	Version   int
+	Conflicts []ConflictModel
+}
@@ -0,0 +1,105 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package scene
+
+import (
+	"net/http"
+
+	"github.com/dimfeld/httptreemux"
+	"github.com/gorilla/websocket"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/tengge1/shadoweditor/helper"
+	"github.com/tengge1/shadoweditor/server"
+)
+
+// upgrader upgrades http connections to websocket. It only accepts requests from
+// the same origin, because the browser sends the token cookie with websocket
+// requests from any site.
+var upgrader = websocket.Upgrader{}
+
+func init() {
+	server.Handle(http.MethodGet, "/api/Scene/Collaborate/:ID", Collaborate, server.SaveScene)
+}
+
+// Collaborate edit a scene with others over websocket. See collaborateMessage for
+// the messages.
+func Collaborate(w http.ResponseWriter, r *http.Request) {
+	params := httptreemux.ContextParams(r.Context())
+	id, err := primitive.ObjectIDFromHex(params["ID"])
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "ID is not allowed.",
+		})
+		return
+	}
+
+	db, err := server.DB()
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	doc := bson.M{}
+	find, _ := db.FindOne(server.SceneCollectionName, bson.M{"ID": id}, &doc)
+
+	if !find {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "The scene is not existed!",
+		})
+		return
+	}
+
+	if !canCollaborate(r, doc) {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "Permission denied.",
+		})
+		return
+	}
+
+	if err := checkLock(r, doc); err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	c := &collaborator{
+		send: make(chan []byte, 256),
+	}
+	c.Username = "Guest"
+	if user, _ := server.GetCurrentUser(r); user != nil {
+		c.UserID = user.ID
+		c.Username = user.Username
+	}
+
+	// the upgrader writes the http error response itself.
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	c.conn = conn
+
+	room, err := joinRoom(id, c)
+	if err != nil {
+		conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseInternalServerErr, err.Error()))
+		conn.Close()
+		return
+	}
+
+	go c.write()
+	c.read(room)
+}
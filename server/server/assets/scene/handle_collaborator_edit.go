@@ -0,0 +1,102 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package scene
+
+import (
+	"net/http"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/tengge1/shadoweditor/helper"
+	"github.com/tengge1/shadoweditor/server"
+)
+
+func init() {
+	server.Handle(http.MethodPost, "/api/Scene/CollaboratorEdit", CollaboratorEdit, server.SaveScene)
+}
+
+// CollaboratorEdit set the users who can co-edit a scene besides its owner.
+// Collaborators is user IDs separated by commas, and empty removes all collaborators.
+func CollaboratorEdit(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	id, err := primitive.ObjectIDFromHex(strings.TrimSpace(r.FormValue("ID")))
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "ID is not allowed.",
+		})
+		return
+	}
+
+	collaborators := []string{}
+	for _, userID := range strings.Split(r.FormValue("Collaborators"), ",") {
+		userID = strings.TrimSpace(userID)
+		if userID == "" {
+			continue
+		}
+		if user, _ := server.GetUser(userID); user == nil {
+			helper.WriteJSON(w, server.Result{
+				Code: 300,
+				Msg:  "The user " + userID + " is not existed!",
+			})
+			return
+		}
+		collaborators = append(collaborators, userID)
+	}
+
+	db, err := server.DB()
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	filter := bson.M{
+		"ID": id,
+	}
+	doc := bson.M{}
+	find, _ := db.FindOne(server.SceneCollectionName, filter, &doc)
+
+	if !find {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "The scene is not existed!",
+		})
+		return
+	}
+
+	if !canModify(r, doc) {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "Permission denied.",
+		})
+		return
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"Collaborators": collaborators,
+		},
+	}
+	if _, err := db.UpdateOne(server.SceneCollectionName, filter, update); err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	helper.WriteJSON(w, server.Result{
+		Code: 200,
+		Msg:  "Saved successfully!",
+	})
+}
@@ -0,0 +1,176 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package scene
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/tengge1/shadoweditor/helper"
+	"github.com/tengge1/shadoweditor/server"
+	"github.com/tengge1/shadoweditor/server/thumbnail"
+)
+
+func init() {
+	server.Handle(http.MethodPost, "/api/Scene/Copy", Copy, server.SaveScene)
+}
+
+// Copy clones a scene to a new scene that belongs to the current user. Version is a
+// version number or a tag name, and the latest version is copied when it is empty.
+// The new scene is named Name, or the name of the scene when it is empty. Users can
+// copy their own scenes, public scenes and templates.
+func Copy(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	id, err := primitive.ObjectIDFromHex(strings.TrimSpace(r.FormValue("ID")))
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "ID is not allowed.",
+		})
+		return
+	}
+
+	db, err := server.DB()
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	source := bson.M{}
+	find, _ := db.FindOne(server.SceneCollectionName, bson.M{"ID": id}, &source)
+
+	if !find {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "The scene is not existed!",
+		})
+		return
+	}
+
+	isPublic, _ := source["IsPublic"].(bool)
+	isTemplate, _ := source["IsTemplate"].(bool)
+	if !isPublic && !isTemplate && !canModify(r, source) {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "Permission denied.",
+		})
+		return
+	}
+
+	name := strings.TrimSpace(r.FormValue("Name"))
+	if name == "" {
+		name = source["Name"].(string)
+	}
+	if strings.HasPrefix(name, "_") {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "Name is not allowed to start with _.",
+		})
+		return
+	}
+
+	version, err := server.ParseSceneVersion(db, id, r.FormValue("Version"))
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	docs, err := server.LoadSceneVersion(db, source, version)
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	now := time.Now()
+	collectionName, err := newCollectionName(db, now)
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	userID := ""
+	if server.Config.Authority.Enabled {
+		user, _ := server.GetCurrentUser(r)
+
+		if user != nil {
+			userID = user.ID
+		}
+	}
+
+	newID := primitive.NewObjectID()
+	pinyin := helper.ConvertToPinYin(name)
+	doc := bson.M{
+		"ID":             newID,
+		"Name":           name,
+		"TotalPinYin":    pinyin.TotalPinYin,
+		"FirstPinYin":    pinyin.FirstPinYin,
+		"CollectionName": collectionName,
+		"Version":        0,
+		"CreateTime":     now,
+		"UpdateTime":     now,
+		"IsPublic":       false,
+	}
+	// rendered thumbnails are deleted when the source is rendered again, so the copy
+	// renders its own.
+	if url, ok := source["Thumbnail"].(string); ok && !thumbnail.IsRendered(url) {
+		doc["Thumbnail"] = url
+	}
+	if category, ok := source["Category"].(string); ok {
+		doc["Category"] = category
+	}
+	if userID != "" {
+		doc["UserID"] = userID
+		doc["UpdateUserID"] = userID
+	}
+
+	list := []interface{}{}
+	for _, item := range docs {
+		// remove _id; otherwise deplicated
+		delete(item, "_id")
+		delete(item, server.VersionField)
+		list = append(list, item)
+	}
+
+	db.DeleteAll(collectionName)
+	if len(list) > 0 {
+		if _, err := db.InsertMany(collectionName, list); err != nil {
+			helper.WriteJSON(w, server.Result{
+				Code: 300,
+				Msg:  err.Error(),
+			})
+			return
+		}
+	}
+	db.InsertOne(server.SceneCollectionName, doc)
+
+	updateThumbnail(db, newID)
+
+	result := saveResult{}
+	result.Code = 200
+	result.Msg = "Copy successfully!"
+	result.ID = newID.Hex()
+	result.Version = 0
+
+	helper.WriteJSON(w, result)
+}
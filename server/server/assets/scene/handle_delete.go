@@ -33,7 +33,7 @@ func Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	db, err := server.Mongo()
+	db, err := server.DB()
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
@@ -57,6 +57,14 @@ func Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := checkLock(r, doc); err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
 	// delete scene summary
 	db.DeleteOne(server.SceneCollectionName, filter)
 
@@ -65,6 +73,15 @@ func Delete(w http.ResponseWriter, r *http.Request) {
 	// delete scene data
 	db.DropCollection(collectionName)
 
+	// delete scene tags
+	db.DeleteMany(server.SceneTagCollectionName, bson.M{"SceneID": id})
+
+	// delete scene branches
+	deleteBranches(db, id)
+
+	// delete published snapshots
+	deletePublishes(db, id)
+
 	helper.WriteJSON(w, server.Result{
 		Code: 200,
 		Msg:  "Delete successfully!",
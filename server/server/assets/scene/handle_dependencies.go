@@ -0,0 +1,122 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package scene
+
+import (
+	"net/http"
+	"os"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/tengge1/shadoweditor/helper"
+	"github.com/tengge1/shadoweditor/server"
+)
+
+func init() {
+	server.Handle(http.MethodGet, "/api/Scene/Dependencies", Dependencies, server.None)
+}
+
+// Dependencies returns the asset files referenced by a scene, and whether they still
+// exist. Version is a version number or a tag name.
+func Dependencies(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	id, err := primitive.ObjectIDFromHex(strings.TrimSpace(r.FormValue("ID")))
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "ID is not allowed.",
+		})
+		return
+	}
+
+	db, err := server.DB()
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	filter := bson.M{
+		"ID": id,
+	}
+	doc := bson.M{}
+	find, _ := db.FindOne(server.SceneCollectionName, filter, &doc)
+	if !find {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "The scene is not existed!",
+		})
+		return
+	}
+
+	version, err := server.ParseSceneVersion(db, id, r.FormValue("Version"))
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	docs, err := server.LoadSceneVersion(db, doc, version)
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	assets, err := server.LoadAssets(db)
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	list := []DependencyModel{}
+	for _, url := range server.SceneURLs(docs) {
+		list = append(list, newDependency(assets, url))
+	}
+
+	helper.WriteJSON(w, server.Result{
+		Code: 200,
+		Msg:  "Get Successfully!",
+		Data: list,
+	})
+}
+
+// newDependency finds the asset that owns the url, and checks the file.
+func newDependency(assets map[string][]bson.M, url string) DependencyModel {
+	dependency := DependencyModel{
+		URL: url,
+	}
+
+	if collectionName, asset, ok := server.FindAsset(assets, url); ok {
+		dependency.CollectionName = collectionName
+		if id, ok := asset["ID"].(primitive.ObjectID); ok {
+			dependency.ID = id.Hex()
+		} else if id, ok := asset["_id"].(primitive.ObjectID); ok {
+			dependency.ID = id.Hex()
+		}
+		dependency.Name, _ = asset["Name"].(string)
+	}
+
+	if stat, err := os.Stat(server.MapPath(url)); err == nil && !stat.IsDir() {
+		dependency.Size = stat.Size()
+		dependency.Exists = true
+	}
+
+	return dependency
+}
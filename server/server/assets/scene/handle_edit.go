@@ -22,7 +22,8 @@ func init() {
 	server.Handle(http.MethodPost, "/api/Scene/Edit", Edit, server.EditScene)
 }
 
-// Edit change name, category and thumbnail of a scene.
+// Edit change name, category and thumbnail of a scene. IsTemplate adds the scene to the
+// template list or removes it, and only administrators can change it.
 func Edit(w http.ResponseWriter, r *http.Request) {
 	r.ParseForm()
 	id, err := primitive.ObjectIDFromHex(strings.TrimSpace(r.FormValue("ID")))
@@ -52,8 +53,9 @@ func Edit(w http.ResponseWriter, r *http.Request) {
 	thumbnail := strings.TrimSpace(r.FormValue("Image"))
 	category := strings.TrimSpace(r.FormValue("Category"))
 	isPublic := strings.TrimSpace(r.FormValue("IsPublic"))
+	isTemplate := strings.TrimSpace(r.FormValue("IsTemplate"))
 
-	db, err := server.Mongo()
+	db, err := server.DB()
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
@@ -96,6 +98,15 @@ func Edit(w http.ResponseWriter, r *http.Request) {
 			})
 			return
 		}
+
+		// templates are curated by administrators
+		if isTemplate != "" && user.RoleName != "Administrator" {
+			helper.WriteJSON(w, server.Result{
+				Code: 300,
+				Msg:  "Permission denied.",
+			})
+			return
+		}
 	}
 
 	pinyin := helper.ConvertToPinYin(name)
@@ -110,6 +121,9 @@ func Edit(w http.ResponseWriter, r *http.Request) {
 		"Thumbnail":   thumbnail,
 		"IsPublic":    isPublic == "true",
 	}
+	if isTemplate != "" {
+		set["IsTemplate"] = isTemplate == "true"
+	}
 	update := bson.M{
 		"$set": set,
 	}
@@ -25,7 +25,8 @@ func init() {
 	server.Handle(http.MethodGet, "/api/Scene/HistoryList", HistoryList, server.None)
 }
 
-// HistoryList returns scene history list.
+// HistoryList returns scene history list. When Version is provided, it is a
+// version number or a tag name, and only that version is returned.
 func HistoryList(w http.ResponseWriter, r *http.Request) {
 	r.ParseForm()
 	id, err := primitive.ObjectIDFromHex(strings.TrimSpace(r.FormValue("ID")))
@@ -36,7 +37,7 @@ func HistoryList(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
-	db, err := server.Mongo()
+	db, err := server.DB()
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
@@ -59,28 +60,52 @@ func HistoryList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// version is a version number or a tag name
+	version, err := server.ParseSceneVersion(db, id, r.FormValue("Version"))
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	tags, err := getTags(db, id)
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+	versionTags := map[int][]TagModel{}
+	for _, tag := range tags {
+		versionTags[tag.Version] = append(versionTags[tag.Version], tag)
+	}
+
 	list := []HistoryModel{}
 
 	sceneID := doc["ID"].(primitive.ObjectID).Hex()
 	name := doc["CollectionName"].(string)
-	version := doc["Version"].(int32)
+	current := int(doc["Version"].(int32))
 	createTime := doc["CreateTime"].(primitive.DateTime).Time()
 
 	list = append(list, HistoryModel{
 		ID:         doc["_id"].(primitive.ObjectID).Hex(),
 		SceneID:    sceneID,
 		SceneName:  name,
-		Version:    int(version),
+		Version:    current,
 		IsNew:      true,
 		CreateTime: createTime,
 		UpdateTime: doc["UpdateTime"].(primitive.DateTime).Time(),
+		Tags:       versionTags[current],
 	})
 
 	// history versions
 	historyName := fmt.Sprintf("%v%v", name, server.HistorySuffix)
-	historyCollection, _ := db.GetCollection(historyName)
+	historyExists, _ := db.CollectionExists(historyName)
 
-	if historyCollection != nil {
+	if historyExists {
 		filter1 := bson.M{
 			"metadata.generator": "OptionsSerializer",
 		}
@@ -95,19 +120,36 @@ func HistoryList(w http.ResponseWriter, r *http.Request) {
 		for _, i := range docs1 {
 			n := i.(primitive.D).Map()
 			historyID := n["_id"].(primitive.ObjectID)
+			historyVersion := int(n["_version"].(int32))
+			// history saved before the version time was recorded
+			updateTime := historyID.Timestamp()
+			if t, ok := n[server.VersionTimeField].(primitive.DateTime); ok {
+				updateTime = t.Time()
+			}
 
 			list = append(list, HistoryModel{
 				ID:         historyID.Hex(),
 				SceneID:    sceneID,
 				SceneName:  name,
-				Version:    int(n["_version"].(int32)),
+				Version:    historyVersion,
 				IsNew:      false,
 				CreateTime: createTime,
-				UpdateTime: historyID.Timestamp(),
+				UpdateTime: updateTime,
+				Tags:       versionTags[historyVersion],
 			})
 		}
 	}
 
+	if version != -1 {
+		filtered := []HistoryModel{}
+		for _, item := range list {
+			if item.Version == version {
+				filtered = append(filtered, item)
+			}
+		}
+		list = filtered
+	}
+
 	helper.WriteJSON(w, server.Result{
 		Code: 200,
 		Msg:  "Get Successfully!",
@@ -131,4 +173,6 @@ type HistoryModel struct {
 	CreateTime time.Time
 	// Update Time
 	UpdateTime time.Time
+	// Named tags of the version
+	Tags []TagModel
 }
@@ -0,0 +1,289 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package scene
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/tengge1/shadoweditor/helper"
+	"github.com/tengge1/shadoweditor/server"
+	"github.com/tengge1/shadoweditor/server/assets/mesh"
+	"github.com/tengge1/shadoweditor/server/assets/texture"
+	"github.com/tengge1/shadoweditor/three"
+)
+
+func init() {
+	server.Handle(http.MethodPost, "/api/Scene/Import", Import, server.SaveScene)
+}
+
+// Import creates a scene from a .glb file or a self-contained .gltf file. The file is
+// added to the meshes and its images to the textures, and the nodes, meshes and
+// materials are saved as scene objects, so that they can be edited one by one.
+func Import(w http.ResponseWriter, r *http.Request) {
+	r.ParseMultipartForm(server.Config.Upload.MaxSize)
+	if r.MultipartForm == nil || len(r.MultipartForm.File["file"]) != 1 {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "Please select an file.",
+		})
+		return
+	}
+
+	file := r.MultipartForm.File["file"][0]
+	fileName := filepath.Base(file.Filename)
+	fileExt := filepath.Ext(fileName)
+	fileNameWithoutExt := strings.TrimSuffix(fileName, fileExt)
+
+	meshType := mesh.Unknown
+	switch strings.ToLower(fileExt) {
+	case ".glb":
+		meshType = mesh.Glb
+	case ".gltf":
+		meshType = mesh.Gltf
+	default:
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "Only glb and gltf file is allowed!",
+		})
+		return
+	}
+
+	name := strings.TrimSpace(r.FormValue("Name"))
+	if name == "" {
+		name = fileNameWithoutExt
+	}
+	if strings.HasPrefix(name, "_") {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "Name is not allowed to start with _.",
+		})
+		return
+	}
+
+	source, err := file.Open()
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+	defer source.Close()
+
+	data, err := ioutil.ReadAll(source)
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	gltf, images, err := readGLTF(fileName, data)
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	db, err := server.DB()
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	userID := ""
+	if server.Config.Authority.Enabled {
+		user, _ := server.GetCurrentUser(r)
+
+		if user != nil {
+			userID = user.ID
+		}
+	}
+
+	now := time.Now()
+
+	// rollbacks remove what has been added when the import fails.
+	rollbacks := []func(){}
+	fail := func(err error) {
+		for i := len(rollbacks) - 1; i >= 0; i-- {
+			rollbacks[i]()
+		}
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+	}
+	addAsset := func(collectionName string, doc bson.M) error {
+		if _, err := db.InsertOne(collectionName, doc); err != nil {
+			server.RemoveAssetFiles(db, doc)
+			return err
+		}
+		rollbacks = append(rollbacks, func() {
+			db.DeleteOne(collectionName, bson.M{"ID": doc["ID"]})
+			server.RemoveAssetFiles(db, doc)
+		})
+		return nil
+	}
+
+	// save images to textures
+	for i := range images {
+		doc, err := addImage(db, &images[i], fmt.Sprintf("%v_%v", fileNameWithoutExt, i), now, userID)
+		if err != nil {
+			fail(err)
+			return
+		}
+		if err := addAsset(server.MapCollectionName, doc); err != nil {
+			fail(err)
+			return
+		}
+	}
+
+	// save the file to meshes, and files with the same content share storage.
+	blob, err := server.AddBlob(db, bytes.NewReader(data), fileName, nil)
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	pinyin := helper.ConvertToPinYin(fileNameWithoutExt)
+	model := bson.M{
+		"ID":          primitive.NewObjectID(),
+		"AddTime":     now,
+		"FileName":    fileName,
+		"FileSize":    file.Size,
+		"FileType":    file.Header.Get("Content-Type"),
+		"FirstPinYin": pinyin.FirstPinYin,
+		"Name":        fileNameWithoutExt,
+		"SaveName":    fileName,
+		"SavePath":    blob.SavePath,
+		"Blobs":       []string{blob.Hash},
+		"Thumbnail":   "",
+		"TotalPinYin": pinyin.TotalPinYin,
+		"Type":        meshType,
+		"Url":         blob.URL(),
+	}
+	if userID != "" {
+		model["UserID"] = userID
+	}
+	if err := addAsset(server.MeshCollectionName, model); err != nil {
+		fail(err)
+		return
+	}
+
+	// create the scene
+	collectionName, err := newCollectionName(db, now)
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	importer := &gltfImporter{
+		doc:    gltf,
+		images: images,
+	}
+	three.Mutex.Lock()
+	list := importer.build(name, model)
+	three.Mutex.Unlock()
+
+	id := primitive.NewObjectID()
+	pinyin = helper.ConvertToPinYin(name)
+	doc := bson.M{
+		"ID":             id,
+		"Name":           name,
+		"TotalPinYin":    pinyin.TotalPinYin,
+		"FirstPinYin":    pinyin.FirstPinYin,
+		"CollectionName": collectionName,
+		"Version":        0,
+		"CreateTime":     now,
+		"UpdateTime":     now,
+		"IsPublic":       false,
+	}
+	if userID != "" {
+		doc["UserID"] = userID
+		doc["UpdateUserID"] = userID
+	}
+
+	if _, err := db.InsertOne(server.SceneCollectionName, doc); err != nil {
+		fail(err)
+		return
+	}
+	rollbacks = append(rollbacks, func() {
+		db.DeleteOne(server.SceneCollectionName, bson.M{"ID": id})
+		db.DropCollection(collectionName)
+	})
+
+	db.DeleteAll(collectionName)
+	if _, err := db.InsertMany(collectionName, list); err != nil {
+		fail(err)
+		return
+	}
+
+	updateThumbnail(db, id)
+
+	result := saveResult{}
+	result.Code = 200
+	result.Msg = "Import successfully!"
+	result.ID = id.Hex()
+	result.Version = 0
+
+	helper.WriteJSON(w, result)
+}
+
+// addImage saves an image of a glTF file as a blob, and returns the texture doc.
+func addImage(db server.Storage, img *gltfImage, name string, now time.Time, userID string) (bson.M, error) {
+	fileExt := ".png"
+	if img.MimeType == "image/jpeg" {
+		fileExt = ".jpg"
+	}
+	fileName := name + fileExt
+
+	blob, err := server.AddBlob(db, bytes.NewReader(img.Data), fileName, nil)
+	if err != nil {
+		return nil, err
+	}
+	img.URL = blob.URL()
+
+	pinyin := helper.ConvertToPinYin(name)
+	doc := bson.M{
+		"ID":          primitive.NewObjectID(),
+		"AddTime":     now,
+		"FileName":    fileName,
+		"FileSize":    int64(len(img.Data)),
+		"FileType":    img.MimeType,
+		"FirstPinYin": pinyin.FirstPinYin,
+		"Name":        name,
+		"SaveName":    fileName,
+		"SavePath":    blob.SavePath,
+		"Blobs":       []string{blob.Hash},
+		"Thumbnail":   img.URL,
+		"TotalPinYin": pinyin.TotalPinYin,
+		"Type":        texture.Unknown,
+		"Url":         img.URL,
+		"CreateTime":  now,
+		"UpdateTime":  now,
+	}
+	if userID != "" {
+		doc["UserID"] = userID
+	}
+	return doc, nil
+}
@@ -9,6 +9,7 @@ package scene
 
 import (
 	"net/http"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -26,7 +27,7 @@ func init() {
 
 // List returns scene list.
 func List(w http.ResponseWriter, r *http.Request) {
-	db, err := server.Mongo()
+	db, err := server.DB()
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
@@ -35,10 +36,6 @@ func List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// get all categories
-	categories := []category.Model{}
-	db.FindAll(server.CategoryCollectionName, &categories)
-
 	docs := bson.A{}
 	opts := options.FindOptions{
 		Sort: bson.M{
@@ -46,47 +43,69 @@ func List(w http.ResponseWriter, r *http.Request) {
 		},
 	}
 
-	if server.Config.Authority.Enabled {
-		user, _ := server.GetCurrentUser(r)
+	db.FindMany(server.SceneCollectionName, listFilter(r), &docs, &opts)
 
-		if user != nil {
-			filter1 := bson.M{
-				"$or": bson.A{
-					bson.M{
-						"UserID": user.ID,
-					},
-					bson.M{
-						"IsPublic": true,
-					},
-				},
-			}
+	list := newModels(db, docs)
 
-			if user.Name == "Administrator" {
-				filter1 = bson.M{
-					"$or": bson.A{
-						filter1,
-						bson.M{
-							"UserID": bson.M{
-								"$exists": 0,
-							},
-						},
-					},
-				}
-			}
-			db.FindMany(server.SceneCollectionName, filter1, &docs, &opts)
-		} else { // no login user can see public scenes
-			filter1 := bson.M{
+	helper.WriteJSON(w, server.Result{
+		Code: 200,
+		Msg:  "Get Successfully!",
+		Data: list,
+	})
+}
+
+// listFilter returns the filter of scenes that the current user can see. Users can see
+// their own scenes, scenes they collaborate on and public scenes, and administrators
+// can also see scenes that belong to nobody. Guests can only see public scenes.
+func listFilter(r *http.Request) bson.M {
+	if !server.Config.Authority.Enabled {
+		return bson.M{}
+	}
+
+	user, _ := server.GetCurrentUser(r)
+	if user == nil { // no login user can see public scenes
+		return bson.M{
+			"IsPublic": true,
+		}
+	}
+
+	filter := bson.M{
+		"$or": bson.A{
+			bson.M{
+				"UserID": user.ID,
+			},
+			bson.M{
+				"Collaborators": user.ID,
+			},
+			bson.M{
 				"IsPublic": true,
-			}
-			db.FindMany(server.SceneCollectionName, filter1, &docs, &opts)
+			},
+		},
+	}
+	if user.Name == "Administrator" {
+		filter = bson.M{
+			"$or": bson.A{
+				filter,
+				bson.M{
+					"UserID": bson.M{
+						"$exists": 0,
+					},
+				},
+			},
 		}
-	} else {
-		db.FindAll(server.SceneCollectionName, &docs, &opts)
 	}
+	return filter
+}
+
+// newModels converts scene docs to the models returned to clients.
+func newModels(db server.Storage, docs bson.A) []Model {
+	// get all categories
+	categories := []category.Model{}
+	db.FindAll(server.CategoryCollectionName, &categories)
 
 	users := []system.User{}
 
-	if err = db.FindAll(server.UserCollectionName, &users); err != nil {
+	if err := db.FindAll(server.UserCollectionName, &users); err != nil {
 		server.Logger.Error(err)
 	}
 
@@ -118,6 +137,17 @@ func List(w http.ResponseWriter, r *http.Request) {
 			isPublic = doc["IsPublic"].(bool)
 		}
 
+		isTemplate, _ := doc["IsTemplate"].(bool)
+
+		collaborators := []string{}
+		if ids, ok := doc["Collaborators"].(primitive.A); ok {
+			for _, id := range ids {
+				if id, ok := id.(string); ok {
+					collaborators = append(collaborators, id)
+				}
+			}
+		}
+
 		username := ""
 		if userID, ok := doc["UserID"]; ok {
 			for _, user := range users {
@@ -128,6 +158,19 @@ func List(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 
+		lockUsername := ""
+		var lockExpireTime *time.Time
+		if lockUserID, expireTime, locked := getLock(doc); locked {
+			lockUsername = lockUserID
+			lockExpireTime = &expireTime
+			for _, user := range users {
+				if user.ID == lockUserID {
+					lockUsername = user.Username
+					break
+				}
+			}
+		}
+
 		info := Model{
 			ID:             doc["ID"].(primitive.ObjectID).Hex(),
 			Name:           doc["Name"].(string),
@@ -141,14 +184,14 @@ func List(w http.ResponseWriter, r *http.Request) {
 			UpdateTime:     doc["UpdateTime"].(primitive.DateTime).Time(),
 			Thumbnail:      thumbnail,
 			IsPublic:       isPublic,
+			IsTemplate:     isTemplate,
 			Username:       username,
+			Collaborators:  collaborators,
+			LockUsername:   lockUsername,
+			LockExpireTime: lockExpireTime,
 		}
 		list = append(list, info)
 	}
 
-	helper.WriteJSON(w, server.Result{
-		Code: 200,
-		Msg:  "Get Successfully!",
-		Data: list,
-	})
+	return list
 }
@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -23,9 +24,16 @@ func init() {
 	server.Handle(http.MethodGet, "/api/Scene/Load", Load, server.None)
 }
 
-// Load load scene data.
+// Load load scene data. Version is a version number or a tag name. When Branch is
+// provided, the data of the branch is loaded. When Share is provided, the published
+// snapshot of the share token is loaded, and Password is required if it has one.
 func Load(w http.ResponseWriter, r *http.Request) {
 	r.ParseForm()
+	if token := strings.TrimSpace(r.FormValue("Share")); token != "" {
+		loadShare(w, token, r.FormValue("Password"))
+		return
+	}
+
 	id, err := primitive.ObjectIDFromHex(strings.TrimSpace(r.FormValue("ID")))
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
@@ -33,12 +41,8 @@ func Load(w http.ResponseWriter, r *http.Request) {
 			Msg:  "ID is not allowed.",
 		})
 	}
-	version := -1
-	if _version, err := strconv.Atoi(strings.TrimSpace(r.FormValue("Version"))); err == nil {
-		version = _version
-	}
 
-	db, err := server.Mongo()
+	db, err := server.DB()
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
@@ -61,17 +65,84 @@ func Load(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	collectionName := doc["CollectionName"].(string)
+	// version is a version number or a tag name
+	version, err := server.ParseSceneVersion(db, id, r.FormValue("Version"))
 
-	docs := bson.A{}
-	if version == -1 {
-		db.FindAll(collectionName, &docs)
-	} else {
-		filter = bson.M{
-			server.VersionField: version,
+	// load a branch of the scene, and tags do not apply to branches.
+	if branchName := strings.TrimSpace(r.FormValue("Branch")); branchName != "" {
+		doc, find, _ = findBranch(db, id, branchName)
+		if !find {
+			helper.WriteJSON(w, server.Result{
+				Code: 300,
+				Msg:  "The branch is not existed!",
+			})
+			return
+		}
+		version = -1
+		if value := strings.TrimSpace(r.FormValue("Version")); value != "" {
+			version, err = strconv.Atoi(value)
 		}
-		db.FindMany(collectionName+server.HistorySuffix, filter, &docs)
 	}
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	docs, err := server.LoadSceneVersion(db, doc, version)
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	helper.WriteJSON(w, server.Result{
+		Code: 200,
+		Msg:  "Get Successfully!",
+		Data: docs,
+	})
+}
+
+// loadShare loads the published snapshot of a share token.
+func loadShare(w http.ResponseWriter, token, password string) {
+	db, err := server.DB()
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	publish, find, err := findPublish(db, token, password, time.Now())
+	if err == errPublishPassword {
+		helper.WriteJSON(w, server.Result{
+			Code: 301,
+			Msg:  err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+	if !find {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "The share link is not existed!",
+		})
+		return
+	}
+
+	docs := bson.A{}
+	db.FindAll(publish["CollectionName"].(string), &docs)
 
 	helper.WriteJSON(w, server.Result{
 		Code: 200,
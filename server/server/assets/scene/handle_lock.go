@@ -0,0 +1,144 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package scene
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/tengge1/shadoweditor/helper"
+	"github.com/tengge1/shadoweditor/server"
+)
+
+func init() {
+	server.Handle(http.MethodPost, "/api/Scene/Lock", Lock, server.SaveScene)
+}
+
+// Lock grant the current user a time-limited lock of a scene, so that others can
+// not save or delete it. Locking again renews the lock.
+func Lock(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	id, err := primitive.ObjectIDFromHex(strings.TrimSpace(r.FormValue("ID")))
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "ID is not allowed.",
+		})
+		return
+	}
+
+	if !server.Config.Authority.Enabled {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "Authority is not enabled.",
+		})
+		return
+	}
+
+	user, _ := server.GetCurrentUser(r)
+	if user == nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "Permission denied.",
+		})
+		return
+	}
+
+	db, err := server.DB()
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	filter := bson.M{
+		"ID": id,
+	}
+	doc := bson.M{}
+	find, _ := db.FindOne(server.SceneCollectionName, filter, &doc)
+
+	if !find {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "The scene is not existed!",
+		})
+		return
+	}
+
+	if !canModify(r, doc) {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "Permission denied.",
+		})
+		return
+	}
+
+	// only grant the lock when it is free, expired or held by the user, so that
+	// two users never get the lock at the same time.
+	now := time.Now()
+	lockFilter := bson.M{
+		"ID": id,
+		"$or": bson.A{
+			bson.M{
+				"LockUserID": nil,
+			},
+			bson.M{
+				"LockUserID": user.ID,
+			},
+			bson.M{
+				"LockExpireTime": bson.M{
+					"$lte": now,
+				},
+			},
+		},
+	}
+	expireTime := now.Add(lockLease)
+	update := bson.M{
+		"$set": bson.M{
+			"LockUserID":     user.ID,
+			"LockExpireTime": expireTime,
+		},
+	}
+	result, err := db.UpdateOne(server.SceneCollectionName, lockFilter, update)
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+	if result.MatchedCount == 0 {
+		doc = bson.M{}
+		db.FindOne(server.SceneCollectionName, filter, &doc)
+		msg := "The scene is locked by others."
+		if err := checkLock(r, doc); err != nil {
+			msg = err.Error()
+		}
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  msg,
+		})
+		return
+	}
+
+	helper.WriteJSON(w, server.Result{
+		Code: 200,
+		Msg:  "Locked successfully!",
+		Data: LockModel{
+			ID:         id.Hex(),
+			Username:   user.Username,
+			ExpireTime: expireTime,
+		},
+	})
+}
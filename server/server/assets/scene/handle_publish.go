@@ -0,0 +1,119 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package scene
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/tengge1/shadoweditor/helper"
+	"github.com/tengge1/shadoweditor/server"
+)
+
+func init() {
+	server.Handle(http.MethodPost, "/api/Scene/Publish", Publish, server.PublishScene)
+}
+
+// Publish creates an immutable snapshot of a scene version that can be loaded by a
+// share token. Version is a version number or a tag name, and the latest version is
+// published when it is empty. Expires is the minutes before the link expires, and 0
+// means never. The link requires Password when it is provided.
+func Publish(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	id, err := primitive.ObjectIDFromHex(strings.TrimSpace(r.FormValue("ID")))
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "ID is not allowed.",
+		})
+		return
+	}
+
+	var expireTime *time.Time
+	if value := strings.TrimSpace(r.FormValue("Expires")); value != "" {
+		expires, err := strconv.Atoi(value)
+		if err != nil || expires < 0 {
+			helper.WriteJSON(w, server.Result{
+				Code: 300,
+				Msg:  "Expires is not allowed.",
+			})
+			return
+		}
+		if expires > 0 {
+			t := time.Now().Add(time.Duration(expires) * time.Minute)
+			expireTime = &t
+		}
+	}
+	password := r.FormValue("Password")
+
+	db, err := server.DB()
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	doc := bson.M{}
+	find, _ := db.FindOne(server.SceneCollectionName, bson.M{"ID": id}, &doc)
+
+	if !find {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "The scene is not existed!",
+		})
+		return
+	}
+
+	if !canModify(r, doc) {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "Permission denied.",
+		})
+		return
+	}
+
+	version, err := server.ParseSceneVersion(db, id, r.FormValue("Version"))
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	userID := ""
+	if server.Config.Authority.Enabled {
+		user, _ := server.GetCurrentUser(r)
+
+		if user != nil {
+			userID = user.ID
+		}
+	}
+
+	publish, err := publishVersion(db, doc, version, expireTime, password, userID)
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	helper.WriteJSON(w, server.Result{
+		Code: 200,
+		Msg:  "Publish successfully!",
+		Data: newPublishModel(publish),
+	})
+}
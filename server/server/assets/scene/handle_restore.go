@@ -0,0 +1,145 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package scene
+
+import (
+	"net/http"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/tengge1/shadoweditor/helper"
+	"github.com/tengge1/shadoweditor/server"
+)
+
+func init() {
+	server.Handle(http.MethodPost, "/api/Scene/Restore", Restore, server.SaveScene)
+}
+
+// Restore make a history version of a scene the latest version. The current
+// scene data is moved to history first, so that a restore can be undone.
+func Restore(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	id, err := primitive.ObjectIDFromHex(strings.TrimSpace(r.FormValue("ID")))
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "ID is not allowed.",
+		})
+		return
+	}
+	if strings.TrimSpace(r.FormValue("Version")) == "" {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "Version is not allowed.",
+		})
+		return
+	}
+
+	db, err := server.DB()
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	filter := bson.M{
+		"ID": id,
+	}
+	doc := bson.M{}
+	find, _ := db.FindOne(server.SceneCollectionName, filter, &doc)
+
+	if !find {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "The scene is not existed!",
+		})
+		return
+	}
+
+	if !canModify(r, doc) {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "Permission denied.",
+		})
+		return
+	}
+
+	if err := checkLock(r, doc); err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	// version is a version number or a tag name
+	restoreVersion, err := server.ParseSceneVersion(db, id, r.FormValue("Version"))
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	// get the data of the history version
+	docs, err := server.LoadSceneVersion(db, doc, restoreVersion)
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	userID := ""
+	if server.Config.Authority.Enabled {
+		user, _ := server.GetCurrentUser(r)
+
+		if user != nil {
+			userID = user.ID
+		}
+	}
+
+	// save history data as the latest version
+	data := []interface{}{}
+	for _, i := range docs {
+		delete(i, "_id")
+		delete(i, server.VersionField)
+		data = append(data, i)
+	}
+
+	version, err := saveData(db, server.SceneCollectionName, doc, data, userID)
+	if err == errConflict {
+		doc = bson.M{}
+		db.FindOne(server.SceneCollectionName, filter, &doc)
+		writeConflict(w, doc)
+		return
+	}
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	updateThumbnail(db, id)
+
+	result := saveResult{}
+	result.Code = 200
+	result.Msg = "Restored successfully!"
+	result.ID = id.Hex()
+	result.Version = version
+
+	helper.WriteJSON(w, result)
+}
@@ -9,6 +9,7 @@ package scene
 
 import (
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -23,7 +24,9 @@ func init() {
 	server.Handle(http.MethodPost, "/api/Scene/Save", Save, server.SaveScene)
 }
 
-// Save save a scene.
+// Save save a scene. When Version is provided, it is the version that the data is
+// based on, and the scene is not saved if others have saved a newer version. When
+// Branch is provided, the data is saved to the branch of the scene instead.
 func Save(w http.ResponseWriter, r *http.Request) {
 	r.ParseForm()
 	id, err := primitive.ObjectIDFromHex(strings.TrimSpace(r.FormValue("ID")))
@@ -48,8 +51,12 @@ func Save(w http.ResponseWriter, r *http.Request) {
 	}
 
 	data := strings.TrimSpace(r.FormValue("Data"))
+	baseVersion := -1
+	if _version, err := strconv.Atoi(strings.TrimSpace(r.FormValue("Version"))); err == nil {
+		baseVersion = _version
+	}
 
-	db, err := server.Mongo()
+	db, err := server.DB()
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
@@ -64,44 +71,69 @@ func Save(w http.ResponseWriter, r *http.Request) {
 	doc := bson.M{}
 	find, _ := db.FindOne(server.SceneCollectionName, filter, &doc)
 
+	// save to a branch of the scene
+	if branchName := strings.TrimSpace(r.FormValue("Branch")); branchName != "" {
+		if !find {
+			helper.WriteJSON(w, server.Result{
+				Code: 300,
+				Msg:  "The scene is not existed!",
+			})
+			return
+		}
+		saveBranch(w, r, db, doc, branchName, baseVersion, data)
+		return
+	}
+
 	now := time.Now()
 	var collectionName string
-	version := -1
+	version := 0
 
 	if !find { // create scene
-		collectionName = "Scene" + helper.TimeToString(now, "yyyyMMddHHmmss")
-		version = 0
+		if collectionName, err = newCollectionName(db, now); err != nil {
+			helper.WriteJSON(w, server.Result{
+				Code: 300,
+				Msg:  err.Error(),
+			})
+			return
+		}
 	} else { // edit scene
-		if server.Config.Authority.Enabled {
-			user, _ := server.GetCurrentUser(r)
-			// save other people's scene
-			if doc["UserID"] != nil && doc["UserID"].(string) != user.ID {
-				helper.WriteJSON(w, server.Result{
-					Code: 300,
-					Msg:  "Permission denied.",
-				})
-				return
-			}
-
-			// not admin save scene without UserID
-			if doc["UserID"] == nil && user.RoleName != "Administrator" {
-				helper.WriteJSON(w, server.Result{
-					Code: 300,
-					Msg:  "Permission denied.",
-				})
-				return
-			}
+		if !canModify(r, doc) {
+			helper.WriteJSON(w, server.Result{
+				Code: 300,
+				Msg:  "Permission denied.",
+			})
+			return
+		}
+		if err := checkLock(r, doc); err != nil {
+			helper.WriteJSON(w, server.Result{
+				Code: 300,
+				Msg:  err.Error(),
+			})
+			return
 		}
 
-		collectionName = doc["CollectionName"].(string)
+		current := 0
 		if doc["Version"] != nil {
-			version = int(doc["Version"].(int32))
-		} else {
-			version = 0
+			current = int(doc["Version"].(int32))
+		}
+		if baseVersion != -1 && baseVersion != current {
+			writeConflict(w, doc)
+			return
 		}
-		version++
 	}
 
+	userID := ""
+	if server.Config.Authority.Enabled {
+		user, _ := server.GetCurrentUser(r)
+
+		if user != nil {
+			userID = user.ID
+		}
+	}
+
+	var list []interface{}
+	bson.UnmarshalExtJSON([]byte(data), false, &list)
+
 	if !find {
 		pinyin := helper.ConvertToPinYin(name)
 		doc = bson.M{
@@ -115,64 +147,129 @@ func Save(w http.ResponseWriter, r *http.Request) {
 			"UpdateTime":     now,
 			"IsPublic":       false,
 		}
-		if server.Config.Authority.Enabled {
-			user, _ := server.GetCurrentUser(r)
-
-			if user != nil {
-				doc["UserID"] = user.ID
-			}
+		if userID != "" {
+			doc["UserID"] = userID
+			doc["UpdateUserID"] = userID
 		}
 
 		db.InsertOne(server.SceneCollectionName, doc)
+
+		// save new scene data
+		db.DeleteAll(collectionName)
+		if len(list) > 0 {
+			db.InsertMany(collectionName, list)
+		}
 	} else {
-		update := bson.M{
-			"$set": bson.M{
-				"Version":    version,
-				"UpdateTime": now,
-			},
+		version, err = saveData(db, server.SceneCollectionName, doc, list, userID)
+		if err == errConflict {
+			doc = bson.M{}
+			db.FindOne(server.SceneCollectionName, filter, &doc)
+			writeConflict(w, doc)
+			return
+		}
+		if err != nil {
+			helper.WriteJSON(w, server.Result{
+				Code: 300,
+				Msg:  err.Error(),
+			})
+			return
 		}
-		db.UpdateOne(server.SceneCollectionName, filter, update)
+	}
 
-		// move current scene data to history
-		old := []bson.M{}
-		db.FindAll(collectionName, &old)
+	updateThumbnail(db, id)
 
-		for _, i := range old {
-			i[server.VersionField] = version - 1
-		}
+	result := saveResult{}
+	result.Code = 200
+	result.Msg = "Saved successfully!"
+	result.ID = id.Hex()
+	result.Version = version
+
+	helper.WriteJSON(w, result)
+}
+
+// saveBranch saves data to a branch of a scene. Branches are not locked with the
+// scene, so that designers can explore alternatives while others edit the scene.
+func saveBranch(w http.ResponseWriter, r *http.Request, db server.Storage, doc bson.M, name string, baseVersion int, data string) {
+	if !canModify(r, doc) {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "Permission denied.",
+		})
+		return
+	}
 
-		if len(old) > 0 {
-			// remove _id; otherwise deplicated
-			for i := 0; i < len(old); i++ {
-				delete(old[i], "_id")
-			}
+	branch, find, _ := findBranch(db, doc["ID"].(primitive.ObjectID), name)
+	if !find {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "The branch is not existed!",
+		})
+		return
+	}
 
-			oldData := []interface{}{}
-			for _, i := range old {
-				oldData = append(oldData, i)
-			}
+	current := 0
+	if branch["Version"] != nil {
+		current = int(branch["Version"].(int32))
+	}
+	if baseVersion != -1 && baseVersion != current {
+		writeConflict(w, branch)
+		return
+	}
+
+	userID := ""
+	if server.Config.Authority.Enabled {
+		user, _ := server.GetCurrentUser(r)
 
-			db.InsertMany(collectionName+server.HistorySuffix, oldData)
+		if user != nil {
+			userID = user.ID
 		}
 	}
 
-	// save new scene data
 	var list []interface{}
 	bson.UnmarshalExtJSON([]byte(data), false, &list)
 
-	docs := bson.A{}
-
-	for _, i := range list {
-		docs = append(docs, i)
+	version, err := saveData(db, server.SceneBranchCollectionName, branch, list, userID)
+	if err == errConflict {
+		branch, _, _ = findBranch(db, doc["ID"].(primitive.ObjectID), name)
+		writeConflict(w, branch)
+		return
+	}
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
 	}
-
-	db.DeleteAll(collectionName)
-	db.InsertMany(collectionName, docs)
 
 	result := saveResult{}
 	result.Code = 200
 	result.Msg = "Saved successfully!"
-	result.ID = id.Hex()
+	result.ID = doc["ID"].(primitive.ObjectID).Hex()
+	result.Version = version
+
+	helper.WriteJSON(w, result)
+}
+
+// writeConflict tells the user that others have saved a newer version of the scene
+// or the branch.
+func writeConflict(w http.ResponseWriter, doc bson.M) {
+	result := conflictResult{}
+	result.Code = 302
+	result.Msg = "The scene has been saved by others."
+	if id, ok := doc["SceneID"].(primitive.ObjectID); ok {
+		result.ID = id.Hex()
+	} else if id, ok := doc["ID"].(primitive.ObjectID); ok {
+		result.ID = id.Hex()
+	}
+	if version, ok := doc["Version"].(int32); ok {
+		result.Version = int(version)
+	}
+	if userID, ok := doc["UpdateUserID"].(string); ok && userID != "" {
+		if user, _ := server.GetUser(userID); user != nil {
+			result.Username = user.Username
+		}
+	}
 
 	helper.WriteJSON(w, result)
 }
@@ -180,5 +277,14 @@ func Save(w http.ResponseWriter, r *http.Request) {
 // saveResult is the result of saving scene.
 type saveResult struct {
 	server.Result
-	ID string
+	ID      string
+	Version int
+}
+
+// conflictResult is the result of saving a scene that others have saved.
+type conflictResult struct {
+	server.Result
+	ID       string
+	Version  int
+	Username string
 }
@@ -8,16 +8,96 @@
 package scene
 
 import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/png"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/dgrijalva/jwt-go"
+	"github.com/dimfeld/httptreemux"
+	"github.com/gorilla/websocket"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/tengge1/shadoweditor/helper"
 	"github.com/tengge1/shadoweditor/server"
+	"github.com/tengge1/shadoweditor/server/thumbnail"
 )
 
+// testDir is the directory that tests store data in, so that tests need no mongo.
+var testDir string
+
+func TestMain(m *testing.M) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		panic(err)
+	}
+	testDir = dir
+	code := m.Run()
+	thumbnail.Wait()
+	os.RemoveAll(testDir)
+	os.Exit(code)
+}
+
+// apiResult is the result of scene apis, such as saveResult and mergeResult.
+type apiResult struct {
+	server.Result
+	ID        string
+	Version   int
+	Conflicts []ConflictModel
+}
+
+// call calls a handler with a form as the client does, and returns the response body.
+func call(handler http.HandlerFunc, values url.Values) []byte {
+	return callAs(handler, "", values)
+}
+
+// callAs is like call, and the request has the login token of a user.
+func callAs(handler http.HandlerFunc, token string, values url.Values) []byte {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(values.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if token != "" {
+		req.AddCookie(&http.Cookie{Name: "token", Value: token})
+	}
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec.Body.Bytes()
+}
+
+// post calls a handler with a form, and returns the result.
+func post(t *testing.T, handler http.HandlerFunc, values url.Values) apiResult {
+	return postAs(t, handler, "", values)
+}
+
+// postAs is like post, and the request has the login token of a user.
+func postAs(t *testing.T, handler http.HandlerFunc, token string, values url.Values) apiResult {
+	result := apiResult{}
+	if err := helper.FromJSON(callAs(handler, token, values), &result); err != nil {
+		t.Error(err)
+	}
+	return result
+}
+
+// get calls a handler with a query string, and returns the response body.
+func get(handler http.HandlerFunc, query string) string {
+	req := httptest.NewRequest(http.MethodGet, "/?"+query, nil)
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec.Body.String()
+}
+
 func TestSceneList(t *testing.T) {
-	server.Create("../config.toml")
+	server.CreateEmbedded(testDir)
 
 	ts := httptest.NewServer(http.HandlerFunc(List))
 	defer ts.Close()
@@ -37,3 +117,1040 @@ func TestSceneList(t *testing.T) {
 
 	t.Log(string(bytes))
 }
+
+func TestSceneRestore(t *testing.T) {
+	server.CreateEmbedded(testDir)
+
+	// version 0 and version 1
+	result := post(t, Save, url.Values{
+		"Name": {"TestSceneRestore"},
+		"Data": {`[{"uuid":"1","Name":"Version0"}]`},
+	})
+	id := result.ID
+	post(t, Save, url.Values{
+		"ID":   {id},
+		"Name": {"TestSceneRestore"},
+		"Data": {`[{"uuid":"1","Name":"Version1"}]`},
+	})
+
+	result = post(t, Restore, url.Values{
+		"ID":      {id},
+		"Version": {"0"},
+	})
+	if result.Code != 200 {
+		t.Errorf("expect 200, got %v: %v", result.Code, result.Msg)
+		return
+	}
+
+	// version 2 is restored from version 0, and version 1 is moved to history.
+	if body := get(Load, "ID="+id); !strings.Contains(body, "Version0") {
+		t.Errorf("expect Version0, got %v", body)
+	}
+	if body := get(Load, "ID="+id+"&Version=1"); !strings.Contains(body, "Version1") {
+		t.Errorf("expect Version1, got %v", body)
+	}
+
+	// restore a version that does not exist
+	result = post(t, Restore, url.Values{
+		"ID":      {id},
+		"Version": {"100"},
+	})
+	if result.Code != 300 {
+		t.Errorf("expect 300, got %v", result.Code)
+	}
+}
+
+func TestSceneDiff(t *testing.T) {
+	server.CreateEmbedded(testDir)
+
+	id := post(t, Save, url.Values{
+		"Name": {"TestSceneDiff"},
+		"Data": {`[
+			{"uuid":"1","name":"Box","type":"Mesh","position":{"x":0,"y":0,"z":0},"material":{"color":1},"userData":{"Url":"/a.obj"}},
+			{"uuid":"2","name":"Light","type":"PointLight"}
+		]`},
+	}).ID
+	post(t, Save, url.Values{
+		"ID":   {id},
+		"Name": {"TestSceneDiff"},
+		"Data": {`[
+			{"uuid":"1","name":"Box","type":"Mesh","position":{"x":1,"y":0,"z":0},"material":{"color":2},"userData":{"Url":"/b.obj"}},
+			{"uuid":"3","name":"Camera","type":"PerspectiveCamera"}
+		]`},
+	})
+
+	result := struct {
+		Code int
+		Msg  string
+		Data DiffModel
+	}{}
+	if err := helper.FromJSON([]byte(get(Diff, "ID="+id+"&From=0")), &result); err != nil {
+		t.Error(err)
+		return
+	}
+	if result.Code != 200 {
+		t.Errorf("expect 200, got %v: %v", result.Code, result.Msg)
+		return
+	}
+
+	data := result.Data
+	if len(data.Added) != 1 || data.Added[0].UUID != "3" {
+		t.Errorf("expect added 3, got %v", data.Added)
+	}
+	if len(data.Removed) != 1 || data.Removed[0].UUID != "2" {
+		t.Errorf("expect removed 2, got %v", data.Removed)
+	}
+	if len(data.Modified) != 1 || data.Modified[0].UUID != "1" {
+		t.Errorf("expect modified 1, got %v", data.Modified)
+		return
+	}
+	paths := []string{}
+	for _, change := range data.Modified[0].Changes {
+		paths = append(paths, change.Path)
+	}
+	if strings.Join(paths, ",") != "material.color,position,userData.Url" {
+		t.Errorf("expect material.color,position,userData.Url, got %v", paths)
+	}
+}
+
+func TestSceneSaveConflict(t *testing.T) {
+	server.CreateEmbedded(testDir)
+
+	result := post(t, Save, url.Values{
+		"Name": {"TestSceneSaveConflict"},
+		"Data": {`[]`},
+	})
+	id := result.ID
+
+	// both users load version 0, and the first one saves version 1.
+	result = post(t, Save, url.Values{
+		"ID":      {id},
+		"Name":    {"TestSceneSaveConflict"},
+		"Version": {"0"},
+		"Data":    {`[]`},
+	})
+	if result.Code != 200 || result.Version != 1 {
+		t.Errorf("expect 200 and version 1, got %v and version %v", result.Code, result.Version)
+		return
+	}
+
+	// the second one saves based on version 0.
+	result = post(t, Save, url.Values{
+		"ID":      {id},
+		"Name":    {"TestSceneSaveConflict"},
+		"Version": {"0"},
+		"Data":    {`[]`},
+	})
+	if result.Code != 302 || result.Version != 1 {
+		t.Errorf("expect 302 and version 1, got %v and version %v", result.Code, result.Version)
+	}
+}
+
+func TestSceneCollaborate(t *testing.T) {
+	server.CreateEmbedded(testDir)
+
+	result := post(t, Save, url.Values{
+		"Name": {"TestSceneCollaborate"},
+		"Data": {`[{"uuid":"1","name":"Scene","type":"Scene"}]`},
+	})
+
+	mux := httptreemux.NewContextMux()
+	mux.GET("/api/Scene/Collaborate/:ID", Collaborate)
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(ts.URL, "http") + "/api/Scene/Collaborate/" + result.ID
+	conn1, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	conn2, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	// receive skips presence messages.
+	receive := func(conn *websocket.Conn) collaborateMessage {
+		for {
+			conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+			msg := collaborateMessage{}
+			if err := conn.ReadJSON(&msg); err != nil {
+				t.Error(err)
+				return msg
+			}
+			if msg.Type != "presence" {
+				return msg
+			}
+		}
+	}
+
+	conn1.WriteJSON(collaborateMessage{
+		Type:   "add",
+		Object: map[string]interface{}{"uuid": "2", "name": "Box", "type": "Mesh"},
+	})
+	if msg := receive(conn2); msg.Type != "add" || msg.UUID != "2" {
+		t.Errorf("expect add 2, got %v %v", msg.Type, msg.UUID)
+	}
+
+	conn2.WriteJSON(collaborateMessage{
+		Type:  "set",
+		UUID:  "2",
+		Path:  "position",
+		Value: map[string]interface{}{"x": 1, "y": 2, "z": 3},
+	})
+	if msg := receive(conn1); msg.Type != "set" || msg.Path != "position" {
+		t.Errorf("expect set position, got %v %v", msg.Type, msg.Path)
+	}
+
+	conn2.WriteJSON(collaborateMessage{
+		Type: "remove",
+		UUID: "3",
+	})
+	if msg := receive(conn2); msg.Type != "error" {
+		t.Errorf("expect error, got %v", msg.Type)
+	}
+
+	// the scene is saved when the last collaborator leaves.
+	conn1.Close()
+	conn2.Close()
+
+	for i := 0; i < 50; i++ {
+		if strings.Contains(get(Load, "ID="+result.ID), "position") {
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Errorf("expect the scene to be saved")
+}
+
+func TestSceneCollaborateConflict(t *testing.T) {
+	server.CreateEmbedded(testDir)
+
+	result := post(t, Save, url.Values{
+		"Name": {"TestSceneCollaborateConflict"},
+		"Data": {`[{"uuid":"1","type":"Scene"},{"uuid":"2","type":"Mesh"}]`},
+	})
+	id, _ := primitive.ObjectIDFromHex(result.ID)
+
+	r := &room{
+		collaborators: map[*collaborator]bool{},
+	}
+	if err := r.load(id); err != nil {
+		t.Error(err)
+		return
+	}
+	c := &collaborator{
+		send: make(chan []byte, 16),
+	}
+	r.collaborators[c] = true
+
+	// receive returns the message that the room sends to the collaborator, the
+	// operations of the collaborator are not sent back.
+	receive := func() collaborateMessage {
+		msg := collaborateMessage{}
+		select {
+		case data := <-c.send:
+			helper.FromJSON(data, &msg)
+		default:
+		}
+		return msg
+	}
+	uuids := func() []string {
+		db, _ := server.DB()
+		doc := bson.M{}
+		db.FindOne(server.SceneCollectionName, bson.M{"ID": id}, &doc)
+		docs, _ := server.LoadSceneVersion(db, doc, -1)
+		list := []string{}
+		for _, i := range docs {
+			list = append(list, i["uuid"].(string))
+		}
+		return list
+	}
+
+	r.apply(c, collaborateMessage{Type: "add", Object: map[string]interface{}{"uuid": "3", "type": "Mesh"}})
+	r.apply(c, collaborateMessage{Type: "set", UUID: "2", Path: "name", Value: "Box"})
+
+	// others remove object 2 and add object 4 outside the collaboration.
+	post(t, Save, url.Values{
+		"ID":   {result.ID},
+		"Name": {"TestSceneCollaborateConflict"},
+		"Data": {`[{"uuid":"1","type":"Scene"},{"uuid":"4","type":"Mesh"}]`},
+	})
+
+	r.persist()
+	msg := receive()
+	if msg.Type != "reload" || len(msg.Rejected) != 1 || msg.Rejected[0].UUID != "2" {
+		t.Errorf("expect reload rejecting set 2, got %v %v", msg.Type, msg.Rejected)
+	}
+	if list := uuids(); strings.Join(list, ",") != "1,4,3" {
+		t.Errorf("expect 1,4,3, got %v", list)
+	}
+
+	// others lock the scene after the room is opened.
+	server.Config.Authority.Enabled = true
+	defer func() {
+		server.Config.Authority.Enabled = false
+	}()
+	db, _ := server.DB()
+	db.UpdateOne(server.SceneCollectionName, bson.M{"ID": id}, bson.M{
+		"$set": bson.M{
+			"LockUserID":     primitive.NewObjectID().Hex(),
+			"LockExpireTime": time.Now().Add(time.Minute),
+		},
+	})
+
+	r.apply(c, collaborateMessage{Type: "add", Object: map[string]interface{}{"uuid": "5", "type": "Mesh"}})
+	r.persist()
+	if msg := receive(); msg.Type != "lock" {
+		t.Errorf("expect lock, got %v", msg.Type)
+	}
+	if list := uuids(); strings.Join(list, ",") != "1,4,3" {
+		t.Errorf("expect 1,4,3, got %v", list)
+	}
+}
+
+func TestSceneLock(t *testing.T) {
+	server.CreateEmbedded(testDir)
+
+	db, err := server.DB()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	// two administrators
+	roleID := primitive.NewObjectID()
+	db.InsertOne(server.RoleCollectionName, bson.M{
+		"ID":   roleID,
+		"Name": "Administrator",
+	})
+	tokens := []string{}
+	for _, username := range []string{"admin1", "admin2"} {
+		userID := primitive.NewObjectID()
+		db.InsertOne(server.UserCollectionName, bson.M{
+			"ID":       userID,
+			"Username": username,
+			"RoleID":   roleID.Hex(),
+		})
+
+		token := jwt.New(jwt.SigningMethodHS256)
+		token.Claims = jwt.MapClaims{
+			"userID": userID.Hex(),
+		}
+		tokenString, err := token.SignedString([]byte(server.Config.Authority.SecretKey))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		tokens = append(tokens, tokenString)
+	}
+
+	// a scene without owner that administrators can edit
+	result := postAs(t, Save, "", url.Values{
+		"Name": {"TestSceneLock"},
+		"Data": {`[]`},
+	})
+	id := result.ID
+
+	server.Config.Authority.Enabled = true
+	defer func() {
+		server.Config.Authority.Enabled = false
+	}()
+
+	if result := postAs(t, Lock, tokens[0], url.Values{"ID": {id}}); result.Code != 200 {
+		t.Errorf("expect 200, got %v: %v", result.Code, result.Msg)
+		return
+	}
+
+	values := url.Values{
+		"ID":   {id},
+		"Name": {"TestSceneLock"},
+		"Data": {`[]`},
+	}
+	if result := postAs(t, Save, tokens[1], values); result.Code != 300 || !strings.Contains(result.Msg, "admin1") {
+		t.Errorf("expect locked by admin1, got %v: %v", result.Code, result.Msg)
+	}
+	if result := postAs(t, Lock, tokens[1], url.Values{"ID": {id}}); result.Code != 300 {
+		t.Errorf("expect 300, got %v: %v", result.Code, result.Msg)
+	}
+	if result := postAs(t, Save, tokens[0], values); result.Code != 200 {
+		t.Errorf("expect 200, got %v: %v", result.Code, result.Msg)
+	}
+
+	// administrators can break the lock
+	if result := postAs(t, Unlock, tokens[1], url.Values{"ID": {id}}); result.Code != 200 {
+		t.Errorf("expect 200, got %v: %v", result.Code, result.Msg)
+	}
+	if result := postAs(t, Save, tokens[1], values); result.Code != 200 {
+		t.Errorf("expect 200, got %v: %v", result.Code, result.Msg)
+	}
+}
+
+func TestSceneCollaborator(t *testing.T) {
+	server.CreateEmbedded(testDir)
+
+	db, err := server.DB()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	// the owner of the scene and a user who co-edits it
+	userIDs := []string{}
+	tokens := []string{}
+	for _, username := range []string{"owner", "collaborator"} {
+		userID := primitive.NewObjectID()
+		db.InsertOne(server.UserCollectionName, bson.M{
+			"ID":       userID,
+			"Username": username,
+		})
+
+		token := jwt.New(jwt.SigningMethodHS256)
+		token.Claims = jwt.MapClaims{
+			"userID": userID.Hex(),
+		}
+		tokenString, err := token.SignedString([]byte(server.Config.Authority.SecretKey))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		userIDs = append(userIDs, userID.Hex())
+		tokens = append(tokens, tokenString)
+	}
+
+	id := primitive.NewObjectID()
+	db.InsertOne(server.SceneCollectionName, bson.M{
+		"ID":             id,
+		"Name":           "TestSceneCollaborator",
+		"CollectionName": "SceneTestSceneCollaborator",
+		"Version":        0,
+		"CreateTime":     time.Now(),
+		"UpdateTime":     time.Now(),
+		"UserID":         userIDs[0],
+	})
+
+	server.Config.Authority.Enabled = true
+	defer func() {
+		server.Config.Authority.Enabled = false
+	}()
+
+	collaborate := func() bool {
+		doc := bson.M{}
+		db.FindOne(server.SceneCollectionName, bson.M{"ID": id}, &doc)
+		user, _ := server.GetUser(userIDs[1])
+		return canCollaborate(server.WithUser(httptest.NewRequest(http.MethodGet, "/", nil), user), doc)
+	}
+	if collaborate() {
+		t.Errorf("expect other users not to co-edit the scene")
+	}
+
+	values := url.Values{"ID": {id.Hex()}, "Collaborators": {userIDs[1]}}
+	if result := postAs(t, CollaboratorEdit, tokens[1], values); result.Code != 300 {
+		t.Errorf("expect only the owner to add collaborators, got %v: %v", result.Code, result.Msg)
+	}
+	if result := postAs(t, CollaboratorEdit, tokens[0], values); result.Code != 200 {
+		t.Errorf("expect 200, got %v: %v", result.Code, result.Msg)
+		return
+	}
+	if !collaborate() {
+		t.Errorf("expect the collaborator to co-edit the scene")
+	}
+
+	// the collaborator can see the scene in the list.
+	list := struct {
+		server.Result
+		Data []bson.M
+	}{}
+	helper.FromJSON(callAs(List, tokens[1], url.Values{}), &list)
+	if len(list.Data) != 1 || list.Data[0]["ID"] != id.Hex() || len(list.Data[0]["Collaborators"].([]interface{})) != 1 {
+		t.Errorf("expect the scene in the list, got %v", list.Data)
+	}
+}
+
+func TestSceneTag(t *testing.T) {
+	server.CreateEmbedded(testDir)
+
+	id := post(t, Save, url.Values{
+		"Name": {"TestSceneTag"},
+		"Data": {`[{"metadata":{"generator":"OptionsSerializer"}},{"uuid":"1","name":"Version0"}]`},
+	}).ID
+	post(t, Save, url.Values{
+		"ID":   {id},
+		"Name": {"TestSceneTag"},
+		"Data": {`[{"metadata":{"generator":"OptionsSerializer"}},{"uuid":"1","name":"Version1"}]`},
+	})
+
+	if result := post(t, TagAdd, url.Values{"ID": {id}, "Version": {"0"}, "Name": {"12"}}); result.Code != 300 {
+		t.Errorf("expect 300, got %v", result.Code)
+	}
+	if result := post(t, TagAdd, url.Values{"ID": {id}, "Version": {"0"}, "Name": {"review 1"}, "Note": {"first"}}); result.Code != 200 {
+		t.Errorf("expect 200, got %v: %v", result.Code, result.Msg)
+		return
+	}
+	if result := post(t, TagAdd, url.Values{"ID": {id}, "Version": {"1"}, "Name": {"review 1"}}); result.Code != 300 {
+		t.Errorf("expect 300, got %v", result.Code)
+	}
+	if result := post(t, TagEdit, url.Values{"ID": {id}, "Name": {"review 1"}, "NewName": {"client review 2"}}); result.Code != 200 {
+		t.Errorf("expect 200, got %v: %v", result.Code, result.Msg)
+	}
+
+	if body := get(TagList, "ID="+id); !strings.Contains(body, "client review 2") {
+		t.Errorf("expect client review 2, got %v", body)
+	}
+	if body := get(Load, "ID="+id+"&Version="+url.QueryEscape("client review 2")); !strings.Contains(body, "Version0") {
+		t.Errorf("expect Version0, got %v", body)
+	}
+	if body := get(HistoryList, "ID="+id+"&Version="+url.QueryEscape("client review 2")); !strings.Contains(body, "client review 2") {
+		t.Errorf("expect client review 2, got %v", body)
+	}
+	if body := get(Load, "ID="+id+"&Version=review"); !strings.Contains(body, `"Code":300`) {
+		t.Errorf("expect 300, got %v", body)
+	}
+
+	// the head version is not in history.
+	if result := post(t, TagAdd, url.Values{"ID": {id}, "Version": {"1"}, "Name": {"head"}}); result.Code != 200 {
+		t.Errorf("expect 200, got %v: %v", result.Code, result.Msg)
+	}
+	if body := get(Load, "ID="+id+"&Version=head"); !strings.Contains(body, "Version1") {
+		t.Errorf("expect Version1, got %v", body)
+	}
+
+	if result := post(t, TagDelete, url.Values{"ID": {id}, "Name": {"client review 2"}}); result.Code != 200 {
+		t.Errorf("expect 200, got %v: %v", result.Code, result.Msg)
+	}
+	if body := get(TagList, "ID="+id); strings.Contains(body, "client review 2") {
+		t.Errorf("expect no tags, got %v", body)
+	}
+}
+
+func TestSceneBranch(t *testing.T) {
+	server.CreateEmbedded(testDir)
+
+	id := post(t, Save, url.Values{
+		"Name": {"TestSceneBranch"},
+		"Data": {`[{"uuid":"1","name":"Box","position":{"x":0,"y":0,"z":0}},{"uuid":"2","name":"Light"}]`},
+	}).ID
+
+	if result := post(t, BranchAdd, url.Values{"ID": {id}, "Name": {"alternative"}}); result.Code != 200 {
+		t.Errorf("expect 200, got %v: %v", result.Code, result.Msg)
+		return
+	}
+	if result := post(t, BranchAdd, url.Values{"ID": {id}, "Name": {"alternative"}}); result.Code != 300 {
+		t.Errorf("expect 300, got %v", result.Code)
+	}
+	if body := get(BranchList, "ID="+id); !strings.Contains(body, "alternative") {
+		t.Errorf("expect alternative, got %v", body)
+	}
+
+	// the branch moves the box and adds a sphere, and the scene renames the light.
+	if result := post(t, Save, url.Values{
+		"ID":     {id},
+		"Name":   {"TestSceneBranch"},
+		"Branch": {"alternative"},
+		"Data":   {`[{"uuid":"1","name":"Box","position":{"x":1,"y":0,"z":0}},{"uuid":"2","name":"Light"},{"uuid":"3","name":"Sphere"}]`},
+	}); result.Code != 200 || result.Version != 1 {
+		t.Errorf("expect 200 and version 1, got %v: %v", result.Code, result.Version)
+	}
+	post(t, Save, url.Values{
+		"ID":   {id},
+		"Name": {"TestSceneBranch"},
+		"Data": {`[{"uuid":"1","name":"Box","position":{"x":0,"y":0,"z":0}},{"uuid":"2","name":"Sun"}]`},
+	})
+	if body := get(Load, "ID="+id+"&Branch=alternative"); !strings.Contains(body, "Sphere") {
+		t.Errorf("expect Sphere, got %v", body)
+	}
+
+	result := post(t, BranchMerge, url.Values{"ID": {id}, "Name": {"alternative"}})
+	if result.Code != 200 || result.Version != 2 {
+		t.Errorf("expect 200 and version 2, got %v: %v %v", result.Code, result.Version, result.Msg)
+	}
+	body := get(Load, "ID="+id)
+	for _, name := range []string{"Sun", "Sphere", `"x":1`} {
+		if !strings.Contains(body, name) {
+			t.Errorf("expect %v, got %v", name, body)
+		}
+	}
+
+	// both sides change the same field.
+	post(t, Save, url.Values{
+		"ID":     {id},
+		"Name":   {"TestSceneBranch"},
+		"Branch": {"alternative"},
+		"Data":   {`[{"uuid":"1","name":"Red Box","position":{"x":1,"y":0,"z":0}},{"uuid":"2","name":"Light"},{"uuid":"3","name":"Sphere"}]`},
+	})
+	post(t, Save, url.Values{
+		"ID":   {id},
+		"Name": {"TestSceneBranch"},
+		"Data": {`[{"uuid":"1","name":"Blue Box","position":{"x":1,"y":0,"z":0}},{"uuid":"2","name":"Sun"},{"uuid":"3","name":"Sphere"}]`},
+	})
+	result = post(t, BranchMerge, url.Values{"ID": {id}, "Name": {"alternative"}})
+	if result.Code != 302 || len(result.Conflicts) != 1 || result.Conflicts[0].Path != "name" {
+		t.Errorf("expect a conflict of name, got %v: %v", result.Code, result.Conflicts)
+	}
+	if body := get(Load, "ID="+id); !strings.Contains(body, "Blue Box") {
+		t.Errorf("expect Blue Box, got %v", body)
+	}
+
+	if result := post(t, BranchDelete, url.Values{"ID": {id}, "Name": {"alternative"}}); result.Code != 200 {
+		t.Errorf("expect 200, got %v: %v", result.Code, result.Msg)
+	}
+	if body := get(BranchList, "ID="+id); strings.Contains(body, "alternative") {
+		t.Errorf("expect no branches, got %v", body)
+	}
+}
+
+func TestSceneDependencies(t *testing.T) {
+	server.CreateEmbedded(testDir)
+	db, err := server.DB()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	// a texture whose file exists, and a mesh whose file is removed.
+	texturePath := server.MapPath("/Upload/Texture/20200101000000/a.png")
+	os.MkdirAll(filepath.Dir(texturePath), 0755)
+	ioutil.WriteFile(texturePath, []byte("png"), 0755)
+
+	textureID := primitive.NewObjectID()
+	db.InsertOne(server.MapCollectionName, bson.M{
+		"ID":       textureID,
+		"Name":     "a",
+		"SavePath": "/Upload/Texture/20200101000000",
+		"Url":      "/Upload/Texture/20200101000000/a.png",
+	})
+	db.InsertOne(server.MeshCollectionName, bson.M{
+		"ID":       primitive.NewObjectID(),
+		"Name":     "model",
+		"SavePath": "/Upload/Model/20200101000000",
+		"Url":      "/Upload/Model/20200101000000/model.obj",
+	})
+
+	saved := post(t, Save, url.Values{
+		"Name": {"TestSceneDependencies"},
+		"Data": {`[
+			{"metadata":{"generator":"ServerObject"},"uuid":"1","userData":{"Url":"/Upload/Model/20200101000000/model.obj"}},
+			{"metadata":{"generator":"MeshSerializer"},"uuid":"2","material":[
+				{"map":{"image":{"src":"/Upload/Texture/20200101000000/a.png"}}},
+				{"map":{"image":{"src":"data:image/png;base64,AAAA"}}}
+			]}
+		]`},
+	})
+
+	body := get(Dependencies, "ID="+saved.ID)
+	result := struct {
+		server.Result
+		Data []DependencyModel
+	}{}
+	if err := helper.FromJSON([]byte(body), &result); err != nil {
+		t.Error(err)
+		return
+	}
+	if result.Code != 200 || len(result.Data) != 2 {
+		t.Errorf("expect 2 dependencies, got %v: %v", result.Code, body)
+		return
+	}
+
+	model, texture := result.Data[0], result.Data[1]
+	if model.CollectionName != server.MeshCollectionName || model.Exists {
+		t.Errorf("expect a missing mesh, got %v", model)
+	}
+	if texture.CollectionName != server.MapCollectionName || texture.ID != textureID.Hex() || !texture.Exists || texture.Size != 3 {
+		t.Errorf("expect an existing texture, got %v", texture)
+	}
+}
+
+func TestSceneImport(t *testing.T) {
+	server.CreateEmbedded(testDir)
+	db, err := server.DB()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	// a node with a textured mesh in a group node.
+	img := bytes.Buffer{}
+	png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 2, 2)))
+
+	zero := 0
+	doc := &helper.GLTF{
+		Asset:  helper.GLTFAsset{Version: "2.0"},
+		Scene:  &zero,
+		Scenes: []helper.GLTFScene{{Nodes: []int{0}}},
+		Nodes: []helper.GLTFNode{
+			{Name: "root", Children: []int{1}},
+			{Name: "box", Mesh: &zero, Translation: []float64{1, 2, 3}},
+		},
+		Meshes: []helper.GLTFMesh{{
+			Primitives: []helper.GLTFPrimitive{{Attributes: map[string]int{"POSITION": 0}, Material: &zero}},
+		}},
+		Materials: []helper.GLTFMaterial{{
+			Name: "red",
+			PBRMetallicRoughness: &helper.GLTFPBR{
+				BaseColorFactor:  []float64{1, 0, 0, 1},
+				BaseColorTexture: &helper.GLTFTextureInfo{Index: 0},
+			},
+		}},
+		Textures:    []helper.GLTFTexture{{Source: &zero}},
+		Images:      []helper.GLTFImage{{MimeType: "image/png", BufferView: &zero}},
+		BufferViews: []helper.GLTFBufferView{{Buffer: 0, ByteLength: img.Len()}},
+		Buffers:     []helper.GLTFBuffer{{ByteLength: img.Len()}},
+	}
+	glb := bytes.Buffer{}
+	if err := helper.WriteGLB(&glb, doc, img.Bytes()); err != nil {
+		t.Error(err)
+		return
+	}
+
+	body := bytes.Buffer{}
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("Name", "TestSceneImport")
+	part, _ := writer.CreateFormFile("file", "box.glb")
+	part.Write(glb.Bytes())
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	rec := httptest.NewRecorder()
+	Import(rec, req)
+
+	saved := saveResult{}
+	helper.FromJSON(rec.Body.Bytes(), &saved)
+	if saved.Code != 200 {
+		t.Errorf("expect 200, got %v", rec.Body.String())
+		return
+	}
+
+	id, _ := primitive.ObjectIDFromHex(saved.ID)
+	scene := bson.M{}
+	db.FindOne(server.SceneCollectionName, bson.M{"ID": id}, &scene)
+	docs := []bson.M{}
+	db.FindAll(scene["CollectionName"].(string), &docs)
+
+	objects := map[string]bson.M{}
+	for _, doc := range docs {
+		metadata := doc["metadata"].(bson.M)
+		objects[metadata["generator"].(string)] = doc
+	}
+	for _, generator := range []string{"OptionsSerializer", "PerspectiveCameraSerializer", "SceneSerializer", "ServerObject", "Object3DSerializer", "MeshSerializer"} {
+		if objects[generator] == nil {
+			t.Errorf("expect %v in the scene", generator)
+			return
+		}
+	}
+
+	mesh := objects["MeshSerializer"]
+	if mesh["name"] != "box" || mesh["parent"] != objects["Object3DSerializer"]["uuid"] {
+		t.Errorf("expect box in root, got %v", mesh)
+	}
+	if position := mesh["position"].(bson.M); position["x"] != 1.0 || position["z"] != 3.0 {
+		t.Errorf("expect position (1, 2, 3), got %v", position)
+	}
+	material := mesh["material"].(bson.M)
+	if material["color"] != int32(0xff0000) {
+		t.Errorf("expect red material, got %v", material["color"])
+	}
+
+	// the hierarchy of the server object has the uuids of saved objects.
+	userData := objects["ServerObject"]["userData"].(bson.M)
+	root := userData["_children"].(bson.A)[0].(bson.M)
+	box := root["children"].(bson.A)[0].(bson.M)
+	if root["uuid"] != objects["Object3DSerializer"]["uuid"] || box["uuid"] != mesh["uuid"] {
+		t.Errorf("expect hierarchy root/box, got %v", userData["_children"])
+	}
+
+	// the model and the texture are added to assets.
+	deps := get(Dependencies, "ID="+saved.ID)
+	result := struct {
+		server.Result
+		Data []DependencyModel
+	}{}
+	helper.FromJSON([]byte(deps), &result)
+	if len(result.Data) != 2 {
+		t.Errorf("expect 2 dependencies, got %v", deps)
+		return
+	}
+	if result.Data[0].CollectionName != server.MeshCollectionName || !result.Data[0].Exists {
+		t.Errorf("expect the model in meshes, got %v", result.Data[0])
+	}
+	if result.Data[1].CollectionName != server.MapCollectionName || !result.Data[1].Exists {
+		t.Errorf("expect the image in textures, got %v", result.Data[1])
+	}
+	if !strings.HasPrefix(userData["Url"].(string), server.BlobDir+"/") {
+		t.Errorf("expect the model saved as a blob, got %v", userData["Url"])
+	}
+}
+
+func TestSceneImportInvalidIndex(t *testing.T) {
+	minus := -1
+	doc := &helper.GLTF{
+		Asset:  helper.GLTFAsset{Version: "2.0"},
+		Scene:  &minus,
+		Scenes: []helper.GLTFScene{{Nodes: []int{0, -1}}},
+		Nodes: []helper.GLTFNode{
+			{Name: "box", Mesh: &minus, Children: []int{-1}},
+			{Name: "other", Mesh: &minus},
+		},
+		Meshes: []helper.GLTFMesh{{
+			Primitives: []helper.GLTFPrimitive{{Attributes: map[string]int{"POSITION": 0}, Material: &minus}},
+		}},
+		Materials: []helper.GLTFMaterial{{
+			PBRMetallicRoughness: &helper.GLTFPBR{
+				BaseColorTexture: &helper.GLTFTextureInfo{Index: 0},
+			},
+		}},
+		Textures: []helper.GLTFTexture{{Source: &minus, Sampler: &minus}},
+	}
+	importer := &gltfImporter{
+		doc: doc,
+	}
+	if list := importer.build("TestSceneImportInvalidIndex", bson.M{"Name": "box"}); len(list) != 5 {
+		t.Errorf("expect 5 docs, got %v", len(list))
+	}
+	if texture := importer.newTexture(&helper.GLTFTextureInfo{Index: 0}, true); texture != nil {
+		t.Errorf("expect no texture, got %v", texture)
+	}
+
+	// images out of buffers
+	doc.Images = []helper.GLTFImage{{BufferView: &minus}}
+	data, _ := json.Marshal(doc)
+	if _, _, err := readGLTF("box.gltf", data); err == nil {
+		t.Errorf("expect error, got nil")
+	}
+	zero := 0
+	doc.Images = []helper.GLTFImage{{BufferView: &zero}}
+	doc.BufferViews = []helper.GLTFBufferView{{Buffer: 0, ByteOffset: -1, ByteLength: 1}}
+	doc.Buffers = []helper.GLTFBuffer{{URI: "data:application/octet-stream;base64,AAAA"}}
+	data, _ = json.Marshal(doc)
+	if _, _, err := readGLTF("box.gltf", data); err == nil {
+		t.Errorf("expect error, got nil")
+	}
+}
+
+func TestScenePublish(t *testing.T) {
+	server.CreateEmbedded(testDir)
+
+	publish := func(values url.Values) bson.M {
+		result := struct {
+			server.Result
+			Data bson.M
+		}{}
+		helper.FromJSON(call(Publish, values), &result)
+		if result.Code != 200 {
+			t.Errorf("expect 200, got %v: %v", result.Code, result.Msg)
+		}
+		return result.Data
+	}
+	load := func(values url.Values) (int, string) {
+		result := struct {
+			server.Result
+			Data []bson.M
+		}{}
+		helper.FromJSON(call(Load, values), &result)
+		if len(result.Data) == 0 {
+			return result.Code, ""
+		}
+		name, _ := result.Data[0]["name"].(string)
+		return result.Code, name
+	}
+
+	saved := post(t, Save, url.Values{
+		"Name": {"TestScenePublish"},
+		"Data": {`[{"uuid":"1","name":"a"}]`},
+	})
+
+	// the snapshot does not change when the scene is saved.
+	share := publish(url.Values{"ID": {saved.ID}})
+	call(Save, url.Values{
+		"ID":   {saved.ID},
+		"Name": {"TestScenePublish"},
+		"Data": {`[{"uuid":"1","name":"b"}]`},
+	})
+	if code, name := load(url.Values{"Share": {share["Token"].(string)}}); code != 200 || name != "a" {
+		t.Errorf("expect a, got %v %v", code, name)
+	}
+
+	// a share link with an expiry and a password.
+	locked := publish(url.Values{"ID": {saved.ID}, "Expires": {"60"}, "Password": {"123"}})
+	if locked["HasPassword"] != true || locked["ExpireTime"] == nil || locked["Version"] != 1.0 {
+		t.Errorf("expect a share link of version 1 with password and expiry, got %v", locked)
+	}
+	token := locked["Token"].(string)
+	if code, _ := load(url.Values{"Share": {token}, "Password": {"456"}}); code != 301 {
+		t.Errorf("expect 301 with a wrong password, got %v", code)
+	}
+	if code, name := load(url.Values{"Share": {token}, "Password": {"123"}}); code != 200 || name != "b" {
+		t.Errorf("expect b, got %v %v", code, name)
+	}
+	db, _ := server.DB()
+	if _, _, err := findPublish(db, token, "123", time.Now().Add(2*time.Hour)); err != errPublishExpired {
+		t.Errorf("expect the share link to expire, got %v", err)
+	}
+
+	body := get(PublishList, "ID="+saved.ID)
+	list := struct {
+		server.Result
+		Data []bson.M
+	}{}
+	helper.FromJSON([]byte(body), &list)
+	if len(list.Data) != 2 {
+		t.Errorf("expect 2 publishes, got %v", body)
+	}
+
+	// the share link no longer works when it is revoked.
+	call(PublishRevoke, url.Values{"ID": {share["ID"].(string)}})
+	if code, _ := load(url.Values{"Share": {share["Token"].(string)}}); code != 300 {
+		t.Errorf("expect 300 after revoked, got %v", code)
+	}
+}
+
+func TestSceneCopy(t *testing.T) {
+	server.CreateEmbedded(testDir)
+
+	saved := post(t, Save, url.Values{
+		"Name": {"TestSceneCopy"},
+		"Data": {`[{"uuid":"1","name":"a"}]`},
+	})
+	call(Save, url.Values{
+		"ID":   {saved.ID},
+		"Name": {"TestSceneCopy"},
+		"Data": {`[{"uuid":"1","name":"b"}]`},
+	})
+	call(Edit, url.Values{
+		"ID":         {saved.ID},
+		"Name":       {"TestSceneCopy"},
+		"IsTemplate": {"true"},
+	})
+
+	// rendered thumbnails are replaced when rendered again, so they are not shared.
+	db, _ := server.DB()
+	sourceID, _ := primitive.ObjectIDFromHex(saved.ID)
+	db.UpdateOne(server.SceneCollectionName, bson.M{"ID": sourceID}, bson.M{
+		"$set": bson.M{"Thumbnail": "/Upload/Thumbnail/TestSceneCopy.png"},
+	})
+
+	// copy version 0 to a new scene
+	copied := post(t, Copy, url.Values{
+		"ID":      {saved.ID},
+		"Version": {"0"},
+		"Name":    {"TestSceneCopy2"},
+	})
+	if copied.Code != 200 || copied.ID == saved.ID || copied.Version != 0 {
+		t.Errorf("expect a new scene, got %v", copied)
+		return
+	}
+
+	id, _ := primitive.ObjectIDFromHex(copied.ID)
+	doc := bson.M{}
+	db.FindOne(server.SceneCollectionName, bson.M{"ID": id}, &doc)
+	if doc["Name"] != "TestSceneCopy2" || doc["IsTemplate"] != nil || doc["Thumbnail"] != nil {
+		t.Errorf("expect a scene named TestSceneCopy2 that is not a template, got %v", doc)
+	}
+	docs := []bson.M{}
+	db.FindAll(doc["CollectionName"].(string), &docs)
+	if len(docs) != 1 || docs[0]["name"] != "a" {
+		t.Errorf("expect data of version 0, got %v", docs)
+	}
+
+	body := get(TemplateList, "")
+	result := struct {
+		server.Result
+		Data []bson.M
+	}{}
+	helper.FromJSON([]byte(body), &result)
+	if len(result.Data) != 1 || result.Data[0]["ID"] != saved.ID || result.Data[0]["IsTemplate"] != true {
+		t.Errorf("expect the template, got %v", body)
+	}
+}
+
+func TestSceneSearch(t *testing.T) {
+	server.CreateEmbedded(testDir)
+
+	saved := post(t, Save, url.Values{
+		"Name": {"TestSceneSearch"},
+		"Data": {`[{"uuid":"1","name":"TestSceneSearchBox","type":"Mesh","material":{"uuid":"m1","type":"MeshStandardMaterial","name":"TestSceneSearchWood"}},` +
+			`{"uuid":"2","name":"Light","type":"PointLight"},` +
+			`{"uuid":"3","name":"Model","type":"Group","metadata":{"generator":"ServerObject"},"userData":{"Url":"/Upload/Model/TestSceneSearch.glb"}}]`},
+	})
+
+	search := func(query string) []SearchModel {
+		result := struct {
+			server.Result
+			Data []SearchModel
+		}{}
+		helper.FromJSON([]byte(get(Search, query)), &result)
+		return result.Data
+	}
+
+	tests := []struct {
+		query string
+		uuid  string
+	}{
+		{"Name=testscenesearchbox", "1"},
+		{"Type=pointlight", "2"},
+		{"Material=TestSceneSearchWood", "1"},
+		{"Url=TestSceneSearch.glb", "3"},
+		{"Name=TestSceneSearch&Type=Mesh", "1"},
+	}
+	for _, test := range tests {
+		list := search(test.query)
+		if len(list) != 1 || list[0].ID != saved.ID || len(list[0].Objects) != 1 || list[0].Objects[0].UUID != test.uuid {
+			t.Errorf("%v: expect object %v, got %v", test.query, test.uuid, list)
+		}
+	}
+	if list := search("Name=TestSceneSearchBox&Type=Group"); len(list) != 0 {
+		t.Errorf("expect no scenes, got %v", list)
+	}
+}
+
+func TestSceneThumbnail(t *testing.T) {
+	server.CreateEmbedded(testDir)
+
+	saved := post(t, Save, url.Values{
+		"Name": {"TestSceneThumbnail"},
+		"Data": {`[{"metadata":{"generator":"SceneSerializer"},"uuid":"0","userData":{"children":[{"uuid":"1","children":[]}]}},` +
+			`{"metadata":{"generator":"MeshSerializer"},"uuid":"1","position":{"x":1,"y":0,"z":0},` +
+			`"geometry":{"type":"BoxBufferGeometry","parameters":{"width":1,"height":1,"depth":1}},"material":{"color":16711680}}]`},
+	})
+
+	thumbnail.Wait()
+
+	db, _ := server.DB()
+	id, _ := primitive.ObjectIDFromHex(saved.ID)
+	doc := bson.M{}
+	db.FindOne(server.SceneCollectionName, bson.M{"ID": id}, &doc)
+	rendered, _ := doc["Thumbnail"].(string)
+	if !strings.HasPrefix(rendered, "/Upload/Thumbnail/") {
+		t.Errorf("expect a rendered thumbnail, got %v", doc["Thumbnail"])
+		return
+	}
+
+	file, err := os.Open(server.MapPath(rendered))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer file.Close()
+	img, err := png.Decode(file)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	// the red box is in the center
+	if r, g, _, _ := img.At(thumbnail.Size/2, thumbnail.Size/2).RGBA(); r == 0 || g != 0 {
+		t.Errorf("expect red in the center, got %v", img.At(thumbnail.Size/2, thumbnail.Size/2))
+	}
+
+	// restoring a version renders the thumbnail again.
+	call(Save, url.Values{"ID": {saved.ID}, "Name": {"TestSceneThumbnail"}, "Data": {"[]"}})
+	thumbnail.Wait()
+	call(Restore, url.Values{"ID": {saved.ID}, "Version": {"0"}})
+	thumbnail.Wait()
+	doc = bson.M{}
+	db.FindOne(server.SceneCollectionName, bson.M{"ID": id}, &doc)
+	if restored, _ := doc["Thumbnail"].(string); restored == rendered || !strings.HasPrefix(restored, "/Upload/Thumbnail/") {
+		t.Errorf("expect a new thumbnail after restore, got %v", restored)
+	}
+}
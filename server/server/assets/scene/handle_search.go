@@ -0,0 +1,100 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package scene
+
+import (
+	"net/http"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/tengge1/shadoweditor/helper"
+	"github.com/tengge1/shadoweditor/server"
+)
+
+func init() {
+	server.Handle(http.MethodGet, "/api/Scene/Search", Search, server.None)
+}
+
+// Search returns the scenes that have objects matching Name, Type, Material or Url,
+// and the uuids of the matching objects. Only the latest version of the scenes that
+// the current user can see is searched.
+func Search(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	query := searchQuery{
+		Name:     strings.TrimSpace(r.FormValue("Name")),
+		Type:     strings.TrimSpace(r.FormValue("Type")),
+		Material: strings.TrimSpace(r.FormValue("Material")),
+		URL:      strings.TrimSpace(r.FormValue("Url")),
+	}
+	if query.empty() {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "Name, Type, Material or Url is required.",
+		})
+		return
+	}
+
+	db, err := server.DB()
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	opts := options.FindOptions{
+		Sort: bson.M{
+			"UpdateTime": -1,
+		},
+	}
+	scenes := []bson.M{}
+	if err := db.FindMany(server.SceneCollectionName, listFilter(r), &scenes, &opts); err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	list := []SearchModel{}
+	for _, scene := range scenes {
+		collectionName, _ := scene["CollectionName"].(string)
+		if collectionName == "" {
+			continue
+		}
+		docs := []bson.M{}
+		if err := db.FindAll(collectionName, &docs); err != nil {
+			helper.WriteJSON(w, server.Result{
+				Code: 300,
+				Msg:  err.Error(),
+			})
+			return
+		}
+
+		objects := query.search(docs)
+		if len(objects) == 0 {
+			continue
+		}
+		model := SearchModel{
+			ID:      scene["ID"].(primitive.ObjectID).Hex(),
+			Objects: objects,
+		}
+		model.Name, _ = scene["Name"].(string)
+		list = append(list, model)
+	}
+
+	helper.WriteJSON(w, server.Result{
+		Code: 200,
+		Msg:  "Get Successfully!",
+		Data: list,
+	})
+}
@@ -0,0 +1,120 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package scene
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/tengge1/shadoweditor/helper"
+	"github.com/tengge1/shadoweditor/server"
+)
+
+func init() {
+	server.Handle(http.MethodPost, "/api/Scene/TagAdd", TagAdd, server.SaveScene)
+}
+
+// TagAdd attach a named tag to a scene version. Tagged versions are never pruned.
+func TagAdd(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	id, err := primitive.ObjectIDFromHex(strings.TrimSpace(r.FormValue("ID")))
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "ID is not allowed.",
+		})
+		return
+	}
+	version, err := strconv.Atoi(strings.TrimSpace(r.FormValue("Version")))
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "Version is not allowed.",
+		})
+		return
+	}
+	name := strings.TrimSpace(r.FormValue("Name"))
+	if err := checkTagName(name); err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+	note := strings.TrimSpace(r.FormValue("Note"))
+
+	db, err := server.DB()
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	doc := bson.M{}
+	find, _ := db.FindOne(server.SceneCollectionName, bson.M{"ID": id}, &doc)
+
+	if !find {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "The scene is not existed!",
+		})
+		return
+	}
+
+	if !canModify(r, doc) {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "Permission denied.",
+		})
+		return
+	}
+
+	if exists, _ := versionExists(db, doc, version); !exists {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "The version is not existed!",
+		})
+		return
+	}
+
+	filter := bson.M{
+		"SceneID": id,
+		"Name":    name,
+	}
+	if count, _ := db.Count(server.SceneTagCollectionName, filter); count > 0 {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "The name is already existed.",
+		})
+		return
+	}
+
+	now := time.Now()
+	tag := bson.M{
+		"ID":         primitive.NewObjectID(),
+		"SceneID":    id,
+		"Name":       name,
+		"Note":       note,
+		"Version":    version,
+		"CreateTime": now,
+		"UpdateTime": now,
+	}
+	db.InsertOne(server.SceneTagCollectionName, tag)
+
+	helper.WriteJSON(w, server.Result{
+		Code: 200,
+		Msg:  "Saved successfully!",
+	})
+}
@@ -0,0 +1,125 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package scene
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/tengge1/shadoweditor/helper"
+	"github.com/tengge1/shadoweditor/server"
+)
+
+func init() {
+	server.Handle(http.MethodPost, "/api/Scene/TagEdit", TagEdit, server.SaveScene)
+}
+
+// TagEdit rename a tag or change its note. The tag is found by Name, and NewName
+// is the name to rename to.
+func TagEdit(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	id, err := primitive.ObjectIDFromHex(strings.TrimSpace(r.FormValue("ID")))
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "ID is not allowed.",
+		})
+		return
+	}
+	name := strings.TrimSpace(r.FormValue("Name"))
+	newName := strings.TrimSpace(r.FormValue("NewName"))
+	if newName == "" {
+		newName = name
+	}
+	if err := checkTagName(newName); err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+	note := strings.TrimSpace(r.FormValue("Note"))
+
+	db, err := server.DB()
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	doc := bson.M{}
+	find, _ := db.FindOne(server.SceneCollectionName, bson.M{"ID": id}, &doc)
+
+	if !find {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "The scene is not existed!",
+		})
+		return
+	}
+
+	if !canModify(r, doc) {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "Permission denied.",
+		})
+		return
+	}
+
+	if newName != name {
+		filter := bson.M{
+			"SceneID": id,
+			"Name":    newName,
+		}
+		if count, _ := db.Count(server.SceneTagCollectionName, filter); count > 0 {
+			helper.WriteJSON(w, server.Result{
+				Code: 300,
+				Msg:  "The name is already existed.",
+			})
+			return
+		}
+	}
+
+	filter := bson.M{
+		"SceneID": id,
+		"Name":    name,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"Name":       newName,
+			"Note":       note,
+			"UpdateTime": time.Now(),
+		},
+	}
+	result, err := db.UpdateOne(server.SceneTagCollectionName, filter, update)
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+	if result.MatchedCount == 0 {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "The tag is not existed!",
+		})
+		return
+	}
+
+	helper.WriteJSON(w, server.Result{
+		Code: 200,
+		Msg:  "Saved successfully!",
+	})
+}
@@ -0,0 +1,58 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package scene
+
+import (
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/tengge1/shadoweditor/helper"
+	"github.com/tengge1/shadoweditor/server"
+)
+
+func init() {
+	server.Handle(http.MethodGet, "/api/Scene/TemplateList", TemplateList, server.None)
+}
+
+// TemplateList returns the scenes that new scenes can be copied from. Templates are
+// listed to everyone, no matter who they belong to.
+func TemplateList(w http.ResponseWriter, r *http.Request) {
+	db, err := server.DB()
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	filter := bson.M{
+		"IsTemplate": true,
+	}
+	opts := options.FindOptions{
+		Sort: bson.M{
+			"Name": 1,
+		},
+	}
+	docs := bson.A{}
+	if err := db.FindMany(server.SceneCollectionName, filter, &docs, &opts); err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	helper.WriteJSON(w, server.Result{
+		Code: 200,
+		Msg:  "Get Successfully!",
+		Data: newModels(db, docs),
+	})
+}
@@ -0,0 +1,99 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package scene
+
+import (
+	"net/http"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/tengge1/shadoweditor/helper"
+	"github.com/tengge1/shadoweditor/server"
+)
+
+func init() {
+	server.Handle(http.MethodPost, "/api/Scene/Unlock", Unlock, server.SaveScene)
+}
+
+// Unlock release the lock of a scene. Administrators can break others' locks.
+func Unlock(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	id, err := primitive.ObjectIDFromHex(strings.TrimSpace(r.FormValue("ID")))
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "ID is not allowed.",
+		})
+		return
+	}
+
+	if !server.Config.Authority.Enabled {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "Authority is not enabled.",
+		})
+		return
+	}
+
+	user, _ := server.GetCurrentUser(r)
+	if user == nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "Permission denied.",
+		})
+		return
+	}
+
+	db, err := server.DB()
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	filter := bson.M{
+		"ID": id,
+	}
+	doc := bson.M{}
+	find, _ := db.FindOne(server.SceneCollectionName, filter, &doc)
+
+	if !find {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "The scene is not existed!",
+		})
+		return
+	}
+
+	if user.RoleName != "Administrator" {
+		if err := checkLock(r, doc); err != nil {
+			helper.WriteJSON(w, server.Result{
+				Code: 300,
+				Msg:  err.Error(),
+			})
+			return
+		}
+	}
+
+	update := bson.M{
+		"$unset": bson.M{
+			"LockUserID":     1,
+			"LockExpireTime": 1,
+		},
+	}
+	db.UpdateOne(server.SceneCollectionName, filter, update)
+
+	helper.WriteJSON(w, server.Result{
+		Code: 200,
+		Msg:  "Unlocked successfully!",
+	})
+}
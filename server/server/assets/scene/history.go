@@ -0,0 +1,151 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package scene
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/tengge1/shadoweditor/helper"
+	"github.com/tengge1/shadoweditor/server"
+)
+
+// errConflict means that others have saved the scene since it was read.
+var errConflict = errors.New("the scene has been saved by others")
+
+// newCollectionName returns a collection name for a new scene. Scenes created in the
+// same second get a number suffix, so that they never share data.
+func newCollectionName(db server.Storage, now time.Time) (string, error) {
+	name := "Scene" + helper.TimeToString(now, "yyyyMMddHHmmss")
+	for i := 1; ; i++ {
+		count, err := db.Count(server.SceneCollectionName, bson.M{
+			"CollectionName": name,
+		})
+		if err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return name, nil
+		}
+		name = fmt.Sprintf("Scene%v_%v", helper.TimeToString(now, "yyyyMMddHHmmss"), i)
+	}
+}
+
+// canModify returns whether the current user is allowed to modify a scene.
+// Users can only modify their own scenes, and only administrators can modify
+// scenes that belong to nobody.
+func canModify(r *http.Request, doc bson.M) bool {
+	if !server.Config.Authority.Enabled {
+		return true
+	}
+	user, _ := server.GetCurrentUser(r)
+	if user == nil {
+		return false
+	}
+	if doc["UserID"] == nil {
+		return user.RoleName == "Administrator"
+	}
+	return doc["UserID"].(string) == user.ID
+}
+
+// canCollaborate returns whether the current user can co-edit a scene. Besides the
+// users who can modify the scene, the collaborators that the owner adds can co-edit it.
+func canCollaborate(r *http.Request, doc bson.M) bool {
+	if canModify(r, doc) {
+		return true
+	}
+	user, _ := server.GetCurrentUser(r)
+	if user == nil {
+		return false
+	}
+	collaborators, _ := doc["Collaborators"].(primitive.A)
+	for _, userID := range collaborators {
+		if userID == user.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// moveToHistory copies the current scene data to the history collection, and tags
+// them with version and the time when the version was saved.
+func moveToHistory(db server.Storage, collectionName string, version int, updateTime interface{}) error {
+	old := []bson.M{}
+	if err := db.FindAll(collectionName, &old); err != nil {
+		return err
+	}
+	if len(old) == 0 {
+		return nil
+	}
+
+	oldData := []interface{}{}
+	for _, i := range old {
+		// remove _id; otherwise deplicated
+		delete(i, "_id")
+		i[server.VersionField] = version
+		if updateTime != nil {
+			i[server.VersionTimeField] = updateTime
+		}
+		oldData = append(oldData, i)
+	}
+
+	_, err := db.InsertMany(collectionName+server.HistorySuffix, oldData)
+	return err
+}
+
+// saveData replaces the data of an existing scene, and moves the current data to
+// history. doc is the scene read before from docCollection (_Scene or _SceneBranch),
+// and errConflict is returned when the scene has been saved since then, so that
+// concurrent saves never overwrite each other.
+func saveData(db server.Storage, docCollection string, doc bson.M, data []interface{}, userID string) (int, error) {
+	collectionName := doc["CollectionName"].(string)
+	current := 0
+	if doc["Version"] != nil {
+		current = int(doc["Version"].(int32))
+	}
+
+	// compare and swap, so that only one of concurrent saves succeeds.
+	filter := bson.M{
+		"ID":      doc["ID"],
+		"Version": doc["Version"],
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"Version":      current + 1,
+			"UpdateTime":   time.Now(),
+			"UpdateUserID": userID,
+		},
+	}
+	result, err := db.UpdateOne(docCollection, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	if result.MatchedCount == 0 {
+		return 0, errConflict
+	}
+
+	// move current scene data to history
+	if err := moveToHistory(db, collectionName, current, doc["UpdateTime"]); err != nil {
+		return 0, err
+	}
+
+	if _, err := db.DeleteAll(collectionName); err != nil {
+		return 0, err
+	}
+	if len(data) > 0 {
+		if _, err := db.InsertMany(collectionName, data); err != nil {
+			return 0, err
+		}
+	}
+	return current + 1, nil
+}
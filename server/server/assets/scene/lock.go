@@ -0,0 +1,73 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package scene
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/tengge1/shadoweditor/server"
+)
+
+// lockLease is how long a scene lock lasts. The holder can lock the scene again
+// to renew it.
+var lockLease = 10 * time.Minute
+
+// LockModel is the lock of a scene.
+type LockModel struct {
+	// Scene ID
+	ID string
+	// The user who holds the lock
+	Username string
+	// The time when the lock expires
+	ExpireTime time.Time
+}
+
+// getLock returns the user who holds a valid lock of the scene, and when it expires.
+func getLock(doc bson.M) (userID string, expireTime time.Time, locked bool) {
+	userID, _ = doc["LockUserID"].(string)
+	expires, _ := doc["LockExpireTime"].(primitive.DateTime)
+	expireTime = expires.Time()
+	if userID == "" || !expireTime.After(time.Now()) {
+		return "", time.Time{}, false
+	}
+	return userID, expireTime, true
+}
+
+// checkLock returns an error if someone else holds a valid lock of the scene.
+func checkLock(r *http.Request, doc bson.M) error {
+	userID := ""
+	if _, _, locked := getLock(doc); locked {
+		if user, _ := server.GetCurrentUser(r); user != nil {
+			userID = user.ID
+		}
+	}
+	return checkUserLock(userID, doc)
+}
+
+// checkUserLock returns an error if a user other than userID holds a valid lock of
+// the scene.
+func checkUserLock(userID string, doc bson.M) error {
+	if !server.Config.Authority.Enabled {
+		return nil
+	}
+	lockUserID, expireTime, locked := getLock(doc)
+	if !locked || lockUserID == userID {
+		return nil
+	}
+
+	username := lockUserID
+	if user, _ := server.GetUser(lockUserID); user != nil {
+		username = user.Username
+	}
+	return fmt.Errorf("The scene is locked by %v until %v.", username, expireTime.Format("2006-01-02 15:04:05"))
+}
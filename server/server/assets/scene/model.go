@@ -35,6 +35,111 @@ type Model struct {
 	Thumbnail string
 	// Is Public
 	IsPublic bool
+	// Whether new scenes can be copied from the scene in the template list
+	IsTemplate bool
 	// The user who the scene belong to
 	Username string
+	// IDs of the users who can co-edit the scene besides its owner
+	Collaborators []string
+	// The user who holds the lock, empty when the scene is not locked
+	LockUsername string
+	// The time when the lock expires
+	LockExpireTime *time.Time `json:",omitempty"`
+}
+
+// TagModel is a named tag of a scene version.
+type TagModel struct {
+	// ID
+	ID string
+	// Scene ID
+	SceneID string
+	// Tag Name, such as `client review 2`
+	Name string
+	// Note
+	Note string
+	// The tagged version
+	Version int
+	// Create Time
+	CreateTime time.Time
+	// Update Time
+	UpdateTime time.Time
+}
+
+// BranchModel is a branch of a scene.
+type BranchModel struct {
+	// ID
+	ID string
+	// Scene ID
+	SceneID string
+	// Branch Name
+	Name string
+	// Collection Name
+	CollectionName string
+	// Version of the branch
+	Version int
+	// The scene version that the branch is forked from
+	ForkVersion int
+	// The branch version that the scene and the branch last have in common
+	MergeBase int
+	// Create Time
+	CreateTime time.Time
+	// Update Time
+	UpdateTime time.Time
+	// The user who created the branch
+	Username string
+}
+
+// PublishModel is an immutable snapshot of a scene version that is shared by a token.
+type PublishModel struct {
+	// ID
+	ID string
+	// Scene ID
+	SceneID string
+	// Share token, the snapshot is loaded by `/api/Scene/Load?Share=token`
+	Token string
+	// The published version
+	Version int
+	// Whether a password is required to load the snapshot
+	HasPassword bool
+	// The time when the share link expires, nil when it never expires
+	ExpireTime *time.Time `json:",omitempty"`
+	// Create Time
+	CreateTime time.Time
+	// The user who published the scene
+	Username string
+}
+
+// ConflictModel is a conflict when merging a branch into its scene.
+type ConflictModel struct {
+	// UUID
+	UUID string
+	// Name
+	Name string
+	// Type
+	Type string
+	// JSON path of the field, empty when the object is removed on one side and
+	// modified on the other side
+	Path string
+	// Value at the merge base
+	Base interface{}
+	// Value in the scene, nil when it is removed
+	Scene interface{}
+	// Value in the branch, nil when it is removed
+	Branch interface{}
+}
+
+// DependencyModel is an asset file referenced by a scene.
+type DependencyModel struct {
+	// The referenced url
+	URL string `json:"Url"`
+	// The collection of the asset, such as `_Mesh` and `_Map`, empty when no asset owns the url
+	CollectionName string
+	// Asset ID
+	ID string
+	// Asset Name
+	Name string
+	// File size in bytes
+	Size int64
+	// Whether the file still exists
+	Exists bool
 }
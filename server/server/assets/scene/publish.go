@@ -0,0 +1,187 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package scene
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/tengge1/shadoweditor/helper"
+	"github.com/tengge1/shadoweditor/server"
+)
+
+// A publish is an immutable snapshot of a scene version. Its data is copied to
+// `Publish` + token, so that saving, restoring or deleting the scene never changes
+// what the share link shows.
+
+// errPublishExpired means that the share link has expired.
+var errPublishExpired = errors.New("The share link has expired.")
+
+// errPublishPassword means that the password of the share link is wrong.
+var errPublishPassword = errors.New("The password is wrong.")
+
+// newShareToken returns a random token that can not be guessed.
+func newShareToken() (string, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
+
+// hashSharePassword returns the md5 of a share password and its salt.
+func hashSharePassword(password, salt string) string {
+	return helper.MD5(password + salt)
+}
+
+// findPublish returns the publish of a share token, and checks its expiry and
+// password.
+func findPublish(db server.Storage, token, password string, now time.Time) (bson.M, bool, error) {
+	doc := bson.M{}
+	find, err := db.FindOne(server.ScenePublishCollectionName, bson.M{"Token": token}, &doc)
+	if err != nil || !find {
+		return nil, false, err
+	}
+	if expireTime, ok := doc["ExpireTime"].(primitive.DateTime); ok && now.After(expireTime.Time()) {
+		return doc, true, errPublishExpired
+	}
+	if hash, _ := doc["Password"].(string); hash != "" {
+		salt, _ := doc["Salt"].(string)
+		if hashSharePassword(password, salt) != hash {
+			return doc, true, errPublishPassword
+		}
+	}
+	return doc, true, nil
+}
+
+// publishVersion copies a scene version to a new snapshot collection, and returns the
+// publish doc.
+func publishVersion(db server.Storage, scene bson.M, version int, expireTime *time.Time, password, userID string) (bson.M, error) {
+	docs, err := server.LoadSceneVersion(db, scene, version)
+	if err != nil {
+		return nil, err
+	}
+	if version == -1 {
+		version = 0
+		if current, ok := scene["Version"].(int32); ok {
+			version = int(current)
+		}
+	}
+
+	token, err := newShareToken()
+	if err != nil {
+		return nil, err
+	}
+	collectionName := "Publish" + token
+
+	data := []interface{}{}
+	for _, doc := range docs {
+		delete(doc, "_id")
+		delete(doc, server.VersionField)
+		data = append(data, doc)
+	}
+	if len(data) > 0 {
+		if _, err := db.InsertMany(collectionName, data); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	publish := bson.M{
+		"ID":             primitive.NewObjectID(),
+		"SceneID":        scene["ID"],
+		"Token":          token,
+		"CollectionName": collectionName,
+		"Version":        version,
+		"Password":       "",
+		"Salt":           "",
+		"CreateTime":     now,
+		"UserID":         userID,
+	}
+	if expireTime != nil {
+		publish["ExpireTime"] = *expireTime
+	}
+	if password != "" {
+		salt := helper.TimeToString(now, "yyyyMMddHHmmss")
+		publish["Password"] = hashSharePassword(password, salt)
+		publish["Salt"] = salt
+	}
+	if _, err := db.InsertOne(server.ScenePublishCollectionName, publish); err != nil {
+		return nil, err
+	}
+	return publish, nil
+}
+
+// deletePublish deletes a publish and its snapshot.
+func deletePublish(db server.Storage, publish bson.M) error {
+	if err := db.DropCollection(publish["CollectionName"].(string)); err != nil {
+		return err
+	}
+	_, err := db.DeleteOne(server.ScenePublishCollectionName, bson.M{
+		"ID": publish["ID"],
+	})
+	return err
+}
+
+// deletePublishes deletes all the publishes of a scene.
+func deletePublishes(db server.Storage, sceneID primitive.ObjectID) error {
+	publishes := []bson.M{}
+	if err := db.FindMany(server.ScenePublishCollectionName, bson.M{"SceneID": sceneID}, &publishes); err != nil {
+		return err
+	}
+	for _, publish := range publishes {
+		if err := deletePublish(db, publish); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newPublishModel converts a publish doc to the model returned to clients. The
+// password hash is never returned.
+func newPublishModel(doc bson.M) PublishModel {
+	publish := PublishModel{
+		ID: doc["ID"].(primitive.ObjectID).Hex(),
+	}
+	if sceneID, ok := doc["SceneID"].(primitive.ObjectID); ok {
+		publish.SceneID = sceneID.Hex()
+	}
+	publish.Token, _ = doc["Token"].(string)
+	switch version := doc["Version"].(type) {
+	case int32:
+		publish.Version = int(version)
+	case int:
+		publish.Version = version
+	}
+	password, _ := doc["Password"].(string)
+	publish.HasPassword = password != ""
+	switch expireTime := doc["ExpireTime"].(type) {
+	case primitive.DateTime:
+		t := expireTime.Time()
+		publish.ExpireTime = &t
+	case time.Time:
+		publish.ExpireTime = &expireTime
+	}
+	switch createTime := doc["CreateTime"].(type) {
+	case primitive.DateTime:
+		publish.CreateTime = createTime.Time()
+	case time.Time:
+		publish.CreateTime = createTime
+	}
+	if userID, ok := doc["UserID"].(string); ok && userID != "" {
+		if user, _ := server.GetUser(userID); user != nil {
+			publish.Username = user.Username
+		}
+	}
+	return publish
+}
@@ -0,0 +1,127 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package scene
+
+import (
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/tengge1/shadoweditor/server"
+)
+
+// SearchModel is a scene that has objects matching a search.
+type SearchModel struct {
+	// Scene ID
+	ID string
+	// Scene Name
+	Name string
+	// The matching objects
+	Objects []SearchObjectModel
+}
+
+// SearchObjectModel is an object matching a search.
+type SearchObjectModel struct {
+	// UUID
+	UUID string
+	// Name
+	Name string
+	// Type, such as `Mesh`
+	Type string
+}
+
+// searchQuery is the conditions of searching objects in scenes, and an object
+// matches when it matches all the conditions that are not empty.
+type searchQuery struct {
+	// part of the object name, case insensitive
+	Name string
+	// object type, such as `Mesh`, case insensitive
+	Type string
+	// part of the material name, or the material type or uuid
+	Material string
+	// part of an asset url that the object references
+	URL string
+}
+
+func (q searchQuery) empty() bool {
+	return q.Name == "" && q.Type == "" && q.Material == "" && q.URL == ""
+}
+
+// search returns the objects of a scene that match the query.
+func (q searchQuery) search(docs []bson.M) []SearchObjectModel {
+	list := []SearchObjectModel{}
+	for _, doc := range docs {
+		uuid, _ := doc["uuid"].(string)
+		if uuid == "" || !q.match(doc) {
+			continue
+		}
+		object := SearchObjectModel{
+			UUID: uuid,
+		}
+		object.Name, _ = doc["name"].(string)
+		object.Type, _ = doc["type"].(string)
+		list = append(list, object)
+	}
+	return list
+}
+
+func (q searchQuery) match(doc bson.M) bool {
+	if q.Name != "" {
+		name, _ := doc["name"].(string)
+		if !containsFold(name, q.Name) {
+			return false
+		}
+	}
+	if q.Type != "" {
+		objectType, _ := doc["type"].(string)
+		if !strings.EqualFold(objectType, q.Type) {
+			return false
+		}
+	}
+	if q.Material != "" && !q.matchMaterial(doc["material"]) {
+		return false
+	}
+	if q.URL != "" {
+		matched := false
+		for _, url := range server.SceneURLs([]bson.M{doc}) {
+			if strings.Contains(url, q.URL) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// matchMaterial returns whether a material, or one of multiple materials, matches.
+func (q searchQuery) matchMaterial(value interface{}) bool {
+	if materials, ok := value.(bson.A); ok {
+		for _, material := range materials {
+			if q.matchMaterial(material) {
+				return true
+			}
+		}
+		return false
+	}
+	material, ok := value.(bson.M)
+	if !ok {
+		return false
+	}
+	name, _ := material["name"].(string)
+	materialType, _ := material["type"].(string)
+	uuid, _ := material["uuid"].(string)
+	return containsFold(name, q.Material) || strings.EqualFold(materialType, q.Material) || uuid == q.Material
+}
+
+// containsFold returns whether s contains substr, case insensitive.
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
@@ -0,0 +1,88 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package scene
+
+import (
+	"fmt"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/tengge1/shadoweditor/server"
+)
+
+// checkTagName returns an error if a tag name is not allowed. Tag names can not be
+// numbers, otherwise they can not be told from version numbers.
+func checkTagName(name string) error {
+	if name == "" {
+		return fmt.Errorf("Name is not allowed to be empty.")
+	}
+	if _, err := strconv.Atoi(name); err == nil {
+		return fmt.Errorf("Name is not allowed to be a number.")
+	}
+	return nil
+}
+
+// getTags returns the tags of a scene, and the latest version comes first.
+func getTags(db server.Storage, sceneID primitive.ObjectID) ([]TagModel, error) {
+	filter := bson.M{
+		"SceneID": sceneID,
+	}
+	opts := options.FindOptions{
+		Sort: bson.M{
+			"Version": -1,
+		},
+	}
+	docs := []bson.M{}
+	if err := db.FindMany(server.SceneTagCollectionName, filter, &docs, &opts); err != nil {
+		return nil, err
+	}
+
+	list := []TagModel{}
+	for _, doc := range docs {
+		tag := TagModel{
+			ID:      doc["ID"].(primitive.ObjectID).Hex(),
+			SceneID: sceneID.Hex(),
+		}
+		tag.Name, _ = doc["Name"].(string)
+		tag.Note, _ = doc["Note"].(string)
+		if version, ok := doc["Version"].(int32); ok {
+			tag.Version = int(version)
+		}
+		if createTime, ok := doc["CreateTime"].(primitive.DateTime); ok {
+			tag.CreateTime = createTime.Time()
+		}
+		if updateTime, ok := doc["UpdateTime"].(primitive.DateTime); ok {
+			tag.UpdateTime = updateTime.Time()
+		}
+		list = append(list, tag)
+	}
+	return list, nil
+}
+
+// versionExists returns whether a version of the scene exists.
+func versionExists(db server.Storage, doc bson.M, version int) (bool, error) {
+	current := 0
+	if doc["Version"] != nil {
+		current = int(doc["Version"].(int32))
+	}
+	if version == current {
+		return true, nil
+	}
+
+	filter := bson.M{
+		server.VersionField: version,
+	}
+	count, err := db.Count(doc["CollectionName"].(string)+server.HistorySuffix, filter)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
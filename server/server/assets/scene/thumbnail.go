@@ -0,0 +1,212 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package scene
+
+import (
+	"path"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/tengge1/shadoweditor/server"
+	"github.com/tengge1/shadoweditor/server/assets/mesh"
+	"github.com/tengge1/shadoweditor/server/thumbnail"
+	"github.com/tengge1/shadoweditor/three"
+)
+
+// thumbnailBuilder collects the meshes of a scene in world space.
+type thumbnailBuilder struct {
+	objects map[string]bson.M
+	meshes  []thumbnail.Mesh
+	// models are the uploaded models that have been read, so that a model used
+	// many times is read once.
+	models map[string]*mesh.Geometry
+}
+
+// updateThumbnail renders the thumbnail of a scene in the background after its data
+// is changed.
+func updateThumbnail(db server.Storage, id primitive.ObjectID) {
+	thumbnail.Update(server.SceneCollectionName, id, func() ([]thumbnail.Mesh, error) {
+		return sceneMeshes(db, id)
+	})
+}
+
+// sceneMeshes returns the meshes of the latest data of a scene.
+func sceneMeshes(db server.Storage, id primitive.ObjectID) ([]thumbnail.Mesh, error) {
+	doc := bson.M{}
+	find, err := db.FindOne(server.SceneCollectionName, bson.M{"ID": id}, &doc)
+	if err != nil || !find {
+		return nil, err
+	}
+	docs := []bson.M{}
+	if err := db.FindAll(doc["CollectionName"].(string), &docs); err != nil {
+		return nil, err
+	}
+	return Meshes(docs), nil
+}
+
+// DataMeshes returns the meshes of the `Data` field of a doc, such as a prefab or a
+// character. Data is a list of serialized objects like scene data, or one object.
+func DataMeshes(db server.Storage, collectionName string, id primitive.ObjectID) ([]thumbnail.Mesh, error) {
+	doc := bson.M{}
+	find, err := db.FindOne(collectionName, bson.M{"ID": id}, &doc)
+	if err != nil || !find {
+		return nil, err
+	}
+	data, _ := doc["Data"].(string)
+	data = strings.TrimSpace(data)
+
+	docs := []bson.M{}
+	if strings.HasPrefix(data, "{") {
+		object := bson.M{}
+		if err := bson.UnmarshalExtJSON([]byte(data), false, &object); err != nil {
+			return nil, err
+		}
+		docs = append(docs, object)
+	} else if data != "" {
+		if err := bson.UnmarshalExtJSON([]byte(data), false, &docs); err != nil {
+			return nil, err
+		}
+	}
+	return Meshes(docs), nil
+}
+
+// Meshes returns the meshes of serialized objects in world space. Parametric
+// geometries and uploaded models that mesh.ReadGeometry supports are rendered, and
+// other objects are skipped. Callers hold three.Mutex.
+func Meshes(docs []bson.M) []thumbnail.Mesh {
+	b := thumbnailBuilder{
+		objects: map[string]bson.M{},
+		meshes:  []thumbnail.Mesh{},
+		models:  map[string]*mesh.Geometry{},
+	}
+	var scene bson.M
+	for _, doc := range docs {
+		if generatorOf(doc) == "SceneSerializer" {
+			scene = doc
+		} else if uuid, ok := doc["uuid"].(string); ok && uuid != "" {
+			b.objects[uuid] = doc
+		}
+	}
+
+	if userData, ok := scene["userData"].(bson.M); ok {
+		if children, ok := userData["children"].(bson.A); ok && len(children) > 0 {
+			b.addChildren(children, three.NewMatrix4())
+			return b.meshes
+		}
+	}
+	// no hierarchy, objects are rendered with their own transforms
+	for _, doc := range docs {
+		if generatorOf(doc) != "SceneSerializer" {
+			b.addObject(doc, server.ObjectMatrix(doc))
+		}
+	}
+	return b.meshes
+}
+
+// addChildren adds the objects of a hierarchy like `[{uuid, children}]`.
+func (b *thumbnailBuilder) addChildren(children bson.A, parent *three.Matrix4) {
+	for _, i := range children {
+		child, ok := i.(bson.M)
+		if !ok {
+			continue
+		}
+		uuid, _ := child["uuid"].(string)
+		doc := b.objects[uuid]
+		if doc == nil {
+			continue
+		}
+		world := three.NewMatrix4().MultiplyMatrices(*parent, *server.ObjectMatrix(doc))
+		// the children of uploaded models are in the model files
+		if b.addObject(doc, world) {
+			continue
+		}
+		if grandChildren, ok := child["children"].(bson.A); ok {
+			b.addChildren(grandChildren, world)
+		}
+	}
+}
+
+// addObject adds the triangles of an object, and returns whether it is an uploaded
+// model.
+func (b *thumbnailBuilder) addObject(doc bson.M, world *three.Matrix4) bool {
+	switch generatorOf(doc) {
+	case "MeshSerializer":
+		geometryDoc, _ := doc["geometry"].(bson.M)
+		geometry := server.NewGeometry(geometryDoc)
+		if geometry == nil {
+			return false
+		}
+		item := thumbnail.Mesh{
+			Indices: geometry.Index,
+			Color:   materialColor(doc["material"]),
+		}
+		for i := 0; i+2 < len(geometry.Position); i += 3 {
+			position := three.NewVector3(geometry.Position[i], geometry.Position[i+1], geometry.Position[i+2])
+			item.Positions = append(item.Positions, *position.ApplyMatrix4(*world))
+		}
+		b.meshes = append(b.meshes, item)
+	case "ServerObject":
+		userData, _ := doc["userData"].(bson.M)
+		url, _ := userData["Url"].(string)
+		meshType, _ := userData["Type"].(string)
+		if url == "" || !mesh.CanReadGeometry(mesh.Type(meshType)) {
+			return true
+		}
+		model, ok := b.models[url]
+		if !ok {
+			// clean the path, so that it never goes out of the public dir.
+			var err error
+			if model, err = mesh.ReadGeometry(server.MapPath(path.Clean(url)), server.Config.Upload.MaxGeometrySize); err != nil {
+				server.Logger.Warnf("read mesh %v: %v", url, err)
+			}
+			b.models[url] = model
+		}
+		if model == nil {
+			return true
+		}
+		item := thumbnail.Mesh{
+			Indices: model.Indices,
+			Color:   thumbnail.DefaultColor,
+		}
+		for _, position := range model.Positions {
+			item.Positions = append(item.Positions, *position.ApplyMatrix4(*world))
+		}
+		b.meshes = append(b.meshes, item)
+		return true
+	}
+	return false
+}
+
+// materialColor returns the color of a material, or the first one of multiple
+// materials.
+func materialColor(value interface{}) int {
+	material, _ := value.(bson.M)
+	if materials, ok := value.(bson.A); ok && len(materials) > 0 {
+		material, _ = materials[0].(bson.M)
+	}
+	switch color := material["color"].(type) {
+	case int32:
+		return int(color)
+	case int64:
+		return int(color)
+	case float64:
+		return int(color)
+	}
+	return thumbnail.DefaultColor
+}
+
+// generatorOf returns the serializer of an object.
+func generatorOf(doc bson.M) string {
+	if metadata, ok := doc["metadata"].(bson.M); ok {
+		generator, _ := metadata["generator"].(string)
+		return generator
+	}
+	return ""
+}
@@ -95,7 +95,7 @@ func Add(w http.ResponseWriter, r *http.Request) {
 	// save to mongo
 	pinyin := helper.ConvertToPinYin(fileNameWithoutExt)
 
-	db, err := server.Mongo()
+	db, err := server.DB()
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
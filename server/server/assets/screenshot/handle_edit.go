@@ -46,7 +46,7 @@ func Edit(w http.ResponseWriter, r *http.Request) {
 	thumbnail := strings.TrimSpace(r.FormValue("Image"))
 
 	// update mongo
-	db, err := server.Mongo()
+	db, err := server.DB()
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
@@ -22,7 +22,7 @@ func init() {
 
 // List returns the assets info list.
 func List(w http.ResponseWriter, r *http.Request) {
-	db, err := server.Mongo()
+	db, err := server.DB()
 	if err != nil {
 		helper.Write(w, err.Error())
 		return
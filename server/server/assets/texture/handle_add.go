@@ -9,10 +9,8 @@ package texture
 
 import (
 	"fmt"
-	"io"
-	"mime/multipart"
 	"net/http"
-	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
@@ -30,8 +28,15 @@ func init() {
 
 // Add upload a texture.
 func Add(w http.ResponseWriter, r *http.Request) {
-	r.ParseMultipartForm(server.Config.Upload.MaxSize)
-	files := r.MultipartForm.File
+	// files are in the form, or are completed chunked uploads.
+	files, err := server.FormFiles(r)
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
 
 	// check upload file
 	if len(files) != 1 && len(files) != 6 {
@@ -43,7 +48,7 @@ func Add(w http.ResponseWriter, r *http.Request) {
 	}
 
 	for _, val := range files {
-		ext := filepath.Ext(val[0].Filename)
+		ext := filepath.Ext(val.Filename)
 		if ext == "" ||
 			strings.ToLower(ext) != ".jpg" &&
 				strings.ToLower(ext) != ".jpeg" &&
@@ -58,65 +63,61 @@ func Add(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// save file
-	now := time.Now()
-
-	savePath := fmt.Sprintf("/Upload/Texture/%v", helper.TimeToString(now, "yyyyMMddHHmmss"))
-	physicalPath := server.MapPath(savePath)
+	key := "file"
+	if len(files) == 6 {
+		key = "posX"
+	}
+	if files[key] == nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  fmt.Sprintf("%v is not uploaded!", key),
+		})
+		return
+	}
 
-	if _, err := os.Stat(physicalPath); os.IsNotExist(err) {
-		os.MkdirAll(physicalPath, 0755)
+	db, err := server.DB()
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
 	}
 
-	for _, val := range files {
-		target, err := os.Create(fmt.Sprintf("%v/%v", physicalPath, val[0].Filename))
-		if err != nil {
-			helper.WriteJSON(w, server.Result{
-				Code: 300,
-				Msg:  err.Error(),
-			})
-			return
-		}
-		defer target.Close()
+	// save files, and files with the same content share storage.
+	now := time.Now()
+	urls := map[string]string{}
+	hashes := []string{}
 
-		source, err := val[0].Open()
+	for name, val := range files {
+		blob, err := addBlob(db, val)
 		if err != nil {
+			for _, hash := range hashes {
+				server.ReleaseBlob(db, hash)
+			}
 			helper.WriteJSON(w, server.Result{
 				Code: 300,
 				Msg:  err.Error(),
 			})
 			return
 		}
-		defer source.Close()
-		io.Copy(target, source)
+		urls[name] = blob.URL()
+		hashes = append(hashes, blob.Hash)
 	}
 
 	// save to Mongo
 	// Cute Texture: File info use posX except url.
-	var file *multipart.FileHeader = nil
-	if len(files) == 6 {
-		file = files["posX"][0]
-	} else {
-		file = files["file"][0]
-	}
+	file := files[key]
+	url := urls[key]
 
 	fileName := file.Filename
 	fileSize := file.Size
-	fileType := file.Header.Get("Content-Type")
+	fileType := file.ContentType
 	fileExt := filepath.Ext(fileName)
 	fileNameWithoutExt := strings.TrimRight(fileName, fileExt)
 
 	pinyin := helper.ConvertToPinYin(fileNameWithoutExt)
 
-	db, err := server.Mongo()
-	if err != nil {
-		helper.WriteJSON(w, server.Result{
-			Code: 300,
-			Msg:  err.Error(),
-		})
-		return
-	}
-
 	doc := bson.M{
 		"ID":          primitive.NewObjectID(),
 		"AddTime":     now,
@@ -126,7 +127,8 @@ func Add(w http.ResponseWriter, r *http.Request) {
 		"FirstPinYin": pinyin.FirstPinYin,
 		"Name":        fileNameWithoutExt,
 		"SaveName":    fileName,
-		"SavePath":    savePath,
+		"SavePath":    path.Dir(url),
+		"Blobs":       hashes,
 	}
 
 	if strings.ToLower(filepath.Ext(file.Filename)) == ".mp4" {
@@ -134,7 +136,7 @@ func Add(w http.ResponseWriter, r *http.Request) {
 		doc["Thumbnail"] = ""
 	} else {
 		// TODO: sky ball is too big, generate a thumbnail
-		doc["Thumbnail"] = fmt.Sprintf("%v/%v", savePath, fileName)
+		doc["Thumbnail"] = url
 	}
 
 	doc["TotalPinYin"] = pinyin.TotalPinYin
@@ -143,24 +145,24 @@ func Add(w http.ResponseWriter, r *http.Request) {
 		doc["Type"] = Cube
 
 		doc1 := bson.M{
-			"PosX": fmt.Sprintf("%v/%v", savePath, files["posX"][0].Filename),
-			"NegX": fmt.Sprintf("%v/%v", savePath, files["negX"][0].Filename),
-			"PosY": fmt.Sprintf("%v/%v", savePath, files["posY"][0].Filename),
-			"NegY": fmt.Sprintf("%v/%v", savePath, files["negY"][0].Filename),
-			"PosZ": fmt.Sprintf("%v/%v", savePath, files["posZ"][0].Filename),
-			"NegZ": fmt.Sprintf("%v/%v", savePath, files["negZ"][0].Filename),
+			"PosX": urls["posX"],
+			"NegX": urls["negX"],
+			"PosY": urls["posY"],
+			"NegY": urls["negY"],
+			"PosZ": urls["posZ"],
+			"NegZ": urls["negZ"],
 		}
 
 		doc["Url"] = doc1
 	} else if strings.ToLower(filepath.Ext(file.Filename)) == ".mp4" { // 视频贴图
 		doc["Type"] = Video
-		doc["Url"] = fmt.Sprintf("%v/%v", savePath, fileName)
+		doc["Url"] = url
 	} else if textureType == "skyBall" {
 		doc["Type"] = SkyBall
-		doc["Url"] = fmt.Sprintf("%v/%v", savePath, fileName)
+		doc["Url"] = url
 	} else {
 		doc["Type"] = Unknown
-		doc["Url"] = fmt.Sprintf("%v/%v", savePath, fileName)
+		doc["Url"] = url
 	}
 
 	doc["CreateTime"] = now
@@ -174,10 +176,31 @@ func Add(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	db.InsertOne(server.MapCollectionName, doc)
+	if _, err := db.InsertOne(server.MapCollectionName, doc); err != nil {
+		server.RemoveAssetFiles(db, doc)
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	// the chunked uploads are kept until the asset is stored, so that a failed add
+	// does not need to upload the files again.
+	server.RemoveUploads(files)
 
 	helper.WriteJSON(w, server.Result{
 		Code: 200,
 		Msg:  "Upload successfully!",
 	})
 }
+
+// addBlob saves an uploaded file as a blob.
+func addBlob(db server.Storage, file *server.UploadFile) (*server.Blob, error) {
+	source, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer source.Close()
+	return server.AddBlob(db, source, file.Filename, nil)
+}
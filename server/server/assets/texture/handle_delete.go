@@ -9,7 +9,7 @@ package texture
 
 import (
 	"net/http"
-	"os"
+	"strconv"
 	"strings"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -23,7 +23,7 @@ func init() {
 	server.Handle(http.MethodPost, "/api/Map/Delete", Delete, server.DeleteTexture)
 }
 
-// Delete delete a texture
+// Delete delete a texture. A texture that is still used is not deleted unless Force is true.
 func Delete(w http.ResponseWriter, r *http.Request) {
 	r.ParseForm()
 	id, err := primitive.ObjectIDFromHex(strings.TrimSpace(r.FormValue("ID")))
@@ -35,7 +35,7 @@ func Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	db, err := server.Mongo()
+	db, err := server.DB()
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
@@ -59,11 +59,10 @@ func Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// delete the folder where texture is
-	path := doc["SavePath"].(string)
-	physicalPath := server.MapPath(path)
-
-	err = os.RemoveAll(physicalPath)
+	// refuse to delete an asset that is still referenced, unless Force is true. Files
+	// that other assets share are kept, so the references do not break.
+	force, _ := strconv.ParseBool(r.FormValue("Force"))
+	shared, err := server.AssetFilesShared(db, doc)
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
@@ -71,6 +70,33 @@ func Delete(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	if savePath, _ := doc["SavePath"].(string); !force && !shared && savePath != "" {
+		list, err := server.WhereUsed(db, savePath)
+		if err != nil {
+			helper.WriteJSON(w, server.Result{
+				Code: 300,
+				Msg:  err.Error(),
+			})
+			return
+		}
+		if len(list) > 0 {
+			helper.WriteJSON(w, server.Result{
+				Code: 302,
+				Msg:  "The asset is still used, and Force is required to delete it.",
+				Data: list,
+			})
+			return
+		}
+	}
+
+	// delete the files of the texture, and files shared with other assets are kept
+	if err := server.RemoveAssetFiles(db, doc); err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
 
 	db.DeleteOne(server.MapCollectionName, filter)
 
@@ -29,7 +29,7 @@ func init() {
 func List(w http.ResponseWriter, r *http.Request) {
 	r.ParseForm()
 
-	db, err := server.Mongo()
+	db, err := server.DB()
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
@@ -16,14 +16,33 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"strconv"
+	"strings"
 	"testing"
 
-	"github.com/prometheus/common/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/tengge1/shadoweditor/helper"
 	"github.com/tengge1/shadoweditor/server"
 )
 
+// testDir is the directory that tests store data in, so that tests need no mongo.
+var testDir string
+
+func TestMain(m *testing.M) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		panic(err)
+	}
+	testDir = dir
+	code := m.Run()
+	os.RemoveAll(testDir)
+	os.Exit(code)
+}
+
 func TestTextureList(t *testing.T) {
-	server.Create("../config.toml")
+	server.CreateEmbedded(testDir)
 
 	ts := httptest.NewServer(http.HandlerFunc(List))
 	defer ts.Close()
@@ -45,7 +64,7 @@ func TestTextureList(t *testing.T) {
 }
 
 func TestTextureAdd(t *testing.T) {
-	server.Create("../config.toml")
+	server.CreateEmbedded(testDir)
 
 	ts := httptest.NewServer(http.HandlerFunc(Add))
 	defer ts.Close()
@@ -58,13 +77,13 @@ func TestTextureAdd(t *testing.T) {
 
 	fileWriter, err := bodyWriter.CreateFormFile("file", name)
 	if err != nil {
-		log.Error(err)
+		t.Log(err)
 		return
 	}
 
 	file, err := os.Open(path)
 	if err != nil {
-		log.Error(err)
+		t.Log(err)
 		return
 	}
 	defer file.Close()
@@ -91,7 +110,7 @@ func TestTextureAdd(t *testing.T) {
 }
 
 func TestTextureEdit(t *testing.T) {
-	server.Create("../config.toml")
+	server.CreateEmbedded(testDir)
 
 	ts := httptest.NewServer(http.HandlerFunc(Edit))
 	defer ts.Close()
@@ -117,7 +136,7 @@ func TestTextureEdit(t *testing.T) {
 }
 
 func TestTextureDelete(t *testing.T) {
-	server.Create("../config.toml")
+	server.CreateEmbedded(testDir)
 
 	ts := httptest.NewServer(http.HandlerFunc(Delete))
 	defer ts.Close()
@@ -139,3 +158,130 @@ func TestTextureDelete(t *testing.T) {
 
 	t.Log(string(bytes))
 }
+
+func TestTextureWhereUsed(t *testing.T) {
+	server.CreateEmbedded(testDir)
+	db, err := server.DB()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	id := primitive.NewObjectID()
+	db.InsertOne(server.MapCollectionName, bson.M{
+		"ID":       id,
+		"Name":     "TestTextureWhereUsed",
+		"SavePath": "/Upload/Texture/20200101000000",
+		"Url":      "/Upload/Texture/20200101000000/a.png",
+	})
+	db.InsertOne(server.SceneCollectionName, bson.M{
+		"ID":             primitive.NewObjectID(),
+		"Name":           "TestTextureWhereUsed",
+		"CollectionName": "SceneTestTextureWhereUsed",
+	})
+	db.InsertOne("SceneTestTextureWhereUsed", bson.M{
+		"metadata": bson.M{"generator": "SceneSerializer"},
+		"background": bson.M{
+			"image": bson.M{"src": "/Upload/Texture/20200101000000/a.png"},
+		},
+	})
+	db.InsertOne(server.MaterialCollectionName, bson.M{
+		"ID":   primitive.NewObjectID(),
+		"Name": "TestTextureWhereUsed",
+		"Data": `{"type":"MeshBasicMaterial","map":{"image":{"src":"/Upload/Texture/20200101000000/a.png"}}}`,
+	})
+
+	result := post(t, WhereUsed, url.Values{"ID": {id.Hex()}})
+	if list, ok := result.Data.([]interface{}); result.Code != 200 || !ok || len(list) != 2 {
+		t.Errorf("expect a scene and a material, got %v", result)
+	}
+
+	if result := post(t, Delete, url.Values{"ID": {id.Hex()}}); result.Code != 302 {
+		t.Errorf("expect 302, got %v: %v", result.Code, result.Msg)
+	}
+	if find, _ := db.FindOne(server.MapCollectionName, bson.M{"ID": id}, &bson.M{}); !find {
+		t.Errorf("a used texture should not be deleted")
+	}
+
+	if result := post(t, Delete, url.Values{"ID": {id.Hex()}, "Force": {"true"}}); result.Code != 200 {
+		t.Errorf("expect 200, got %v: %v", result.Code, result.Msg)
+	}
+	if find, _ := db.FindOne(server.MapCollectionName, bson.M{"ID": id}, &bson.M{}); find {
+		t.Errorf("the texture should be deleted with Force")
+	}
+}
+
+func TestTextureAddShared(t *testing.T) {
+	server.CreateEmbedded(testDir)
+	db, err := server.DB()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	// upload the same content with two names
+	add := func(name string) bson.M {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, _ := form.CreateFormFile("file", name+".png")
+		part.Write([]byte("TestTextureAddShared"))
+		form.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/", &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		rec := httptest.NewRecorder()
+		Add(rec, req)
+
+		doc := bson.M{}
+		db.FindOne(server.MapCollectionName, bson.M{"Name": name}, &doc)
+		return doc
+	}
+	a := add("TestTextureAddSharedA")
+	b := add("TestTextureAddSharedB")
+	if a["Url"] == nil || a["Url"] != b["Url"] {
+		t.Errorf("expect the same url, got %v and %v", a["Url"], b["Url"])
+		return
+	}
+	physicalPath := server.MapPath(a["Url"].(string))
+
+	// the shared file is used by a material
+	db.InsertOne(server.MaterialCollectionName, bson.M{
+		"ID":   primitive.NewObjectID(),
+		"Name": "TestTextureAddShared",
+		"Data": `{"type":"MeshBasicMaterial","map":{"image":{"src":"` + a["Url"].(string) + `"}}}`,
+	})
+
+	remove := func(doc bson.M, force bool) server.Result {
+		return post(t, Delete, url.Values{
+			"ID":    {doc["ID"].(primitive.ObjectID).Hex()},
+			"Force": {strconv.FormatBool(force)},
+		})
+	}
+	if result := remove(a, false); result.Code != 200 {
+		t.Errorf("expect a duplicate to be deleted, got %v: %v", result.Code, result.Msg)
+	}
+	if _, err := os.Stat(physicalPath); err != nil {
+		t.Errorf("the file should be kept while it is used: %v", err)
+	}
+	if result := remove(b, false); result.Code != 302 {
+		t.Errorf("expect the last texture to be used, got %v: %v", result.Code, result.Msg)
+	}
+	remove(b, true)
+	if _, err := os.Stat(physicalPath); !os.IsNotExist(err) {
+		t.Errorf("the file should be removed with the last texture")
+	}
+}
+
+// post calls a handler with a form, and returns the result.
+func post(t *testing.T, handler http.HandlerFunc, values url.Values) server.Result {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(values.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+
+	result := server.Result{}
+	if err := helper.FromJSON(rec.Body.Bytes(), &result); err != nil {
+		t.Error(err)
+	}
+	return result
+}
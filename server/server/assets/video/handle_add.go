@@ -8,10 +8,7 @@
 package video
 
 import (
-	"fmt"
-	"io"
 	"net/http"
-	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -29,11 +26,18 @@ func init() {
 
 // Add upload a video.
 func Add(w http.ResponseWriter, r *http.Request) {
-	r.ParseMultipartForm(server.Config.Upload.MaxSize)
-	files := r.MultipartForm.File
+	// files are in the form, or are completed chunked uploads.
+	files, err := server.FormFiles(r)
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
 
 	// check upload file
-	if len(files) != 1 {
+	if len(files) != 1 || files["file"] == nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
 			Msg:  "Only one file is allowed to upload!",
@@ -41,10 +45,10 @@ func Add(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	file := files["file"][0]
+	file := files["file"]
 	fileName := file.Filename
 	fileSize := file.Size
-	fileType := file.Header.Get("Content-Type")
+	fileType := file.ContentType
 	fileExt := filepath.Ext(fileName)
 	fileNameWithoutExt := strings.TrimRight(fileName, fileExt)
 
@@ -57,18 +61,7 @@ func Add(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// save file
-	now := time.Now()
-
-	savePath := fmt.Sprintf("/Upload/Video/%v", helper.TimeToString(now, "yyyyMMddHHmmss"))
-	physicalPath := server.MapPath(savePath)
-
-	if _, err := os.Stat(physicalPath); os.IsNotExist(err) {
-		os.MkdirAll(physicalPath, 0755)
-	}
-
-	targetPath := filepath.Join(physicalPath, fileName)
-	target, err := os.Create(targetPath)
+	db, err := server.DB()
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
@@ -76,7 +69,9 @@ func Add(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
-	defer target.Close()
+
+	// save file, and files with the same content share storage.
+	now := time.Now()
 
 	source, err := file.Open()
 	if err != nil {
@@ -88,12 +83,7 @@ func Add(w http.ResponseWriter, r *http.Request) {
 	}
 	defer source.Close()
 
-	io.Copy(target, source)
-
-	// save to mongo
-	pinyin := helper.ConvertToPinYin(fileNameWithoutExt)
-
-	db, err := server.Mongo()
+	blob, err := server.AddBlob(db, source, fileName, nil)
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
@@ -102,7 +92,10 @@ func Add(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	url := fmt.Sprintf("%v/%v", savePath, fileName)
+	// save to mongo
+	pinyin := helper.ConvertToPinYin(fileNameWithoutExt)
+
+	url := blob.URL()
 
 	doc := bson.M{
 		"ID":          primitive.NewObjectID(),
@@ -114,7 +107,8 @@ func Add(w http.ResponseWriter, r *http.Request) {
 		"TotalPinYin": pinyin.TotalPinYin,
 		"Name":        fileNameWithoutExt,
 		"SaveName":    fileName,
-		"SavePath":    savePath,
+		"SavePath":    blob.SavePath,
+		"Blobs":       []string{blob.Hash},
 		"Url":         url,
 		"Thumbnail":   "",
 		"CreateTime":  now,
@@ -129,7 +123,18 @@ func Add(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	db.InsertOne(server.VideoCollectionName, doc)
+	if _, err := db.InsertOne(server.VideoCollectionName, doc); err != nil {
+		server.RemoveAssetFiles(db, doc)
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	// the chunked uploads are kept until the asset is stored, so that a failed add
+	// does not need to upload the files again.
+	server.RemoveUploads(files)
 
 	helper.WriteJSON(w, server.Result{
 		Code: 200,
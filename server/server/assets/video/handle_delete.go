@@ -9,7 +9,6 @@ package video
 
 import (
 	"net/http"
-	"os"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -34,7 +33,7 @@ func Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	db, err := server.Mongo()
+	db, err := server.DB()
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
@@ -58,9 +57,8 @@ func Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	path := doc["SavePath"].(string)
-	physicalPath := server.MapPath(path)
-	os.RemoveAll(physicalPath)
+	// files shared with other assets are kept
+	server.RemoveAssetFiles(db, doc)
 
 	db.DeleteOne(server.VideoCollectionName, filter)
 
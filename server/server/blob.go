@@ -0,0 +1,216 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// BlobDir is the dir that uploaded files are saved in by the SHA-256 hash of their
+// content, such as `/Upload/Blob/ab/ab12...`.
+const BlobDir = "/Upload/Blob"
+
+// BlobTempPrefix is the name prefix of the temp files in BlobDir that uploads are
+// written to before they are hashed.
+const BlobTempPrefix = "upload-"
+
+// blobMutex makes adding and releasing blobs atomic.
+var blobMutex sync.Mutex
+
+// Blob is an uploaded file, or the files extracted from it, that assets share when
+// their contents are the same.
+type Blob struct {
+	// SHA-256 hash of the uploaded file in hex, with the suffix `-extracted` when the
+	// files are extracted from it
+	Hash string
+	// The dir that the files are saved in, such as `/Upload/Blob/ab/ab12...`
+	SavePath string
+	// The file name in SavePath, it is empty when the file is extracted.
+	Name string
+	// Whether the same content has been uploaded before
+	Existed bool
+}
+
+// URL returns the url of the blob file.
+func (b *Blob) URL() string {
+	return fmt.Sprintf("%v/%v", b.SavePath, b.Name)
+}
+
+// AddBlob saves an uploaded file as a blob, and adds a reference to it. If extract is
+// not nil, it is used to extract the file to the blob dir, such as unzipping, or the
+// file is saved as name. When the same content has been uploaded before, the saved
+// blob is returned, and its files may have other names.
+func AddBlob(db Storage, source io.Reader, name string, extract func(file, dir string) error) (*Blob, error) {
+	blobDir := MapPath(BlobDir)
+	if _, err := os.Stat(blobDir); os.IsNotExist(err) {
+		os.MkdirAll(blobDir, 0755)
+	}
+
+	// hash while writing to a temp file, so that large files are not in memory.
+	temp, err := ioutil.TempFile(blobDir, BlobTempPrefix+"*")
+	if err != nil {
+		return nil, err
+	}
+	tempPath := temp.Name()
+	defer os.Remove(tempPath)
+
+	hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(temp, hash), source)
+	temp.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	blob := &Blob{
+		Hash: hex.EncodeToString(hash.Sum(nil)),
+	}
+	// the extracted files and the uploaded file itself are different blobs, even if
+	// they are from the same content.
+	if extract != nil {
+		blob.Hash += "-extracted"
+	}
+
+	blobMutex.Lock()
+	defer blobMutex.Unlock()
+
+	filter := bson.M{
+		"Hash": blob.Hash,
+	}
+	doc := bson.M{}
+	find, err := db.FindOne(BlobCollectionName, filter, &doc)
+	if err != nil {
+		return nil, err
+	}
+	if find {
+		if _, err := db.UpdateOne(BlobCollectionName, filter, bson.M{
+			"$inc": bson.M{
+				"RefCount": 1,
+			},
+		}); err != nil {
+			return nil, err
+		}
+		blob.SavePath, _ = doc["SavePath"].(string)
+		blob.Name, _ = doc["Name"].(string)
+		blob.Existed = true
+		return blob, nil
+	}
+
+	blob.SavePath = fmt.Sprintf("%v/%v/%v", BlobDir, blob.Hash[:2], blob.Hash)
+	physicalPath := MapPath(blob.SavePath)
+	// a dir that is left by a failed upload
+	os.RemoveAll(physicalPath)
+	if err := os.MkdirAll(physicalPath, 0755); err != nil {
+		return nil, err
+	}
+
+	if extract != nil {
+		err = extract(tempPath, physicalPath)
+	} else {
+		// the name is from the client, so only the base name is used.
+		blob.Name = filepath.Base(filepath.Clean("/" + name))
+		err = os.Rename(tempPath, filepath.Join(physicalPath, blob.Name))
+	}
+	if err != nil {
+		os.RemoveAll(physicalPath)
+		return nil, err
+	}
+
+	if _, err := db.InsertOne(BlobCollectionName, bson.M{
+		"Hash":     blob.Hash,
+		"SavePath": blob.SavePath,
+		"Name":     blob.Name,
+		"RefCount": 1,
+		"AddTime":  time.Now(),
+	}); err != nil {
+		os.RemoveAll(physicalPath)
+		return nil, err
+	}
+	return blob, nil
+}
+
+// ReleaseBlob removes a reference to a blob, and removes the blob files when it is
+// the last reference.
+func ReleaseBlob(db Storage, hash string) error {
+	blobMutex.Lock()
+	defer blobMutex.Unlock()
+
+	filter := bson.M{
+		"Hash": hash,
+	}
+	doc := bson.M{}
+	find, err := db.FindOne(BlobCollectionName, filter, &doc)
+	if err != nil || !find {
+		return err
+	}
+
+	if toNumber(doc["RefCount"], 0) > 1 {
+		_, err := db.UpdateOne(BlobCollectionName, filter, bson.M{
+			"$inc": bson.M{
+				"RefCount": -1,
+			},
+		})
+		return err
+	}
+
+	if _, err := db.DeleteOne(BlobCollectionName, filter); err != nil {
+		return err
+	}
+	if savePath, _ := doc["SavePath"].(string); savePath != "" {
+		return os.RemoveAll(MapPath(savePath))
+	}
+	return nil
+}
+
+// RemoveAssetFiles removes the files of an asset doc. Assets that are saved as blobs
+// only release their blobs, and assets uploaded before have their own `SavePath`.
+func RemoveAssetFiles(db Storage, doc bson.M) error {
+	if hashes, ok := toArray(doc["Blobs"]); ok {
+		for _, hash := range hashes {
+			if hash, ok := hash.(string); ok {
+				if err := ReleaseBlob(db, hash); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if savePath, _ := doc["SavePath"].(string); savePath != "" {
+		return os.RemoveAll(MapPath(savePath))
+	}
+	return nil
+}
+
+// AssetFilesShared returns whether all the blobs of an asset are referenced by other
+// assets too, so that deleting the asset keeps its files.
+func AssetFilesShared(db Storage, doc bson.M) (bool, error) {
+	hashes, ok := toArray(doc["Blobs"])
+	if !ok || len(hashes) == 0 {
+		return false, nil
+	}
+	for _, hash := range hashes {
+		blob := bson.M{}
+		find, err := db.FindOne(BlobCollectionName, bson.M{"Hash": hash}, &blob)
+		if err != nil {
+			return false, err
+		}
+		if !find || toNumber(blob["RefCount"], 0) <= 1 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package server
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestBlob(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := CreateEmbedded(dir); err != nil {
+		t.Fatal(err)
+	}
+	db, err := DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := AddBlob(db, strings.NewReader("content"), "../a.png", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Existed || !strings.HasPrefix(a.URL(), BlobDir+"/") || !strings.HasSuffix(a.URL(), "/a.png") {
+		t.Errorf("expect a new blob named a.png, got %v", a)
+	}
+
+	// the same content shares the file
+	b, err := AddBlob(db, strings.NewReader("content"), "b.png", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !b.Existed || b.URL() != a.URL() {
+		t.Errorf("expect %v, got %v", a.URL(), b)
+	}
+
+	// extracted blobs
+	c, err := AddBlob(db, strings.NewReader("zip"), "c.zip", func(file, dir string) error {
+		return ioutil.WriteFile(filepath.Join(dir, "c.obj"), []byte("v 0 0 0"), 0644)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(MapPath(c.SavePath + "/c.obj")); err != nil {
+		t.Error(err)
+	}
+
+	// the same content that is not extracted is another blob
+	d, err := AddBlob(db, strings.NewReader("zip"), "d.zip", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Existed || d.SavePath == c.SavePath {
+		t.Errorf("expect a new blob, got %v", d)
+	}
+	if _, err := os.Stat(MapPath(d.URL())); err != nil {
+		t.Error(err)
+	}
+
+	// blob files are removed when the last reference is released
+	if err := RemoveAssetFiles(db, bson.M{"Blobs": bson.A{a.Hash}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(MapPath(a.URL())); err != nil {
+		t.Errorf("expect the blob to be kept, got %v", err)
+	}
+	if err := ReleaseBlob(db, b.Hash); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(MapPath(a.SavePath)); !os.IsNotExist(err) {
+		t.Errorf("expect the blob to be removed")
+	}
+	if find, _ := db.FindOne(BlobCollectionName, bson.M{"Hash": a.Hash}, &bson.M{}); find {
+		t.Errorf("expect the blob doc to be removed")
+	}
+
+	// assets uploaded before blobs have their own dirs
+	os.MkdirAll(MapPath("/Upload/Model/1"), 0755)
+	ioutil.WriteFile(MapPath("/Upload/Model/1/a.obj"), nil, 0644)
+	if err := RemoveAssetFiles(db, bson.M{"SavePath": "/Upload/Model/1"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(MapPath("/Upload/Model/1")); !os.IsNotExist(err) {
+		t.Errorf("expect the dir to be removed")
+	}
+}
@@ -26,7 +26,7 @@ func List(w http.ResponseWriter, r *http.Request) {
 	r.ParseForm()
 	typ := strings.TrimSpace(r.FormValue("Type"))
 
-	db, err := server.Mongo()
+	db, err := server.DB()
 	if err != nil {
 		helper.WriteJSON(w, err.Error())
 		return
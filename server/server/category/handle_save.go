@@ -48,7 +48,7 @@ func Save(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// update mongo
-	db, err := server.Mongo()
+	db, err := server.DB()
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
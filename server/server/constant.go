@@ -12,6 +12,12 @@ const (
 	CategoryCollectionName string = "_Category"
 	// SceneCollectionName is the collection name that we store scenes in mongo.
 	SceneCollectionName string = "_Scene"
+	// SceneTagCollectionName is the collection name that we store named tags of scene versions in mongo.
+	SceneTagCollectionName string = "_SceneTag"
+	// SceneBranchCollectionName is the collection name that we store scene branches in mongo.
+	SceneBranchCollectionName string = "_SceneBranch"
+	// ScenePublishCollectionName is the collection name that we store published snapshots of scenes in mongo.
+	ScenePublishCollectionName string = "_ScenePublish"
 	// MeshCollectionName is the collection name that we store meshes in mongo.
 	MeshCollectionName string = "_Mesh"
 	// MapCollectionName is the collection name that we store textures in mongo.
@@ -34,6 +40,10 @@ const (
 	VideoCollectionName string = "_Video"
 	// FileCollectionName is the collection name that we store files in mongo.
 	FileCollectionName string = "_File"
+	// BlobCollectionName is the collection name that we store the reference counts of uploaded files in mongo.
+	BlobCollectionName string = "_Blob"
+	// UploadCollectionName is the collection name that we store chunked uploads in mongo.
+	UploadCollectionName string = "_Upload"
 	// ConfigCollectionName is the collection name that we store system configs in mongo.
 	ConfigCollectionName string = "_Config"
 	// RoleCollectionName is the collection name that we store roles in mongo.
@@ -52,6 +62,8 @@ const (
 	HistorySuffix string = "_history"
 	// VersionField is the field we add to scene history records, and it is the scene version number.
 	VersionField string = "_version"
+	// VersionTimeField is the field we add to scene history records, and it is the time when the version was saved.
+	VersionTimeField string = "_versionTime"
 )
 
 // GetAllAuthorities returns all authority ids and names.
@@ -8,6 +8,8 @@
 package server
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -62,6 +64,50 @@ func Create(path string) error {
 	return nil
 }
 
+// CreateEmbedded create the server context without config.toml, and store data in files
+// instead of mongo. The public dir is `<dir>/public` and logs are saved to `<dir>/logs`.
+// It is used by `serve --embedded` when there is no config file, and by tests.
+func CreateEmbedded(dir string) error {
+	secretKey := make([]byte, 16)
+	if _, err := rand.Read(secretKey); err != nil {
+		return err
+	}
+
+	config := &helper.ConfigModel{}
+	config.Server.Port = ":2020"
+	config.Database.Type = FileStorage
+	config.Database.Database = "ShadowEditor"
+	config.Authority.Expires = 120
+	config.Authority.SecretKey = hex.EncodeToString(secretKey)
+	config.Upload.MaxSize = 1000000000
+	config.Upload.MaxUnzipSize = 2000000000
+	config.Upload.MaxUnzipFiles = 10000
+	config.Upload.MaxChunkedSize = 20000000000
+	config.Upload.MaxGeometrySize = 200000000
+	config.Path.PublicDir = filepath.Join(dir, "public")
+	config.Path.LogDir = filepath.Join(dir, "logs")
+	config.Log.File = filepath.Join(config.Path.LogDir, "ShadowEditor.txt")
+
+	if _, err := os.Stat(config.Path.LogDir); os.IsNotExist(err) {
+		os.MkdirAll(config.Path.LogDir, 0755)
+	}
+
+	writer, err := os.OpenFile(config.Log.File, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0755)
+	if err != nil {
+		return err
+	}
+
+	Config = config
+	Logger = &logrus.Logger{
+		Out:       writer,
+		Formatter: new(logFormatter),
+		Hooks:     make(logrus.LevelHooks),
+		Level:     logrus.DebugLevel,
+	}
+
+	return nil
+}
+
 // Mongo create a new mongo client.
 // DO NOT call `db.Disconnect()` because of singleton.
 func Mongo() (*helper.Mongo, error) {
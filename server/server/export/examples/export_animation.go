@@ -8,30 +8,19 @@
 package examples
 
 import (
-	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 
-	"github.com/tengge1/shadoweditor/server"
-
 	"github.com/tengge1/shadoweditor/helper"
 )
 
 func exportAnimation(path string) {
-	port := server.Config.Server.Port
-
-	result, _ := helper.Get(fmt.Sprintf("http://%v/api/Animation/List", port))
-
 	dirName := filepath.Join(path, "api", "Animation")
 	if _, err := os.Stat(dirName); os.IsNotExist(err) {
 		os.MkdirAll(dirName, 0755)
 	}
 
-	// get list
-	fileName := filepath.Join(path, "api", "Animation", "List")
-	ioutil.WriteFile(fileName, []byte(result), 0755)
-
 	// other apis
 	apiList := []string{
 		"/api/Animation/Add",
@@ -45,7 +34,7 @@ func exportAnimation(path string) {
 	})
 
 	for _, i := range apiList {
-		fileName = filepath.Join(path, i)
+		fileName := filepath.Join(path, i)
 		ioutil.WriteFile(fileName, []byte(data), 0755)
 	}
 }
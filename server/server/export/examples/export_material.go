@@ -8,29 +8,19 @@
 package examples
 
 import (
-	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 
 	"github.com/tengge1/shadoweditor/helper"
-	"github.com/tengge1/shadoweditor/server"
 )
 
 func exportMaterial(path string) {
-	port := server.Config.Server.Port
-
-	result, _ := helper.Get(fmt.Sprintf("http://%v/api/Material/List", port))
-
 	dirName := filepath.Join(path, "api", "Material")
 	if _, err := os.Stat(dirName); os.IsNotExist(err) {
 		os.MkdirAll(dirName, 0755)
 	}
 
-	// get list
-	fileName := filepath.Join(path, "api", "Material", "List")
-	ioutil.WriteFile(fileName, []byte(result), 0755)
-
 	// other apis
 	apiList := []string{
 		"/api/Material/Add",
@@ -44,7 +34,7 @@ func exportMaterial(path string) {
 	})
 
 	for _, i := range apiList {
-		fileName = filepath.Join(path, i)
+		fileName := filepath.Join(path, i)
 		ioutil.WriteFile(fileName, []byte(data), 0755)
 	}
 }
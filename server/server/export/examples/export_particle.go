@@ -10,37 +10,33 @@ package examples
 import (
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"path/filepath"
 
 	"github.com/tengge1/shadoweditor/helper"
 	"github.com/tengge1/shadoweditor/server"
+	"github.com/tengge1/shadoweditor/server/system"
 )
 
-func exportParticle(path string) {
-	port := server.Config.Server.Port
-
-	result, _ := helper.Get(fmt.Sprintf("http://%v/api/Particle/List", port))
+func exportParticle(path string, user *system.User) {
+	result, _ := server.ServeLocal(http.MethodGet, "/api/Particle/List", user)
 
 	dirName := filepath.Join(path, "api", "Particle")
 	if _, err := os.Stat(dirName); os.IsNotExist(err) {
 		os.MkdirAll(dirName, 0755)
 	}
 
-	// get list
-	fileName := filepath.Join(path, "api", "Particle", "List")
-	ioutil.WriteFile(fileName, []byte(result), 0755)
-
 	// export particles
 	var obj map[string]interface{}
-	helper.FromJSON([]byte(result), &obj)
+	helper.FromJSON(result, &obj)
 
 	if array, ok := obj["Data"].([]interface{}); ok {
 		for _, i := range array {
 			id := i.(map[string]interface{})["ID"].(string)
-			result, _ = helper.Get(fmt.Sprintf("http://%v/api/Particle/Get?ID=%v", port, id))
-			fileName = fmt.Sprintf("%v/api/Particle/Particle_%v", path, id)
-			ioutil.WriteFile(fileName, []byte(result), 0755)
+			result, _ = server.ServeLocal(http.MethodGet, "/api/Particle/Get?ID="+id, user)
+			fileName := fmt.Sprintf("%v/api/Particle/Particle_%v", path, id)
+			ioutil.WriteFile(fileName, result, 0755)
 		}
 	}
 
@@ -57,7 +53,7 @@ func exportParticle(path string) {
 	})
 
 	for _, i := range apiList {
-		fileName = filepath.Join(path, i)
+		fileName := filepath.Join(path, i)
 		ioutil.WriteFile(fileName, []byte(data), 0755)
 	}
 }
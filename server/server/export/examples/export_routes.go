@@ -0,0 +1,69 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package examples
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tengge1/shadoweditor/helper"
+	"github.com/tengge1/shadoweditor/server"
+	"github.com/tengge1/shadoweditor/server/system"
+)
+
+// exportAuthorities are the authorities of the routes that are exported. Examples are
+// public, so routes that need other authorities, such as the user list, are not exported.
+var exportAuthorities = map[server.Authority]bool{
+	server.None:           true,
+	server.ListAnimation:  true,
+	server.ListAudio:      true,
+	server.ListCharacter:  true,
+	server.ListTexture:    true,
+	server.ListMaterial:   true,
+	server.ListMesh:       true,
+	server.ListParticle:   true,
+	server.ListPrefab:     true,
+	server.ListScreenshot: true,
+	server.ListVideo:      true,
+}
+
+// exportRoutes exports the response of every registered public or asset list GET api
+// without parameters to the file of its path, such as `api/Mesh/List`. Routes with path
+// parameters, such as `/api/Scene/Collaborate/:ID`, can not be exported, and routes
+// that fail are skipped. Apis report errors with a json code other than 200, such as
+// when a required query param is missing, so those results are skipped too.
+func exportRoutes(path string, user *system.User) error {
+	for _, route := range server.Routes() {
+		if route.Method != http.MethodGet || strings.Contains(route.Path, ":") || !exportAuthorities[route.Authority] {
+			continue
+		}
+
+		result, err := server.ServeLocal(http.MethodGet, route.Path, user)
+		if err != nil {
+			server.Logger.Warnf("skip exporting %v: %v", route.Path, err)
+			continue
+		}
+		obj := server.Result{}
+		if err := helper.FromJSON(result, &obj); err == nil && obj.Code != 0 && obj.Code != 200 {
+			server.Logger.Warnf("skip exporting %v: %v", route.Path, obj.Msg)
+			continue
+		}
+
+		fileName := filepath.Join(path, filepath.FromSlash(route.Path))
+		if _, err := os.Stat(filepath.Dir(fileName)); os.IsNotExist(err) {
+			os.MkdirAll(filepath.Dir(fileName), 0755)
+		}
+		if err := ioutil.WriteFile(fileName, result, 0755); err != nil {
+			return err
+		}
+	}
+	return nil
+}
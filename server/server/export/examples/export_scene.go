@@ -10,37 +10,33 @@ package examples
 import (
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"path/filepath"
 
 	"github.com/tengge1/shadoweditor/helper"
 	"github.com/tengge1/shadoweditor/server"
+	"github.com/tengge1/shadoweditor/server/system"
 )
 
-func exportScene(path string) {
-	port := server.Config.Server.Port
-
-	result, _ := helper.Get(fmt.Sprintf("http://%v/api/Scene/List", port))
+func exportScene(path string, user *system.User) {
+	result, _ := server.ServeLocal(http.MethodGet, "/api/Scene/List", user)
 
 	dirName := filepath.Join(path, "api", "Scene")
 	if _, err := os.Stat(dirName); os.IsNotExist(err) {
 		os.MkdirAll(dirName, 0755)
 	}
 
-	// get list
-	fileName := filepath.Join(path, "api", "Scene", "List")
-	ioutil.WriteFile(fileName, []byte(result), 0755)
-
 	// export scenes
 	var obj map[string]interface{}
-	helper.FromJSON([]byte(result), &obj)
+	helper.FromJSON(result, &obj)
 
 	if array, ok := obj["Data"].([]interface{}); ok {
 		for _, i := range array {
 			id := i.(map[string]interface{})["ID"].(string)
-			result, _ = helper.Get(fmt.Sprintf("http://%v/api/Scene/Load?ID=%v", port, id))
-			fileName = fmt.Sprintf("%v/api/Scene/Scene_%v", path, id)
-			ioutil.WriteFile(fileName, []byte(result), 0755)
+			result, _ = server.ServeLocal(http.MethodGet, "/api/Scene/Load?ID="+id, user)
+			fileName := fmt.Sprintf("%v/api/Scene/Scene_%v", path, id)
+			ioutil.WriteFile(fileName, result, 0755)
 		}
 	}
 
@@ -57,7 +53,7 @@ func exportScene(path string) {
 	})
 
 	for _, i := range apiList {
-		fileName = filepath.Join(path, i)
+		fileName := filepath.Join(path, i)
 		ioutil.WriteFile(fileName, []byte(data), 0755)
 	}
 }
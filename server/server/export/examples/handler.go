@@ -16,8 +16,8 @@ import (
 	"time"
 
 	"github.com/tengge1/shadoweditor/helper"
-
 	"github.com/tengge1/shadoweditor/server"
+	"github.com/tengge1/shadoweditor/server/system"
 )
 
 func init() {
@@ -34,8 +34,21 @@ func Run(w http.ResponseWriter, r *http.Request) {
 		os.MkdirAll(path, 0755)
 	}
 
+	// handlers are called in process as the current user, or as an administrator when
+	// authority is not enabled.
+	user, _ := server.GetCurrentUser(r)
+	if user == nil {
+		user = server.NewAdministrator()
+	}
+
 	copyStaticAssets(path)
-	createDataFile(path)
+	if err := createDataFile(path, user); err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
 
 	result := Result{}
 	result.Code = 200
@@ -83,29 +96,34 @@ func copyStaticAssets(path string) error {
 	return nil
 }
 
-func createDataFile(path string) {
+func createDataFile(path string, user *system.User) error {
 	dirName := filepath.Join(path, "api")
 
 	if _, err := os.Stat(dirName); os.IsNotExist(err) {
 		os.MkdirAll(dirName, 0755)
 	}
 
+	if err := exportRoutes(path, user); err != nil {
+		return err
+	}
+
 	exportAnimation(path)
 	exportAudio(path)
 	exportCategory(path)
-	exportCharacter(path)
+	exportCharacter(path, user)
 	exportConfig(path)
 	exportMaterial(path)
 	exportMesh(path)
-	exportParticle(path)
-	exportPrefab(path)
-	exportScene(path)
+	exportParticle(path, user)
+	exportPrefab(path, user)
+	exportScene(path, user)
 	exportScreenshot(path)
-	exportSummary(path)
 	exportTexture(path)
 	exportTools(path)
 	exportUpload(path)
 	exportVideo(path)
+
+	return nil
 }
 
 // Result is export examples result.
@@ -0,0 +1,81 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package examples
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tengge1/shadoweditor/helper"
+	"github.com/tengge1/shadoweditor/server"
+	_ "github.com/tengge1/shadoweditor/server/system/role" // admin apis that are not exported
+	_ "github.com/tengge1/shadoweditor/server/system/user"
+	_ "github.com/tengge1/shadoweditor/server/tools/plugin"
+)
+
+func TestExportRoutes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	server.CreateEmbedded(dir)
+	server.Config.Authority.Enabled = true
+
+	// an asset list, an api that only administrators can request, apis that fail,
+	// and an api with parameters.
+	server.Handle(http.MethodGet, "/api/TestExportRoutes/List", func(w http.ResponseWriter, r *http.Request) {
+		user, _ := server.GetCurrentUser(r)
+		w.Write([]byte(user.Name))
+	}, server.ListMesh)
+	server.Handle(http.MethodGet, "/api/TestExportRoutes/Users", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("Users"))
+	}, server.Administrator)
+	server.Handle(http.MethodGet, "/api/TestExportRoutes/Error", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}, server.None)
+	server.Handle(http.MethodGet, "/api/TestExportRoutes/Failed", func(w http.ResponseWriter, r *http.Request) {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "ID is not allowed.",
+		})
+	}, server.None)
+	server.Handle(http.MethodGet, "/api/TestExportRoutes/Get/:ID", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("Get"))
+	}, server.None)
+
+	path := filepath.Join(dir, "export")
+	if err := exportRoutes(path, server.NewAdministrator()); err != nil {
+		t.Error(err)
+		return
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(path, "api", "TestExportRoutes", "List"))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if string(data) != "Administrator" {
+		t.Errorf("expect Administrator, got %v", string(data))
+	}
+	for _, name := range []string{"Users", "Error", "Failed", "Get"} {
+		if _, err := os.Stat(filepath.Join(path, "api", "TestExportRoutes", name)); !os.IsNotExist(err) {
+			t.Errorf("expect %v not to be exported", name)
+		}
+	}
+	for _, name := range []string{"User", "Role", "Department", "OperatingAuthority", "Plugin"} {
+		if _, err := os.Stat(filepath.Join(path, "api", name)); !os.IsNotExist(err) {
+			t.Errorf("expect %v apis not to be exported", name)
+		}
+	}
+}
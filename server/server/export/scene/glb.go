@@ -0,0 +1,640 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package scene
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/tengge1/shadoweditor/helper"
+	"github.com/tengge1/shadoweditor/server"
+	"github.com/tengge1/shadoweditor/server/assets/mesh"
+	"github.com/tengge1/shadoweditor/three"
+)
+
+// wrapModes maps three.js wrapping modes to glTF.
+var wrapModes = map[int]int{
+	1000: 10497, // RepeatWrapping
+	1001: 33071, // ClampToEdgeWrapping
+	1002: 33648, // MirroredRepeatWrapping
+}
+
+// glbBuilder converts serialized scene objects to a glTF document with one binary
+// buffer. Parametric geometries and the triangles of uploaded models can be converted,
+// because the vertices of other geometries are not saved in the scene.
+type glbBuilder struct {
+	doc       helper.GLTF
+	bin       bytes.Buffer
+	objects   map[string]bson.M
+	materials map[string]int
+	images    map[string]int
+	// mesh index of uploaded models by url
+	models    map[string]int
+	lights    []interface{}
+	extension map[string]bool
+	// names of objects that can not be converted
+	skipped []string
+}
+
+// writeGLB exports the scene data to a .glb file under dir.
+func writeGLB(name string, docs []bson.M, dir, fileName string) ([]string, error) {
+	b := newGLBBuilder()
+	three.Mutex.Lock()
+	b.build(name, docs)
+	three.Mutex.Unlock()
+
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		os.MkdirAll(dir, 0755)
+	}
+	file, err := os.Create(filepath.Join(dir, fileName))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if err := helper.WriteGLB(file, &b.doc, b.bin.Bytes()); err != nil {
+		return nil, err
+	}
+	return b.skipped, nil
+}
+
+func newGLBBuilder() *glbBuilder {
+	return &glbBuilder{
+		doc: helper.GLTF{
+			Asset: helper.GLTFAsset{
+				Version:   "2.0",
+				Generator: "ShadowEditor",
+			},
+		},
+		objects:   map[string]bson.M{},
+		materials: map[string]int{},
+		images:    map[string]int{},
+		models:    map[string]int{},
+		extension: map[string]bool{},
+		skipped:   []string{},
+	}
+}
+
+// build converts the scene objects, and the hierarchy is read from the scene object.
+func (b *glbBuilder) build(name string, docs []bson.M) {
+	var scene bson.M
+	for _, doc := range docs {
+		if generatorOf(doc) == "SceneSerializer" {
+			scene = doc
+			continue
+		}
+		if uuid, ok := doc["uuid"].(string); ok && uuid != "" {
+			b.objects[uuid] = doc
+		}
+	}
+
+	roots := []int{}
+	if scene != nil {
+		if userData, ok := scene["userData"].(bson.M); ok {
+			if children, ok := userData["children"].(bson.A); ok {
+				roots = b.addChildren(children)
+			}
+		}
+	}
+	if len(roots) == 0 { // no hierarchy, export all objects in order
+		for _, doc := range docs {
+			if uuid, ok := doc["uuid"].(string); ok && b.objects[uuid] != nil && generatorOf(doc) != "SceneSerializer" {
+				roots = append(roots, b.addNode(doc))
+			}
+		}
+	}
+
+	sceneIndex := 0
+	b.doc.Scene = &sceneIndex
+	b.doc.Scenes = []helper.GLTFScene{{
+		Name:  name,
+		Nodes: roots,
+	}}
+
+	if len(b.lights) > 0 {
+		b.doc.Extensions = map[string]interface{}{
+			"KHR_lights_punctual": bson.M{
+				"lights": b.lights,
+			},
+		}
+	}
+	for extension := range b.extension {
+		b.doc.ExtensionsUsed = append(b.doc.ExtensionsUsed, extension)
+	}
+	sort.Strings(b.doc.ExtensionsUsed)
+	if b.bin.Len() > 0 {
+		b.doc.Buffers = []helper.GLTFBuffer{{
+			ByteLength: b.bin.Len(),
+		}}
+	}
+}
+
+// addChildren adds the nodes of a hierarchy like `[{uuid, children}]`.
+func (b *glbBuilder) addChildren(children bson.A) []int {
+	nodes := []int{}
+	for _, i := range children {
+		child, ok := i.(bson.M)
+		if !ok {
+			continue
+		}
+		uuid, _ := child["uuid"].(string)
+		doc := b.objects[uuid]
+		if doc == nil {
+			continue
+		}
+		index := b.addNode(doc)
+		if grandChildren, ok := child["children"].(bson.A); ok {
+			b.doc.Nodes[index].Children = b.addChildren(grandChildren)
+		}
+		nodes = append(nodes, index)
+	}
+	return nodes
+}
+
+// addNode adds a node for an object, and returns the node index.
+func (b *glbBuilder) addNode(doc bson.M) int {
+	node := helper.GLTFNode{}
+	node.Name, _ = doc["name"].(string)
+
+	position := toVector3(doc["position"], 0)
+	quaternion := toQuaternion(doc)
+	scale := toVector3(doc["scale"], 1)
+
+	switch generator := generatorOf(doc); generator {
+	case "MeshSerializer":
+		if mesh, ok := b.addMesh(doc); ok {
+			node.Mesh = &mesh
+		} else {
+			b.skip(doc, generator)
+		}
+	case "ServerObject":
+		if mesh, ok := b.addModel(doc); ok {
+			node.Mesh = &mesh
+		} else {
+			b.skip(doc, generator)
+		}
+	case "DirectionalLightSerializer", "SpotLightSerializer":
+		// glTF lights point to -Z, and three.js lights point to the target at origin.
+		lookAt := three.NewMatrix4().LookAt(*position, *three.NewVector3(0, 0, 0), *three.NewVector3(0, 1, 0))
+		quaternion = three.NewQuaternion(0, 0, 0, 1).SetFromRotationMatrix(*lookAt)
+		node.Extensions = b.addLight(doc, generator)
+	case "PointLightSerializer":
+		node.Extensions = b.addLight(doc, generator)
+	case "GroupSerializer", "Object3DSerializer", "BoneSerializer":
+	default:
+		b.skip(doc, generator)
+	}
+
+	matrix := three.NewMatrix4().Compose(*position, *quaternion, *scale)
+	if !matrix.Equals(*three.NewMatrix4()) {
+		node.Matrix = matrix.Elements[:]
+	}
+
+	b.doc.Nodes = append(b.doc.Nodes, node)
+	return len(b.doc.Nodes) - 1
+}
+
+// skip records an object that can not be converted.
+func (b *glbBuilder) skip(doc bson.M, generator string) {
+	name, _ := doc["name"].(string)
+	b.skipped = append(b.skipped, fmt.Sprintf("%v (%v)", name, generator))
+}
+
+// addMesh adds a mesh with a parametric geometry.
+func (b *glbBuilder) addMesh(doc bson.M) (int, bool) {
+	geometryDoc, _ := doc["geometry"].(bson.M)
+	geometry := server.NewGeometry(geometryDoc)
+	if geometry == nil {
+		return 0, false
+	}
+
+	attributes := map[string]int{
+		"POSITION":   b.addFloats(geometry.Position, "VEC3", true),
+		"NORMAL":     b.addFloats(geometry.Normal, "VEC3", false),
+		"TEXCOORD_0": b.addFloats(geometry.UV, "VEC2", false),
+	}
+	vertexCount := len(geometry.Position) / 3
+
+	mesh := helper.GLTFMesh{
+		Primitives: []helper.GLTFPrimitive{},
+	}
+	mesh.Name, _ = doc["name"].(string)
+
+	if materials, ok := doc["material"].(bson.A); ok && len(geometry.Groups) > 0 {
+		// multi materials, one primitive per group
+		for _, group := range geometry.Groups {
+			if group.Count == 0 {
+				continue
+			}
+			indices := b.addIndices(geometry.Index[group.Start:group.Start+group.Count], vertexCount)
+			primitive := helper.GLTFPrimitive{
+				Attributes: attributes,
+				Indices:    &indices,
+			}
+			if group.MaterialIndex < len(materials) {
+				if material, ok := materials[group.MaterialIndex].(bson.M); ok {
+					index := b.addMaterial(material)
+					primitive.Material = &index
+				}
+			}
+			mesh.Primitives = append(mesh.Primitives, primitive)
+		}
+	} else {
+		indices := b.addIndices(geometry.Index, vertexCount)
+		primitive := helper.GLTFPrimitive{
+			Attributes: attributes,
+			Indices:    &indices,
+		}
+		material, _ := doc["material"].(bson.M)
+		if materials, ok := doc["material"].(bson.A); ok && len(materials) > 0 {
+			material, _ = materials[0].(bson.M)
+		}
+		if material != nil {
+			index := b.addMaterial(material)
+			primitive.Material = &index
+		}
+		mesh.Primitives = append(mesh.Primitives, primitive)
+	}
+
+	b.doc.Meshes = append(b.doc.Meshes, mesh)
+	return len(b.doc.Meshes) - 1, true
+}
+
+// addModel adds a mesh with the triangles of an uploaded model, and the model is
+// added once for objects that share the same url.
+func (b *glbBuilder) addModel(doc bson.M) (int, bool) {
+	userData, _ := doc["userData"].(bson.M)
+	url, _ := userData["Url"].(string)
+	meshType, _ := userData["Type"].(string)
+	if url == "" || !mesh.CanReadGeometry(mesh.Type(meshType)) {
+		return 0, false
+	}
+	if index, ok := b.models[url]; ok {
+		return index, index >= 0
+	}
+
+	// clean the path, so that it never goes out of the public dir.
+	geometry, err := mesh.ReadGeometry(server.MapPath(path.Clean(url)), server.Config.Upload.MaxGeometrySize)
+	if err != nil || len(geometry.Positions) == 0 {
+		if err != nil {
+			server.Logger.Warnf("read mesh %v: %v", url, err)
+		}
+		b.models[url] = -1
+		return 0, false
+	}
+
+	positions := make([]float64, 0, len(geometry.Positions)*3)
+	for _, position := range geometry.Positions {
+		positions = append(positions, position.X, position.Y, position.Z)
+	}
+	indices := b.addIndices(geometry.Indices, len(geometry.Positions))
+	gltfMesh := helper.GLTFMesh{
+		Primitives: []helper.GLTFPrimitive{{
+			Attributes: map[string]int{
+				"POSITION": b.addFloats(positions, "VEC3", true),
+			},
+			Indices: &indices,
+		}},
+	}
+	gltfMesh.Name, _ = doc["name"].(string)
+
+	b.doc.Meshes = append(b.doc.Meshes, gltfMesh)
+	b.models[url] = len(b.doc.Meshes) - 1
+	return b.models[url], true
+}
+
+// addMaterial converts a three.js material to a metallic-roughness material.
+func (b *glbBuilder) addMaterial(doc bson.M) int {
+	uuid, _ := doc["uuid"].(string)
+	if index, ok := b.materials[uuid]; ok && uuid != "" {
+		return index
+	}
+
+	material := helper.GLTFMaterial{}
+	material.Name, _ = doc["name"].(string)
+
+	opacity := toNumber(doc["opacity"], 1)
+	metalness, roughness := 0.0, 1.0
+	switch doc["type"] {
+	case "MeshStandardMaterial", "MeshPhysicalMaterial":
+		metalness = toNumber(doc["metalness"], 0)
+		roughness = toNumber(doc["roughness"], 1)
+	case "MeshBasicMaterial":
+		material.Extensions = map[string]interface{}{
+			"KHR_materials_unlit": bson.M{},
+		}
+		b.extension["KHR_materials_unlit"] = true
+	}
+
+	material.PBRMetallicRoughness = &helper.GLTFPBR{
+		BaseColorFactor:  append(toColor(doc["color"], 0xffffff), opacity),
+		BaseColorTexture: b.addTexture(doc["map"]),
+		MetallicFactor:   &metalness,
+		RoughnessFactor:  &roughness,
+	}
+	material.NormalTexture = b.addTexture(doc["normalMap"])
+	material.OcclusionTexture = b.addTexture(doc["aoMap"])
+	material.EmissiveTexture = b.addTexture(doc["emissiveMap"])
+
+	if emissive := toColor(doc["emissive"], 0); emissive[0] > 0 || emissive[1] > 0 || emissive[2] > 0 {
+		intensity := toNumber(doc["emissiveIntensity"], 1)
+		for i := range emissive {
+			emissive[i] = math.Min(1, emissive[i]*intensity)
+		}
+		material.EmissiveFactor = emissive
+	}
+
+	if alphaTest := toNumber(doc["alphaTest"], 0); alphaTest > 0 {
+		material.AlphaMode = "MASK"
+		material.AlphaCutoff = &alphaTest
+	} else if transparent, _ := doc["transparent"].(bool); transparent {
+		material.AlphaMode = "BLEND"
+	}
+	if toNumber(doc["side"], 0) == 2 { // DoubleSide
+		material.DoubleSided = true
+	}
+
+	b.doc.Materials = append(b.doc.Materials, material)
+	index := len(b.doc.Materials) - 1
+	if uuid != "" {
+		b.materials[uuid] = index
+	}
+	return index
+}
+
+// addTexture embeds the image of a texture. glTF only supports png and jpeg images.
+func (b *glbBuilder) addTexture(value interface{}) *helper.GLTFTextureInfo {
+	texture, ok := value.(bson.M)
+	if !ok {
+		return nil
+	}
+	image, ok := texture["image"].(bson.M)
+	if !ok {
+		return nil
+	}
+	src, _ := image["src"].(string)
+	if src == "" {
+		return nil
+	}
+
+	imageIndex, ok := b.images[src]
+	if !ok {
+		data, mimeType, err := readImage(src)
+		if err != nil {
+			name, _ := texture["name"].(string)
+			b.skipped = append(b.skipped, fmt.Sprintf("%v (%v)", name, err.Error()))
+			return nil
+		}
+		bufferView := b.addBufferView(data, 0)
+		b.doc.Images = append(b.doc.Images, helper.GLTFImage{
+			MimeType:   mimeType,
+			BufferView: &bufferView,
+		})
+		imageIndex = len(b.doc.Images) - 1
+		b.images[src] = imageIndex
+	}
+
+	sampler := helper.GLTFSampler{
+		WrapS: wrapModes[int(toNumber(texture["wrapS"], 1001))],
+		WrapT: wrapModes[int(toNumber(texture["wrapT"], 1001))],
+	}
+	b.doc.Samplers = append(b.doc.Samplers, sampler)
+	samplerIndex := len(b.doc.Samplers) - 1
+
+	gltfTexture := helper.GLTFTexture{
+		Sampler: &samplerIndex,
+		Source:  &imageIndex,
+	}
+	gltfTexture.Name, _ = texture["name"].(string)
+	b.doc.Textures = append(b.doc.Textures, gltfTexture)
+
+	return &helper.GLTFTextureInfo{
+		Index: len(b.doc.Textures) - 1,
+	}
+}
+
+// addLight adds a KHR_lights_punctual light, and returns the node extensions.
+func (b *glbBuilder) addLight(doc bson.M, generator string) map[string]interface{} {
+	light := bson.M{
+		"color":     toColor(doc["color"], 0xffffff),
+		"intensity": toNumber(doc["intensity"], 1),
+	}
+	if name, ok := doc["name"].(string); ok && name != "" {
+		light["name"] = name
+	}
+
+	switch generator {
+	case "DirectionalLightSerializer":
+		light["type"] = "directional"
+	case "PointLightSerializer":
+		light["type"] = "point"
+	case "SpotLightSerializer":
+		light["type"] = "spot"
+		angle := toNumber(doc["angle"], math.Pi/3)
+		light["spot"] = bson.M{
+			"innerConeAngle": angle * (1 - toNumber(doc["penumbra"], 0)),
+			"outerConeAngle": angle,
+		}
+	}
+	if distance := toNumber(doc["distance"], 0); distance > 0 {
+		light["range"] = distance
+	}
+
+	b.lights = append(b.lights, light)
+	b.extension["KHR_lights_punctual"] = true
+
+	return map[string]interface{}{
+		"KHR_lights_punctual": bson.M{
+			"light": len(b.lights) - 1,
+		},
+	}
+}
+
+// addBufferView appends data to the binary buffer, and returns the buffer view index.
+func (b *glbBuilder) addBufferView(data []byte, target int) int {
+	// buffer views are aligned to 4 bytes
+	for b.bin.Len()%4 != 0 {
+		b.bin.WriteByte(0)
+	}
+	b.doc.BufferViews = append(b.doc.BufferViews, helper.GLTFBufferView{
+		ByteOffset: b.bin.Len(),
+		ByteLength: len(data),
+		Target:     target,
+	})
+	b.bin.Write(data)
+	return len(b.doc.BufferViews) - 1
+}
+
+// addFloats adds a float32 vertex attribute, and returns the accessor index.
+func (b *glbBuilder) addFloats(values []float64, accessorType string, bounds bool) int {
+	size := 3
+	if accessorType == "VEC2" {
+		size = 2
+	}
+
+	data := make([]byte, len(values)*4)
+	for i, value := range values {
+		binary.LittleEndian.PutUint32(data[i*4:], math.Float32bits(float32(value)))
+	}
+	bufferView := b.addBufferView(data, helper.GLTFArrayBuffer)
+
+	accessor := helper.GLTFAccessor{
+		BufferView:    &bufferView,
+		ComponentType: helper.GLTFFloat,
+		Count:         len(values) / size,
+		Type:          accessorType,
+	}
+	if bounds && len(values) >= size { // POSITION requires min and max
+		accessor.Min = make([]float64, size)
+		accessor.Max = make([]float64, size)
+		for j := 0; j < size; j++ {
+			accessor.Min[j] = float64(float32(values[j]))
+			accessor.Max[j] = float64(float32(values[j]))
+		}
+		for i := size; i < len(values); i++ {
+			value := float64(float32(values[i]))
+			accessor.Min[i%size] = math.Min(accessor.Min[i%size], value)
+			accessor.Max[i%size] = math.Max(accessor.Max[i%size], value)
+		}
+	}
+
+	b.doc.Accessors = append(b.doc.Accessors, accessor)
+	return len(b.doc.Accessors) - 1
+}
+
+// addIndices adds the indices of triangles, and returns the accessor index.
+func (b *glbBuilder) addIndices(indices []int, vertexCount int) int {
+	var data []byte
+	componentType := helper.GLTFUnsignedShort
+	if vertexCount > math.MaxUint16 {
+		componentType = helper.GLTFUnsignedInt
+		data = make([]byte, len(indices)*4)
+		for i, index := range indices {
+			binary.LittleEndian.PutUint32(data[i*4:], uint32(index))
+		}
+	} else {
+		data = make([]byte, len(indices)*2)
+		for i, index := range indices {
+			binary.LittleEndian.PutUint16(data[i*2:], uint16(index))
+		}
+	}
+	bufferView := b.addBufferView(data, helper.GLTFElementArrayBuffer)
+
+	b.doc.Accessors = append(b.doc.Accessors, helper.GLTFAccessor{
+		BufferView:    &bufferView,
+		ComponentType: componentType,
+		Count:         len(indices),
+		Type:          "SCALAR",
+	})
+	return len(b.doc.Accessors) - 1
+}
+
+// readImage reads a png or jpeg image from the public dir or a data url.
+func readImage(src string) ([]byte, string, error) {
+	if strings.HasPrefix(src, "data:") {
+		// data:image/png;base64,...
+		comma := strings.Index(src, ",")
+		if comma < 0 || !strings.HasSuffix(src[:comma], ";base64") {
+			return nil, "", fmt.Errorf("invalid data url")
+		}
+		mimeType := strings.TrimSuffix(strings.TrimPrefix(src[:comma], "data:"), ";base64")
+		if mimeType != "image/png" && mimeType != "image/jpeg" {
+			return nil, "", fmt.Errorf("%v is not supported", mimeType)
+		}
+		data, err := base64.StdEncoding.DecodeString(src[comma+1:])
+		return data, mimeType, err
+	}
+
+	if !strings.HasPrefix(src, "/") {
+		return nil, "", fmt.Errorf("%v is not supported", src)
+	}
+	var mimeType string
+	switch strings.ToLower(filepath.Ext(src)) {
+	case ".png":
+		mimeType = "image/png"
+	case ".jpg", ".jpeg":
+		mimeType = "image/jpeg"
+	default:
+		return nil, "", fmt.Errorf("%v is not supported", filepath.Ext(src))
+	}
+	// clean the path, so that it never goes out of the public dir.
+	data, err := ioutil.ReadFile(server.MapPath(path.Clean(src)))
+	return data, mimeType, err
+}
+
+// generatorOf returns the serializer of an object.
+func generatorOf(doc bson.M) string {
+	if metadata, ok := doc["metadata"].(bson.M); ok {
+		generator, _ := metadata["generator"].(string)
+		return generator
+	}
+	return ""
+}
+
+// toVector3 converts `{x, y, z}` to a vector.
+func toVector3(value interface{}, def float64) *three.Vector3 {
+	doc, _ := value.(bson.M)
+	return three.NewVector3(toNumber(doc["x"], def), toNumber(doc["y"], def), toNumber(doc["z"], def))
+}
+
+// toQuaternion returns the rotation of an object from its quaternion or rotation.
+func toQuaternion(doc bson.M) *three.Quaternion {
+	if q, ok := doc["quaternion"].(bson.M); ok {
+		return three.NewQuaternion(toNumber(q["x"], 0), toNumber(q["y"], 0), toNumber(q["z"], 0), toNumber(q["w"], 1))
+	}
+	if r, ok := doc["rotation"].(bson.M); ok {
+		order, _ := r["order"].(string)
+		euler := three.NewEuler(toNumber(r["x"], 0), toNumber(r["y"], 0), toNumber(r["z"], 0), order)
+		return three.NewQuaternion(0, 0, 0, 1).SetFromEuler(*euler, false)
+	}
+	return three.NewQuaternion(0, 0, 0, 1)
+}
+
+// toColor converts a hex color, such as 0xffffff, to `[r, g, b]` from 0 to 1.
+func toColor(value interface{}, def float64) []float64 {
+	hex := int(toNumber(value, def))
+	return []float64{
+		float64(hex>>16&255) / 255,
+		float64(hex>>8&255) / 255,
+		float64(hex&255) / 255,
+	}
+}
+
+// toNumber converts a bson number to float64.
+func toNumber(value interface{}, def float64) float64 {
+	switch v := value.(type) {
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	}
+	return def
+}
+
+// glbResult is the result of exporting a scene to glb.
+type glbResult struct {
+	server.Result
+	URL string `json:"Url"`
+	// Objects that are not exported
+	Skipped []string
+}
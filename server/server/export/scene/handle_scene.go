@@ -9,11 +9,12 @@ package scene
 
 import (
 	"fmt"
+	"io"
 	"io/ioutil"
-	"log"
 	"net/http"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -29,7 +30,9 @@ func init() {
 	server.Handle(http.MethodPost, "/api/ExportScene/Run", Scene, server.PublishScene)
 }
 
-// Scene publish scene to static contents.
+// Scene publish scene to static contents. When Format is `glb`, the scene is
+// exported to a glTF 2.0 binary file instead. When Zip is true, the static contents
+// are returned as a zip file.
 func Scene(w http.ResponseWriter, r *http.Request) {
 	r.ParseForm()
 	id, err := primitive.ObjectIDFromHex(strings.TrimSpace(r.FormValue("ID")))
@@ -40,12 +43,8 @@ func Scene(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
-	version, err := strconv.Atoi(strings.TrimSpace(r.FormValue("version")))
-	if err != nil {
-		version = -1
-	}
 
-	db, err := server.Mongo()
+	db, err := server.DB()
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
@@ -69,18 +68,29 @@ func Scene(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// get scene data
-	collectionName := doc["CollectionName"].(string)
+	// version is a version number or a tag name
+	version, err := server.ParseSceneVersion(db, id, r.FormValue("version"))
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
 
-	var docs bson.A
+	// get scene data
+	docs, err := server.LoadSceneVersion(db, doc, version)
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
 
-	if version == -1 { // lastest version
-		db.FindAll(collectionName, &docs)
-	} else { // specific version
-		filter = bson.M{
-			server.VersionField: version,
-		}
-		db.FindMany(fmt.Sprintf("%v%v", collectionName, server.HistorySuffix), filter, &docs)
+	if strings.ToLower(strings.TrimSpace(r.FormValue("Format"))) == "glb" {
+		exportGLB(w, doc, docs)
+		return
 	}
 
 	// create temp dir
@@ -113,67 +123,26 @@ func Scene(w http.ResponseWriter, r *http.Request) {
 
 	ioutil.WriteFile(filepath.Join(path, "view.html"), []byte(viewFileData), 0755)
 
-	faviconPath := server.MapPath("/favicon.ico")
-	helper.CopyFile(faviconPath, filepath.Join(path, "favicon.ico"))
-
-	assetsPath := server.MapPath("/assets")
-	err = helper.CopyDirectory(assetsPath, filepath.Join(path, "assets"))
-	if err != nil {
-		log.Println(err.Error())
-		return
+	// copy the files that the viewer loads, rather than all the editor assets
+	for _, name := range append(viewFiles(viewFileData), runtimeFiles...) {
+		if err := copyPath(server.MapPath("/"+name), filepath.Join(path, filepath.FromSlash(name))); err != nil {
+			helper.WriteJSON(w, server.Result{
+				Code: 300,
+				Msg:  err.Error(),
+			})
+			return
+		}
 	}
 
-	buildPath := server.MapPath("/build")
-	helper.CopyDirectory(buildPath, filepath.Join(path, "build"))
-
 	// analysis scene, and copy necessary assets
-	data := bson.A{}
-
-	urls := []string{}
-
-	for _, i := range docs {
-		doc := i.(primitive.D).Map()
-		generator := doc["metadata"].(primitive.D).Map()["generator"].(string)
-
-		if generator == "ServerObject" { // server model
-			userData := doc["userData"].(primitive.D).Map()
-			urls = append(urls, userData["Url"].(string)) // model files
-
-			if val, ok := userData["Animation"].(primitive.D); ok { // MMD animation
-				urls = append(urls, val.Map()["Url"].(string))
-			}
-			if val, ok := userData["CameraAnimation"].(primitive.D); ok { // MMD camara animation
-				urls = append(urls, val.Map()["Url"].(string))
-			}
-			if val, ok := userData["Audio"].(primitive.D); ok { // MMD audio
-				urls = append(urls, val.Map()["Url"].(string))
-			}
-		} else if generator == "SceneSerializer" { // scene
-			if val, ok := doc["background"].(primitive.D); ok { // texture or cube texture
-				background := val.Map()
-				if background["metadata"].(primitive.D).Map()["generator"].(string) == "CubeTextureSerializer" { // cube texture
-					array := background["image"].(bson.A)
-					for _, j := range array {
-						urls = append(urls, j.(primitive.D).Map()["src"].(string))
-					}
-				} else { // other texture
-					urls = append(urls, background["image"].(primitive.D).Map()["src"].(string))
-				}
-			}
-		} else if generator == "MeshSerializer" || generator == "SpriteSerializer" { // mesh
-			if val, ok := doc["material"].(bson.A); ok {
-				for _, material := range val {
-					getURLInMaterial(material.(primitive.D).Map(), &urls)
-				}
-			} else if val, ok := doc["material"].(primitive.D); ok {
-				getURLInMaterial(val.Map(), &urls)
-			}
-		} else if generator == "AudioSerializer" {
-			userData := doc["userData"].(primitive.D).Map()
-			urls = append(urls, userData["Url"].(string))
-		}
-
-		data = append(data, doc)
+	urls := server.SceneURLs(docs)
+	assets, err := server.LoadAssets(db)
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
 	}
 
 	// write scene data to files
@@ -184,8 +153,7 @@ func Scene(w http.ResponseWriter, r *http.Request) {
 			os.MkdirAll(dir, 0755)
 		}
 
-		// copy assets
-		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0755)
+		bytes, err := helper.ToJSON(docs)
 		if err != nil {
 			helper.WriteJSON(w, server.Result{
 				Code: 300,
@@ -193,40 +161,35 @@ func Scene(w http.ResponseWriter, r *http.Request) {
 			})
 			return
 		}
-		defer file.Close()
 
-		bytes, err := helper.ToJSON(data)
-		if err != nil {
+		if err := ioutil.WriteFile(path, bytes, 0755); err != nil {
 			helper.WriteJSON(w, server.Result{
 				Code: 300,
 				Msg:  err.Error(),
 			})
 			return
 		}
-
-		file.Write(bytes)
 	}
 
 	for _, url := range urls {
-		if !strings.HasPrefix(url, "/") { // maybe base64 url
-			continue
+		// models may load the files next to them, such as `.mtl` and `.bin`.
+		if collectionName, asset, ok := server.FindAsset(assets, url); ok && collectionName == server.MeshCollectionName {
+			url, _ = asset["SavePath"].(string)
 		}
-
-		// LOL has multiple urls, and they are split by `;`
-		_urls := strings.Split(url, ";")
-
-		for _, _url := range _urls {
-			if _url == "" {
-				continue
-			}
-
-			sourceDirName := filepath.Dir(server.MapPath(_url))
-			targetDirName := filepath.Dir(strings.ReplaceAll(path+_url, "/", string(os.PathSeparator)))
-
-			helper.CopyDirectory(sourceDirName, targetDirName)
+		if err := copyPath(server.MapPath(url), filepath.Join(path, filepath.FromSlash(url))); err != nil {
+			helper.WriteJSON(w, server.Result{
+				Code: 300,
+				Msg:  err.Error(),
+			})
+			return
 		}
 	}
 
+	if zip, _ := strconv.ParseBool(r.FormValue("Zip")); zip {
+		writeZip(w, path, id.Hex()+".zip")
+		return
+	}
+
 	result := sceneResult{}
 	result.Code = 200
 	result.Msg = "Export successfully!"
@@ -234,76 +197,95 @@ func Scene(w http.ResponseWriter, r *http.Request) {
 	helper.WriteJSON(w, result)
 }
 
-func getURLInMaterial(material bson.M, urls *[]string) {
-	var textures []bson.M
-	if val, ok := material["alphaMap"].(primitive.D); ok {
-		textures = append(textures, val.Map())
-	}
-	if val, ok := material["aoMap"].(primitive.D); ok {
-		textures = append(textures, val.Map())
-	}
-	if val, ok := material["bumpMap"].(primitive.D); ok {
-		textures = append(textures, val.Map())
-	}
-	if val, ok := material["displacementMap"].(primitive.D); ok {
-		textures = append(textures, val.Map())
-	}
-	if val, ok := material["emissiveMap"].(primitive.D); ok {
-		textures = append(textures, val.Map())
-	}
-	if val, ok := material["envMap"].(primitive.D); ok {
-		textures = append(textures, val.Map())
-	}
-	if val, ok := material["lightMap"].(primitive.D); ok {
-		textures = append(textures, val.Map())
+// runtimeFiles are the files and dirs that the player loads at runtime, such as the
+// loaders of server objects and the textures of components.
+var runtimeFiles = []string{
+	"assets/js/loaders",
+	"assets/js/libs/draco",
+	"assets/textures/SPE",
+	"assets/textures/VolumetricFire",
+	"assets/textures/lensflare",
+	"assets/textures/particles",
+	"assets/textures/patterns",
+	"assets/textures/terrain",
+	"assets/textures/grid.png",
+}
+
+// viewFileRegex matches the relative urls of scripts, styles and icons in view.html.
+var viewFileRegex = regexp.MustCompile(`(?:src|href)="([^":/][^":]*)"`)
+
+// viewFiles returns the files that view.html references, such as `build/ShadowEditor.js`.
+func viewFiles(html string) []string {
+	files := []string{}
+	for _, match := range viewFileRegex.FindAllStringSubmatch(html, -1) {
+		files = append(files, match[1])
 	}
-	if val, ok := material["map"].(primitive.D); ok {
-		textures = append(textures, val.Map())
+	return files
+}
+
+// copyPath copies a file or a dir, and a path that is not existed is skipped.
+func copyPath(source, dest string) error {
+	stat, err := os.Stat(source)
+	if os.IsNotExist(err) {
+		return nil
 	}
-	if val, ok := material["metalnessMap"].(primitive.D); ok {
-		textures = append(textures, val.Map())
+	if err != nil {
+		return err
 	}
-	if val, ok := material["normalMap"].(primitive.D); ok {
-		textures = append(textures, val.Map())
+	if stat.IsDir() {
+		return helper.CopyDirectory(source, dest)
 	}
-	if val, ok := material["roughnessMap"].(primitive.D); ok {
-		textures = append(textures, val.Map())
+	return helper.CopyFile(source, dest)
+}
+
+// writeZip packs the exported dir into a zip file next to it, and streams it to the client.
+func writeZip(w http.ResponseWriter, dir, fileName string) {
+	zipPath := dir + ".zip"
+	if err := helper.Zip(dir, zipPath); err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
 	}
-	// texture in uniforms
-	if uniformsVal, ok := material["uniforms"]; ok {
-		if uniforms, ok := uniformsVal.(primitive.D); ok {
-			for _, uniformVal := range uniforms.Map() {
-				if uniform, ok := uniformVal.(primitive.D); ok {
-					isTexture := false
-					for key, val := range uniform.Map() {
-						if key == "type" && val == "t" {
-							isTexture = true
-							break
-						}
-					}
-					if isTexture {
-						for key, val := range uniform.Map() {
-							if key == "value" {
-								if texture, ok := val.(primitive.D); ok {
-									textures = append(textures, texture.Map())
-								}
-							}
-						}
-					}
-				}
-			}
-		}
+
+	file, err := os.Open(zipPath)
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
 	}
+	defer file.Close()
 
-	for _, material := range textures {
-		if val, ok := material["image"]; ok && val != nil {
-			image := val.(primitive.D).Map()
-			src := image["src"].(string)
-			if strings.HasPrefix(src, "/") {
-				*urls = append(*urls, src)
-			}
-		}
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	io.Copy(w, file)
+}
+
+// exportGLB exports the data of a scene version to a .glb file in the temp dir.
+func exportGLB(w http.ResponseWriter, doc bson.M, docs []bson.M) {
+	now := time.Now()
+	dir := fmt.Sprintf("/temp/%v", helper.TimeToString(now, "yyyyMMddHHmmss"))
+	fileName := doc["ID"].(primitive.ObjectID).Hex() + ".glb"
+	name, _ := doc["Name"].(string)
+
+	skipped, err := writeGLB(name, docs, server.MapPath(dir), fileName)
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
 	}
+
+	result := glbResult{}
+	result.Code = 200
+	result.Msg = "Export successfully!"
+	result.URL = dir + "/" + fileName
+	result.Skipped = skipped
+	helper.WriteJSON(w, result)
 }
 
 type sceneResult struct {
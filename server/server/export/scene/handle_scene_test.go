@@ -0,0 +1,259 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package scene
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/tengge1/shadoweditor/helper"
+	"github.com/tengge1/shadoweditor/server"
+)
+
+// testDir is the directory that tests store data in, so that tests need no mongo.
+var testDir string
+
+func TestMain(m *testing.M) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		panic(err)
+	}
+	testDir = dir
+	code := m.Run()
+	os.RemoveAll(testDir)
+	os.Exit(code)
+}
+
+func TestExportGLB(t *testing.T) {
+	if err := server.CreateEmbedded(testDir); err != nil {
+		t.Error(err)
+		return
+	}
+	db, err := server.DB()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	id := primitive.NewObjectID()
+	db.InsertOne(server.SceneCollectionName, bson.M{
+		"ID":             id,
+		"Name":           "TestExportGLB",
+		"CollectionName": "SceneTestExportGLB",
+		"Version":        0,
+	})
+
+	var data []interface{}
+	bson.UnmarshalExtJSON([]byte(`[
+		{"metadata":{"generator":"SceneSerializer"},"uuid":"scene","userData":{"children":[
+			{"uuid":"group","children":[{"uuid":"box","children":[]}]},
+			{"uuid":"light","children":[]},
+			{"uuid":"sky","children":[]},
+			{"uuid":"model","children":[]},
+			{"uuid":"model2","children":[]}
+		]}},
+		{"metadata":{"generator":"GroupSerializer"},"uuid":"group","name":"Group","position":{"x":1,"y":2,"z":3}},
+		{"metadata":{"generator":"MeshSerializer"},"uuid":"box","name":"Box",
+			"geometry":{"type":"BoxBufferGeometry","parameters":{"width":2,"height":2,"depth":2}},
+			"material":{"type":"MeshStandardMaterial","uuid":"m1","color":16711680,"metalness":0.5,"roughness":0.5}},
+		{"metadata":{"generator":"DirectionalLightSerializer"},"uuid":"light","name":"Sun","position":{"x":0,"y":10,"z":0},"color":16777215,"intensity":1},
+		{"metadata":{"generator":"SkySerializer"},"uuid":"sky","name":"Sky"},
+		{"metadata":{"generator":"ServerObject"},"uuid":"model","name":"Model","userData":{"Url":"/Upload/Model/TestExportGLB/model.obj","Type":"obj"}},
+		{"metadata":{"generator":"ServerObject"},"uuid":"model2","name":"Model2","userData":{"Url":"/Upload/Model/TestExportGLB/model.obj","Type":"obj"}}
+	]`), false, &data)
+	db.InsertMany("SceneTestExportGLB", data)
+
+	modelDir := server.MapPath("/Upload/Model/TestExportGLB")
+	os.MkdirAll(modelDir, 0755)
+	ioutil.WriteFile(filepath.Join(modelDir, "model.obj"), []byte("v 0 0 0\nv 4 0 0\nv 0 4 0\nf 1 2 3\n"), 0755)
+
+	values := url.Values{
+		"ID":     {id.Hex()},
+		"Format": {"glb"},
+	}
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(values.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	Scene(rec, req)
+
+	result := glbResult{}
+	if err := helper.FromJSON(rec.Body.Bytes(), &result); err != nil {
+		t.Error(err)
+		return
+	}
+	if result.Code != 200 {
+		t.Errorf("expect 200, got %v: %v", result.Code, result.Msg)
+		return
+	}
+	if len(result.Skipped) != 1 || !strings.HasPrefix(result.Skipped[0], "Sky") {
+		t.Errorf("expect Sky skipped, got %v", result.Skipped)
+	}
+
+	bytes, err := ioutil.ReadFile(server.MapPath(result.URL))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	doc, bin, err := helper.ReadGLB(bytes)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if len(doc.Scenes) != 1 || len(doc.Scenes[0].Nodes) != 5 {
+		t.Errorf("expect 5 root nodes, got %v", doc.Scenes)
+	}
+	if len(doc.Nodes) != 6 || doc.Nodes[0].Name != "Group" || len(doc.Nodes[0].Children) != 1 {
+		t.Errorf("unexpected nodes: %v", doc.Nodes)
+		return
+	}
+	if matrix := doc.Nodes[0].Matrix; len(matrix) != 16 || matrix[12] != 1 || matrix[13] != 2 || matrix[14] != 3 {
+		t.Errorf("expect translation (1, 2, 3), got %v", matrix)
+	}
+	if len(doc.Meshes) != 2 || len(doc.Meshes[0].Primitives) != 1 {
+		t.Errorf("expect 2 meshes, got %v", doc.Meshes)
+		return
+	}
+	position := doc.Accessors[doc.Meshes[0].Primitives[0].Attributes["POSITION"]]
+	if position.Count != 24 || position.Max[0] != 1 || position.Min[0] != -1 {
+		t.Errorf("unexpected position: %v", position)
+	}
+
+	// the uploaded model is embedded once, and shared by both objects.
+	if doc.Nodes[4].Mesh == nil || *doc.Nodes[4].Mesh != 1 || doc.Nodes[5].Mesh == nil || *doc.Nodes[5].Mesh != 1 {
+		t.Errorf("expect the models to share mesh 1, got %v", doc.Nodes[4:])
+	}
+	position = doc.Accessors[doc.Meshes[1].Primitives[0].Attributes["POSITION"]]
+	if position.Count != 3 || position.Max[0] != 4 || position.Max[1] != 4 {
+		t.Errorf("unexpected model position: %v", position)
+	}
+	if len(doc.Materials) != 1 || doc.Materials[0].PBRMetallicRoughness.BaseColorFactor[0] != 1 {
+		t.Errorf("unexpected materials: %v", doc.Materials)
+	}
+	if len(doc.ExtensionsUsed) != 1 || doc.ExtensionsUsed[0] != "KHR_lights_punctual" {
+		t.Errorf("expect KHR_lights_punctual, got %v", doc.ExtensionsUsed)
+	}
+	if len(bin) < doc.Buffers[0].ByteLength {
+		t.Errorf("expect %v bytes, got %v", doc.Buffers[0].ByteLength, len(bin))
+	}
+
+	// the light looks at origin from above, so -Z of the node points down.
+	if matrix := doc.Nodes[2].Matrix; len(matrix) != 16 || matrix[9] < 0.99 {
+		t.Errorf("expect the light to point down, got %v", matrix)
+	}
+}
+
+func TestExportZip(t *testing.T) {
+	if err := server.CreateEmbedded(testDir); err != nil {
+		t.Error(err)
+		return
+	}
+	db, err := server.DB()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	// static contents, two textures in a dir that only one is used by the scene, and
+	// a model with its material file
+	files := map[string]string{
+		"/view.html":                `<script src="assets/js/three.min.js"></script><%SceneID%>`,
+		"/assets/js/three.min.js":   "three",
+		"/assets/js/codemirror.js":  "codemirror",
+		"/assets/examples/a.json":   "example",
+		"/Upload/Texture/1/a.png":   "a",
+		"/Upload/Texture/1/b.png":   "b",
+		"/Upload/Model/1/model.obj": "obj",
+		"/Upload/Model/1/model.mtl": "mtl",
+	}
+	for name, content := range files {
+		path := server.MapPath(name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		ioutil.WriteFile(path, []byte(content), 0755)
+	}
+
+	db.InsertOne(server.MeshCollectionName, bson.M{
+		"ID":       primitive.NewObjectID(),
+		"Name":     "TestExportZip",
+		"SavePath": "/Upload/Model/1",
+		"Url":      "/Upload/Model/1/model.obj",
+	})
+
+	id := primitive.NewObjectID()
+	db.InsertOne(server.SceneCollectionName, bson.M{
+		"ID":             id,
+		"Name":           "TestExportZip",
+		"CollectionName": "SceneTestExportZip",
+		"Version":        0,
+	})
+
+	var data []interface{}
+	bson.UnmarshalExtJSON([]byte(`[
+		{"metadata":{"generator":"SceneSerializer"},"uuid":"scene"},
+		{"metadata":{"generator":"MeshSerializer"},"uuid":"box","name":"Box",
+			"material":{"type":"MeshBasicMaterial","map":{"image":{"src":"/Upload/Texture/1/a.png"}}}},
+		{"metadata":{"generator":"ServerObject"},"uuid":"model","userData":{"Url":"/Upload/Model/1/model.obj"}}
+	]`), false, &data)
+	db.InsertMany("SceneTestExportZip", data)
+
+	// the head version is read from the scene collection rather than history.
+	values := url.Values{
+		"ID":      {id.Hex()},
+		"Zip":     {"true"},
+		"version": {"0"},
+	}
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(values.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	Scene(rec, req)
+
+	if contentType := rec.Header().Get("Content-Type"); contentType != "application/zip" {
+		t.Errorf("expect application/zip, got %v: %v", contentType, rec.Body.String())
+		return
+	}
+
+	body := rec.Body.Bytes()
+	reader, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	names := map[string]bool{}
+	for _, f := range reader.File {
+		names[f.Name] = true
+		if f.Name == "Scene/"+id.Hex()+".txt" {
+			file, _ := f.Open()
+			content, _ := ioutil.ReadAll(file)
+			file.Close()
+			if !strings.Contains(string(content), "Box") {
+				t.Errorf("expect Box in scene data, got %v", string(content))
+			}
+		}
+	}
+	for _, name := range []string{"view.html", "assets/js/three.min.js", "Scene/" + id.Hex() + ".txt", "Upload/Texture/1/a.png", "Upload/Model/1/model.mtl"} {
+		if !names[name] {
+			t.Errorf("expect %v in zip, got %v", name, names)
+		}
+	}
+	for _, name := range []string{"Upload/Texture/1/b.png", "assets/js/codemirror.js", "assets/examples/a.json"} {
+		if names[name] {
+			t.Errorf("expect %v not to be exported", name)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package server
+
+import (
+	"math"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/tengge1/shadoweditor/three"
+)
+
+// NewGeometry creates the vertices of a serialized parametric geometry, such as
+// `BoxBufferGeometry`, and returns nil for other geometries. Callers hold three.Mutex.
+func NewGeometry(doc bson.M) *three.BufferGeometry {
+	if doc == nil {
+		return nil
+	}
+	parameters, _ := toMap(doc["parameters"])
+	num := func(key string, def float64) float64 {
+		return toNumber(parameters[key], def)
+	}
+	count := func(key string, def float64) int {
+		return int(math.Floor(toNumber(parameters[key], def)))
+	}
+	openEnded, _ := parameters["openEnded"].(bool)
+
+	switch doc["type"] {
+	case "BoxBufferGeometry", "BoxGeometry":
+		return three.NewBoxBufferGeometry(num("width", 1), num("height", 1), num("depth", 1),
+			count("widthSegments", 1), count("heightSegments", 1), count("depthSegments", 1))
+	case "PlaneBufferGeometry", "PlaneGeometry":
+		return three.NewPlaneBufferGeometry(num("width", 1), num("height", 1),
+			count("widthSegments", 1), count("heightSegments", 1))
+	case "SphereBufferGeometry", "SphereGeometry":
+		return three.NewSphereBufferGeometry(num("radius", 1), count("widthSegments", 8), count("heightSegments", 6),
+			num("phiStart", 0), num("phiLength", math.Pi*2), num("thetaStart", 0), num("thetaLength", math.Pi))
+	case "CylinderBufferGeometry", "CylinderGeometry":
+		return three.NewCylinderBufferGeometry(num("radiusTop", 1), num("radiusBottom", 1), num("height", 1),
+			count("radialSegments", 8), count("heightSegments", 1), openEnded, num("thetaStart", 0), num("thetaLength", math.Pi*2))
+	case "ConeBufferGeometry", "ConeGeometry":
+		return three.NewConeBufferGeometry(num("radius", 1), num("height", 1),
+			count("radialSegments", 8), count("heightSegments", 1), openEnded, num("thetaStart", 0), num("thetaLength", math.Pi*2))
+	case "CircleBufferGeometry", "CircleGeometry":
+		return three.NewCircleBufferGeometry(num("radius", 1), count("segments", 8), num("thetaStart", 0), num("thetaLength", math.Pi*2))
+	case "RingBufferGeometry", "RingGeometry":
+		return three.NewRingBufferGeometry(num("innerRadius", 0.5), num("outerRadius", 1),
+			count("thetaSegments", 8), count("phiSegments", 1), num("thetaStart", 0), num("thetaLength", math.Pi*2))
+	case "TorusBufferGeometry", "TorusGeometry":
+		return three.NewTorusBufferGeometry(num("radius", 1), num("tube", 0.4),
+			count("radialSegments", 8), count("tubularSegments", 6), num("arc", math.Pi*2))
+	}
+	return nil
+}
+
+// ObjectMatrix returns the local matrix of a serialized object from its position,
+// quaternion or rotation, and scale.
+func ObjectMatrix(doc bson.M) *three.Matrix4 {
+	position := three.NewVector3(0, 0, 0)
+	if val, ok := toMap(doc["position"]); ok {
+		position.Set(toNumber(val["x"], 0), toNumber(val["y"], 0), toNumber(val["z"], 0))
+	}
+	quaternion := three.NewQuaternion(0, 0, 0, 1)
+	if val, ok := toMap(doc["quaternion"]); ok {
+		quaternion.Set(toNumber(val["x"], 0), toNumber(val["y"], 0), toNumber(val["z"], 0), toNumber(val["w"], 1))
+	} else if val, ok := toMap(doc["rotation"]); ok {
+		order, _ := val["order"].(string)
+		euler := three.NewEuler(toNumber(val["x"], 0), toNumber(val["y"], 0), toNumber(val["z"], 0), order)
+		quaternion.SetFromEuler(*euler, false)
+	}
+	scale := three.NewVector3(1, 1, 1)
+	if val, ok := toMap(doc["scale"]); ok {
+		scale.Set(toNumber(val["x"], 1), toNumber(val["y"], 1), toNumber(val["z"], 1))
+	}
+	return three.NewMatrix4().Compose(*position, *quaternion, *scale)
+}
+
+// toNumber converts a bson number to float64.
+func toNumber(value interface{}, def float64) float64 {
+	switch v := value.(type) {
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	}
+	return def
+}
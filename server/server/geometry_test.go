@@ -0,0 +1,58 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package server
+
+import (
+	"math"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/tengge1/shadoweditor/three"
+)
+
+func TestNewGeometry(t *testing.T) {
+	geometry := NewGeometry(bson.M{
+		"type": "BoxBufferGeometry",
+		"parameters": bson.M{
+			"width":  int32(2),
+			"height": 4.0,
+		},
+	})
+	if geometry == nil || len(geometry.Index) != 36 {
+		t.Errorf("expect a box of 12 triangles, got %v", geometry)
+		return
+	}
+	box := three.Box3{}
+	box.SetFromArray(geometry.Position)
+	if box.Max.X != 1 || box.Max.Y != 2 || box.Max.Z != 0.5 {
+		t.Errorf("expect a box of 2x4x1, got %v", box.Max)
+	}
+
+	if geometry := NewGeometry(bson.M{"type": "BufferGeometry"}); geometry != nil {
+		t.Errorf("expect nil, got %v", geometry)
+	}
+}
+
+func TestObjectMatrix(t *testing.T) {
+	// rotate 90 degrees around y, then move
+	matrix := ObjectMatrix(bson.M{
+		"position": bson.M{"x": 1.0, "y": int32(2), "z": 3.0},
+		"rotation": bson.M{"x": 0.0, "y": math.Pi / 2, "z": 0.0, "order": "XYZ"},
+		"scale":    bson.M{"x": 2.0, "y": 2.0, "z": 2.0},
+	})
+	point := three.NewVector3(1, 0, 0).ApplyMatrix4(*matrix)
+	expected := three.NewVector3(1, 2, 1)
+	if point.DistanceTo(*expected) > 1e-9 {
+		t.Errorf("expect %v, got %v", expected, point)
+	}
+
+	if matrix := ObjectMatrix(bson.M{}); !matrix.Equals(*three.NewMatrix4()) {
+		t.Errorf("expect identity, got %v", matrix.Elements)
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package server
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// janitorInterval is how often the temp dir is checked.
+const janitorInterval = 10 * time.Minute
+
+// startJanitor removes expired exports in the temp dir, and chunked uploads that are
+// not completed in time, in the background.
+func startJanitor() {
+	if Config.Export.TempExpires <= 0 {
+		return
+	}
+	expires := time.Duration(Config.Export.TempExpires) * time.Minute
+
+	go func() {
+		for {
+			removed, err := CleanTemp(MapPath("/temp"), expires, time.Now())
+			if err != nil {
+				Logger.Warnf("clean temp dir failed: %v", err)
+			} else if len(removed) > 0 {
+				Logger.Infof("removed %v expired files from temp dir", len(removed))
+			}
+			if db, err := DB(); err == nil {
+				if count, err := CleanUploads(db, expires, time.Now()); err != nil {
+					Logger.Warnf("clean chunked uploads failed: %v", err)
+				} else if count > 0 {
+					Logger.Infof("removed %v expired chunked uploads", count)
+				}
+			}
+			time.Sleep(janitorInterval)
+		}
+	}()
+}
+
+// CleanTemp removes files and dirs in the temp dir that are older than expires,
+// and returns the removed paths.
+func CleanTemp(dir string, expires time.Duration, now time.Time) ([]string, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	removed := []string{}
+	for _, info := range infos {
+		if now.Sub(info.ModTime()) < expires {
+			continue
+		}
+		path := filepath.Join(dir, info.Name())
+		if err := os.RemoveAll(path); err != nil {
+			return removed, err
+		}
+		removed = append(removed, path)
+	}
+	return removed, nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package server
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCleanTemp(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	now := time.Now()
+
+	oldDir := filepath.Join(dir, "20200101000000")
+	os.MkdirAll(filepath.Join(oldDir, "Scene"), 0755)
+	ioutil.WriteFile(filepath.Join(oldDir, "Scene", "a.txt"), []byte("a"), 0755)
+	os.Chtimes(oldDir, now.Add(-2*time.Hour), now.Add(-2*time.Hour))
+
+	oldZip := filepath.Join(dir, "20200101000000.zip")
+	ioutil.WriteFile(oldZip, []byte("zip"), 0755)
+	os.Chtimes(oldZip, now.Add(-2*time.Hour), now.Add(-2*time.Hour))
+
+	newDir := filepath.Join(dir, "20200101010000")
+	os.MkdirAll(newDir, 0755)
+
+	removed, err := CleanTemp(dir, time.Hour, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 2 {
+		t.Errorf("expect 2 removed, got %v", removed)
+	}
+	if _, err := os.Stat(oldDir); !os.IsNotExist(err) {
+		t.Errorf("%v should be removed", oldDir)
+	}
+	if _, err := os.Stat(oldZip); !os.IsNotExist(err) {
+		t.Errorf("%v should be removed", oldZip)
+	}
+	if _, err := os.Stat(newDir); err != nil {
+		t.Errorf("%v should be kept", newDir)
+	}
+
+	// missing temp dir is not an error
+	if _, err := CleanTemp(filepath.Join(dir, "none"), time.Hour, now); err != nil {
+		t.Error(err)
+	}
+}
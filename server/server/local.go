@@ -0,0 +1,76 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+
+	"github.com/tengge1/shadoweditor/server/system"
+)
+
+// routes are the apis registered by Handle.
+var routes = []Route{}
+
+// Route is an api registered by Handle.
+type Route struct {
+	// Method, such as `GET` and `POST`
+	Method string
+	// Path, such as `/api/Scene/List`
+	Path string
+	// Authority that the api requires
+	Authority Authority
+}
+
+// Routes returns the registered apis sorted by path.
+func Routes() []Route {
+	list := make([]Route, len(routes))
+	copy(list, routes)
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Path == list[j].Path {
+			return list[i].Method < list[j].Method
+		}
+		return list[i].Path < list[j].Path
+	})
+	return list
+}
+
+// NewAdministrator returns a user that has all the authorities, and it is used to
+// call handlers in process when there is no login user.
+func NewAdministrator() *system.User {
+	user := &system.User{
+		Username:             "Administrator",
+		Name:                 "Administrator",
+		RoleName:             "Administrator",
+		OperatingAuthorities: []string{string(Login)},
+	}
+	for _, item := range GetAllAuthorities() {
+		user.OperatingAuthorities = append(user.OperatingAuthorities, item.ID)
+	}
+	return user
+}
+
+// ServeLocal calls the handler of an api in process as the user, and returns the
+// response body. Authority is checked like requests from clients, so it works no
+// matter whether https or authority is enabled.
+func ServeLocal(method, target string, user *system.User) ([]byte, error) {
+	req := httptest.NewRequest(method, target, nil)
+	if user != nil {
+		req = WithUser(req, user)
+	}
+	rec := httptest.NewRecorder()
+
+	ValidateTokenMiddleware(rec, req, mux.ServeHTTP)
+
+	if rec.Code != http.StatusOK {
+		return nil, fmt.Errorf("%v %v: %v", method, target, http.StatusText(rec.Code))
+	}
+	return rec.Body.Bytes(), nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package server
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestServeLocal(t *testing.T) {
+	if err := Create("../config.toml"); err != nil {
+		t.Error(err)
+		return
+	}
+	Config.Authority.Enabled = true
+	defer func() {
+		Config.Authority.Enabled = false
+	}()
+
+	path := "/api/TestServeLocal"
+	Handle(http.MethodGet, path, func(w http.ResponseWriter, r *http.Request) {
+		user, _ := GetCurrentUser(r)
+		w.Write([]byte(user.Name + ":" + r.FormValue("ID")))
+	}, Administrator)
+
+	found := false
+	for _, route := range Routes() {
+		if route.Path == path && route.Method == http.MethodGet && route.Authority == Administrator {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expect %v in routes", path)
+	}
+
+	// guests are not allowed
+	body, err := ServeLocal(http.MethodGet, path+"?ID=1", nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if expected := `{"Code":301,"Msg":"Not allowed."}`; string(body) != expected {
+		t.Errorf("expect %v, got %v", expected, string(body))
+	}
+
+	body, err = ServeLocal(http.MethodGet, path+"?ID=1", NewAdministrator())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if expected := "Administrator:1"; string(body) != expected {
+		t.Errorf("expect %v, got %v", expected, string(body))
+	}
+}
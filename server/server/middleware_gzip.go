@@ -16,9 +16,10 @@ import (
 
 // GZipMiddleware is used for determining if the incoming request should be served gzipped data.
 // When the request `Content-Encoding` contains `gzip`, we write a gzipped response.
+// WebSocket connections are never gzipped, because they hijack the connection.
 func GZipMiddleware(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
 	encoding := r.Header.Get("Accept-Encoding")
-	if encoding == "" {
+	if encoding == "" || strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
 		next.ServeHTTP(w, r)
 		return
 	}
@@ -0,0 +1,25 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package server
+
+import (
+	"net/http"
+	"path"
+	"strings"
+)
+
+// ProtectDataMiddleware prevents downloading `/data`, where the file storage saves
+// documents including users and passwords, as static files.
+func ProtectDataMiddleware(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	p := strings.ToLower(path.Clean("/" + r.URL.Path))
+	if p == "/data" || strings.HasPrefix(p, "/data/") {
+		http.NotFound(w, r)
+		return
+	}
+	next.ServeHTTP(w, r)
+}
@@ -0,0 +1,38 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProtectDataMiddleware(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}
+
+	tests := map[string]int{
+		"/data/_User.bson":    http.StatusNotFound,
+		"/Data/_User.bson":    http.StatusNotFound,
+		"/Upload/../data/a":   http.StatusNotFound,
+		"/data":               http.StatusNotFound,
+		"/database.html":      http.StatusOK,
+		"/Upload/Model/a.obj": http.StatusOK,
+	}
+
+	for path, code := range tests {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		ProtectDataMiddleware(w, r, handler)
+		if w.Code != code {
+			t.Errorf("%v: expect %v, got %v", path, code, w.Code)
+		}
+	}
+}
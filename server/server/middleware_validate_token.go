@@ -22,7 +22,7 @@ func ValidateTokenMiddleware(w http.ResponseWriter, r *http.Request, next http.H
 		return
 	}
 
-	auth, ok := apiAuthorities[r.URL.Path]
+	auth, ok := lookupAuthority(r.URL.Path)
 	if !ok {
 		// path is not registered.
 		Logger.Errorf("%v is not registered", r.URL.Path)
@@ -77,7 +77,7 @@ func writeNotAllowed(w http.ResponseWriter) {
 // canInitialize means if we can initialize the system. Return true when
 // the system is not initialized.
 func canInitialize() bool {
-	db, err := Mongo()
+	db, err := DB()
 	if err != nil {
 		return false
 	}
@@ -9,7 +9,7 @@ package server
 
 // Result present a server handler result.
 type Result struct {
-	// The Response Code: 200 - ok; 300 -error; 301 - not authorized.
+	// The Response Code: 200 - ok; 300 -error; 301 - not authorized; 302 - conflict.
 	Code int `json:"Code" bson:"Code"`
 	// The Response Message
 	Msg string `json:"Msg" bson:"Msg"`
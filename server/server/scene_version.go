@@ -0,0 +1,82 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package server
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ParseSceneVersion parses the version param of scene apis. It is a version number
+// or a tag name of the scene, and an empty string means the latest version (-1).
+func ParseSceneVersion(db Storage, sceneID primitive.ObjectID, value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return -1, nil
+	}
+	if version, err := strconv.Atoi(value); err == nil {
+		return version, nil
+	}
+
+	filter := bson.M{
+		"SceneID": sceneID,
+		"Name":    value,
+	}
+	tag := bson.M{}
+	find, err := db.FindOne(SceneTagCollectionName, filter, &tag)
+	if err != nil {
+		return -1, err
+	}
+	if !find {
+		return -1, fmt.Errorf("tag %v is not existed", value)
+	}
+
+	switch version := tag["Version"].(type) {
+	case int32:
+		return int(version), nil
+	case int64:
+		return int(version), nil
+	}
+	return -1, fmt.Errorf("tag %v has no version", value)
+}
+
+// LoadSceneVersion returns the scene data of a version. Version -1 or the version of
+// the scene returns the latest data, and others are read from history.
+func LoadSceneVersion(db Storage, doc bson.M, version int) ([]bson.M, error) {
+	collectionName := doc["CollectionName"].(string)
+	current := 0
+	if doc["Version"] != nil {
+		current = int(doc["Version"].(int32))
+	}
+
+	docs := []bson.M{}
+	if version == -1 || version == current {
+		if err := db.FindAll(collectionName, &docs); err != nil {
+			return nil, err
+		}
+		return docs, nil
+	}
+
+	filter := bson.M{
+		VersionField: version,
+	}
+	if err := db.FindMany(collectionName+HistorySuffix, filter, &docs); err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("version %v is not existed", version)
+	}
+	for _, doc := range docs {
+		delete(doc, VersionTimeField)
+	}
+	return docs, nil
+}
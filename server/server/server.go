@@ -15,6 +15,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 
 	"github.com/dimfeld/httptreemux"
 	"github.com/tengge1/shadoweditor/helper"
@@ -37,6 +38,7 @@ func init() {
 //
 // Negroni is an idiomatic HTTP Middleware for Golang. `negroni.Classic` add three
 // middleware: Recovery, Logger, Static. We add two: CrossOriginHandler, GZipHandler.
+// ProtectDataMiddleware prevents the file storage being downloaded as static files.
 //
 // Then, we use `httptreemux` to map route to the handler.
 func Start() {
@@ -56,11 +58,14 @@ func Start() {
 
 	handler := negroni.New(recovery, logger)
 	handler.Use(negroni.HandlerFunc(CrossOriginMiddleware))
+	handler.Use(negroni.HandlerFunc(ProtectDataMiddleware))
 	handler.Use(static)
 	handler.Use(negroni.HandlerFunc(GZipMiddleware))
 	handler.Use(negroni.HandlerFunc(ValidateTokenMiddleware))
 	handler.UseHandler(mux)
 
+	startJanitor()
+
 	srv := http.Server{Addr: Config.Server.Port, Handler: handler}
 	idleConnsClosed := make(chan struct{})
 
@@ -100,6 +105,11 @@ func Handle(method, path string, handler http.HandlerFunc, authority Authority)
 		panic(fmt.Errorf("path (%v) has already been handled", path))
 	}
 	apiAuthorities[path] = authority
+	routes = append(routes, Route{
+		Method:    method,
+		Path:      path,
+		Authority: authority,
+	})
 	mux.UsingContext().Handle(method, path, handler)
 }
 
@@ -107,3 +117,40 @@ func Handle(method, path string, handler http.HandlerFunc, authority Authority)
 func corsHandler(w http.ResponseWriter, r *http.Request, params map[string]string) {
 	helper.EnableCrossDomain(w, r)
 }
+
+// lookupAuthority returns the authority that a request path requires. Paths of
+// routes with parameters, such as `/api/Scene/Collaborate/:ID`, are matched
+// segment by segment.
+func lookupAuthority(path string) (Authority, bool) {
+	if auth, ok := apiAuthorities[path]; ok {
+		return auth, true
+	}
+	segments := strings.Split(path, "/")
+	for route, auth := range apiAuthorities {
+		if !strings.Contains(route, ":") {
+			continue
+		}
+		routeSegments := strings.Split(route, "/")
+		if len(routeSegments) != len(segments) {
+			continue
+		}
+		matched := true
+		for i, segment := range routeSegments {
+			if strings.HasPrefix(segment, ":") {
+				if segments[i] == "" {
+					matched = false
+					break
+				}
+				continue
+			}
+			if segment != segments[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return auth, true
+		}
+	}
+	return None, false
+}
@@ -153,3 +153,29 @@ func TestMimeType(t *testing.T) {
 		t.Errorf("expect %v, get %v", expect, get)
 	}
 }
+
+func TestLookupAuthority(t *testing.T) {
+	apiAuthorities["/api/TestLookupAuthority"] = None
+	apiAuthorities["/api/TestLookupAuthority/:ID"] = SaveScene
+	defer func() {
+		delete(apiAuthorities, "/api/TestLookupAuthority")
+		delete(apiAuthorities, "/api/TestLookupAuthority/:ID")
+	}()
+
+	tests := []struct {
+		path      string
+		authority Authority
+		ok        bool
+	}{
+		{"/api/TestLookupAuthority", None, true},
+		{"/api/TestLookupAuthority/5e9996894a8c40e5755b5e09", SaveScene, true},
+		{"/api/TestLookupAuthority/", None, false},
+		{"/api/TestLookupAuthority/1/2", None, false},
+	}
+	for _, test := range tests {
+		auth, ok := lookupAuthority(test.path)
+		if auth != test.authority || ok != test.ok {
+			t.Errorf("%v: expect %v %v, got %v %v", test.path, test.authority, test.ok, auth, ok)
+		}
+	}
+}
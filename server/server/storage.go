@@ -0,0 +1,143 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package server
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/tengge1/shadoweditor/helper"
+)
+
+const (
+	// MongoStorage stores documents in mongo. It is the default storage.
+	MongoStorage string = "mongo"
+	// SQLiteStorage stores documents in a sqlite database file.
+	SQLiteStorage string = "sqlite"
+	// FileStorage stores documents in files under `path.public_dir`/data. It is used
+	// in embedded mode, and needs no database server.
+	FileStorage string = "file"
+)
+
+// Storage is the document storage that handlers read and write. Filters, updates and
+// find options are written in mongo style, so *helper.Mongo implements Storage directly,
+// and other storages such as *helper.SQLite evaluate them on their own.
+type Storage interface {
+	// ListCollectionNames list collectionNames of database.
+	ListCollectionNames() (collectionNames []string, err error)
+	// CollectionExists determine whether a collection existed.
+	CollectionExists(name string) (existed bool, err error)
+	// CreateCollection create a new collection.
+	CreateCollection(name string) error
+	// DropCollection drop a collection.
+	DropCollection(name string) error
+	// InsertOne insert one document to a collection.
+	InsertOne(collectionName string, document interface{}) (*mongo.InsertOneResult, error)
+	// InsertMany insert many documents to a collection.
+	InsertMany(collectionName string, documents []interface{}) (*mongo.InsertManyResult, error)
+	// Count get documents count of a collection.
+	Count(collectionName string, filter interface{}) (int64, error)
+	// FindOne find one document from a collection.
+	FindOne(collectionName string, filter interface{}, result interface{}, opts ...*options.FindOneOptions) (find bool, err error)
+	// FindMany find many documents in the collection.
+	FindMany(collectionName string, filter interface{}, results interface{}, opts ...*options.FindOptions) (err error)
+	// FindAll find all the documents in the collection.
+	FindAll(collectionName string, results interface{}, opts ...*options.FindOptions) (err error)
+	// UpdateOne update one document.
+	UpdateOne(collectionName string, filter interface{}, update interface{}) (*mongo.UpdateResult, error)
+	// UpdateMany update many documents in the collection.
+	UpdateMany(collectionName string, filter interface{}, update interface{}) (*mongo.UpdateResult, error)
+	// UpdateAll update all the documents in the collection.
+	UpdateAll(collectionName string, update interface{}) (*mongo.UpdateResult, error)
+	// DeleteOne delete one document from the collection.
+	DeleteOne(collectionName string, filter interface{}) (*mongo.DeleteResult, error)
+	// DeleteMany delete many documents from the collection.
+	DeleteMany(collectionName string, filter interface{}) (*mongo.DeleteResult, error)
+	// DeleteAll delete all documents from the collection.
+	DeleteAll(collectionName string) (*mongo.DeleteResult, error)
+}
+
+var (
+	// storage caches the storage that DB returns.
+	storage Storage
+	// storageSource is the database type and path that storage is opened with.
+	storageSource string
+	// storageMutex prevents opening the storage twice.
+	storageMutex sync.Mutex
+)
+
+// DB returns the storage selected by `database.type` in config.toml.
+// DO NOT close it because of singleton.
+func DB() (Storage, error) {
+	if Config == nil {
+		return nil, fmt.Errorf("config is not initialized")
+	}
+
+	storageMutex.Lock()
+	defer storageMutex.Unlock()
+
+	// config may be created again, such as in tests, so reopen the storage when it changes.
+	source := fmt.Sprintf("%v:%v:%v", Config.Database.Type, Config.Database.File, Config.Path.PublicDir)
+	if storage != nil && source == storageSource {
+		return storage, nil
+	}
+	// mongo clients are shared by Mongo, so only the storages that DB opens are closed.
+	if closer, ok := storage.(io.Closer); ok {
+		if err := closer.Close(); err != nil && Logger != nil {
+			Logger.Error(err)
+		}
+	}
+	storage = nil
+
+	var err error
+	switch Config.Database.Type {
+	case "", MongoStorage:
+		var db *helper.Mongo
+		if db, err = Mongo(); err == nil {
+			storage = db
+		}
+	case SQLiteStorage:
+		var db *helper.SQLite
+		if db, err = openSQLite(Config.Database.File); err == nil {
+			storage = db
+		}
+	case FileStorage:
+		var db *helper.FileDB
+		if db, err = helper.NewFileDB(MapPath("/data")); err == nil {
+			storage = db
+		}
+	default:
+		err = fmt.Errorf("unknown database type: %v", Config.Database.Type)
+	}
+
+	if err != nil {
+		if Logger != nil {
+			Logger.Error(err)
+		}
+		return nil, err
+	}
+	storageSource = source
+	return storage, nil
+}
+
+// openSQLite opens a sqlite database file, and creates its directory if needed.
+func openSQLite(path string) (*helper.SQLite, error) {
+	dir := filepath.Dir(path)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, err
+		}
+	}
+	return helper.NewSQLite(path)
+}
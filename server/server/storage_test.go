@@ -0,0 +1,123 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package server
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/tengge1/shadoweditor/helper"
+)
+
+func TestSQLiteStorage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	config := Config
+	defer func() {
+		Config = config
+		storage = nil
+	}()
+
+	Config = &helper.ConfigModel{}
+	Config.Database.Type = SQLiteStorage
+	Config.Database.File = filepath.Join(dir, "data", "test.db")
+	storage = nil
+
+	db, err := DB()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if _, ok := db.(*helper.SQLite); !ok {
+		t.Errorf("expect *helper.SQLite, got %T", db)
+	}
+
+	if _, err := db.InsertOne(SceneCollectionName, bson.M{"Name": "Scene1"}); err != nil {
+		t.Error(err)
+	}
+	count, err := db.Count(SceneCollectionName, bson.M{"Name": "Scene1"})
+	if err != nil {
+		t.Error(err)
+	}
+	if count != 1 {
+		t.Errorf("expect 1, got %v", count)
+	}
+	if _, err := os.Stat(Config.Database.File); err != nil {
+		t.Error(err)
+	}
+
+	// the previous database is closed when the config changes.
+	Config.Database.File = filepath.Join(dir, "data", "test2.db")
+	if _, err := DB(); err != nil {
+		t.Error(err)
+	}
+	if _, err := db.Count(SceneCollectionName, bson.M{}); err == nil {
+		t.Errorf("expect the previous database to be closed")
+	}
+}
+
+func TestUnknownStorage(t *testing.T) {
+	config := Config
+	defer func() {
+		Config = config
+		storage = nil
+	}()
+
+	Config = &helper.ConfigModel{}
+	Config.Database.Type = "unknown"
+	storage = nil
+
+	if _, err := DB(); err == nil {
+		t.Errorf("expect error, got nil")
+	}
+}
+
+func TestFileStorage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	config, logger := Config, Logger
+	defer func() {
+		Config, Logger = config, logger
+		storage = nil
+	}()
+
+	if err := CreateEmbedded(dir); err != nil {
+		t.Error(err)
+		return
+	}
+
+	db, err := DB()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if _, ok := db.(*helper.FileDB); !ok {
+		t.Errorf("expect *helper.FileDB, got %T", db)
+	}
+
+	if _, err := db.InsertOne(UserCollectionName, bson.M{"Username": "admin"}); err != nil {
+		t.Error(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "public", "data", UserCollectionName+".bson")); err != nil {
+		t.Error(err)
+	}
+}
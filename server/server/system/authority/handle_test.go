@@ -12,13 +12,28 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
 	"testing"
 
 	"github.com/tengge1/shadoweditor/server"
 )
 
+// testDir is the directory that tests store data in, so that tests need no mongo.
+var testDir string
+
+func TestMain(m *testing.M) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		panic(err)
+	}
+	testDir = dir
+	code := m.Run()
+	os.RemoveAll(testDir)
+	os.Exit(code)
+}
+
 func TestOperatingAuthorityGet(t *testing.T) {
-	server.Create("../../config.toml")
+	server.CreateEmbedded(testDir)
 	server.Config.Authority.Enabled = true
 
 	ts := httptest.NewServer(http.HandlerFunc(Get))
@@ -43,7 +58,7 @@ func TestOperatingAuthorityGet(t *testing.T) {
 }
 
 func TestOperatingAuthoritySave(t *testing.T) {
-	server.Create("../../config.toml")
+	server.CreateEmbedded(testDir)
 	server.Config.Authority.Enabled = true
 
 	ts := httptest.NewServer(http.HandlerFunc(Save))
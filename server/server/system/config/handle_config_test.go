@@ -9,6 +9,7 @@ package config
 
 import (
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -17,8 +18,22 @@ import (
 	"github.com/tengge1/shadoweditor/server"
 )
 
+// testDir is the directory that tests store data in, so that tests need no mongo.
+var testDir string
+
+func TestMain(m *testing.M) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		panic(err)
+	}
+	testDir = dir
+	code := m.Run()
+	os.RemoveAll(testDir)
+	os.Exit(code)
+}
+
 func TestHandleConfigNoAuthority(t *testing.T) {
-	server.Create("../../config.toml")
+	server.CreateEmbedded(testDir)
 	server.Config.Authority.Enabled = false
 
 	ts := httptest.NewServer(http.HandlerFunc(Get))
@@ -34,7 +49,7 @@ func TestHandleConfigNoAuthority(t *testing.T) {
 }
 
 func TestHandleConfigNotLogin(t *testing.T) {
-	server.Create("../../config.toml")
+	server.CreateEmbedded(testDir)
 	server.Config.Authority.Enabled = true
 
 	ts := httptest.NewServer(http.HandlerFunc(Get))
@@ -50,7 +65,7 @@ func TestHandleConfigNotLogin(t *testing.T) {
 }
 
 func TestHandleConfigLoginAdmin(t *testing.T) {
-	server.Create("../../config.toml")
+	server.CreateEmbedded(testDir)
 	server.Config.Authority.Enabled = true
 
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
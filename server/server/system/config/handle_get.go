@@ -24,7 +24,7 @@ func init() {
 
 // Get get the server config of the current user.
 func Get(w http.ResponseWriter, r *http.Request) {
-	db, err := server.Mongo()
+	db, err := server.DB()
 	if err != nil {
 		helper.Write(w, err.Error())
 		return
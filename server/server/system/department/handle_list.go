@@ -23,7 +23,7 @@ func init() {
 
 // List returns the department list.
 func List(w http.ResponseWriter, r *http.Request) {
-	db, err := server.Mongo()
+	db, err := server.DB()
 	if err != nil {
 		helper.Write(w, err.Error())
 		return
@@ -36,7 +36,7 @@ func Initialize(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// check whether is initialized
-	db, err := server.Mongo()
+	db, err := server.DB()
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
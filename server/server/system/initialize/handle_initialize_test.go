@@ -12,13 +12,28 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
 	"testing"
 
 	"github.com/tengge1/shadoweditor/server"
 )
 
+// testDir is the directory that tests store data in, so that tests need no mongo.
+var testDir string
+
+func TestMain(m *testing.M) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		panic(err)
+	}
+	testDir = dir
+	code := m.Run()
+	os.RemoveAll(testDir)
+	os.Exit(code)
+}
+
 func TestInitialize(t *testing.T) {
-	server.Create("../../config.toml")
+	server.CreateEmbedded(testDir)
 	server.Config.Authority.Enabled = true
 
 	ts := httptest.NewServer(http.HandlerFunc(Initialize))
@@ -41,7 +56,7 @@ func TestInitialize(t *testing.T) {
 }
 
 func TestReset(t *testing.T) {
-	server.Create("../../config.toml")
+	server.CreateEmbedded(testDir)
 	server.Config.Authority.Enabled = true
 
 	ts := httptest.NewServer(http.HandlerFunc(Reset))
@@ -23,7 +23,7 @@ func init() {
 
 // Reset reset the system, delete all the configs, roles, users, departments and authorities.
 func Reset(w http.ResponseWriter, r *http.Request) {
-	db, err := server.Mongo()
+	db, err := server.DB()
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
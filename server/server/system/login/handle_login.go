@@ -47,7 +47,7 @@ func Login(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// get salt
-	db, err := server.Mongo()
+	db, err := server.DB()
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
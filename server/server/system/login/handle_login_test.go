@@ -12,13 +12,28 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
 	"testing"
 
 	"github.com/tengge1/shadoweditor/server"
 )
 
+// testDir is the directory that tests store data in, so that tests need no mongo.
+var testDir string
+
+func TestMain(m *testing.M) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		panic(err)
+	}
+	testDir = dir
+	code := m.Run()
+	os.RemoveAll(testDir)
+	os.Exit(code)
+}
+
 func TestLogin(t *testing.T) {
-	server.Create("../../config.toml")
+	server.CreateEmbedded(testDir)
 	server.Config.Authority.Enabled = true
 
 	ts := httptest.NewServer(http.HandlerFunc(Login))
@@ -44,7 +59,7 @@ func TestLogin(t *testing.T) {
 }
 
 func TestLogout(t *testing.T) {
-	server.Create("../../config.toml")
+	server.CreateEmbedded(testDir)
 	server.Config.Authority.Enabled = true
 
 	ts := httptest.NewServer(http.HandlerFunc(Logout))
@@ -107,7 +107,7 @@ func ChangePassword(w http.ResponseWriter, r *http.Request) {
 		},
 	}
 
-	db, err := server.Mongo()
+	db, err := server.DB()
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
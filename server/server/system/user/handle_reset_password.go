@@ -62,7 +62,7 @@ func ResetPassword(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// check whether user is existed
-	db, err := server.Mongo()
+	db, err := server.DB()
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
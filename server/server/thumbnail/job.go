@@ -0,0 +1,148 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package thumbnail
+
+import (
+	"fmt"
+	"image/png"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/tengge1/shadoweditor/server"
+	"github.com/tengge1/shadoweditor/three"
+)
+
+// Size is the width and height of thumbnails.
+const Size = 256
+
+// dir is the dir that the thumbnails rendered by the server are saved in.
+const dir = "/Upload/Thumbnail"
+
+var (
+	// mu guards pending.
+	mu sync.Mutex
+	// pending is the docs that are waiting to be rendered.
+	pending = map[string]bool{}
+	// jobs is the jobs that are not finished.
+	jobs sync.WaitGroup
+)
+
+// Update renders the thumbnail of a doc in the background, and saves it to the
+// `Thumbnail` field of the doc. Meshes are loaded when the job runs, so a doc that
+// is saved many times is only rendered once. Thumbnails uploaded by users are
+// never replaced.
+func Update(collectionName string, id primitive.ObjectID, load func() ([]Mesh, error)) {
+	key := collectionName + "/" + id.Hex()
+
+	mu.Lock()
+	if pending[key] {
+		mu.Unlock()
+		return
+	}
+	pending[key] = true
+	mu.Unlock()
+
+	jobs.Add(1)
+	go func() {
+		defer jobs.Done()
+		// loading and rendering meshes use three.
+		three.Mutex.Lock()
+		defer three.Mutex.Unlock()
+
+		// saves during rendering need another job.
+		mu.Lock()
+		delete(pending, key)
+		mu.Unlock()
+
+		if err := update(collectionName, id, load); err != nil && err != errEmpty {
+			server.Logger.Warnf("render thumbnail of %v failed: %v", key, err)
+		}
+	}()
+}
+
+// Wait waits until all the thumbnails are rendered.
+func Wait() {
+	jobs.Wait()
+}
+
+// IsRendered returns whether a thumbnail url is rendered by the server. Rendered
+// thumbnails are replaced and deleted when the doc is rendered again.
+func IsRendered(url string) bool {
+	return strings.HasPrefix(url, dir+"/")
+}
+
+func update(collectionName string, id primitive.ObjectID, load func() ([]Mesh, error)) error {
+	db, err := server.DB()
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{
+		"ID": id,
+	}
+	doc := bson.M{}
+	find, err := db.FindOne(collectionName, filter, &doc)
+	if err != nil || !find {
+		return err
+	}
+	old, _ := doc["Thumbnail"].(string)
+	if old != "" && !IsRendered(old) {
+		return nil
+	}
+
+	meshes, err := load()
+	if err != nil {
+		return err
+	}
+	img, err := Render(meshes, Size, Size)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(server.MapPath(dir)); os.IsNotExist(err) {
+		os.MkdirAll(server.MapPath(dir), 0755)
+	}
+	url := fmt.Sprintf("%v/%v_%v.png", dir, id.Hex(), time.Now().UnixNano())
+	file, err := os.Create(server.MapPath(url))
+	if err != nil {
+		return err
+	}
+	err = png.Encode(file, img)
+	file.Close()
+	if err != nil {
+		os.Remove(server.MapPath(url))
+		return err
+	}
+
+	// users may have uploaded a thumbnail while rendering.
+	if old == "" {
+		filter["Thumbnail"] = bson.M{
+			"$in": bson.A{nil, ""},
+		}
+	} else {
+		filter["Thumbnail"] = old
+	}
+	result, err := db.UpdateOne(collectionName, filter, bson.M{
+		"$set": bson.M{
+			"Thumbnail": url,
+		},
+	})
+	if err != nil || result.MatchedCount == 0 {
+		os.Remove(server.MapPath(url))
+		return err
+	}
+	if old != "" {
+		os.Remove(server.MapPath(old))
+	}
+	return nil
+}
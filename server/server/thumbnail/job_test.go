@@ -0,0 +1,80 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package thumbnail
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/tengge1/shadoweditor/server"
+	"github.com/tengge1/shadoweditor/three"
+)
+
+func TestUpdate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+	server.CreateEmbedded(dir)
+
+	db, err := server.DB()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	rendered, uploaded := primitive.NewObjectID(), primitive.NewObjectID()
+	db.InsertOne(server.SceneCollectionName, bson.M{"ID": rendered})
+	db.InsertOne(server.SceneCollectionName, bson.M{"ID": uploaded, "Thumbnail": "/Upload/File/1/a.png"})
+
+	load := func() ([]Mesh, error) {
+		return []Mesh{{
+			Positions: []three.Vector3{*three.NewVector3(0, 0, 0), *three.NewVector3(1, 0, 0), *three.NewVector3(0, 1, 0)},
+			Indices:   []int{0, 1, 2},
+			Color:     DefaultColor,
+		}}, nil
+	}
+	thumbnailOf := func(id primitive.ObjectID) string {
+		doc := bson.M{}
+		db.FindOne(server.SceneCollectionName, bson.M{"ID": id}, &doc)
+		thumbnail, _ := doc["Thumbnail"].(string)
+		return thumbnail
+	}
+
+	Update(server.SceneCollectionName, rendered, load)
+	Update(server.SceneCollectionName, uploaded, load)
+	Wait()
+
+	first := thumbnailOf(rendered)
+	if !strings.HasPrefix(first, "/Upload/Thumbnail/") {
+		t.Errorf("expect a rendered thumbnail, got %v", first)
+		return
+	}
+	if _, err := os.Stat(server.MapPath(first)); err != nil {
+		t.Error(err)
+	}
+	if thumbnail := thumbnailOf(uploaded); thumbnail != "/Upload/File/1/a.png" {
+		t.Errorf("expect the uploaded thumbnail not to be replaced, got %v", thumbnail)
+	}
+
+	// rendering again replaces the file
+	Update(server.SceneCollectionName, rendered, load)
+	Wait()
+	if second := thumbnailOf(rendered); second == first {
+		t.Errorf("expect a new thumbnail, got %v", second)
+	}
+	if _, err := os.Stat(server.MapPath(first)); !os.IsNotExist(err) {
+		t.Errorf("expect %v to be removed", first)
+	}
+}
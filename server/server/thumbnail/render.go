@@ -0,0 +1,205 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package thumbnail
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/tengge1/shadoweditor/three"
+)
+
+// DefaultColor is the color of meshes that have no material color.
+const DefaultColor = 0x8fa8c8
+
+// background is the color of pixels that no triangle covers.
+var background = [3]float64{0.93, 0.93, 0.93}
+
+// samples is the supersampling factor, so that edges are antialiased.
+const samples = 2
+
+// errEmpty means that there is no triangle to render.
+var errEmpty = errors.New("nothing to render")
+
+// Mesh is triangles in world space that are rendered in one color.
+type Mesh struct {
+	// Vertex positions
+	Positions []three.Vector3
+	// Triangle vertex indices into positions, three for each triangle
+	Indices []int
+	// Color, such as 0xffffff
+	Color int
+}
+
+// Render renders meshes with flat shading. The camera looks at the center of
+// the bounds from the front right top, and all the meshes are in the view. Callers
+// hold three.Mutex.
+func Render(meshes []Mesh, width, height int) (*image.RGBA, error) {
+	box := three.Box3{}
+	box.MakeEmpty()
+	for _, mesh := range meshes {
+		for _, index := range mesh.Indices {
+			if index < 0 || index >= len(mesh.Positions) {
+				return nil, errors.New("vertex index is out of range")
+			}
+			box.ExpandByPoint(mesh.Positions[index])
+		}
+	}
+	if box.IsEmpty() {
+		return nil, errEmpty
+	}
+	center := box.GetCenter(three.Vector3{})
+	radius := box.GetSize(three.Vector3{}).Length() / 2
+	if math.IsInf(radius, 0) || math.IsNaN(radius) {
+		return nil, errors.New("invalid vertex positions")
+	}
+	if radius == 0 {
+		radius = 1
+	}
+
+	// fit the bounding sphere in the view
+	aspect := float64(width) / float64(height)
+	halfFov := 22.5 * math.Pi / 180
+	distance := radius / math.Sin(halfFov)
+	if aspect < 1 {
+		distance /= aspect
+	}
+	direction := three.NewVector3(1, 0.8, 1.2).Normalize()
+	eye := three.NewVector3(0, 0, 0).AddVectors(*center, *direction.Clone().MultiplyScalar(distance))
+	near := math.Max(distance-radius*1.01, distance*0.001)
+	far := distance + radius*1.01
+	top := near * math.Tan(halfFov)
+	right := top * aspect
+
+	camera := three.NewMatrix4().LookAt(*eye, *center, *three.NewVector3(0, 1, 0))
+	camera.SetPosition(eye.X, eye.Y, eye.Z)
+	view := three.NewMatrix4().GetInverse(*camera)
+	projection := three.NewMatrix4().MakePerspective(-right, right, top, -top, near, far)
+	viewProjection := three.NewMatrix4().MultiplyMatrices(*projection, *view)
+
+	light := three.NewVector3(0.5, 1, 0.8).Normalize()
+
+	r := rasterizer{
+		width:  width * samples,
+		height: height * samples,
+	}
+	r.colors = make([][3]float64, r.width*r.height)
+	r.depths = make([]float64, r.width*r.height)
+	for i := range r.colors {
+		r.colors[i] = background
+		r.depths[i] = math.Inf(1)
+	}
+
+	for _, mesh := range meshes {
+		base := [3]float64{
+			float64(mesh.Color>>16&255) / 255,
+			float64(mesh.Color>>8&255) / 255,
+			float64(mesh.Color&255) / 255,
+		}
+		triangle := three.Triangle{}
+		for i := 0; i+2 < len(mesh.Indices); i += 3 {
+			triangle.SetFromPointsAndIndices(mesh.Positions, mesh.Indices[i], mesh.Indices[i+1], mesh.Indices[i+2])
+			normal := triangle.GetNormal(three.Vector3{})
+			if normal.LengthSq() == 0 {
+				continue
+			}
+			// both sides are lit, because the winding of some models is not consistent.
+			intensity := 0.35 + 0.65*math.Abs(normal.Dot(*light))
+			shade := [3]float64{base[0] * intensity, base[1] * intensity, base[2] * intensity}
+
+			points := [3]three.Vector3{triangle.A, triangle.B, triangle.C}
+			for j := range points {
+				points[j].ApplyMatrix4(*viewProjection)
+				points[j].X = (points[j].X + 1) / 2 * float64(r.width)
+				points[j].Y = (1 - points[j].Y) / 2 * float64(r.height)
+			}
+			r.fill(points, shade)
+		}
+	}
+
+	return r.image(width, height), nil
+}
+
+// rasterizer fills triangles with a depth buffer.
+type rasterizer struct {
+	width  int
+	height int
+	colors [][3]float64
+	depths []float64
+}
+
+// fill fills a triangle in screen space, and z is the depth from -1 to 1.
+func (r *rasterizer) fill(points [3]three.Vector3, shade [3]float64) {
+	a, b, c := points[0], points[1], points[2]
+	area := edge(a, b, c.X, c.Y)
+	if area == 0 {
+		return
+	}
+
+	minX := int(math.Max(math.Floor(math.Min(a.X, math.Min(b.X, c.X))), 0))
+	maxX := int(math.Min(math.Ceil(math.Max(a.X, math.Max(b.X, c.X))), float64(r.width-1)))
+	minY := int(math.Max(math.Floor(math.Min(a.Y, math.Min(b.Y, c.Y))), 0))
+	maxY := int(math.Min(math.Ceil(math.Max(a.Y, math.Max(b.Y, c.Y))), float64(r.height-1)))
+
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			px, py := float64(x)+0.5, float64(y)+0.5
+			// barycentric coordinates
+			l0 := edge(b, c, px, py) / area
+			l1 := edge(c, a, px, py) / area
+			l2 := edge(a, b, px, py) / area
+			if l0 < 0 || l1 < 0 || l2 < 0 {
+				continue
+			}
+			z := l0*a.Z + l1*b.Z + l2*c.Z
+			index := y*r.width + x
+			if z < -1 || z > 1 || z >= r.depths[index] {
+				continue
+			}
+			r.depths[index] = z
+			r.colors[index] = shade
+		}
+	}
+}
+
+// image averages the samples of each pixel.
+func (r *rasterizer) image(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			sum := [3]float64{}
+			for sy := 0; sy < samples; sy++ {
+				for sx := 0; sx < samples; sx++ {
+					c := r.colors[(y*samples+sy)*r.width+x*samples+sx]
+					sum[0] += c[0]
+					sum[1] += c[1]
+					sum[2] += c[2]
+				}
+			}
+			img.SetRGBA(x, y, color.RGBA{
+				R: toByte(sum[0] / (samples * samples)),
+				G: toByte(sum[1] / (samples * samples)),
+				B: toByte(sum[2] / (samples * samples)),
+				A: 255,
+			})
+		}
+	}
+	return img
+}
+
+// edge returns twice the signed area of the triangle (a, b, p).
+func edge(a, b three.Vector3, px, py float64) float64 {
+	return (b.X-a.X)*(py-a.Y) - (b.Y-a.Y)*(px-a.X)
+}
+
+// toByte converts a color component from 0 to 1 to a byte.
+func toByte(value float64) uint8 {
+	return uint8(math.Max(0, math.Min(255, value*255+0.5)))
+}
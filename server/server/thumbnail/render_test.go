@@ -0,0 +1,49 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package thumbnail
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/tengge1/shadoweditor/three"
+)
+
+func TestRender(t *testing.T) {
+	box := three.NewBoxBufferGeometry(1, 1, 1, 1, 1, 1)
+	mesh := Mesh{
+		Indices: box.Index,
+		Color:   0xff0000,
+	}
+	for i := 0; i < len(box.Position); i += 3 {
+		mesh.Positions = append(mesh.Positions, *three.NewVector3(box.Position[i], box.Position[i+1], box.Position[i+2]))
+	}
+
+	img, err := Render([]Mesh{mesh}, 64, 32)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if size := img.Bounds().Size(); size.X != 64 || size.Y != 32 {
+		t.Errorf("expect 64x32, got %v", size)
+	}
+
+	// the box is in the center, and it is red and shaded.
+	center := img.RGBAAt(32, 16)
+	if center.R == 0 || center.G != 0 || center.B != 0 {
+		t.Errorf("expect red in the center, got %v", center)
+	}
+	corner := img.RGBAAt(0, 0)
+	if expected := (color.RGBA{237, 237, 237, 255}); corner != expected {
+		t.Errorf("expect background %v in the corner, got %v", expected, corner)
+	}
+
+	if _, err := Render([]Mesh{}, 64, 32); err != errEmpty {
+		t.Errorf("expect errEmpty, got %v", err)
+	}
+}
@@ -29,6 +29,15 @@ func init() {
 
 // Handle backup collections to a directory.
 func Handle(w http.ResponseWriter, r *http.Request) {
+	switch server.Config.Database.Type {
+	case server.SQLiteStorage:
+		backupSQLite(w, r)
+		return
+	case server.FileStorage:
+		backupFiles(w, r)
+		return
+	}
+
 	db, err := server.Mongo()
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
@@ -113,6 +122,66 @@ func Handle(w http.ResponseWriter, r *http.Request) {
 	helper.WriteJSON(w, result)
 }
 
+// backupSQLite copies the sqlite database file to the backup directory.
+func backupSQLite(w http.ResponseWriter, r *http.Request) {
+	now := time.Now()
+	backupDir := server.MapPath("../backup/database/dump" + helper.TimeToString(now, "yyyyMMddHHmmss"))
+
+	if _, err := os.Stat(backupDir); os.IsNotExist(err) {
+		os.MkdirAll(backupDir, 0755)
+	}
+
+	source := server.Config.Database.File
+	if err := helper.CopyFile(source, filepath.Join(backupDir, filepath.Base(source))); err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	result := backupResult{}
+	result.Code = 200
+	result.Msg = "Backup database successfully!"
+	result.Path = backupDir
+	helper.WriteJSON(w, result)
+}
+
+// backupFiles copies the collection files of the file storage to the backup directory.
+// Collections are written to temp files and renamed, so temp files are skipped.
+func backupFiles(w http.ResponseWriter, r *http.Request) {
+	now := time.Now()
+	backupDir := server.MapPath("../backup/database/dump" + helper.TimeToString(now, "yyyyMMddHHmmss"))
+
+	if _, err := os.Stat(backupDir); os.IsNotExist(err) {
+		os.MkdirAll(backupDir, 0755)
+	}
+
+	files, err := filepath.Glob(filepath.Join(server.MapPath("/data"), "*.bson"))
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+	for _, file := range files {
+		if err := helper.CopyFile(file, filepath.Join(backupDir, filepath.Base(file))); err != nil {
+			helper.WriteJSON(w, server.Result{
+				Code: 300,
+				Msg:  err.Error(),
+			})
+			return
+		}
+	}
+
+	result := backupResult{}
+	result.Code = 200
+	result.Msg = "Backup database successfully!"
+	result.Path = backupDir
+	helper.WriteJSON(w, result)
+}
+
 type backupResult struct {
 	server.Result
 	Path string
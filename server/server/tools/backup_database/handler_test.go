@@ -0,0 +1,56 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package backupdatabase
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/tengge1/shadoweditor/helper"
+	"github.com/tengge1/shadoweditor/server"
+)
+
+func TestHandleFileStorage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	server.CreateEmbedded(dir)
+
+	db, err := server.DB()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	db.InsertOne(server.SceneCollectionName, bson.M{"Name": "TestHandleFileStorage"})
+
+	rec := httptest.NewRecorder()
+	Handle(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+
+	result := backupResult{}
+	if err := helper.FromJSON(rec.Body.Bytes(), &result); err != nil {
+		t.Error(err)
+		return
+	}
+	if result.Code != 200 {
+		t.Errorf("expect 200, got %v: %v", result.Code, result.Msg)
+		return
+	}
+	if _, err := os.Stat(filepath.Join(result.Path, server.SceneCollectionName+".bson")); err != nil {
+		t.Error(err)
+	}
+}
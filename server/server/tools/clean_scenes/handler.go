@@ -21,9 +21,9 @@ func init() {
 	server.Handle(http.MethodPost, "/api/CleanUpScenes/Run", Handle, server.Administrator)
 }
 
-// Handle clean up history scenes and deleted scenes in the mongo.
+// Handle clean up history scenes and deleted scenes in the database.
 func Handle(w http.ResponseWriter, r *http.Request) {
-	db, err := server.Mongo()
+	db, err := server.DB()
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
@@ -33,8 +33,10 @@ func Handle(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var scenes []bson.M
+	var branches []bson.M
 
 	db.FindAll(server.SceneCollectionName, &scenes)
+	db.FindAll(server.SceneBranchCollectionName, &branches)
 
 	collectionNames, err := db.ListCollectionNames()
 	if err != nil {
@@ -46,11 +48,26 @@ func Handle(w http.ResponseWriter, r *http.Request) {
 	}
 
 	for _, collectionName := range collectionNames {
+		// branches keep their history, because the fork point is in it.
+		if strings.HasPrefix(collectionName, "Branch") {
+			name := strings.TrimSuffix(collectionName, "_history")
+			contains := false
+			for _, branch := range branches {
+				if branch["CollectionName"].(string) == name {
+					contains = true
+					break
+				}
+			}
+			if !contains {
+				db.DropCollection(collectionName)
+			}
+			continue
+		}
 		if !strings.HasPrefix(collectionName, "Scene") {
 			continue
 		}
 		if strings.HasSuffix(collectionName, "_history") {
-			db.DropCollection(collectionName)
+			cleanHistory(db, collectionName, scenes)
 			continue
 		}
 
@@ -72,3 +89,39 @@ func Handle(w http.ResponseWriter, r *http.Request) {
 		Msg:  "Execute sucessfully!",
 	})
 }
+
+// cleanHistory deletes the history of a scene except tagged versions, because
+// tagged versions are never pruned.
+func cleanHistory(db server.Storage, collectionName string, scenes []bson.M) {
+	name := strings.TrimSuffix(collectionName, server.HistorySuffix)
+	var scene bson.M
+	for _, i := range scenes {
+		if i["CollectionName"].(string) == name {
+			scene = i
+			break
+		}
+	}
+	if scene == nil {
+		db.DropCollection(collectionName)
+		return
+	}
+
+	var tags []bson.M
+	db.FindMany(server.SceneTagCollectionName, bson.M{
+		"SceneID": scene["ID"],
+	}, &tags)
+	if len(tags) == 0 {
+		db.DropCollection(collectionName)
+		return
+	}
+
+	versions := bson.A{}
+	for _, tag := range tags {
+		versions = append(versions, tag["Version"])
+	}
+	db.DeleteMany(collectionName, bson.M{
+		server.VersionField: bson.M{
+			"$nin": versions,
+		},
+	})
+}
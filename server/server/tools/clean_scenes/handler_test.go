@@ -0,0 +1,69 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package cleanscenes
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/tengge1/shadoweditor/server"
+)
+
+func TestHandle(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	server.CreateEmbedded(dir)
+
+	db, err := server.DB()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	// a scene with a tagged version, a scene without tags and a deleted scene
+	tagged, untagged := primitive.NewObjectID(), primitive.NewObjectID()
+	db.InsertMany(server.SceneCollectionName, []interface{}{
+		bson.M{"ID": tagged, "CollectionName": "Scene1", "Version": 3},
+		bson.M{"ID": untagged, "CollectionName": "Scene2", "Version": 1},
+	})
+	db.InsertOne(server.SceneTagCollectionName, bson.M{"SceneID": tagged, "Name": "review", "Version": 1})
+	for _, name := range []string{"Scene1", "Scene2", "Scene3"} {
+		db.InsertOne(name, bson.M{"uuid": "1"})
+		for i := 0; i < 3; i++ {
+			db.InsertOne(name+server.HistorySuffix, bson.M{"uuid": "1", server.VersionField: i})
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	Handle(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+
+	docs := []bson.M{}
+	db.FindAll("Scene1"+server.HistorySuffix, &docs)
+	if len(docs) != 1 || docs[0][server.VersionField] != int32(1) {
+		t.Errorf("expect tagged version 1 kept, got %v", docs)
+	}
+	for _, name := range []string{"Scene2" + server.HistorySuffix, "Scene3" + server.HistorySuffix, "Scene3"} {
+		if existed, _ := db.CollectionExists(name); existed {
+			t.Errorf("expect %v dropped", name)
+		}
+	}
+	if existed, _ := db.CollectionExists("Scene1"); !existed {
+		t.Errorf("expect Scene1 kept")
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package cleanuploads
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/tengge1/shadoweditor/helper"
+	"github.com/tengge1/shadoweditor/server"
+)
+
+func init() {
+	server.Handle(http.MethodPost, "/api/CleanUpUploads/Run", Handle, server.Administrator)
+}
+
+// Handle reports orphaned uploads and temp exports. It is a dry run unless Confirm is
+// true, and then the reported paths given by Path are deleted.
+func Handle(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+
+	db, err := server.DB()
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	if confirm, _ := strconv.ParseBool(r.FormValue("Confirm")); !confirm {
+		result, err := FindOrphans(db)
+		if err != nil {
+			helper.WriteJSON(w, server.Result{
+				Code: 300,
+				Msg:  err.Error(),
+			})
+			return
+		}
+
+		helper.WriteJSON(w, server.Result{
+			Code: 200,
+			Msg:  "Get Successfully!",
+			Data: result,
+		})
+		return
+	}
+
+	result, err := Delete(db, r.Form["Path"])
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	helper.WriteJSON(w, server.Result{
+		Code: 200,
+		Msg:  "Execute sucessfully!",
+		Data: result,
+	})
+}
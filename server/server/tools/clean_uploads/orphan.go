@@ -0,0 +1,195 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package cleanuploads
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/tengge1/shadoweditor/server"
+)
+
+// uploadDirs are the upload dirs and the collections whose docs own the files in them.
+var uploadDirs = []struct {
+	Dir            string
+	CollectionName string
+}{
+	{"/Upload/Model", server.MeshCollectionName},
+	{"/Upload/Texture", server.MapCollectionName},
+	{"/Upload/Audio", server.AudioCollectionName},
+	{"/Upload/Animation", server.AnimationCollectionName},
+	{"/Upload/Video", server.VideoCollectionName},
+	{"/Upload/Screenshot", server.ScreenshotCollectionName},
+	{"/Upload/File", server.FileCollectionName},
+	{"/Upload/Font", server.TypefaceCollectionName},
+	{server.BlobDir, server.BlobCollectionName},
+}
+
+// Model is an orphaned file or dir.
+type Model struct {
+	// The path under the public dir, such as `/Upload/Model/20200101000000`
+	Path string
+	// Size in bytes, it is the total size of files when the path is a dir.
+	Size int64
+	// Last modified time
+	ModTime time.Time
+}
+
+// Result is the orphaned files and dirs, and their total size.
+type Result struct {
+	// Orphaned files and dirs
+	Items []Model
+	// Total size in bytes
+	Size int64
+}
+
+// FindOrphans returns the files and dirs in the upload dirs that no doc references,
+// and the files and dirs in the temp dir that are older than `temp_expires`.
+func FindOrphans(db server.Storage) (*Result, error) {
+	result := &Result{
+		Items: []Model{},
+	}
+
+	for _, upload := range uploadDirs {
+		docs := []bson.M{}
+		if err := db.FindAll(upload.CollectionName, &docs); err != nil {
+			return nil, err
+		}
+		used := []string{}
+		for _, doc := range docs {
+			used = append(used, savePathOf(doc))
+		}
+
+		items, err := listDir(upload.Dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			// files that are being uploaded
+			if upload.Dir == server.BlobDir && strings.HasPrefix(path.Base(item.Path), server.BlobTempPrefix) {
+				continue
+			}
+			if !isUsed(item.Path, used) {
+				result.add(item)
+			}
+		}
+	}
+
+	// nothing references temp exports, and the ones that have not expired may be
+	// downloading, the janitor removes them when they expire.
+	expires := time.Duration(server.Config.Export.TempExpires) * time.Minute
+	items, err := listDir("/temp")
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for _, item := range items {
+		if now.Sub(item.ModTime) >= expires {
+			result.add(item)
+		}
+	}
+
+	return result, nil
+}
+
+// Delete removes the given paths that are still orphaned, and returns the removed ones.
+// Paths that are not in the orphans are skipped, so that it only deletes what a dry run
+// has reported.
+func Delete(db server.Storage, paths []string) (*Result, error) {
+	orphans, err := FindOrphans(db)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Items: []Model{},
+	}
+	for _, p := range paths {
+		for _, item := range orphans.Items {
+			if item.Path != p {
+				continue
+			}
+			if err := os.RemoveAll(server.MapPath(item.Path)); err != nil {
+				return result, err
+			}
+			result.add(item)
+			break
+		}
+	}
+	return result, nil
+}
+
+func (r *Result) add(item Model) {
+	r.Items = append(r.Items, item)
+	r.Size += item.Size
+}
+
+// savePathOf returns the dir that an asset is saved in. Typefaces have no `SavePath`,
+// and their `Url` is the font file.
+func savePathOf(doc bson.M) string {
+	if savePath, ok := doc["SavePath"].(string); ok && savePath != "" {
+		return savePath
+	}
+	if url, ok := doc["Url"].(string); ok && url != "" {
+		return path.Dir(url)
+	}
+	return ""
+}
+
+// isUsed returns whether a dir is one of the used dirs, or contains one of them.
+func isUsed(dir string, used []string) bool {
+	for _, p := range used {
+		if p == dir || strings.HasPrefix(p, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// listDir returns the files and dirs in a dir under the public dir.
+func listDir(dir string) ([]Model, error) {
+	infos, err := ioutil.ReadDir(server.MapPath(dir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	list := []Model{}
+	for _, info := range infos {
+		item := Model{
+			Path:    dir + "/" + info.Name(),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		}
+		if info.IsDir() {
+			item.Size = dirSize(server.MapPath(item.Path))
+		}
+		list = append(list, item)
+	}
+	return list, nil
+}
+
+// dirSize returns the total size of files in a dir.
+func dirSize(dir string) int64 {
+	var size int64
+	filepath.Walk(dir, func(_ string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
@@ -0,0 +1,94 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package cleanuploads
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/tengge1/shadoweditor/server"
+)
+
+func TestFindOrphans(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	server.CreateEmbedded(dir)
+
+	db, err := server.DB()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	files := map[string]string{
+		"/Upload/Model/20200101000000/model.obj": "used",
+		"/Upload/Model/20200102000000/model.obj": "orphan",
+		"/Upload/Font/20200101000000/font.ttf":   "used",
+		"/temp/20200101000000.zip":               "temp",
+		"/temp/20200102000000.zip":               "exporting",
+		server.BlobDir + "/upload-1":             "uploading",
+	}
+	for name, content := range files {
+		path := server.MapPath(name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		ioutil.WriteFile(path, []byte(content), 0755)
+	}
+	// exports that have not expired are kept.
+	server.Config.Export.TempExpires = 60
+	old := time.Now().Add(-61 * time.Minute)
+	os.Chtimes(server.MapPath("/temp/20200101000000.zip"), old, old)
+
+	db.InsertOne(server.MeshCollectionName, bson.M{
+		"ID":       primitive.NewObjectID(),
+		"SavePath": "/Upload/Model/20200101000000",
+	})
+	db.InsertOne(server.TypefaceCollectionName, bson.M{
+		"ID":  primitive.NewObjectID(),
+		"Url": "/Upload/Font/20200101000000/font.ttf",
+	})
+
+	result, err := FindOrphans(db)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(result.Items) != 2 || result.Size != 10 {
+		t.Errorf("expect 2 orphans of 10 bytes, got %+v", result)
+		return
+	}
+	if result.Items[0].Path != "/Upload/Model/20200102000000" || result.Items[1].Path != "/temp/20200101000000.zip" {
+		t.Errorf("unexpected orphans: %+v", result.Items)
+	}
+
+	// used paths are never deleted, even if they are given.
+	deleted, err := Delete(db, []string{"/Upload/Model/20200101000000", "/Upload/Model/20200102000000"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(deleted.Items) != 1 || deleted.Items[0].Path != "/Upload/Model/20200102000000" {
+		t.Errorf("unexpected deleted: %+v", deleted.Items)
+	}
+	if _, err := os.Stat(server.MapPath("/Upload/Model/20200101000000")); err != nil {
+		t.Errorf("used model should be kept")
+	}
+	if _, err := os.Stat(server.MapPath("/Upload/Model/20200102000000")); !os.IsNotExist(err) {
+		t.Errorf("orphaned model should be deleted")
+	}
+}
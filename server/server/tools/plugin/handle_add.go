@@ -37,7 +37,7 @@ func Add(w http.ResponseWriter, r *http.Request) {
 	source := r.FormValue("Source")
 	description := r.FormValue("Description")
 
-	db, err := server.Mongo()
+	db, err := server.DB()
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
@@ -37,7 +37,7 @@ func List(w http.ResponseWriter, r *http.Request) {
 	}
 	keyword := strings.TrimSpace(r.FormValue("keyword"))
 
-	db, err := server.Mongo()
+	db, err := server.DB()
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
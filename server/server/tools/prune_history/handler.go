@@ -0,0 +1,37 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package prunehistory
+
+import (
+	"net/http"
+
+	"github.com/tengge1/shadoweditor/helper"
+	"github.com/tengge1/shadoweditor/server"
+)
+
+func init() {
+	server.Handle(http.MethodPost, "/api/PruneHistory/Run", Handle, server.Administrator)
+}
+
+// Handle prune scene history versions to the retention policy in config.toml.
+func Handle(w http.ResponseWriter, r *http.Request) {
+	result, err := Prune(server.Config.History)
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	helper.WriteJSON(w, server.Result{
+		Code: 200,
+		Msg:  "Execute sucessfully!",
+		Data: result,
+	})
+}
@@ -0,0 +1,230 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package prunehistory
+
+import (
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/tengge1/shadoweditor/helper"
+	"github.com/tengge1/shadoweditor/server"
+)
+
+// Model is the result of pruning scene history.
+type Model struct {
+	// How many scenes are pruned
+	Scenes int
+	// How many history versions are deleted
+	Versions int
+	// How many documents are deleted
+	Documents int64
+	// How many bytes are reclaimed, it is the bson size of deleted documents.
+	Bytes int64
+}
+
+// version is a history version of a scene.
+type version struct {
+	// Version
+	Version int
+	// The time when the version was saved
+	Time time.Time
+	// Documents count
+	Documents int64
+	// Bson size of documents
+	Bytes int64
+}
+
+// Prune deletes history versions of all the scenes that the policy does not keep.
+// Tagged versions are always kept.
+func Prune(policy helper.HistoryConfigModel) (*Model, error) {
+	result := &Model{}
+	if policy.KeepVersions <= 0 && policy.DailyAfterDays <= 0 {
+		return result, nil
+	}
+
+	db, err := server.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	scenes := []bson.M{}
+	if err := db.FindAll(server.SceneCollectionName, &scenes); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, scene := range scenes {
+		collectionName, ok := scene["CollectionName"].(string)
+		if !ok {
+			continue
+		}
+		historyName := collectionName + server.HistorySuffix
+		existed, err := db.CollectionExists(historyName)
+		if err != nil {
+			return nil, err
+		}
+		if !existed {
+			continue
+		}
+
+		docs := []bson.M{}
+		if err := db.FindAll(historyName, &docs); err != nil {
+			return nil, err
+		}
+
+		// tagged versions are never pruned
+		tagged, err := getTaggedVersions(db, scene["ID"])
+		if err != nil {
+			return nil, err
+		}
+		versions := []version{}
+		for _, v := range getVersions(docs) {
+			if !tagged[v.Version] {
+				versions = append(versions, v)
+			}
+		}
+		deleted := pruneVersions(versions, policy, now)
+		if len(deleted) == 0 {
+			continue
+		}
+
+		numbers := bson.A{}
+		for _, v := range deleted {
+			numbers = append(numbers, v.Version)
+			result.Bytes += v.Bytes
+		}
+		filter := bson.M{
+			server.VersionField: bson.M{
+				"$in": numbers,
+			},
+		}
+		deleteResult, err := db.DeleteMany(historyName, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Scenes++
+		result.Versions += len(deleted)
+		result.Documents += deleteResult.DeletedCount
+	}
+
+	return result, nil
+}
+
+// getTaggedVersions returns the versions of a scene that have tags.
+func getTaggedVersions(db server.Storage, sceneID interface{}) (map[int]bool, error) {
+	tags := []bson.M{}
+	filter := bson.M{
+		"SceneID": sceneID,
+	}
+	if err := db.FindMany(server.SceneTagCollectionName, filter, &tags); err != nil {
+		return nil, err
+	}
+
+	tagged := map[int]bool{}
+	for _, tag := range tags {
+		if number, ok := toInt(tag["Version"]); ok {
+			tagged[number] = true
+		}
+	}
+	return tagged, nil
+}
+
+// getVersions groups history documents by version.
+func getVersions(docs []bson.M) []version {
+	versions := map[int]*version{}
+	for _, doc := range docs {
+		number, ok := toInt(doc[server.VersionField])
+		if !ok {
+			continue
+		}
+		v, ok := versions[number]
+		if !ok {
+			v = &version{Version: number}
+			versions[number] = v
+		}
+		v.Documents++
+		if data, err := bson.Marshal(doc); err == nil {
+			v.Bytes += int64(len(data))
+		}
+		if t, ok := toTime(doc[server.VersionTimeField]); ok {
+			v.Time = t
+		} else if id, ok := doc["_id"].(primitive.ObjectID); ok && v.Time.IsZero() {
+			// history saved before the version time was recorded, and it is the time
+			// when the version was moved to history.
+			v.Time = id.Timestamp()
+		}
+	}
+
+	list := []version{}
+	for _, v := range versions {
+		list = append(list, *v)
+	}
+	return list
+}
+
+// pruneVersions returns the versions that the policy does not keep. A version is kept
+// when any of the rules that are set keeps it:
+//
+// KeepVersions keeps the latest KeepVersions versions. DailyAfterDays keeps the versions
+// saved in the last DailyAfterDays days, and the latest version of each day before.
+func pruneVersions(versions []version, policy helper.HistoryConfigModel, now time.Time) []version {
+	deleted := []version{}
+	if policy.KeepVersions <= 0 && policy.DailyAfterDays <= 0 {
+		return deleted
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Version > versions[j].Version
+	})
+
+	days := map[string]bool{}
+	for i, v := range versions {
+		day := v.Time.Format("2006-01-02")
+		latestOfDay := !days[day]
+		days[day] = true
+
+		if policy.KeepVersions > 0 && i < policy.KeepVersions {
+			continue
+		}
+		if policy.DailyAfterDays > 0 && (latestOfDay || now.Sub(v.Time) <= time.Duration(policy.DailyAfterDays)*24*time.Hour) {
+			continue
+		}
+		deleted = append(deleted, v)
+	}
+	return deleted
+}
+
+// toInt converts a bson number to int.
+func toInt(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case int32:
+		return int(v), true
+	case int64:
+		return int(v), true
+	case float64:
+		return int(v), true
+	case int:
+		return v, true
+	}
+	return 0, false
+}
+
+// toTime converts a bson date to time.
+func toTime(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case primitive.DateTime:
+		return v.Time(), true
+	case time.Time:
+		return v, true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,136 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package prunehistory
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/tengge1/shadoweditor/helper"
+	"github.com/tengge1/shadoweditor/server"
+)
+
+func TestPruneVersions(t *testing.T) {
+	now := time.Date(2020, 6, 30, 12, 0, 0, 0, time.Local)
+	day := 24 * time.Hour
+
+	// version 9 is the latest, and two versions are saved each day.
+	versions := []version{}
+	for i := 0; i < 10; i++ {
+		versions = append(versions, version{
+			Version: i,
+			Time:    now.Add(-time.Duration(9-i) / 2 * day).Add(-time.Duration(i%2) * time.Hour),
+		})
+	}
+
+	tests := []struct {
+		policy helper.HistoryConfigModel
+		expect []int
+	}{
+		{helper.HistoryConfigModel{}, []int{}},
+		{helper.HistoryConfigModel{KeepVersions: 3}, []int{6, 5, 4, 3, 2, 1, 0}},
+		{helper.HistoryConfigModel{DailyAfterDays: 2}, []int{2, 0}},
+		{helper.HistoryConfigModel{DailyAfterDays: 1}, []int{4, 2, 0}},
+		// versions that either rule keeps are kept
+		{helper.HistoryConfigModel{KeepVersions: 6, DailyAfterDays: 1}, []int{2, 0}},
+	}
+
+	for _, test := range tests {
+		deleted := pruneVersions(versions, test.policy, now)
+		got := []int{}
+		for _, v := range deleted {
+			got = append(got, v.Version)
+		}
+		if len(got) != len(test.expect) {
+			t.Errorf("%+v: expect %v, got %v", test.policy, test.expect, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != test.expect[i] {
+				t.Errorf("%+v: expect %v, got %v", test.policy, test.expect, got)
+				break
+			}
+		}
+	}
+}
+
+func TestGetVersions(t *testing.T) {
+	saved := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	id := primitive.NewObjectID()
+
+	// the version time is used rather than the time when it is moved to history.
+	versions := getVersions([]bson.M{
+		{"_id": id, server.VersionField: int32(1), server.VersionTimeField: primitive.NewDateTimeFromTime(saved)},
+		{"_id": id, server.VersionField: int32(2)},
+	})
+	times := map[int]time.Time{}
+	for _, v := range versions {
+		times[v.Version] = v.Time
+	}
+	if !times[1].Equal(saved) || !times[2].Equal(id.Timestamp()) {
+		t.Errorf("expect %v and %v, got %v", saved, id.Timestamp(), times)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	server.CreateEmbedded(dir)
+
+	db, err := server.DB()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	id := primitive.NewObjectID()
+	db.InsertOne(server.SceneCollectionName, bson.M{
+		"ID":             id,
+		"Name":           "TestPrune",
+		"CollectionName": "Scene20200630120000",
+		"Version":        5,
+	})
+	for i := 0; i < 5; i++ {
+		db.InsertMany("Scene20200630120000"+server.HistorySuffix, []interface{}{
+			bson.M{"uuid": "1", server.VersionField: i},
+			bson.M{"uuid": "2", server.VersionField: i},
+		})
+	}
+
+	// version 0 is tagged, so it is kept.
+	db.InsertOne(server.SceneTagCollectionName, bson.M{
+		"ID":      primitive.NewObjectID(),
+		"SceneID": id,
+		"Name":    "client review 2",
+		"Version": 0,
+	})
+
+	result, err := Prune(helper.HistoryConfigModel{KeepVersions: 2})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if result.Scenes != 1 || result.Versions != 2 || result.Documents != 4 || result.Bytes == 0 {
+		t.Errorf("expect 1 scene, 2 versions, 4 documents, got %+v", result)
+	}
+
+	count, _ := db.Count("Scene20200630120000"+server.HistorySuffix, bson.M{})
+	if count != 6 {
+		t.Errorf("expect 6, got %v", count)
+	}
+}
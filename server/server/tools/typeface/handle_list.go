@@ -24,7 +24,7 @@ func init() {
 
 // List returns the typeface list.
 func List(w http.ResponseWriter, r *http.Request) {
-	db, err := server.Mongo()
+	db, err := server.DB()
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
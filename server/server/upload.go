@@ -0,0 +1,203 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package server
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ChunkDir is the dir that the data of chunked uploads is saved in.
+const ChunkDir = "/Upload/Chunk"
+
+// UploadFile is a file uploaded in a multipart form, or a completed chunked upload.
+type UploadFile struct {
+	// File name from the client
+	Filename string
+	// Size in bytes
+	Size int64
+	// Content type, such as `image/png`
+	ContentType string
+
+	open func() (io.ReadCloser, error)
+	// id of the chunked upload, it is zero for multipart files.
+	id primitive.ObjectID
+}
+
+// Open opens the file for reading.
+func (f *UploadFile) Open() (io.ReadCloser, error) {
+	return f.open()
+}
+
+// uploadLocks are the mutexes of chunked uploads, so that chunks of an upload are
+// written one at a time.
+var uploadLocks sync.Map
+
+// LockUpload locks a chunked upload, and returns the unlock func. The upload should
+// be read again after it is locked, because it may be completed or removed.
+func LockUpload(id primitive.ObjectID) func() {
+	mu, _ := uploadLocks.LoadOrStore(id, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	return mu.(*sync.Mutex).Unlock
+}
+
+// ForgetUploadLock drops the mutex of a chunked upload that accepts no more chunks,
+// such as when it is completed.
+func ForgetUploadLock(id primitive.ObjectID) {
+	uploadLocks.Delete(id)
+}
+
+// UploadPath returns the physical path of the data of a chunked upload.
+func UploadPath(id primitive.ObjectID) string {
+	return MapPath(fmt.Sprintf("%v/%v", ChunkDir, id.Hex()))
+}
+
+// FormFiles returns the uploaded files of a request by their form keys. Files are
+// in the multipart form, or are completed chunked uploads given by `UploadID`, and
+// their keys are set when the uploads are created.
+func FormFiles(r *http.Request) (map[string]*UploadFile, error) {
+	if err := r.ParseMultipartForm(Config.Upload.MaxSize); err != nil && err != http.ErrNotMultipart {
+		return nil, err
+	}
+
+	files := map[string]*UploadFile{}
+	if r.MultipartForm != nil {
+		for key, headers := range r.MultipartForm.File {
+			if len(headers) == 0 {
+				continue
+			}
+			header := headers[0]
+			files[key] = &UploadFile{
+				Filename:    header.Filename,
+				Size:        header.Size,
+				ContentType: header.Header.Get("Content-Type"),
+				open: func() (io.ReadCloser, error) {
+					return header.Open()
+				},
+			}
+		}
+	}
+
+	ids := r.Form["UploadID"]
+	if len(ids) == 0 {
+		return files, nil
+	}
+
+	db, err := DB()
+	if err != nil {
+		return nil, err
+	}
+	for _, hex := range ids {
+		doc, err := FindUpload(db, r, hex)
+		if err != nil {
+			return nil, err
+		}
+		if completed, _ := doc["Completed"].(bool); !completed {
+			return nil, fmt.Errorf("upload %v is not completed", hex)
+		}
+		key, _ := doc["Key"].(string)
+		if _, ok := files[key]; ok {
+			return nil, fmt.Errorf("file %v is uploaded more than once", key)
+		}
+		id := doc["ID"].(primitive.ObjectID)
+		path := UploadPath(id)
+		fileName, _ := doc["FileName"].(string)
+		fileType, _ := doc["FileType"].(string)
+		files[key] = &UploadFile{
+			Filename:    fileName,
+			Size:        int64(toNumber(doc["FileSize"], 0)),
+			ContentType: fileType,
+			open: func() (io.ReadCloser, error) {
+				return os.Open(path)
+			},
+			id: id,
+		}
+	}
+	return files, nil
+}
+
+// FindUpload returns the doc of a chunked upload by its hex id. Users can only find
+// the uploads created by themselves.
+func FindUpload(db Storage, r *http.Request, hex string) (bson.M, error) {
+	id, err := primitive.ObjectIDFromHex(hex)
+	if err != nil {
+		return nil, fmt.Errorf("upload id %v is not allowed", hex)
+	}
+	doc := bson.M{}
+	find, err := db.FindOne(UploadCollectionName, bson.M{"ID": id}, &doc)
+	if err != nil {
+		return nil, err
+	}
+	if !find {
+		return nil, fmt.Errorf("upload %v does not exist or has expired", hex)
+	}
+	if userID, _ := doc["UserID"].(string); userID != "" {
+		user, _ := GetCurrentUser(r)
+		if user == nil || user.ID != userID {
+			return nil, fmt.Errorf("upload %v does not exist or has expired", hex)
+		}
+	}
+	return doc, nil
+}
+
+// RemoveUploads removes the chunked uploads of files, after they are saved.
+func RemoveUploads(files map[string]*UploadFile) {
+	db, err := DB()
+	if err != nil {
+		return
+	}
+	for _, file := range files {
+		if !file.id.IsZero() {
+			RemoveUpload(db, file.id)
+		}
+	}
+}
+
+// RemoveUpload removes a chunked upload and its data.
+func RemoveUpload(db Storage, id primitive.ObjectID) error {
+	ForgetUploadLock(id)
+	if _, err := db.DeleteOne(UploadCollectionName, bson.M{"ID": id}); err != nil {
+		return err
+	}
+	if err := os.Remove(UploadPath(id)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// CleanUploads removes chunked uploads whose data has not been changed for expires,
+// and returns the removed count.
+func CleanUploads(db Storage, expires time.Duration, now time.Time) (int, error) {
+	docs := []bson.M{}
+	if err := db.FindAll(UploadCollectionName, &docs); err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, doc := range docs {
+		id, ok := doc["ID"].(primitive.ObjectID)
+		if !ok {
+			continue
+		}
+		// uploads whose data is lost are removed too.
+		if info, err := os.Stat(UploadPath(id)); err == nil && now.Sub(info.ModTime()) < expires {
+			continue
+		}
+		if err := RemoveUpload(db, id); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package upload
+
+import (
+	"os"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/tengge1/shadoweditor/server"
+)
+
+// ChunkModel is the state of a chunked upload.
+type ChunkModel struct {
+	// ID, it is `UploadID` when the file is added as an asset.
+	ID string
+	// File name
+	FileName string
+	// File size in bytes
+	FileSize int64
+	// Form key that the file is added as, such as `file` or `posX`
+	Key string
+	// Bytes that have been uploaded, and the next chunk starts from it.
+	Offset int64
+	// Whether the checksum has been verified, and the file can be added as an asset.
+	Completed bool
+}
+
+// newChunkModel returns the state of a chunked upload from its doc.
+func newChunkModel(doc bson.M) (ChunkModel, error) {
+	id := doc["ID"].(primitive.ObjectID)
+	model := ChunkModel{
+		ID: id.Hex(),
+	}
+	model.FileName, _ = doc["FileName"].(string)
+	model.FileSize = toInt64(doc["FileSize"])
+	model.Key, _ = doc["Key"].(string)
+	model.Completed, _ = doc["Completed"].(bool)
+
+	info, err := os.Stat(server.UploadPath(id))
+	if err != nil {
+		return model, err
+	}
+	model.Offset = info.Size()
+	return model, nil
+}
+
+// toInt64 converts a number of a doc to int64.
+func toInt64(value interface{}) int64 {
+	switch number := value.(type) {
+	case int32:
+		return int64(number)
+	case int64:
+		return number
+	case float64:
+		return int64(number)
+	}
+	return 0
+}
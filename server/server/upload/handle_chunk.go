@@ -0,0 +1,164 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package upload
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/tengge1/shadoweditor/helper"
+	"github.com/tengge1/shadoweditor/server"
+)
+
+func init() {
+	server.Handle(http.MethodPost, "/api/Upload/Chunk", Chunk, server.Login)
+}
+
+// Chunk writes a chunk of a chunked upload. The request body is the chunk data, and
+// `ID`, `Offset` and the optional `Checksum` (SHA-256 of the chunk in hex) are in the
+// url query. Offset should be the uploaded size, and a chunk whose checksum does not
+// match is discarded.
+func Chunk(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	offset, err := strconv.ParseInt(query.Get("Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "Offset is not allowed.",
+		})
+		return
+	}
+	checksum := strings.ToLower(strings.TrimSpace(query.Get("Checksum")))
+
+	db, err := server.DB()
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	doc, err := server.FindUpload(db, r, query.Get("ID"))
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+	id := doc["ID"].(primitive.ObjectID)
+
+	unlock := server.LockUpload(id)
+	defer unlock()
+
+	// read again, because the upload may be completed while waiting for the lock.
+	if doc, err = server.FindUpload(db, r, query.Get("ID")); err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	model, err := newChunkModel(doc)
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+	if model.Completed {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "The upload is completed.",
+			Data: model,
+		})
+		return
+	}
+	// the client resumes from the returned offset.
+	if offset != model.Offset {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  fmt.Sprintf("Offset should be %v.", model.Offset),
+			Data: model,
+		})
+		return
+	}
+
+	file, err := os.OpenFile(server.UploadPath(id), os.O_WRONLY, 0644)
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	// a chunk is one request, and chunks that are larger than the rest of the file
+	// are refused.
+	var body io.Reader = r.Body
+	if max := server.Config.Upload.MaxSize; max > 0 {
+		body = http.MaxBytesReader(w, r.Body, max)
+	}
+	body = io.LimitReader(body, model.FileSize-offset+1)
+
+	hash := sha256.New()
+	written, err := io.Copy(io.MultiWriter(file, hash), body)
+
+	msg := ""
+	if err != nil {
+		msg = err.Error()
+	} else if offset+written > model.FileSize {
+		msg = "The chunk is out of the file."
+	} else if checksum != "" && checksum != hex.EncodeToString(hash.Sum(nil)) {
+		msg = "Checksum of the chunk does not match."
+	}
+	if msg != "" {
+		file.Truncate(offset)
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  msg,
+			Data: model,
+		})
+		return
+	}
+
+	db.UpdateOne(server.UploadCollectionName, bson.M{"ID": id}, bson.M{
+		"$set": bson.M{
+			"UpdateTime": time.Now(),
+		},
+	})
+
+	model.Offset = offset + written
+	helper.WriteJSON(w, server.Result{
+		Code: 200,
+		Msg:  "Upload successfully!",
+		Data: model,
+	})
+}
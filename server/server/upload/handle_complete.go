@@ -0,0 +1,140 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package upload
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/tengge1/shadoweditor/helper"
+	"github.com/tengge1/shadoweditor/server"
+)
+
+func init() {
+	server.Handle(http.MethodPost, "/api/Upload/Complete", Complete, server.Login)
+}
+
+// Complete verifies the checksum of a chunked upload when all the chunks are
+// uploaded. If the checksum does not match, the data is discarded and the file
+// should be uploaded again.
+func Complete(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+
+	db, err := server.DB()
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	doc, err := server.FindUpload(db, r, r.FormValue("ID"))
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+	id := doc["ID"].(primitive.ObjectID)
+
+	unlock := server.LockUpload(id)
+	defer unlock()
+
+	// read again, because the upload may be completed while waiting for the lock.
+	if doc, err = server.FindUpload(db, r, r.FormValue("ID")); err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	model, err := newChunkModel(doc)
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+	if model.Offset != model.FileSize {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  fmt.Sprintf("%v of %v bytes are uploaded.", model.Offset, model.FileSize),
+			Data: model,
+		})
+		return
+	}
+
+	if !model.Completed {
+		sum, err := checksumOf(server.UploadPath(id))
+		if err != nil {
+			helper.WriteJSON(w, server.Result{
+				Code: 300,
+				Msg:  err.Error(),
+			})
+			return
+		}
+		if expected, _ := doc["Checksum"].(string); sum != expected {
+			os.Truncate(server.UploadPath(id), 0)
+			model.Offset = 0
+			helper.WriteJSON(w, server.Result{
+				Code: 300,
+				Msg:  "Checksum does not match, please upload the file again.",
+				Data: model,
+			})
+			return
+		}
+
+		if _, err := db.UpdateOne(server.UploadCollectionName, bson.M{"ID": id}, bson.M{
+			"$set": bson.M{
+				"Completed":  true,
+				"UpdateTime": time.Now(),
+			},
+		}); err != nil {
+			helper.WriteJSON(w, server.Result{
+				Code: 300,
+				Msg:  err.Error(),
+			})
+			return
+		}
+		model.Completed = true
+		server.ForgetUploadLock(id)
+	}
+
+	helper.WriteJSON(w, server.Result{
+		Code: 200,
+		Msg:  "Upload successfully!",
+		Data: model,
+	})
+}
+
+// checksumOf returns the SHA-256 of a file in hex.
+func checksumOf(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,139 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package upload
+
+import (
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/tengge1/shadoweditor/helper"
+	"github.com/tengge1/shadoweditor/server"
+)
+
+func init() {
+	server.Handle(http.MethodPost, "/api/Upload/Init", Init, server.Login)
+}
+
+// Init creates a chunked upload. Chunks are uploaded to `/api/Upload/Chunk`, and the
+// upload is completed by `/api/Upload/Complete` when all the chunks are uploaded.
+// Then, its ID is sent as `UploadID` to add the file as an asset, such as
+// `/api/Mesh/Add`.
+func Init(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	fileName := filepath.Base(strings.TrimSpace(r.FormValue("FileName")))
+	fileSize, err := strconv.ParseInt(r.FormValue("FileSize"), 10, 64)
+	checksum := strings.ToLower(strings.TrimSpace(r.FormValue("Checksum")))
+	key := strings.TrimSpace(r.FormValue("Key"))
+	fileType := strings.TrimSpace(r.FormValue("FileType"))
+
+	if fileName == "" || fileName == "." || fileName == string(filepath.Separator) {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "FileName is not allowed to be empty.",
+		})
+		return
+	}
+	if err != nil || fileSize < 0 {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "FileSize is not allowed.",
+		})
+		return
+	}
+	if max := server.Config.Upload.MaxChunkedSize; max > 0 && fileSize > max {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  fmt.Sprintf("The file is larger than %v bytes.", max),
+		})
+		return
+	}
+	if bytes, err := hex.DecodeString(checksum); err != nil || len(bytes) != 32 {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  "Checksum should be the SHA-256 of the file in hex.",
+		})
+		return
+	}
+	if key == "" {
+		key = "file"
+	}
+	if fileType == "" {
+		fileType = mime.TypeByExtension(filepath.Ext(fileName))
+	}
+
+	db, err := server.DB()
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	id := primitive.NewObjectID()
+	chunkDir := server.MapPath(server.ChunkDir)
+	if _, err := os.Stat(chunkDir); os.IsNotExist(err) {
+		os.MkdirAll(chunkDir, 0755)
+	}
+	file, err := os.Create(server.UploadPath(id))
+	if err != nil {
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+	file.Close()
+
+	now := time.Now()
+	doc := bson.M{
+		"ID":         id,
+		"FileName":   fileName,
+		"FileSize":   fileSize,
+		"FileType":   fileType,
+		"Checksum":   checksum,
+		"Key":        key,
+		"Completed":  false,
+		"CreateTime": now,
+		"UpdateTime": now,
+	}
+
+	if server.Config.Authority.Enabled {
+		user, _ := server.GetCurrentUser(r)
+
+		if user != nil {
+			doc["UserID"] = user.ID
+		}
+	}
+
+	if _, err := db.InsertOne(server.UploadCollectionName, doc); err != nil {
+		os.Remove(server.UploadPath(id))
+		helper.WriteJSON(w, server.Result{
+			Code: 300,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	model, _ := newChunkModel(doc)
+	helper.WriteJSON(w, server.Result{
+		Code: 200,
+		Msg:  "Create successfully!",
+		Data: model,
+	})
+}
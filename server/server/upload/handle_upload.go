@@ -8,10 +8,7 @@
 package upload
 
 import (
-	"fmt"
-	"io"
 	"net/http"
-	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -58,18 +55,7 @@ func Upload(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// save file
-	now := time.Now()
-
-	savePath := fmt.Sprintf("/Upload/File/%v", helper.TimeToString(now, "yyyyMMddHHmmss"))
-	physicalPath := server.MapPath(savePath)
-
-	if _, err := os.Stat(physicalPath); os.IsNotExist(err) {
-		os.MkdirAll(physicalPath, 0755)
-	}
-
-	targetPath := fmt.Sprintf("%v/%v", physicalPath, fileName)
-	target, err := os.Create(targetPath)
+	db, err := server.DB()
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
@@ -77,7 +63,9 @@ func Upload(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
-	defer target.Close()
+
+	// save file, and files with the same content share storage.
+	now := time.Now()
 
 	source, err := file.Open()
 	if err != nil {
@@ -89,12 +77,7 @@ func Upload(w http.ResponseWriter, r *http.Request) {
 	}
 	defer source.Close()
 
-	io.Copy(target, source)
-
-	// save to mongo
-	pinyin := helper.ConvertToPinYin(fileNameWithoutExt)
-
-	db, err := server.Mongo()
+	blob, err := server.AddBlob(db, source, fileName, nil)
 	if err != nil {
 		helper.WriteJSON(w, server.Result{
 			Code: 300,
@@ -103,7 +86,10 @@ func Upload(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	url := fmt.Sprintf("%v/%v", savePath, fileName)
+	// save to mongo
+	pinyin := helper.ConvertToPinYin(fileNameWithoutExt)
+
+	url := blob.URL()
 
 	doc := bson.M{
 		"AddTime":     now,
@@ -113,7 +99,8 @@ func Upload(w http.ResponseWriter, r *http.Request) {
 		"FirstPinYin": pinyin.FirstPinYin,
 		"Name":        fileNameWithoutExt,
 		"SaveName":    fileName,
-		"SavePath":    savePath,
+		"SavePath":    blob.SavePath,
+		"Blobs":       []string{blob.Hash},
 		"Thumbnail":   "",
 		"TotalPinYin": pinyin.TotalPinYin,
 		"Url":         url,
@@ -6,3 +6,172 @@
 // You can also visit: https://gitee.com/tengge1/ShadowEditor
 
 package upload
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/tengge1/shadoweditor/helper"
+	"github.com/tengge1/shadoweditor/server"
+	"github.com/tengge1/shadoweditor/server/assets/audio"
+	"github.com/tengge1/shadoweditor/server/assets/video"
+)
+
+// testDir is the directory that tests store data in, so that tests need no mongo.
+var testDir string
+
+func TestMain(m *testing.M) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		panic(err)
+	}
+	testDir = dir
+	code := m.Run()
+	os.RemoveAll(testDir)
+	os.Exit(code)
+}
+
+// chunkResult is the result of chunked upload apis.
+type chunkResult struct {
+	server.Result
+	Data ChunkModel
+}
+
+func postForm(t *testing.T, handler http.HandlerFunc, values url.Values) chunkResult {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(values.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+
+	result := chunkResult{}
+	if err := helper.FromJSON(rec.Body.Bytes(), &result); err != nil {
+		t.Error(err)
+	}
+	return result
+}
+
+func postChunk(t *testing.T, id string, offset int, data []byte, checksum string) chunkResult {
+	target := fmt.Sprintf("/?ID=%v&Offset=%v&Checksum=%v", id, offset, checksum)
+	req := httptest.NewRequest(http.MethodPost, target, bytes.NewReader(data))
+	rec := httptest.NewRecorder()
+	Chunk(rec, req)
+
+	result := chunkResult{}
+	if err := helper.FromJSON(rec.Body.Bytes(), &result); err != nil {
+		t.Error(err)
+	}
+	return result
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestChunkedUpload(t *testing.T) {
+	server.CreateEmbedded(testDir)
+
+	data := bytes.Repeat([]byte("TestChunkedUpload"), 200)
+	result := postForm(t, Init, url.Values{
+		"FileName": {"TestChunkedUpload.mp3"},
+		"FileSize": {fmt.Sprint(len(data))},
+		"Checksum": {sha256Hex(data)},
+	})
+	if result.Code != 200 || result.Data.Key != "file" {
+		t.Fatalf("expect 200, got %v", result)
+	}
+	id := result.Data.ID
+
+	// a broken chunk is discarded
+	if result := postChunk(t, id, 0, data[:1000], sha256Hex(data[1:1001])); result.Code != 300 || result.Data.Offset != 0 {
+		t.Errorf("expect 300 at offset 0, got %v", result)
+	}
+	if result := postChunk(t, id, 0, data[:1000], sha256Hex(data[:1000])); result.Code != 200 || result.Data.Offset != 1000 {
+		t.Errorf("expect 200 at offset 1000, got %v", result)
+	}
+	// a chunk at a wrong offset is refused, and the client resumes from the offset
+	if result := postChunk(t, id, 500, data[500:1500], ""); result.Code != 300 || result.Data.Offset != 1000 {
+		t.Errorf("expect 300 at offset 1000, got %v", result)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/?ID="+id, nil)
+	rec := httptest.NewRecorder()
+	Offset(rec, req)
+	offset := chunkResult{}
+	helper.FromJSON(rec.Body.Bytes(), &offset)
+	if offset.Code != 200 || offset.Data.Offset != 1000 || offset.Data.Completed {
+		t.Errorf("expect offset 1000, got %v", rec.Body.String())
+	}
+
+	// it can not be added before it is completed
+	if result := postForm(t, audio.Add, url.Values{"UploadID": {id}}); result.Code != 300 {
+		t.Errorf("expect 300, got %v", result)
+	}
+	if result := postForm(t, Complete, url.Values{"ID": {id}}); result.Code != 300 {
+		t.Errorf("expect 300, got %v", result)
+	}
+
+	if result := postChunk(t, id, 1000, data[1000:], ""); result.Code != 200 || result.Data.Offset != int64(len(data)) {
+		t.Errorf("expect 200 at offset %v, got %v", len(data), result)
+	}
+	if result := postForm(t, Complete, url.Values{"ID": {id}}); result.Code != 200 || !result.Data.Completed {
+		t.Errorf("expect 200, got %v", result)
+	}
+
+	// the upload is kept when it fails to be added
+	if result := postForm(t, video.Add, url.Values{"UploadID": {id}}); result.Code != 300 {
+		t.Errorf("expect 300, got %v", result)
+	}
+	if result := postForm(t, audio.Add, url.Values{"UploadID": {id}}); result.Code != 200 {
+		t.Errorf("expect 200, got %v", result)
+	}
+	db, _ := server.DB()
+	doc := bson.M{}
+	db.FindOne(server.AudioCollectionName, bson.M{"Name": "TestChunkedUpload"}, &doc)
+	url, _ := doc["Url"].(string)
+	if saved, err := ioutil.ReadFile(server.MapPath(url)); err != nil || !bytes.Equal(saved, data) {
+		t.Errorf("expect the uploaded data in %v, got %v", url, err)
+	}
+
+	// the upload is removed after it is added
+	if find, _ := db.FindOne(server.UploadCollectionName, bson.M{}, &bson.M{}); find {
+		t.Errorf("expect the upload to be removed")
+	}
+}
+
+func TestChunkedUploadChecksum(t *testing.T) {
+	server.CreateEmbedded(testDir)
+
+	data := []byte("TestChunkedUploadChecksum")
+	result := postForm(t, Init, url.Values{
+		"FileName": {"TestChunkedUploadChecksum.mp3"},
+		"FileSize": {fmt.Sprint(len(data))},
+		"Checksum": {sha256Hex([]byte("other"))},
+	})
+	if result.Code != 200 {
+		t.Fatalf("expect 200, got %v", result)
+	}
+	id := result.Data.ID
+
+	// chunks out of the file are refused
+	if result := postChunk(t, id, 0, append(data, '!'), ""); result.Code != 300 || result.Data.Offset != 0 {
+		t.Errorf("expect 300 at offset 0, got %v", result)
+	}
+	if result := postChunk(t, id, 0, data, ""); result.Code != 200 {
+		t.Errorf("expect 200, got %v", result)
+	}
+	if result := postForm(t, Complete, url.Values{"ID": {id}}); result.Code != 300 || result.Data.Offset != 0 {
+		t.Errorf("expect 300 and the data is discarded, got %v", result)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package server
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCleanUploads(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := CreateEmbedded(dir); err != nil {
+		t.Fatal(err)
+	}
+	db, err := DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.MkdirAll(MapPath(ChunkDir), 0755)
+
+	now := time.Now()
+	oldID, newID, lostID := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	for _, id := range []primitive.ObjectID{oldID, newID, lostID} {
+		db.InsertOne(UploadCollectionName, bson.M{"ID": id})
+	}
+	ioutil.WriteFile(UploadPath(oldID), []byte("old"), 0644)
+	os.Chtimes(UploadPath(oldID), now.Add(-2*time.Hour), now.Add(-2*time.Hour))
+	ioutil.WriteFile(UploadPath(newID), []byte("new"), 0644)
+
+	count, err := CleanUploads(db, time.Hour, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("expect 2 removed, got %v", count)
+	}
+	if _, err := os.Stat(UploadPath(oldID)); !os.IsNotExist(err) {
+		t.Errorf("expired upload should be removed")
+	}
+	if find, _ := db.FindOne(UploadCollectionName, bson.M{"ID": newID}, &bson.M{}); !find {
+		t.Errorf("new upload should be kept")
+	}
+	if find, _ := db.FindOne(UploadCollectionName, bson.M{"ID": lostID}, &bson.M{}); find {
+		t.Errorf("upload without data should be removed")
+	}
+}
@@ -19,8 +19,22 @@ import (
 	"github.com/tengge1/shadoweditor/server/system"
 )
 
+// userContextKey is the context key of the user that a request is made as.
+type userContextKey struct{}
+
+// WithUser returns a copy of the request that is made as the user, so that handlers
+// can be called in process without a token.
+func WithUser(r *http.Request, user *system.User) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), userContextKey{}, user))
+}
+
 // GetCurrentUser get the current login user. It returns nil if there is something wrong.
 func GetCurrentUser(r *http.Request) (*system.User, error) {
+	// request made in process as a user
+	if user, ok := r.Context().Value(userContextKey{}).(*system.User); ok {
+		return user, nil
+	}
+
 	var cookie *http.Cookie = nil
 	for _, item := range r.Cookies() {
 		if item.Name == "token" {
@@ -61,18 +75,18 @@ func GetUser(userID string) (*system.User, error) {
 		return nil, err
 	}
 
-	// create mongo client
-	mongo, err := Mongo()
+	// get storage
+	db, err := DB()
 	if err != nil {
 		return nil, err
 	}
 
-	// get user from mongo
+	// get user from storage
 	filter := bson.M{
 		"ID": userObjectID,
 	}
 	user := system.User{}
-	find, err := mongo.FindOne(UserCollectionName, filter, &user)
+	find, err := db.FindOne(UserCollectionName, filter, &user)
 	if err != nil {
 		return nil, err
 	}
@@ -96,7 +110,7 @@ func GetUser(userID string) (*system.User, error) {
 	}
 
 	role := system.Role{}
-	find, err = mongo.FindOne(RoleCollectionName, filter, &role)
+	find, err = db.FindOne(RoleCollectionName, filter, &role)
 	if err != nil {
 		return nil, err
 	}
@@ -122,15 +136,12 @@ func GetUser(userID string) (*system.User, error) {
 			"RoleID": role.ID,
 		}
 
-		cursor, err := mongo.Find(OperatingAuthorityCollectionName, filter)
-		if err != nil {
+		authorities := []system.RoleAuthority{}
+		if err := db.FindMany(OperatingAuthorityCollectionName, filter, &authorities); err != nil {
 			return nil, err
 		}
 
-		for cursor.Next(context.TODO()) {
-			authority := system.RoleAuthority{}
-			cursor.Decode(&authority)
-
+		for _, authority := range authorities {
 			user.OperatingAuthorities = append(user.OperatingAuthorities, authority.AuthorityID)
 		}
 	}
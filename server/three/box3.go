@@ -58,14 +58,14 @@ type Box3 struct {
 }
 
 // Set :
-func (b Box3) Set(min, max Vector3) *Box3 {
+func (b *Box3) Set(min, max Vector3) *Box3 {
 	b.Min.Copy(min)
 	b.Max.Copy(max)
-	return &b
+	return b
 }
 
 // SetFromArray :
-func (b Box3) SetFromArray(array []float64) *Box3 {
+func (b *Box3) SetFromArray(array []float64) *Box3 {
 	minX := math.Inf(1)
 	minY := math.Inf(1)
 	minZ := math.Inf(1)
@@ -103,44 +103,44 @@ func (b Box3) SetFromArray(array []float64) *Box3 {
 	b.Min.Set(minX, minY, minZ)
 	b.Max.Set(maxX, maxY, maxZ)
 
-	return &b
+	return b
 }
 
 // SetFromPoints :
-func (b Box3) SetFromPoints(points []Vector3) *Box3 {
+func (b *Box3) SetFromPoints(points []Vector3) *Box3 {
 	b.MakeEmpty()
 
 	for i, il := 0, len(points); i < il; i++ {
 		b.ExpandByPoint(points[i])
 	}
 
-	return &b
+	return b
 }
 
 // SetFromCenterAndSize :
-func (b Box3) SetFromCenterAndSize(center, size Vector3) *Box3 {
+func (b *Box3) SetFromCenterAndSize(center, size Vector3) *Box3 {
 	halfSize := _vectorB3.Copy(size).MultiplyScalar(0.5)
 
 	b.Min.Copy(center).Sub(*halfSize)
 	b.Max.Copy(center).Add(*halfSize)
 
-	return &b
+	return b
 }
 
 // Clone :
-func (b Box3) Clone() *Box3 {
-	return NewBox3(Vector3{}, Vector3{}).Copy(b)
+func (b *Box3) Clone() *Box3 {
+	return NewBox3(Vector3{}, Vector3{}).Copy(*b)
 }
 
 // Copy :
-func (b Box3) Copy(box Box3) *Box3 {
+func (b *Box3) Copy(box Box3) *Box3 {
 	b.Min.Copy(box.Min)
 	b.Max.Copy(box.Max)
-	return &b
+	return b
 }
 
 // MakeEmpty :
-func (b Box3) MakeEmpty() *Box3 {
+func (b *Box3) MakeEmpty() *Box3 {
 	b.Min.X = math.Inf(1)
 	b.Min.Y = math.Inf(1)
 	b.Min.Z = math.Inf(1)
@@ -149,17 +149,17 @@ func (b Box3) MakeEmpty() *Box3 {
 	b.Max.Y = math.Inf(-1)
 	b.Max.Z = math.Inf(-1)
 
-	return &b
+	return b
 }
 
 // IsEmpty :
-func (b Box3) IsEmpty() bool {
+func (b *Box3) IsEmpty() bool {
 	// b is a more robust check for empty than ( volume <= 0 ) because volume can get positive with two negative axes
 	return (b.Max.X < b.Min.X) || (b.Max.Y < b.Min.Y) || (b.Max.Z < b.Min.Z)
 }
 
 // GetCenter :
-func (b Box3) GetCenter(target Vector3) *Vector3 {
+func (b *Box3) GetCenter(target Vector3) *Vector3 {
 	if b.IsEmpty() {
 		return target.Set(0, 0, 0)
 	}
@@ -167,7 +167,7 @@ func (b Box3) GetCenter(target Vector3) *Vector3 {
 }
 
 // GetSize :
-func (b Box3) GetSize(target Vector3) *Vector3 {
+func (b *Box3) GetSize(target Vector3) *Vector3 {
 	if b.IsEmpty() {
 		return target.Set(0, 0, 0)
 	}
@@ -175,42 +175,42 @@ func (b Box3) GetSize(target Vector3) *Vector3 {
 }
 
 // ExpandByPoint :
-func (b Box3) ExpandByPoint(point Vector3) *Box3 {
+func (b *Box3) ExpandByPoint(point Vector3) *Box3 {
 	b.Min.Min(point)
 	b.Max.Max(point)
-	return &b
+	return b
 }
 
 // ExpandByVector :
-func (b Box3) ExpandByVector(vector Vector3) *Box3 {
+func (b *Box3) ExpandByVector(vector Vector3) *Box3 {
 	b.Min.Sub(vector)
 	b.Max.Add(vector)
-	return &b
+	return b
 }
 
 // ExpandByScalar :
-func (b Box3) ExpandByScalar(scalar float64) *Box3 {
+func (b *Box3) ExpandByScalar(scalar float64) *Box3 {
 	b.Min.AddScalar(-scalar)
 	b.Max.AddScalar(scalar)
-	return &b
+	return b
 }
 
 // ContainsPoint :
-func (b Box3) ContainsPoint(point Vector3) bool {
+func (b *Box3) ContainsPoint(point Vector3) bool {
 	return !(point.X < b.Min.X || point.X > b.Max.X ||
 		point.Y < b.Min.Y || point.Y > b.Max.Y ||
 		point.Z < b.Min.Z || point.Z > b.Max.Z)
 }
 
 // ContainsBox :
-func (b Box3) ContainsBox(box Box3) bool {
+func (b *Box3) ContainsBox(box Box3) bool {
 	return b.Min.X <= box.Min.X && box.Max.X <= b.Max.X &&
 		b.Min.Y <= box.Min.Y && box.Max.Y <= b.Max.Y &&
 		b.Min.Z <= box.Min.Z && box.Max.Z <= b.Max.Z
 }
 
 // GetParameter :
-func (b Box3) GetParameter(point, target Vector3) *Vector3 {
+func (b *Box3) GetParameter(point, target Vector3) *Vector3 {
 	// b can potentially have a divide by zero if the box
 	// has a size dimension of 0.
 	return target.Set(
@@ -221,7 +221,7 @@ func (b Box3) GetParameter(point, target Vector3) *Vector3 {
 }
 
 // IntersectsBox :
-func (b Box3) IntersectsBox(box Box3) bool {
+func (b *Box3) IntersectsBox(box Box3) bool {
 	// using 6 splitting planes to rule out intersections.
 	return !(box.Max.X < b.Min.X || box.Min.X > b.Max.X ||
 		box.Max.Y < b.Min.Y || box.Min.Y > b.Max.Y ||
@@ -229,7 +229,7 @@ func (b Box3) IntersectsBox(box Box3) bool {
 }
 
 // IntersectsSphere :
-func (b Box3) IntersectsSphere(sphere Sphere) bool {
+func (b *Box3) IntersectsSphere(sphere Sphere) bool {
 	// Find the point on the AABB closest to the sphere center.
 	b.ClampPoint(sphere.Center, _vectorB3)
 
@@ -238,7 +238,7 @@ func (b Box3) IntersectsSphere(sphere Sphere) bool {
 }
 
 // IntersectsPlane :
-func (b Box3) IntersectsPlane(plane Plane) bool {
+func (b *Box3) IntersectsPlane(plane Plane) bool {
 	// We compute the minimum and maximum dot product values. If those values
 	// are on the same side (back or front) of the plane, then there is no intersection.
 	var min, max float64
@@ -271,7 +271,7 @@ func (b Box3) IntersectsPlane(plane Plane) bool {
 }
 
 // IntersectsTriangle :
-func (b Box3) IntersectsTriangle(triangle Triangle) bool {
+func (b *Box3) IntersectsTriangle(triangle Triangle) bool {
 	if b.IsEmpty() {
 		return false
 	}
@@ -317,25 +317,25 @@ func (b Box3) IntersectsTriangle(triangle Triangle) bool {
 }
 
 // ClampPoint :
-func (b Box3) ClampPoint(point Vector3, target Vector3) *Vector3 {
+func (b *Box3) ClampPoint(point Vector3, target Vector3) *Vector3 {
 	return target.Copy(point).Clamp(b.Min, b.Max)
 }
 
 // DistanceToPoint :
-func (b Box3) DistanceToPoint(point Vector3) float64 {
+func (b *Box3) DistanceToPoint(point Vector3) float64 {
 	clampedPoint := _vectorB3.Copy(point).Clamp(b.Min, b.Max)
 	return clampedPoint.Sub(point).Length()
 }
 
 // GetBoundingSphere :
-func (b Box3) GetBoundingSphere(target Sphere) *Sphere {
+func (b *Box3) GetBoundingSphere(target Sphere) *Sphere {
 	b.GetCenter(target.Center)
 	target.Radius = b.GetSize(_vectorB3).Length() * 0.5
 	return &target
 }
 
 // Intersect :
-func (b Box3) Intersect(box Box3) *Box3 {
+func (b *Box3) Intersect(box Box3) *Box3 {
 	b.Min.Max(box.Min)
 	b.Max.Min(box.Max)
 
@@ -344,21 +344,21 @@ func (b Box3) Intersect(box Box3) *Box3 {
 		b.MakeEmpty()
 	}
 
-	return &b
+	return b
 }
 
 // Union :
-func (b Box3) Union(box Box3) *Box3 {
+func (b *Box3) Union(box Box3) *Box3 {
 	b.Min.Min(box.Min)
 	b.Max.Max(box.Max)
-	return &b
+	return b
 }
 
 // ApplyMatrix4 :
-func (b Box3) ApplyMatrix4(matrix Matrix4) *Box3 {
+func (b *Box3) ApplyMatrix4(matrix Matrix4) *Box3 {
 	// transform of empty box is an empty box.
 	if b.IsEmpty() {
-		return &b
+		return b
 	}
 
 	// NOTE: I am using a binary pattern to specify all 2^3 combinations below
@@ -373,19 +373,19 @@ func (b Box3) ApplyMatrix4(matrix Matrix4) *Box3 {
 
 	b.SetFromPoints(_points)
 
-	return &b
+	return b
 }
 
 // Translate :
-func (b Box3) Translate(offset Vector3) *Box3 {
+func (b *Box3) Translate(offset Vector3) *Box3 {
 	b.Min.Add(offset)
 	b.Max.Add(offset)
 
-	return &b
+	return b
 }
 
 // Equals :
-func (b Box3) Equals(box Box3) bool {
+func (b *Box3) Equals(box Box3) bool {
 	return box.Min.Equals(b.Min) && box.Max.Equals(b.Max)
 }
 
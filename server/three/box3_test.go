@@ -0,0 +1,32 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package three
+
+import (
+	"testing"
+)
+
+func TestBox3SetFromPoints(t *testing.T) {
+	box := NewBox3(*NewVector3(-1, -1, -1), *NewVector3(1, 1, 1))
+
+	// the old corner of the box is one of the points
+	points := []Vector3{box.Max, *NewVector3(3, -2, 0)}
+	if result := box.SetFromPoints(points); result != box {
+		t.Errorf("expect the receiver to be returned")
+	}
+	if !box.Min.Equals(*NewVector3(1, -2, 0)) || !box.Max.Equals(*NewVector3(3, 1, 1)) {
+		t.Errorf("expect (1, -2, 0) - (3, 1, 1), got %v - %v", box.Min, box.Max)
+	}
+	if !points[0].Equals(*NewVector3(1, 1, 1)) {
+		t.Errorf("expect the points not to change, got %v", points)
+	}
+
+	if box.SetFromPoints(nil); !box.IsEmpty() {
+		t.Errorf("expect an empty box, got %v - %v", box.Min, box.Max)
+	}
+}
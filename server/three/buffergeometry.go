@@ -0,0 +1,437 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+//
+// This package is translated from three.js, visit `https://github.com/mrdoob/three.js`
+// for more information.
+
+package three
+
+import "math"
+
+// BufferGeometry : the indexed triangles of a geometry.
+type BufferGeometry struct {
+	Position []float64
+	Normal   []float64
+	UV       []float64
+	Index    []int
+	Groups   []GeometryGroup
+}
+
+// GeometryGroup : a range of indices rendered with one material.
+type GeometryGroup struct {
+	Start         int
+	Count         int
+	MaterialIndex int
+}
+
+// AddGroup :
+func (g *BufferGeometry) AddGroup(start, count, materialIndex int) {
+	g.Groups = append(g.Groups, GeometryGroup{start, count, materialIndex})
+}
+
+// NewBoxBufferGeometry :
+func NewBoxBufferGeometry(width, height, depth float64, widthSegments, heightSegments, depthSegments int) *BufferGeometry {
+	g := &BufferGeometry{}
+	width = orDefault(width, 1)
+	height = orDefault(height, 1)
+	depth = orDefault(depth, 1)
+	widthSegments = orDefaultInt(widthSegments, 1)
+	heightSegments = orDefaultInt(heightSegments, 1)
+	depthSegments = orDefaultInt(depthSegments, 1)
+
+	numberOfVertices, groupStart := 0, 0
+
+	buildPlane := func(u, v, w int, udir, vdir, width, height, depth float64, gridX, gridY, materialIndex int) {
+		segmentWidth := width / float64(gridX)
+		segmentHeight := height / float64(gridY)
+		widthHalf, heightHalf, depthHalf := width/2, height/2, depth/2
+		gridX1, gridY1 := gridX+1, gridY+1
+		vertexCounter, groupCount := 0, 0
+		vector := [3]float64{}
+
+		for iy := 0; iy < gridY1; iy++ {
+			y := float64(iy)*segmentHeight - heightHalf
+			for ix := 0; ix < gridX1; ix++ {
+				x := float64(ix)*segmentWidth - widthHalf
+
+				vector[u], vector[v], vector[w] = x*udir, y*vdir, depthHalf
+				g.Position = append(g.Position, vector[0], vector[1], vector[2])
+
+				vector[u], vector[v], vector[w] = 0, 0, 1
+				if depth <= 0 {
+					vector[w] = -1
+				}
+				g.Normal = append(g.Normal, vector[0], vector[1], vector[2])
+
+				g.UV = append(g.UV, float64(ix)/float64(gridX), 1-float64(iy)/float64(gridY))
+				vertexCounter++
+			}
+		}
+
+		for iy := 0; iy < gridY; iy++ {
+			for ix := 0; ix < gridX; ix++ {
+				a := numberOfVertices + ix + gridX1*iy
+				b := numberOfVertices + ix + gridX1*(iy+1)
+				c := numberOfVertices + (ix + 1) + gridX1*(iy+1)
+				d := numberOfVertices + (ix + 1) + gridX1*iy
+				g.Index = append(g.Index, a, b, d, b, c, d)
+				groupCount += 6
+			}
+		}
+
+		g.AddGroup(groupStart, groupCount, materialIndex)
+		groupStart += groupCount
+		numberOfVertices += vertexCounter
+	}
+
+	buildPlane(2, 1, 0, -1, -1, depth, height, width, depthSegments, heightSegments, 0)  // px
+	buildPlane(2, 1, 0, 1, -1, depth, height, -width, depthSegments, heightSegments, 1)  // nx
+	buildPlane(0, 2, 1, 1, 1, width, depth, height, widthSegments, depthSegments, 2)     // py
+	buildPlane(0, 2, 1, 1, -1, width, depth, -height, widthSegments, depthSegments, 3)   // ny
+	buildPlane(0, 1, 2, 1, -1, width, height, depth, widthSegments, heightSegments, 4)   // pz
+	buildPlane(0, 1, 2, -1, -1, width, height, -depth, widthSegments, heightSegments, 5) // nz
+
+	return g
+}
+
+// NewPlaneBufferGeometry :
+func NewPlaneBufferGeometry(width, height float64, widthSegments, heightSegments int) *BufferGeometry {
+	g := &BufferGeometry{}
+	width = orDefault(width, 1)
+	height = orDefault(height, 1)
+
+	widthHalf, heightHalf := width/2, height/2
+	gridX := orDefaultInt(widthSegments, 1)
+	gridY := orDefaultInt(heightSegments, 1)
+	gridX1, gridY1 := gridX+1, gridY+1
+	segmentWidth := width / float64(gridX)
+	segmentHeight := height / float64(gridY)
+
+	for iy := 0; iy < gridY1; iy++ {
+		y := float64(iy)*segmentHeight - heightHalf
+		for ix := 0; ix < gridX1; ix++ {
+			x := float64(ix)*segmentWidth - widthHalf
+			g.Position = append(g.Position, x, -y, 0)
+			g.Normal = append(g.Normal, 0, 0, 1)
+			g.UV = append(g.UV, float64(ix)/float64(gridX), 1-float64(iy)/float64(gridY))
+		}
+	}
+
+	for iy := 0; iy < gridY; iy++ {
+		for ix := 0; ix < gridX; ix++ {
+			a := ix + gridX1*iy
+			b := ix + gridX1*(iy+1)
+			c := (ix + 1) + gridX1*(iy+1)
+			d := (ix + 1) + gridX1*iy
+			g.Index = append(g.Index, a, b, d, b, c, d)
+		}
+	}
+
+	return g
+}
+
+// NewSphereBufferGeometry :
+func NewSphereBufferGeometry(radius float64, widthSegments, heightSegments int, phiStart, phiLength, thetaStart, thetaLength float64) *BufferGeometry {
+	g := &BufferGeometry{}
+	radius = orDefault(radius, 1)
+	widthSegments = int(math.Max(3, float64(orDefaultInt(widthSegments, 8))))
+	heightSegments = int(math.Max(2, float64(orDefaultInt(heightSegments, 6))))
+	thetaEnd := thetaStart + thetaLength
+
+	index := 0
+	grid := [][]int{}
+
+	for iy := 0; iy <= heightSegments; iy++ {
+		verticesRow := []int{}
+		v := float64(iy) / float64(heightSegments)
+
+		// special case for the poles
+		uOffset := 0.0
+		if iy == 0 {
+			uOffset = 0.5 / float64(widthSegments)
+		} else if iy == heightSegments {
+			uOffset = -0.5 / float64(widthSegments)
+		}
+
+		for ix := 0; ix <= widthSegments; ix++ {
+			u := float64(ix) / float64(widthSegments)
+
+			vertex := NewVector3(
+				-radius*math.Cos(phiStart+u*phiLength)*math.Sin(thetaStart+v*thetaLength),
+				radius*math.Cos(thetaStart+v*thetaLength),
+				radius*math.Sin(phiStart+u*phiLength)*math.Sin(thetaStart+v*thetaLength),
+			)
+			g.Position = append(g.Position, vertex.X, vertex.Y, vertex.Z)
+
+			normal := vertex.Clone().Normalize()
+			g.Normal = append(g.Normal, normal.X, normal.Y, normal.Z)
+
+			g.UV = append(g.UV, u+uOffset, 1-v)
+
+			verticesRow = append(verticesRow, index)
+			index++
+		}
+
+		grid = append(grid, verticesRow)
+	}
+
+	for iy := 0; iy < heightSegments; iy++ {
+		for ix := 0; ix < widthSegments; ix++ {
+			a := grid[iy][ix+1]
+			b := grid[iy][ix]
+			c := grid[iy+1][ix]
+			d := grid[iy+1][ix+1]
+
+			if iy != 0 || thetaStart > 0 {
+				g.Index = append(g.Index, a, b, d)
+			}
+			if iy != heightSegments-1 || thetaEnd < math.Pi {
+				g.Index = append(g.Index, b, c, d)
+			}
+		}
+	}
+
+	return g
+}
+
+// NewCylinderBufferGeometry :
+func NewCylinderBufferGeometry(radiusTop, radiusBottom, height float64, radialSegments, heightSegments int, openEnded bool, thetaStart, thetaLength float64) *BufferGeometry {
+	g := &BufferGeometry{}
+	height = orDefault(height, 1)
+	radialSegments = orDefaultInt(radialSegments, 8)
+	heightSegments = orDefaultInt(heightSegments, 1)
+
+	index := 0
+	indexArray := [][]int{}
+	halfHeight := height / 2
+	groupStart := 0
+
+	// torso
+	{
+		groupCount := 0
+		slope := (radiusBottom - radiusTop) / height
+
+		for y := 0; y <= heightSegments; y++ {
+			indexRow := []int{}
+			v := float64(y) / float64(heightSegments)
+			radius := v*(radiusBottom-radiusTop) + radiusTop
+
+			for x := 0; x <= radialSegments; x++ {
+				u := float64(x) / float64(radialSegments)
+				theta := u*thetaLength + thetaStart
+				sinTheta, cosTheta := math.Sin(theta), math.Cos(theta)
+
+				g.Position = append(g.Position, radius*sinTheta, -v*height+halfHeight, radius*cosTheta)
+
+				normal := NewVector3(sinTheta, slope, cosTheta).Normalize()
+				g.Normal = append(g.Normal, normal.X, normal.Y, normal.Z)
+
+				g.UV = append(g.UV, u, 1-v)
+
+				indexRow = append(indexRow, index)
+				index++
+			}
+
+			indexArray = append(indexArray, indexRow)
+		}
+
+		for x := 0; x < radialSegments; x++ {
+			for y := 0; y < heightSegments; y++ {
+				a := indexArray[y][x]
+				b := indexArray[y+1][x]
+				c := indexArray[y+1][x+1]
+				d := indexArray[y][x+1]
+				g.Index = append(g.Index, a, b, d, b, c, d)
+				groupCount += 6
+			}
+		}
+
+		g.AddGroup(groupStart, groupCount, 0)
+		groupStart += groupCount
+	}
+
+	generateCap := func(top bool) {
+		groupCount := 0
+		radius, sign, materialIndex := radiusBottom, -1.0, 2
+		if top {
+			radius, sign, materialIndex = radiusTop, 1.0, 1
+		}
+
+		centerIndexStart := index
+		for x := 1; x <= radialSegments; x++ {
+			g.Position = append(g.Position, 0, halfHeight*sign, 0)
+			g.Normal = append(g.Normal, 0, sign, 0)
+			g.UV = append(g.UV, 0.5, 0.5)
+			index++
+		}
+		centerIndexEnd := index
+
+		for x := 0; x <= radialSegments; x++ {
+			u := float64(x) / float64(radialSegments)
+			theta := u*thetaLength + thetaStart
+			cosTheta, sinTheta := math.Cos(theta), math.Sin(theta)
+
+			g.Position = append(g.Position, radius*sinTheta, halfHeight*sign, radius*cosTheta)
+			g.Normal = append(g.Normal, 0, sign, 0)
+			g.UV = append(g.UV, cosTheta*0.5+0.5, sinTheta*0.5*sign+0.5)
+			index++
+		}
+
+		for x := 0; x < radialSegments; x++ {
+			c := centerIndexStart + x
+			i := centerIndexEnd + x
+			if top {
+				g.Index = append(g.Index, i, i+1, c)
+			} else {
+				g.Index = append(g.Index, i+1, i, c)
+			}
+			groupCount += 3
+		}
+
+		g.AddGroup(groupStart, groupCount, materialIndex)
+		groupStart += groupCount
+	}
+
+	if !openEnded {
+		if radiusTop > 0 {
+			generateCap(true)
+		}
+		if radiusBottom > 0 {
+			generateCap(false)
+		}
+	}
+
+	return g
+}
+
+// NewConeBufferGeometry :
+func NewConeBufferGeometry(radius, height float64, radialSegments, heightSegments int, openEnded bool, thetaStart, thetaLength float64) *BufferGeometry {
+	return NewCylinderBufferGeometry(0, radius, height, radialSegments, heightSegments, openEnded, thetaStart, thetaLength)
+}
+
+// NewCircleBufferGeometry :
+func NewCircleBufferGeometry(radius float64, segments int, thetaStart, thetaLength float64) *BufferGeometry {
+	g := &BufferGeometry{}
+	radius = orDefault(radius, 1)
+	segments = int(math.Max(3, float64(orDefaultInt(segments, 8))))
+
+	// center point
+	g.Position = append(g.Position, 0, 0, 0)
+	g.Normal = append(g.Normal, 0, 0, 1)
+	g.UV = append(g.UV, 0.5, 0.5)
+
+	for s := 0; s <= segments; s++ {
+		segment := thetaStart + float64(s)/float64(segments)*thetaLength
+		x, y := radius*math.Cos(segment), radius*math.Sin(segment)
+
+		g.Position = append(g.Position, x, y, 0)
+		g.Normal = append(g.Normal, 0, 0, 1)
+		g.UV = append(g.UV, (x/radius+1)/2, (y/radius+1)/2)
+	}
+
+	for i := 1; i <= segments; i++ {
+		g.Index = append(g.Index, i, i+1, 0)
+	}
+
+	return g
+}
+
+// NewRingBufferGeometry :
+func NewRingBufferGeometry(innerRadius, outerRadius float64, thetaSegments, phiSegments int, thetaStart, thetaLength float64) *BufferGeometry {
+	g := &BufferGeometry{}
+	innerRadius = orDefault(innerRadius, 0.5)
+	outerRadius = orDefault(outerRadius, 1)
+	thetaSegments = int(math.Max(3, float64(orDefaultInt(thetaSegments, 8))))
+	phiSegments = orDefaultInt(phiSegments, 1)
+
+	radius := innerRadius
+	radiusStep := (outerRadius - innerRadius) / float64(phiSegments)
+
+	for j := 0; j <= phiSegments; j++ {
+		for i := 0; i <= thetaSegments; i++ {
+			segment := thetaStart + float64(i)/float64(thetaSegments)*thetaLength
+			x, y := radius*math.Cos(segment), radius*math.Sin(segment)
+
+			g.Position = append(g.Position, x, y, 0)
+			g.Normal = append(g.Normal, 0, 0, 1)
+			g.UV = append(g.UV, (x/outerRadius+1)/2, (y/outerRadius+1)/2)
+		}
+		radius += radiusStep
+	}
+
+	for j := 0; j < phiSegments; j++ {
+		thetaSegmentLevel := j * (thetaSegments + 1)
+		for i := 0; i < thetaSegments; i++ {
+			segment := i + thetaSegmentLevel
+			a := segment
+			b := segment + thetaSegments + 1
+			c := segment + thetaSegments + 2
+			d := segment + 1
+			g.Index = append(g.Index, a, b, d, b, c, d)
+		}
+	}
+
+	return g
+}
+
+// NewTorusBufferGeometry :
+func NewTorusBufferGeometry(radius, tube float64, radialSegments, tubularSegments int, arc float64) *BufferGeometry {
+	g := &BufferGeometry{}
+	radius = orDefault(radius, 1)
+	tube = orDefault(tube, 0.4)
+	radialSegments = orDefaultInt(radialSegments, 8)
+	tubularSegments = orDefaultInt(tubularSegments, 6)
+	arc = orDefault(arc, math.Pi*2)
+
+	for j := 0; j <= radialSegments; j++ {
+		for i := 0; i <= tubularSegments; i++ {
+			u := float64(i) / float64(tubularSegments) * arc
+			v := float64(j) / float64(radialSegments) * math.Pi * 2
+
+			vertex := NewVector3(
+				(radius+tube*math.Cos(v))*math.Cos(u),
+				(radius+tube*math.Cos(v))*math.Sin(u),
+				tube*math.Sin(v),
+			)
+			g.Position = append(g.Position, vertex.X, vertex.Y, vertex.Z)
+
+			center := NewVector3(radius*math.Cos(u), radius*math.Sin(u), 0)
+			normal := NewVector3(0, 0, 0).SubVectors(*vertex, *center).Normalize()
+			g.Normal = append(g.Normal, normal.X, normal.Y, normal.Z)
+
+			g.UV = append(g.UV, float64(i)/float64(tubularSegments), float64(j)/float64(radialSegments))
+		}
+	}
+
+	for j := 1; j <= radialSegments; j++ {
+		for i := 1; i <= tubularSegments; i++ {
+			a := (tubularSegments+1)*j + i - 1
+			b := (tubularSegments+1)*(j-1) + i - 1
+			c := (tubularSegments+1)*(j-1) + i
+			d := (tubularSegments+1)*j + i
+			g.Index = append(g.Index, a, b, d, b, c, d)
+		}
+	}
+
+	return g
+}
+
+// orDefault returns value, or def when value is 0, like `value || def` in javascript.
+func orDefault(value, def float64) float64 {
+	if value == 0 {
+		return def
+	}
+	return value
+}
+
+// orDefaultInt returns value, or def when value is 0.
+func orDefaultInt(value, def int) int {
+	if value <= 0 {
+		return def
+	}
+	return value
+}
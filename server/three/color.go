@@ -100,36 +100,36 @@ func LinearToSRGB(d float64) float64 {
 }
 
 // Set :
-func (c Color) Set(r, g, b float64) *Color {
+func (c *Color) Set(r, g, b float64) *Color {
 	return c.SetRGB(r, g, b)
 }
 
 // SetScalar :
-func (c Color) SetScalar(scalar float64) *Color {
+func (c *Color) SetScalar(scalar float64) *Color {
 	c.R = scalar
 	c.G = scalar
 	c.B = scalar
-	return &c
+	return c
 }
 
 // SetHex :
-func (c Color) SetHex(hex int) *Color {
+func (c *Color) SetHex(hex int) *Color {
 	c.R = float64((hex >> 16 & 255) / 255)
 	c.G = float64((hex >> 8 & 255) / 255)
 	c.B = float64((hex & 255) / 255)
-	return &c
+	return c
 }
 
 // SetRGB :
-func (c Color) SetRGB(r, g, b float64) *Color {
+func (c *Color) SetRGB(r, g, b float64) *Color {
 	c.R = r
 	c.G = g
 	c.B = b
-	return &c
+	return c
 }
 
 // SetHSL :
-func (c Color) SetHSL(h, s, l float64) *Color {
+func (c *Color) SetHSL(h, s, l float64) *Color {
 	// h,s,l ranges are in 0.0 - 1.0
 	h = float64(EuclideanModulo(int(h), 1))
 	s = Clamp(s, 0, 1)
@@ -150,43 +150,43 @@ func (c Color) SetHSL(h, s, l float64) *Color {
 		c.B = Hue2Rgb(q, p, h-1/3)
 	}
 
-	return &c
+	return c
 }
 
 // SetColorName :
-func (c Color) SetColorName(style string) *Color {
+func (c *Color) SetColorName(style string) *Color {
 	// color keywords
 	if hex, ok := ColorKeywords[style]; ok {
 		c.SetHex(hex)
 	} else {
 		panic("THREE.Color: Unknown color " + style)
 	}
-	return &c
+	return c
 }
 
 // Clone :
-func (c Color) Clone() *Color {
+func (c *Color) Clone() *Color {
 	return NewColor(c.R, c.B, c.G)
 }
 
 // Copy :
-func (c Color) Copy(color Color) *Color {
+func (c *Color) Copy(color Color) *Color {
 	c.R = color.R
 	c.G = color.G
 	c.B = color.B
-	return &c
+	return c
 }
 
 // CopyGammaToLinear : gammaFactor default is 2.0
-func (c Color) CopyGammaToLinear(color Color, gammaFactor float64) *Color {
+func (c *Color) CopyGammaToLinear(color Color, gammaFactor float64) *Color {
 	c.R = math.Pow(color.R, gammaFactor)
 	c.G = math.Pow(color.G, gammaFactor)
 	c.B = math.Pow(color.B, gammaFactor)
-	return &c
+	return c
 }
 
 // CopyLinearToGamma : gammaFactor default is 2.0
-func (c Color) CopyLinearToGamma(color Color, gammaFactor float64) *Color {
+func (c *Color) CopyLinearToGamma(color Color, gammaFactor float64) *Color {
 	safeInverse := 1.0
 	if gammaFactor > 0 {
 		gammaFactor = 1.0 / gammaFactor
@@ -194,62 +194,62 @@ func (c Color) CopyLinearToGamma(color Color, gammaFactor float64) *Color {
 	c.R = math.Pow(color.R, safeInverse)
 	c.G = math.Pow(color.G, safeInverse)
 	c.B = math.Pow(color.B, safeInverse)
-	return &c
+	return c
 }
 
 // ConvertGammaToLinear :
-func (c Color) ConvertGammaToLinear(gammaFactor float64) *Color {
-	c.CopyGammaToLinear(c, gammaFactor)
-	return &c
+func (c *Color) ConvertGammaToLinear(gammaFactor float64) *Color {
+	c.CopyGammaToLinear(*c, gammaFactor)
+	return c
 }
 
 // ConvertLinearToGamma :
-func (c Color) ConvertLinearToGamma(gammaFactor float64) *Color {
-	c.CopyLinearToGamma(c, gammaFactor)
-	return &c
+func (c *Color) ConvertLinearToGamma(gammaFactor float64) *Color {
+	c.CopyLinearToGamma(*c, gammaFactor)
+	return c
 }
 
 // CopySRGBToLinear :
-func (c Color) CopySRGBToLinear(color Color) *Color {
+func (c *Color) CopySRGBToLinear(color Color) *Color {
 	c.R = SRGBToLinear(color.R)
 	c.G = SRGBToLinear(color.G)
 	c.B = SRGBToLinear(color.B)
-	return &c
+	return c
 }
 
 // CopyLinearToSRGB :
-func (c Color) CopyLinearToSRGB(color Color) *Color {
+func (c *Color) CopyLinearToSRGB(color Color) *Color {
 	c.R = LinearToSRGB(color.R)
 	c.G = LinearToSRGB(color.G)
 	c.B = LinearToSRGB(color.B)
-	return &c
+	return c
 }
 
 // ConvertSRGBToLinear :
-func (c Color) ConvertSRGBToLinear() *Color {
-	c.CopySRGBToLinear(c)
-	return &c
+func (c *Color) ConvertSRGBToLinear() *Color {
+	c.CopySRGBToLinear(*c)
+	return c
 }
 
 // ConvertLinearToSRGB :
-func (c Color) ConvertLinearToSRGB() *Color {
-	c.CopyLinearToSRGB(c)
-	return &c
+func (c *Color) ConvertLinearToSRGB() *Color {
+	c.CopyLinearToSRGB(*c)
+	return c
 }
 
 // GetHex :
-func (c Color) GetHex() int {
+func (c *Color) GetHex() int {
 	return (int(c.R)*255)<<16 ^ (int(c.G)*255)<<8 ^ (int(c.B)*255)<<0
 }
 
 // GetHexString :
-func (c Color) GetHexString() string {
+func (c *Color) GetHexString() string {
 	str := "000000" + strconv.FormatInt(int64(c.GetHex()), 16)
 	return str[len(str)-6:]
 }
 
 // GetHSL :
-func (c Color) GetHSL(target HSL) *HSL {
+func (c *Color) GetHSL(target HSL) *HSL {
 	// h,s,l ranges are in 0.0 - 1.0
 	r, g, b := c.R, c.G, c.B
 	max := math.Max(r, math.Max(g, b))
@@ -291,80 +291,80 @@ func (c Color) GetHSL(target HSL) *HSL {
 }
 
 // GetStyle :
-func (c Color) GetStyle() string {
+func (c *Color) GetStyle() string {
 	return "rgb(" + strconv.Itoa((int(c.R)*255)|0) +
 		"," + strconv.Itoa((int(c.G)*255)|0) + "," +
 		strconv.Itoa((int(c.B)*255)|0) + ")"
 }
 
 // OffsetHSL :
-func (c Color) OffsetHSL(h, s, l float64) *Color {
+func (c *Color) OffsetHSL(h, s, l float64) *Color {
 	c.GetHSL(_hslA)
 	_hslA.H += h
 	_hslA.S += s
 	_hslA.L += l
 	c.SetHSL(_hslA.H, _hslA.S, _hslA.L)
-	return &c
+	return c
 }
 
 // Add :
-func (c Color) Add(color Color) *Color {
+func (c *Color) Add(color Color) *Color {
 	c.R += color.R
 	c.G += color.G
 	c.B += color.B
-	return &c
+	return c
 }
 
 // AddColors :
-func (c Color) AddColors(color1, color2 Color) *Color {
+func (c *Color) AddColors(color1, color2 Color) *Color {
 	c.R = color1.R + color2.R
 	c.G = color1.G + color2.G
 	c.B = color1.B + color2.B
-	return &c
+	return c
 }
 
 // AddScalar :
-func (c Color) AddScalar(s float64) *Color {
+func (c *Color) AddScalar(s float64) *Color {
 	c.R += s
 	c.G += s
 	c.B += s
-	return &c
+	return c
 }
 
 // Sub :
-func (c Color) Sub(color Color) *Color {
+func (c *Color) Sub(color Color) *Color {
 	c.R = math.Max(0, c.R-color.R)
 	c.G = math.Max(0, c.G-color.G)
 	c.B = math.Max(0, c.B-color.B)
-	return &c
+	return c
 }
 
 // Multiply :
-func (c Color) Multiply(color Color) *Color {
+func (c *Color) Multiply(color Color) *Color {
 	c.R *= color.R
 	c.G *= color.G
 	c.B *= color.B
-	return &c
+	return c
 }
 
 // MultiplyScalar :
-func (c Color) MultiplyScalar(s float64) *Color {
+func (c *Color) MultiplyScalar(s float64) *Color {
 	c.R *= s
 	c.G *= s
 	c.B *= s
-	return &c
+	return c
 }
 
 // Lerp :
-func (c Color) Lerp(color Color, alpha float64) *Color {
+func (c *Color) Lerp(color Color, alpha float64) *Color {
 	c.R += (color.R - c.R) * alpha
 	c.G += (color.G - c.G) * alpha
 	c.B += (color.B - c.B) * alpha
-	return &c
+	return c
 }
 
 // LerpHSL :
-func (c Color) LerpHSL(color Color, alpha float64) *Color {
+func (c *Color) LerpHSL(color Color, alpha float64) *Color {
 	c.GetHSL(_hslA)
 	color.GetHSL(_hslB)
 
@@ -373,27 +373,27 @@ func (c Color) LerpHSL(color Color, alpha float64) *Color {
 	l := Lerp(_hslA.L, _hslB.L, alpha)
 
 	c.SetHSL(h, s, l)
-	return &c
+	return c
 }
 
 // Equals :
-func (c Color) Equals(d Color) bool {
+func (c *Color) Equals(d Color) bool {
 	return (d.R == c.R) && (d.G == c.G) && (d.B == c.B)
 }
 
 // FromArray :
-func (c Color) FromArray(array []float64, offset int) *Color {
+func (c *Color) FromArray(array []float64, offset int) *Color {
 	if len(array) < offset+3 {
 		panic("array length should be greater than offset+3")
 	}
 	c.R = array[offset]
 	c.G = array[offset+1]
 	c.B = array[offset+2]
-	return &c
+	return c
 }
 
 // ToArray :
-func (c Color) ToArray(array []float64, offset int) []float64 {
+func (c *Color) ToArray(array []float64, offset int) []float64 {
 	if len(array) < offset+3 {
 		panic("array length should be greater than offset+3")
 	}
@@ -404,6 +404,6 @@ func (c Color) ToArray(array []float64, offset int) []float64 {
 }
 
 // ToJSON :
-func (c Color) ToJSON() int {
+func (c *Color) ToJSON() int {
 	return c.GetHex()
 }
@@ -27,35 +27,35 @@ type Cylindrical struct {
 }
 
 // Set :
-func (c Cylindrical) Set(radius, theta, y float64) *Cylindrical {
+func (c *Cylindrical) Set(radius, theta, y float64) *Cylindrical {
 	c.Radius = radius
 	c.Theta = theta
 	c.Y = y
-	return &c
+	return c
 }
 
 // Clone :
-func (c Cylindrical) Clone() *Cylindrical {
+func (c *Cylindrical) Clone() *Cylindrical {
 	return &Cylindrical{c.Radius, c.Theta, c.Y}
 }
 
 // Copy :
-func (c Cylindrical) Copy(other *Cylindrical) *Cylindrical {
+func (c *Cylindrical) Copy(other *Cylindrical) *Cylindrical {
 	c.Radius = other.Radius
 	c.Theta = other.Theta
 	c.Y = other.Y
-	return &c
+	return c
 }
 
 // setFromVector3 :
-func (c Cylindrical) setFromVector3(v Vector3) *Cylindrical {
+func (c *Cylindrical) setFromVector3(v Vector3) *Cylindrical {
 	return c.SetFromCartesianCoords(v.X, v.Y, v.Z)
 }
 
 // SetFromCartesianCoords :
-func (c Cylindrical) SetFromCartesianCoords(x, y, z float64) *Cylindrical {
+func (c *Cylindrical) SetFromCartesianCoords(x, y, z float64) *Cylindrical {
 	c.Radius = math.Sqrt(x*x + z*z)
 	c.Theta = math.Atan2(x, z)
 	c.Y = y
-	return &c
+	return c
 }
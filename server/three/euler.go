@@ -30,7 +30,7 @@ func NewEuler(x, y, z float64, order string) *Euler {
 	if order == "" {
 		order = DefaultOrder
 	}
-	return &Euler{x, y, z, order, nil}
+	return &Euler{x, y, z, order, func() {}}
 }
 
 // Euler :
@@ -43,51 +43,51 @@ type Euler struct {
 }
 
 // X :
-func (e Euler) X() float64 {
+func (e *Euler) X() float64 {
 	return e._x
 }
 
 // SetX :
-func (e Euler) SetX(value float64) {
+func (e *Euler) SetX(value float64) {
 	e._x = value
 	e._onChangeCallback()
 }
 
 // Y :
-func (e Euler) Y() float64 {
+func (e *Euler) Y() float64 {
 	return e._y
 }
 
 // SetY :
-func (e Euler) SetY(value float64) {
+func (e *Euler) SetY(value float64) {
 	e._y = value
 	e._onChangeCallback()
 }
 
 // Z :
-func (e Euler) Z() float64 {
+func (e *Euler) Z() float64 {
 	return e._z
 }
 
 // SetZ :
-func (e Euler) SetZ(value float64) {
+func (e *Euler) SetZ(value float64) {
 	e._z = value
 	e._onChangeCallback()
 }
 
 // Order :
-func (e Euler) Order() string {
+func (e *Euler) Order() string {
 	return e._order
 }
 
 // SetOrder :
-func (e Euler) SetOrder(value string) {
+func (e *Euler) SetOrder(value string) {
 	e._order = value
 	e._onChangeCallback()
 }
 
 // Set :
-func (e Euler) Set(x, y, z float64, order string) *Euler {
+func (e *Euler) Set(x, y, z float64, order string) *Euler {
 	e._x = x
 	e._y = y
 	e._z = z
@@ -97,16 +97,16 @@ func (e Euler) Set(x, y, z float64, order string) *Euler {
 
 	e._onChangeCallback()
 
-	return &e
+	return e
 }
 
 // Clone :
-func (e Euler) Clone() *Euler {
+func (e *Euler) Clone() *Euler {
 	return NewEuler(e._x, e._y, e._z, e._order)
 }
 
 // Copy :
-func (e Euler) Copy(euler Euler) *Euler {
+func (e *Euler) Copy(euler Euler) *Euler {
 	e._x = euler._x
 	e._y = euler._y
 	e._z = euler._z
@@ -114,15 +114,15 @@ func (e Euler) Copy(euler Euler) *Euler {
 
 	e._onChangeCallback()
 
-	return &e
+	return e
 }
 
 // SetFromRotationMatrix :
-func (e Euler) SetFromRotationMatrix(m Matrix4, order string, update bool) *Euler {
+func (e *Euler) SetFromRotationMatrix(m Matrix4, order string, update bool) *Euler {
 	clamp := Clamp
 
 	// assumes the upper 3x3 of m is a pure rotation matrix (i.e, unscaled)
-	te := m.Elements
+	te := &m.Elements
 	m11, m12, m13 := te[0], te[4], te[8]
 	m21, m22, m23 := te[1], te[5], te[9]
 	m31, m32, m33 := te[2], te[6], te[10]
@@ -202,18 +202,18 @@ func (e Euler) SetFromRotationMatrix(m Matrix4, order string, update bool) *Eule
 		e._onChangeCallback()
 	}
 
-	return &e
+	return e
 }
 
 // SetFromQuaternion :
-func (e Euler) SetFromQuaternion(q Quaternion, order string, update bool) *Euler {
+func (e *Euler) SetFromQuaternion(q Quaternion, order string, update bool) *Euler {
 	_matrix.MakeRotationFromQuaternion(q)
 
 	return e.SetFromRotationMatrix(*_matrix, order, update)
 }
 
 // SetFromVector3 :
-func (e Euler) SetFromVector3(v Vector3, order string) *Euler {
+func (e *Euler) SetFromVector3(v Vector3, order string) *Euler {
 	if order == "" {
 		order = e._order
 	}
@@ -221,15 +221,15 @@ func (e Euler) SetFromVector3(v Vector3, order string) *Euler {
 }
 
 // Reorder :
-func (e Euler) Reorder(newOrder string) *Euler {
+func (e *Euler) Reorder(newOrder string) *Euler {
 	// WARNING: e discards revolution information -bhouston
-	_quaternion.SetFromEuler(e, false)
+	_quaternion.SetFromEuler(*e, false)
 
 	return e.SetFromQuaternion(*_quaternion, newOrder, true)
 }
 
 // Equals :
-func (e Euler) Equals(euler Euler) bool {
+func (e *Euler) Equals(euler Euler) bool {
 	return (euler._x == e._x) &&
 		(euler._y == e._y) &&
 		(euler._z == e._z) &&
@@ -237,7 +237,7 @@ func (e Euler) Equals(euler Euler) bool {
 }
 
 // FromArray :
-func (e Euler) FromArray(array []float64, order string) *Euler {
+func (e *Euler) FromArray(array []float64, order string) *Euler {
 	if len(array) < 3 {
 		panic("array length should be greater than 3")
 	}
@@ -251,11 +251,11 @@ func (e Euler) FromArray(array []float64, order string) *Euler {
 
 	e._onChangeCallback()
 
-	return &e
+	return e
 }
 
 // ToArray :
-func (e Euler) ToArray(array []float64, offset int) ([]float64, string) {
+func (e *Euler) ToArray(array []float64, offset int) ([]float64, string) {
 	if len(array) < offset+3 {
 		panic("array length should be greater than offset+3")
 	}
@@ -267,11 +267,11 @@ func (e Euler) ToArray(array []float64, offset int) ([]float64, string) {
 }
 
 // ToVector3 :
-func (e Euler) ToVector3(optionalResult Vector3) *Vector3 {
+func (e *Euler) ToVector3(optionalResult Vector3) *Vector3 {
 	return optionalResult.Set(e._x, e._y, e._z)
 }
 
-func (e Euler) _onChange(callback onChangeCallback) *Euler {
+func (e *Euler) _onChange(callback onChangeCallback) *Euler {
 	e._onChangeCallback = callback
-	return &e
+	return e
 }
@@ -26,7 +26,7 @@ type Frustum struct {
 }
 
 // Set :
-func (f Frustum) Set(p0, p1, p2, p3, p4, p5 Plane) *Frustum {
+func (f *Frustum) Set(p0, p1, p2, p3, p4, p5 Plane) *Frustum {
 	planes := f.Planes
 
 	planes[0].Copy(p0)
@@ -36,27 +36,27 @@ func (f Frustum) Set(p0, p1, p2, p3, p4, p5 Plane) *Frustum {
 	planes[4].Copy(p4)
 	planes[5].Copy(p5)
 
-	return &f
+	return f
 }
 
 // Clone :
-func (f Frustum) Clone() *Frustum {
-	return NewFrustum(f.Planes[0], f.Planes[1], f.Planes[2], f.Planes[3], f.Planes[4], f.Planes[5]).Copy(f)
+func (f *Frustum) Clone() *Frustum {
+	return NewFrustum(f.Planes[0], f.Planes[1], f.Planes[2], f.Planes[3], f.Planes[4], f.Planes[5]).Copy(*f)
 }
 
 // Copy :
-func (f Frustum) Copy(frustum Frustum) *Frustum {
+func (f *Frustum) Copy(frustum Frustum) *Frustum {
 	planes := f.Planes
 
 	for i := 0; i < 6; i++ {
 		planes[i].Copy(frustum.Planes[i])
 	}
 
-	return &f
+	return f
 }
 
 // SetFromProjectionMatrix :
-func (f Frustum) SetFromProjectionMatrix(m Matrix4) *Frustum {
+func (f *Frustum) SetFromProjectionMatrix(m Matrix4) *Frustum {
 	planes := f.Planes
 	me := m.Elements
 	me0, me1, me2, me3 := me[0], me[1], me[2], me[3]
@@ -71,11 +71,11 @@ func (f Frustum) SetFromProjectionMatrix(m Matrix4) *Frustum {
 	planes[4].SetComponents(me3-me2, me7-me6, me11-me10, me15-me14).Normalize()
 	planes[5].SetComponents(me3+me2, me7+me6, me11+me10, me15+me14).Normalize()
 
-	return &f
+	return f
 }
 
 // IntersectsSphere :
-func (f Frustum) IntersectsSphere(sphere Sphere) bool {
+func (f *Frustum) IntersectsSphere(sphere Sphere) bool {
 	planes := f.Planes
 	center := sphere.Center
 	negRadius := -sphere.Radius
@@ -91,7 +91,7 @@ func (f Frustum) IntersectsSphere(sphere Sphere) bool {
 }
 
 // IntersectsBox :
-func (f Frustum) IntersectsBox(box Box3) bool {
+func (f *Frustum) IntersectsBox(box Box3) bool {
 	var planes = f.Planes
 	for i := 0; i < 6; i++ {
 		var plane = planes[i]
@@ -119,7 +119,7 @@ func (f Frustum) IntersectsBox(box Box3) bool {
 }
 
 // ContainsPoint :
-func (f Frustum) ContainsPoint(point Vector3) bool {
+func (f *Frustum) ContainsPoint(point Vector3) bool {
 	var planes = f.Planes
 	for i := 0; i < 6; i++ {
 		if planes[i].DistanceToPoint(point) < 0 {
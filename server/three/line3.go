@@ -25,51 +25,51 @@ type Line3 struct {
 }
 
 // Set :
-func (l Line3) Set(start, end Vector3) *Line3 {
+func (l *Line3) Set(start, end Vector3) *Line3 {
 	l.Start.Copy(start)
 	l.End.Copy(end)
-	return &l
+	return l
 }
 
 // Clone :
-func (l Line3) Clone() *Line3 {
-	return NewLine3(l.Start, l.End).Copy(l)
+func (l *Line3) Clone() *Line3 {
+	return NewLine3(l.Start, l.End).Copy(*l)
 }
 
 // Copy :
-func (l Line3) Copy(line Line3) *Line3 {
+func (l *Line3) Copy(line Line3) *Line3 {
 	l.Start.Copy(line.Start)
 	l.End.Copy(line.End)
-	return &l
+	return l
 }
 
 // GetCenter :
-func (l Line3) GetCenter(target Vector3) *Vector3 {
+func (l *Line3) GetCenter(target Vector3) *Vector3 {
 	return target.AddVectors(l.Start, l.End).MultiplyScalar(0.5)
 }
 
 // Delta :
-func (l Line3) Delta(target Vector3) *Vector3 {
+func (l *Line3) Delta(target Vector3) *Vector3 {
 	return target.SubVectors(l.End, l.Start)
 }
 
 // DistanceSq :
-func (l Line3) DistanceSq() float64 {
+func (l *Line3) DistanceSq() float64 {
 	return l.Start.DistanceToSquared(l.End)
 }
 
 // Distance :
-func (l Line3) Distance() float64 {
+func (l *Line3) Distance() float64 {
 	return l.Start.DistanceTo(l.End)
 }
 
 // At :
-func (l Line3) At(t float64, target Vector3) *Vector3 {
+func (l *Line3) At(t float64, target Vector3) *Vector3 {
 	return l.Delta(target).MultiplyScalar(t).Add(l.Start)
 }
 
 // ClosestPointToPointParameter :
-func (l Line3) ClosestPointToPointParameter(point Vector3, clampToLine bool) float64 {
+func (l *Line3) ClosestPointToPointParameter(point Vector3, clampToLine bool) float64 {
 	_startP.SubVectors(point, l.Start)
 	_startEnd.SubVectors(l.End, l.Start)
 
@@ -85,19 +85,19 @@ func (l Line3) ClosestPointToPointParameter(point Vector3, clampToLine bool) flo
 }
 
 // ClosestPointToPoint :
-func (l Line3) ClosestPointToPoint(point Vector3, clampToLine bool, target Vector3) *Vector3 {
+func (l *Line3) ClosestPointToPoint(point Vector3, clampToLine bool, target Vector3) *Vector3 {
 	t := l.ClosestPointToPointParameter(point, clampToLine)
 	return l.Delta(target).MultiplyScalar(t).Add(l.Start)
 }
 
 // ApplyMatrix4 :
-func (l Line3) ApplyMatrix4(matrix Matrix4) *Line3 {
+func (l *Line3) ApplyMatrix4(matrix Matrix4) *Line3 {
 	l.Start.ApplyMatrix4(matrix)
 	l.End.ApplyMatrix4(matrix)
-	return &l
+	return l
 }
 
 // Equals :
-func (l Line3) Equals(line Line3) bool {
+func (l *Line3) Equals(line Line3) bool {
 	return line.Start.Equals(l.Start) && line.End.Equals(l.End)
 }
@@ -17,7 +17,7 @@ import (
 	"strings"
 )
 
-var _lut = []string{}
+var _lut = make([]string, 256)
 
 func init() {
 	for i := int64(0); i < 256; i++ {
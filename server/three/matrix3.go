@@ -28,8 +28,8 @@ type Matrix3 struct {
 }
 
 // Set :
-func (m Matrix3) Set(n11, n12, n13, n21, n22, n23, n31, n32, n33 float64) *Matrix3 {
-	te := m.Elements
+func (m *Matrix3) Set(n11, n12, n13, n21, n22, n23, n31, n32, n33 float64) *Matrix3 {
+	te := &m.Elements
 
 	te[0] = n11
 	te[1] = n21
@@ -41,22 +41,22 @@ func (m Matrix3) Set(n11, n12, n13, n21, n22, n23, n31, n32, n33 float64) *Matri
 	te[7] = n23
 	te[8] = n33
 
-	return &m
+	return m
 }
 
 // Identity :
-func (m Matrix3) Identity() *Matrix3 {
+func (m *Matrix3) Identity() *Matrix3 {
 	m.Set(
 		1, 0, 0,
 		0, 1, 0,
 		0, 0, 1,
 	)
 
-	return &m
+	return m
 }
 
 // Clone :
-func (m Matrix3) Clone() *Matrix3 {
+func (m *Matrix3) Clone() *Matrix3 {
 	array := make([]float64, 0)
 	for _, elem := range m.Elements {
 		array = append(array, elem)
@@ -65,8 +65,8 @@ func (m Matrix3) Clone() *Matrix3 {
 }
 
 // Copy :
-func (m Matrix3) Copy(n Matrix3) *Matrix3 {
-	te := m.Elements
+func (m *Matrix3) Copy(n Matrix3) *Matrix3 {
+	te := &m.Elements
 	me := n.Elements
 
 	te[0] = me[0]
@@ -79,20 +79,20 @@ func (m Matrix3) Copy(n Matrix3) *Matrix3 {
 	te[7] = me[7]
 	te[8] = me[8]
 
-	return &m
+	return m
 }
 
 // ExtractBasis :
-func (m Matrix3) ExtractBasis(xAxis, yAxis, zAxis Vector3) *Matrix3 {
-	xAxis.SetFromMatrix3Column(m, 0)
-	yAxis.SetFromMatrix3Column(m, 1)
-	zAxis.SetFromMatrix3Column(m, 2)
+func (m *Matrix3) ExtractBasis(xAxis, yAxis, zAxis Vector3) *Matrix3 {
+	xAxis.SetFromMatrix3Column(*m, 0)
+	yAxis.SetFromMatrix3Column(*m, 1)
+	zAxis.SetFromMatrix3Column(*m, 2)
 
-	return &m
+	return m
 }
 
 // SetFromMatrix4 :
-func (m Matrix3) SetFromMatrix4(n Matrix4) *Matrix3 {
+func (m *Matrix3) SetFromMatrix4(n Matrix4) *Matrix3 {
 	me := n.Elements
 
 	m.Set(
@@ -101,24 +101,24 @@ func (m Matrix3) SetFromMatrix4(n Matrix4) *Matrix3 {
 		me[2], me[6], me[10],
 	)
 
-	return &m
+	return m
 }
 
 // Multiply :
-func (m Matrix3) Multiply(n Matrix3) *Matrix3 {
-	return m.MultiplyMatrices(m, n)
+func (m *Matrix3) Multiply(n Matrix3) *Matrix3 {
+	return m.MultiplyMatrices(*m, n)
 }
 
 // Premultiply :
-func (m Matrix3) Premultiply(n Matrix3) *Matrix3 {
-	return m.MultiplyMatrices(n, m)
+func (m *Matrix3) Premultiply(n Matrix3) *Matrix3 {
+	return m.MultiplyMatrices(n, *m)
 }
 
 // MultiplyMatrices :
-func (m Matrix3) MultiplyMatrices(a, b Matrix3) *Matrix3 {
+func (m *Matrix3) MultiplyMatrices(a, b Matrix3) *Matrix3 {
 	ae := a.Elements
 	be := b.Elements
-	te := m.Elements
+	te := &m.Elements
 
 	a11, a12, a13 := ae[0], ae[3], ae[6]
 	a21, a22, a23 := ae[1], ae[4], ae[7]
@@ -140,12 +140,12 @@ func (m Matrix3) MultiplyMatrices(a, b Matrix3) *Matrix3 {
 	te[5] = a31*b12 + a32*b22 + a33*b32
 	te[8] = a31*b13 + a32*b23 + a33*b33
 
-	return &m
+	return m
 }
 
 // MultiplyScalar :
-func (m Matrix3) MultiplyScalar(s float64) *Matrix3 {
-	te := m.Elements
+func (m *Matrix3) MultiplyScalar(s float64) *Matrix3 {
+	te := &m.Elements
 
 	te[0] *= s
 	te[3] *= s
@@ -157,12 +157,12 @@ func (m Matrix3) MultiplyScalar(s float64) *Matrix3 {
 	te[5] *= s
 	te[8] *= s
 
-	return &m
+	return m
 }
 
 // Determinant :
-func (m Matrix3) Determinant() float64 {
-	te := m.Elements
+func (m *Matrix3) Determinant() float64 {
+	te := &m.Elements
 
 	a, b, c := te[0], te[1], te[2]
 	d, e, f := te[3], te[4], te[5]
@@ -172,9 +172,9 @@ func (m Matrix3) Determinant() float64 {
 }
 
 // GetInverse :
-func (m Matrix3) GetInverse(matrix Matrix3) *Matrix3 {
+func (m *Matrix3) GetInverse(matrix Matrix3) *Matrix3 {
 	me := matrix.Elements
-	te := m.Elements
+	te := &m.Elements
 
 	n11, n21, n31 := me[0], me[1], me[2]
 	n12, n22, n32 := me[3], me[4], me[5]
@@ -204,12 +204,12 @@ func (m Matrix3) GetInverse(matrix Matrix3) *Matrix3 {
 	te[7] = (n21*n13 - n23*n11) * detInv
 	te[8] = (n22*n11 - n21*n12) * detInv
 
-	return &m
+	return m
 }
 
 // Transpose :
-func (m Matrix3) Transpose() *Matrix3 {
-	te := m.Elements
+func (m *Matrix3) Transpose() *Matrix3 {
+	te := &m.Elements
 
 	tmp := te[1]
 	te[1] = te[3]
@@ -223,20 +223,20 @@ func (m Matrix3) Transpose() *Matrix3 {
 	te[5] = te[7]
 	te[7] = tmp
 
-	return &m
+	return m
 }
 
 // GetNormalMatrix :
-func (m Matrix3) GetNormalMatrix(matrix4 Matrix4) *Matrix3 {
-	return m.SetFromMatrix4(matrix4).GetInverse(m).Transpose()
+func (m *Matrix3) GetNormalMatrix(matrix4 Matrix4) *Matrix3 {
+	return m.SetFromMatrix4(matrix4).GetInverse(*m).Transpose()
 }
 
 // TransposeIntoArray :
-func (m Matrix3) TransposeIntoArray(r []float64) *Matrix3 {
+func (m *Matrix3) TransposeIntoArray(r []float64) *Matrix3 {
 	if len(r) < 9 {
 		panic("array length should be greater than 9")
 	}
-	te := m.Elements
+	te := &m.Elements
 	r[0] = te[0]
 	r[1] = te[3]
 	r[2] = te[6]
@@ -246,11 +246,11 @@ func (m Matrix3) TransposeIntoArray(r []float64) *Matrix3 {
 	r[6] = te[2]
 	r[7] = te[5]
 	r[8] = te[8]
-	return &m
+	return m
 }
 
 // SetUvTransform :
-func (m Matrix3) SetUvTransform(tx, ty, sx, sy, rotation, cx, cy float64) *Matrix3 {
+func (m *Matrix3) SetUvTransform(tx, ty, sx, sy, rotation, cx, cy float64) *Matrix3 {
 	c := math.Cos(rotation)
 	s := math.Sin(rotation)
 
@@ -259,11 +259,11 @@ func (m Matrix3) SetUvTransform(tx, ty, sx, sy, rotation, cx, cy float64) *Matri
 		-sy*s, sy*c, -sy*(-s*cx+c*cy)+cy+ty,
 		0, 0, 1,
 	)
-	return &m
+	return m
 }
 
 // Scale :
-func (m Matrix3) Scale(sx, sy float64) *Matrix3 {
+func (m *Matrix3) Scale(sx, sy float64) *Matrix3 {
 	var te = m.Elements
 
 	te[0] *= sx
@@ -273,15 +273,15 @@ func (m Matrix3) Scale(sx, sy float64) *Matrix3 {
 	te[4] *= sy
 	te[7] *= sy
 
-	return &m
+	return m
 }
 
 // Rotate :
-func (m Matrix3) Rotate(theta float64) *Matrix3 {
+func (m *Matrix3) Rotate(theta float64) *Matrix3 {
 	c := math.Cos(theta)
 	s := math.Sin(theta)
 
-	te := m.Elements
+	te := &m.Elements
 
 	a11, a12, a13 := te[0], te[3], te[6]
 	a21, a22, a23 := te[1], te[4], te[7]
@@ -294,12 +294,12 @@ func (m Matrix3) Rotate(theta float64) *Matrix3 {
 	te[4] = -s*a12 + c*a22
 	te[7] = -s*a13 + c*a23
 
-	return &m
+	return m
 }
 
 // Translate :
-func (m Matrix3) Translate(tx, ty float64) *Matrix3 {
-	te := m.Elements
+func (m *Matrix3) Translate(tx, ty float64) *Matrix3 {
+	te := &m.Elements
 
 	te[0] += tx * te[2]
 	te[3] += tx * te[5]
@@ -308,12 +308,12 @@ func (m Matrix3) Translate(tx, ty float64) *Matrix3 {
 	te[4] += ty * te[5]
 	te[7] += ty * te[8]
 
-	return &m
+	return m
 }
 
 // Equals :
-func (m Matrix3) Equals(matrix Matrix3) bool {
-	te := m.Elements
+func (m *Matrix3) Equals(matrix Matrix3) bool {
+	te := &m.Elements
 	me := matrix.Elements
 
 	for i := 0; i < 9; i++ {
@@ -326,7 +326,7 @@ func (m Matrix3) Equals(matrix Matrix3) bool {
 }
 
 // FromArray :
-func (m Matrix3) FromArray(array []float64, offset int) *Matrix3 {
+func (m *Matrix3) FromArray(array []float64, offset int) *Matrix3 {
 	if len(array) < offset+9 {
 		panic("array length should be greater than offset+9")
 	}
@@ -334,15 +334,15 @@ func (m Matrix3) FromArray(array []float64, offset int) *Matrix3 {
 		m.Elements[i] = array[i+offset]
 	}
 
-	return &m
+	return m
 }
 
 // ToArray :
-func (m Matrix3) ToArray(array []float64, offset int) []float64 {
+func (m *Matrix3) ToArray(array []float64, offset int) []float64 {
 	if len(array) < offset+9 {
 		panic("array length should be greater than offset+9")
 	}
-	te := m.Elements
+	te := &m.Elements
 	array[offset] = te[0]
 	array[offset+1] = te[1]
 	array[offset+2] = te[2]
@@ -37,8 +37,8 @@ type Matrix4 struct {
 }
 
 // Set :
-func (m Matrix4) Set(n11, n12, n13, n14, n21, n22, n23, n24, n31, n32, n33, n34, n41, n42, n43, n44 float64) *Matrix4 {
-	te := m.Elements
+func (m *Matrix4) Set(n11, n12, n13, n14, n21, n22, n23, n24, n31, n32, n33, n34, n41, n42, n43, n44 float64) *Matrix4 {
+	te := &m.Elements
 
 	te[0] = n11
 	te[4] = n12
@@ -57,11 +57,11 @@ func (m Matrix4) Set(n11, n12, n13, n14, n21, n22, n23, n24, n31, n32, n33, n34,
 	te[11] = n43
 	te[15] = n44
 
-	return &m
+	return m
 }
 
 // Identity :
-func (m Matrix4) Identity() *Matrix4 {
+func (m *Matrix4) Identity() *Matrix4 {
 	m.Set(
 		1, 0, 0, 0,
 		0, 1, 0, 0,
@@ -69,11 +69,11 @@ func (m Matrix4) Identity() *Matrix4 {
 		0, 0, 0, 1,
 	)
 
-	return &m
+	return m
 }
 
 // Clone :
-func (m Matrix4) Clone() *Matrix4 {
+func (m *Matrix4) Clone() *Matrix4 {
 	array := make([]float64, 0)
 	for _, elem := range m.Elements {
 		array = append(array, elem)
@@ -82,8 +82,8 @@ func (m Matrix4) Clone() *Matrix4 {
 }
 
 // Copy :
-func (m Matrix4) Copy(n Matrix4) *Matrix4 {
-	te := m.Elements
+func (m *Matrix4) Copy(n Matrix4) *Matrix4 {
+	te := &m.Elements
 	me := n.Elements
 
 	te[0] = me[0]
@@ -103,31 +103,31 @@ func (m Matrix4) Copy(n Matrix4) *Matrix4 {
 	te[14] = me[14]
 	te[15] = me[15]
 
-	return &m
+	return m
 }
 
 // CopyPosition :
-func (m Matrix4) CopyPosition(n Matrix4) *Matrix4 {
+func (m *Matrix4) CopyPosition(n Matrix4) *Matrix4 {
 	te, me := m.Elements, n.Elements
 
 	te[12] = me[12]
 	te[13] = me[13]
 	te[14] = me[14]
 
-	return &m
+	return m
 }
 
 // ExtractBasis :
-func (m Matrix4) ExtractBasis(xAxis, yAxis, zAxis Vector3) *Matrix4 {
-	xAxis.SetFromMatrixColumn(m, 0)
-	yAxis.SetFromMatrixColumn(m, 1)
-	zAxis.SetFromMatrixColumn(m, 2)
+func (m *Matrix4) ExtractBasis(xAxis, yAxis, zAxis Vector3) *Matrix4 {
+	xAxis.SetFromMatrixColumn(*m, 0)
+	yAxis.SetFromMatrixColumn(*m, 1)
+	zAxis.SetFromMatrixColumn(*m, 2)
 
-	return &m
+	return m
 }
 
 // MakeBasis :
-func (m Matrix4) MakeBasis(xAxis, yAxis, zAxis Vector3) *Matrix4 {
+func (m *Matrix4) MakeBasis(xAxis, yAxis, zAxis Vector3) *Matrix4 {
 	m.Set(
 		xAxis.X, yAxis.X, zAxis.X, 0,
 		xAxis.Y, yAxis.Y, zAxis.Y, 0,
@@ -135,13 +135,13 @@ func (m Matrix4) MakeBasis(xAxis, yAxis, zAxis Vector3) *Matrix4 {
 		0, 0, 0, 1,
 	)
 
-	return &m
+	return m
 }
 
 // ExtractRotation :
-func (m Matrix4) ExtractRotation(n Matrix4) *Matrix4 {
+func (m *Matrix4) ExtractRotation(n Matrix4) *Matrix4 {
 	// m method does not support reflection matrices
-	te := m.Elements
+	te := &m.Elements
 	me := n.Elements
 
 	scaleX := 1 / _v1Matrix4.SetFromMatrixColumn(n, 0).Length()
@@ -168,12 +168,12 @@ func (m Matrix4) ExtractRotation(n Matrix4) *Matrix4 {
 	te[14] = 0
 	te[15] = 1
 
-	return &m
+	return m
 }
 
 // MakeRotationFromEuler :
-func (m Matrix4) MakeRotationFromEuler(euler Euler) *Matrix4 {
-	te := m.Elements
+func (m *Matrix4) MakeRotationFromEuler(euler Euler) *Matrix4 {
+	te := &m.Elements
 
 	x, y, z := euler.X(), euler.Y(), euler.Z()
 	a, b := math.Cos(x), math.Sin(x)
@@ -277,17 +277,17 @@ func (m Matrix4) MakeRotationFromEuler(euler Euler) *Matrix4 {
 	te[14] = 0
 	te[15] = 1
 
-	return &m
+	return m
 }
 
 // MakeRotationFromQuaternion :
-func (m Matrix4) MakeRotationFromQuaternion(q Quaternion) *Matrix4 {
+func (m *Matrix4) MakeRotationFromQuaternion(q Quaternion) *Matrix4 {
 	return m.Compose(*_zero, q, *_one)
 }
 
 // LookAt :
-func (m Matrix4) LookAt(eye, target, up Vector3) *Matrix4 {
-	te := m.Elements
+func (m *Matrix4) LookAt(eye, target, up Vector3) *Matrix4 {
+	te := &m.Elements
 
 	_z.SubVectors(eye, target)
 
@@ -324,24 +324,24 @@ func (m Matrix4) LookAt(eye, target, up Vector3) *Matrix4 {
 	te[6] = _y.Z
 	te[10] = _z.Z
 
-	return &m
+	return m
 }
 
 // Multiply :
-func (m Matrix4) Multiply(n Matrix4) *Matrix4 {
-	return m.MultiplyMatrices(m, n)
+func (m *Matrix4) Multiply(n Matrix4) *Matrix4 {
+	return m.MultiplyMatrices(*m, n)
 }
 
 // Premultiply :
-func (m Matrix4) Premultiply(n Matrix4) *Matrix4 {
-	return m.MultiplyMatrices(n, m)
+func (m *Matrix4) Premultiply(n Matrix4) *Matrix4 {
+	return m.MultiplyMatrices(n, *m)
 }
 
 // MultiplyMatrices :
-func (m Matrix4) MultiplyMatrices(a, b Matrix4) *Matrix4 {
+func (m *Matrix4) MultiplyMatrices(a, b Matrix4) *Matrix4 {
 	ae := a.Elements
 	be := b.Elements
-	te := m.Elements
+	te := &m.Elements
 
 	a11, a12, a13, a14 := ae[0], ae[4], ae[8], ae[12]
 	a21, a22, a23, a24 := ae[1], ae[5], ae[9], ae[13]
@@ -373,12 +373,12 @@ func (m Matrix4) MultiplyMatrices(a, b Matrix4) *Matrix4 {
 	te[11] = a41*b13 + a42*b23 + a43*b33 + a44*b43
 	te[15] = a41*b14 + a42*b24 + a43*b34 + a44*b44
 
-	return &m
+	return m
 }
 
 // MultiplyScalar :
-func (m Matrix4) MultiplyScalar(s float64) *Matrix4 {
-	te := m.Elements
+func (m *Matrix4) MultiplyScalar(s float64) *Matrix4 {
+	te := &m.Elements
 
 	te[0] *= s
 	te[4] *= s
@@ -397,12 +397,12 @@ func (m Matrix4) MultiplyScalar(s float64) *Matrix4 {
 	te[11] *= s
 	te[15] *= s
 
-	return &m
+	return m
 }
 
 // Determinant :
-func (m Matrix4) Determinant() float64 {
-	te := m.Elements
+func (m *Matrix4) Determinant() float64 {
+	te := &m.Elements
 
 	n11, n12, n13, n14 := te[0], te[4], te[8], te[12]
 	n21, n22, n23, n24 := te[1], te[5], te[9], te[13]
@@ -438,8 +438,8 @@ func (m Matrix4) Determinant() float64 {
 }
 
 // Transpose :
-func (m Matrix4) Transpose() *Matrix4 {
-	te := m.Elements
+func (m *Matrix4) Transpose() *Matrix4 {
+	te := &m.Elements
 	var tmp float64
 
 	tmp = te[1]
@@ -462,24 +462,24 @@ func (m Matrix4) Transpose() *Matrix4 {
 	te[11] = te[14]
 	te[14] = tmp
 
-	return &m
+	return m
 }
 
 // SetPosition :
-func (m Matrix4) SetPosition(x, y, z float64) *Matrix4 {
-	te := m.Elements
+func (m *Matrix4) SetPosition(x, y, z float64) *Matrix4 {
+	te := &m.Elements
 
 	te[12] = x
 	te[13] = y
 	te[14] = z
 
-	return &m
+	return m
 }
 
 // GetInverse :
-func (m Matrix4) GetInverse(n Matrix4) *Matrix4 {
+func (m *Matrix4) GetInverse(n Matrix4) *Matrix4 {
 	// based on http://www.euclideanspace.com/maths/algebra/matrix/functions/inverse/fourD/index.htm
-	te := m.Elements
+	te := &m.Elements
 	me := n.Elements
 
 	n11, n21, n31, n41 := me[0], me[1], me[2], me[3]
@@ -520,12 +520,12 @@ func (m Matrix4) GetInverse(n Matrix4) *Matrix4 {
 	te[14] = (n14*n22*n31 - n12*n24*n31 - n14*n21*n32 + n11*n24*n32 + n12*n21*n34 - n11*n22*n34) * detInv
 	te[15] = (n12*n23*n31 - n13*n22*n31 + n13*n21*n32 - n11*n23*n32 - n12*n21*n33 + n11*n22*n33) * detInv
 
-	return &m
+	return m
 }
 
 // Scale :
-func (m Matrix4) Scale(v Vector3) *Matrix4 {
-	te := m.Elements
+func (m *Matrix4) Scale(v Vector3) *Matrix4 {
+	te := &m.Elements
 	x, y, z := v.X, v.Y, v.Z
 
 	te[0] *= x
@@ -541,12 +541,12 @@ func (m Matrix4) Scale(v Vector3) *Matrix4 {
 	te[7] *= y
 	te[11] *= z
 
-	return &m
+	return m
 }
 
 // GetMaxScaleOnAxis :
-func (m Matrix4) GetMaxScaleOnAxis() float64 {
-	te := m.Elements
+func (m *Matrix4) GetMaxScaleOnAxis() float64 {
+	te := &m.Elements
 
 	scaleXSq := te[0]*te[0] + te[1]*te[1] + te[2]*te[2]
 	scaleYSq := te[4]*te[4] + te[5]*te[5] + te[6]*te[6]
@@ -556,7 +556,7 @@ func (m Matrix4) GetMaxScaleOnAxis() float64 {
 }
 
 // MakeTranslation :
-func (m Matrix4) MakeTranslation(x, y, z float64) *Matrix4 {
+func (m *Matrix4) MakeTranslation(x, y, z float64) *Matrix4 {
 	m.Set(
 		1, 0, 0, x,
 		0, 1, 0, y,
@@ -564,11 +564,11 @@ func (m Matrix4) MakeTranslation(x, y, z float64) *Matrix4 {
 		0, 0, 0, 1,
 	)
 
-	return &m
+	return m
 }
 
 // MakeRotationX :
-func (m Matrix4) MakeRotationX(theta float64) *Matrix4 {
+func (m *Matrix4) MakeRotationX(theta float64) *Matrix4 {
 	c, s := math.Cos(theta), math.Sin(theta)
 
 	m.Set(
@@ -578,11 +578,11 @@ func (m Matrix4) MakeRotationX(theta float64) *Matrix4 {
 		0, 0, 0, 1,
 	)
 
-	return &m
+	return m
 }
 
 // MakeRotationY :
-func (m Matrix4) MakeRotationY(theta float64) *Matrix4 {
+func (m *Matrix4) MakeRotationY(theta float64) *Matrix4 {
 	c, s := math.Cos(theta), math.Sin(theta)
 
 	m.Set(
@@ -592,11 +592,11 @@ func (m Matrix4) MakeRotationY(theta float64) *Matrix4 {
 		0, 0, 0, 1,
 	)
 
-	return &m
+	return m
 }
 
 // MakeRotationZ :
-func (m Matrix4) MakeRotationZ(theta float64) *Matrix4 {
+func (m *Matrix4) MakeRotationZ(theta float64) *Matrix4 {
 	c, s := math.Cos(theta), math.Sin(theta)
 
 	m.Set(
@@ -606,11 +606,11 @@ func (m Matrix4) MakeRotationZ(theta float64) *Matrix4 {
 		0, 0, 0, 1,
 	)
 
-	return &m
+	return m
 }
 
 // MakeRotationAxis :
-func (m Matrix4) MakeRotationAxis(axis Vector3, angle float64) *Matrix4 {
+func (m *Matrix4) MakeRotationAxis(axis Vector3, angle float64) *Matrix4 {
 	// Based on http://www.gamedev.net/reference/articles/article1199.asp
 	c := math.Cos(angle)
 	s := math.Sin(angle)
@@ -625,11 +625,11 @@ func (m Matrix4) MakeRotationAxis(axis Vector3, angle float64) *Matrix4 {
 		0, 0, 0, 1,
 	)
 
-	return &m
+	return m
 }
 
 // MakeScale :
-func (m Matrix4) MakeScale(x, y, z float64) *Matrix4 {
+func (m *Matrix4) MakeScale(x, y, z float64) *Matrix4 {
 	m.Set(
 		x, 0, 0, 0,
 		0, y, 0, 0,
@@ -637,11 +637,11 @@ func (m Matrix4) MakeScale(x, y, z float64) *Matrix4 {
 		0, 0, 0, 1,
 	)
 
-	return &m
+	return m
 }
 
 // MakeShear :
-func (m Matrix4) MakeShear(x, y, z float64) *Matrix4 {
+func (m *Matrix4) MakeShear(x, y, z float64) *Matrix4 {
 	m.Set(
 		1, y, z, 0,
 		x, 1, z, 0,
@@ -649,12 +649,12 @@ func (m Matrix4) MakeShear(x, y, z float64) *Matrix4 {
 		0, 0, 0, 1,
 	)
 
-	return &m
+	return m
 }
 
 // Compose :
-func (m Matrix4) Compose(position Vector3, quaternion Quaternion, scale Vector3) *Matrix4 {
-	te := m.Elements
+func (m *Matrix4) Compose(position Vector3, quaternion Quaternion, scale Vector3) *Matrix4 {
+	te := &m.Elements
 
 	x, y, z, w := quaternion._x, quaternion._y, quaternion._z, quaternion._w
 	x2, y2, z2 := x+x, y+y, z+z
@@ -684,12 +684,12 @@ func (m Matrix4) Compose(position Vector3, quaternion Quaternion, scale Vector3)
 	te[14] = position.Z
 	te[15] = 1
 
-	return &m
+	return m
 }
 
 // Decompose :
-func (m Matrix4) Decompose(position Vector3, quaternion Quaternion, scale Vector3) *Matrix4 {
-	te := m.Elements
+func (m *Matrix4) Decompose(position *Vector3, quaternion *Quaternion, scale *Vector3) *Matrix4 {
+	te := &m.Elements
 
 	sx := _v1Matrix4.Set(te[0], te[1], te[2]).Length()
 	sy := _v1Matrix4.Set(te[4], te[5], te[6]).Length()
@@ -706,7 +706,7 @@ func (m Matrix4) Decompose(position Vector3, quaternion Quaternion, scale Vector
 	position.Z = te[14]
 
 	// scale the rotation part
-	_m1.Copy(m)
+	_m1.Copy(*m)
 
 	invSX := 1 / sx
 	invSY := 1 / sy
@@ -730,12 +730,12 @@ func (m Matrix4) Decompose(position Vector3, quaternion Quaternion, scale Vector
 	scale.Y = sy
 	scale.Z = sz
 
-	return &m
+	return m
 }
 
 // MakePerspective :
-func (m Matrix4) MakePerspective(left, right, top, bottom, near, far float64) *Matrix4 {
-	te := m.Elements
+func (m *Matrix4) MakePerspective(left, right, top, bottom, near, far float64) *Matrix4 {
+	te := &m.Elements
 	x := 2 * near / (right - left)
 	y := 2 * near / (top - bottom)
 
@@ -761,12 +761,12 @@ func (m Matrix4) MakePerspective(left, right, top, bottom, near, far float64) *M
 	te[11] = -1
 	te[15] = 0
 
-	return &m
+	return m
 }
 
 // MakeOrthographic :
-func (m Matrix4) MakeOrthographic(left, right, top, bottom, near, far float64) *Matrix4 {
-	te := m.Elements
+func (m *Matrix4) MakeOrthographic(left, right, top, bottom, near, far float64) *Matrix4 {
+	te := &m.Elements
 	w := 1.0 / (right - left)
 	h := 1.0 / (top - bottom)
 	p := 1.0 / (far - near)
@@ -792,12 +792,12 @@ func (m Matrix4) MakeOrthographic(left, right, top, bottom, near, far float64) *
 	te[11] = 0
 	te[15] = 1
 
-	return &m
+	return m
 }
 
 // Equals :
-func (m Matrix4) Equals(matrix Matrix4) bool {
-	te := m.Elements
+func (m *Matrix4) Equals(matrix Matrix4) bool {
+	te := &m.Elements
 	me := matrix.Elements
 
 	for i := 0; i < 16; i++ {
@@ -810,22 +810,22 @@ func (m Matrix4) Equals(matrix Matrix4) bool {
 }
 
 // FromArray :
-func (m Matrix4) FromArray(array []float64, offset int) *Matrix4 {
+func (m *Matrix4) FromArray(array []float64, offset int) *Matrix4 {
 	if len(array) < offset+16 {
 		panic("array length should be greater than offset+16")
 	}
 	for i := 0; i < 16; i++ {
 		m.Elements[i] = array[i+offset]
 	}
-	return &m
+	return m
 }
 
 // ToArray :
-func (m Matrix4) ToArray(array []float64, offset int) []float64 {
+func (m *Matrix4) ToArray(array []float64, offset int) []float64 {
 	if len(array) < offset+16 {
 		panic("array length should be greater than offset+16")
 	}
-	te := m.Elements
+	te := &m.Elements
 	array[offset] = te[0]
 	array[offset+1] = te[1]
 	array[offset+2] = te[2]
@@ -0,0 +1,54 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package three
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMatrix4Multiply(t *testing.T) {
+	// multiply a matrix by itself
+	m := NewMatrix4().MakeTranslation(1, 2, 3)
+	if result := m.Multiply(*m); result != m {
+		t.Errorf("expect the receiver to be returned")
+	}
+	if e := m.Elements; e[12] != 2 || e[13] != 4 || e[14] != 6 {
+		t.Errorf("expect translation (2, 4, 6), got %v", e)
+	}
+
+	// translate after scaling
+	m = NewMatrix4().MakeTranslation(1, 0, 0)
+	m.Multiply(*NewMatrix4().MakeScale(2, 2, 2))
+	if e := m.Elements; e[0] != 2 || e[12] != 1 {
+		t.Errorf("expect scale 2 and translation 1, got %v", e)
+	}
+	m.Premultiply(*m)
+	if e := m.Elements; e[0] != 4 || e[12] != 3 {
+		t.Errorf("expect scale 4 and translation 3, got %v", e)
+	}
+}
+
+func TestMatrix4Compose(t *testing.T) {
+	quaternion := NewQuaternion(0, 0, 0, 1).SetFromAxisAngle(*NewVector3(0, 0, 1), math.Pi/2)
+
+	m := NewMatrix4()
+	m.Compose(*NewVector3(1, 2, 3), *quaternion, *NewVector3(2, 2, 2))
+
+	position, scale := NewVector3(0, 0, 0), NewVector3(0, 0, 0)
+	rotation := NewQuaternion(0, 0, 0, 1)
+	m.Decompose(position, rotation, scale)
+	if !position.Equals(*NewVector3(1, 2, 3)) || !near(scale.X, 2) || !near(rotation.Z(), quaternion.Z()) {
+		t.Errorf("expect the composed transform, got %v %v %v", position, rotation, scale)
+	}
+}
+
+// near returns whether two numbers are equal within float error.
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
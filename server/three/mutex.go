@@ -0,0 +1,15 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package three
+
+import "sync"
+
+// Mutex guards the package variables that functions use as temporary values, like
+// three.js does, so these functions are not safe to call at the same time. Code that
+// runs in request handlers or other goroutines holds Mutex while it calls them.
+var Mutex sync.Mutex
@@ -27,79 +27,79 @@ type Plane struct {
 }
 
 // Set :
-func (p Plane) Set(normal Vector3, constant float64) *Plane {
+func (p *Plane) Set(normal Vector3, constant float64) *Plane {
 	p.Normal.Copy(normal)
 	p.Constant = constant
-	return &p
+	return p
 }
 
 // SetComponents :
-func (p Plane) SetComponents(x, y, z, w float64) *Plane {
+func (p *Plane) SetComponents(x, y, z, w float64) *Plane {
 	p.Normal.Set(x, y, z)
 	p.Constant = w
-	return &p
+	return p
 }
 
 // SetFromNormalAndCoplanarPoint :
-func (p Plane) SetFromNormalAndCoplanarPoint(normal, point Vector3) *Plane {
+func (p *Plane) SetFromNormalAndCoplanarPoint(normal, point Vector3) *Plane {
 	p.Normal.Copy(normal)
 	p.Constant = -point.Dot(p.Normal)
-	return &p
+	return p
 }
 
 // SetFromCoplanarPoints :
-func (p Plane) SetFromCoplanarPoints(a, b, c Vector3) *Plane {
+func (p *Plane) SetFromCoplanarPoints(a, b, c Vector3) *Plane {
 	normal := _vector1.SubVectors(c, b).Cross(*_vector2.SubVectors(a, b)).Normalize()
 	// Q: should an error be thrown if normal is zero (e.g. degenerate plane)?
 	p.SetFromNormalAndCoplanarPoint(*normal, a)
-	return &p
+	return p
 }
 
 // Clone :
-func (p Plane) Clone() *Plane {
-	return NewPlane(p.Normal, p.Constant).Copy(p)
+func (p *Plane) Clone() *Plane {
+	return NewPlane(p.Normal, p.Constant).Copy(*p)
 }
 
 // Copy :
-func (p Plane) Copy(plane Plane) *Plane {
+func (p *Plane) Copy(plane Plane) *Plane {
 	p.Normal.Copy(plane.Normal)
 	p.Constant = plane.Constant
-	return &p
+	return p
 }
 
 // Normalize :
-func (p Plane) Normalize() *Plane {
+func (p *Plane) Normalize() *Plane {
 	// Note: will lead to a divide by zero if the plane is invalid.
 	inverseNormalLength := 1.0 / p.Normal.Length()
 	p.Normal.MultiplyScalar(inverseNormalLength)
 	p.Constant *= inverseNormalLength
-	return &p
+	return p
 }
 
 // Negate :
-func (p Plane) Negate() *Plane {
+func (p *Plane) Negate() *Plane {
 	p.Constant *= -1
 	p.Normal.Negate()
-	return &p
+	return p
 }
 
 // DistanceToPoint :
-func (p Plane) DistanceToPoint(point Vector3) float64 {
+func (p *Plane) DistanceToPoint(point Vector3) float64 {
 	return p.Normal.Dot(point) + p.Constant
 }
 
 // DistanceToSphere :
-func (p Plane) DistanceToSphere(sphere Sphere) float64 {
+func (p *Plane) DistanceToSphere(sphere Sphere) float64 {
 	return p.DistanceToPoint(sphere.Center) - sphere.Radius
 }
 
 // ProjectPoint :
-func (p Plane) ProjectPoint(point, target Vector3) *Vector3 {
+func (p *Plane) ProjectPoint(point, target Vector3) *Vector3 {
 	return target.Copy(p.Normal).MultiplyScalar(-p.DistanceToPoint(point)).Add(point)
 }
 
 // IntersectLine :
-func (p Plane) IntersectLine(line Line3, target Vector3) *Vector3 {
+func (p *Plane) IntersectLine(line Line3, target Vector3) *Vector3 {
 	direction := line.Delta(_vector1)
 	denominator := p.Normal.Dot(*direction)
 	if denominator == 0 {
@@ -120,7 +120,7 @@ func (p Plane) IntersectLine(line Line3, target Vector3) *Vector3 {
 }
 
 // IntersectsLine :
-func (p Plane) IntersectsLine(line Line3) bool {
+func (p *Plane) IntersectsLine(line Line3) bool {
 	// Note: p tests if a line intersects the plane, not whether it (or its end-points) are coplanar with it.
 	startSign := p.DistanceToPoint(line.Start)
 	endSign := p.DistanceToPoint(line.End)
@@ -128,36 +128,36 @@ func (p Plane) IntersectsLine(line Line3) bool {
 }
 
 // IntersectsBox :
-func (p Plane) IntersectsBox(box Box3) bool {
-	return box.IntersectsPlane(p)
+func (p *Plane) IntersectsBox(box Box3) bool {
+	return box.IntersectsPlane(*p)
 }
 
 // IntersectsSphere :
-func (p Plane) IntersectsSphere(sphere Sphere) bool {
-	return sphere.IntersectsPlane(p)
+func (p *Plane) IntersectsSphere(sphere Sphere) bool {
+	return sphere.IntersectsPlane(*p)
 }
 
 // CoplanarPoint :
-func (p Plane) CoplanarPoint(target Vector3) *Vector3 {
+func (p *Plane) CoplanarPoint(target Vector3) *Vector3 {
 	return target.Copy(p.Normal).MultiplyScalar(-p.Constant)
 }
 
 // ApplyMatrix4 :
-func (p Plane) ApplyMatrix4(matrix Matrix4) *Plane {
+func (p *Plane) ApplyMatrix4(matrix Matrix4) *Plane {
 	normalMatrix := _normalMatrix.GetNormalMatrix(matrix)
 	referencePoint := p.CoplanarPoint(_vector1).ApplyMatrix4(matrix)
 	normal := p.Normal.ApplyMatrix3(*normalMatrix).Normalize()
 	p.Constant = -referencePoint.Dot(*normal)
-	return &p
+	return p
 }
 
 // Translate :
-func (p Plane) Translate(offset Vector3) *Plane {
+func (p *Plane) Translate(offset Vector3) *Plane {
 	p.Constant -= offset.Dot(p.Normal)
-	return &p
+	return p
 }
 
 // Equals :
-func (p Plane) Equals(plane Plane) bool {
+func (p *Plane) Equals(plane Plane) bool {
 	return plane.Normal.Equals(p.Normal) && plane.Constant == p.Constant
 }
@@ -21,7 +21,7 @@ type onChangeCallback func()
 
 // NewQuaternion :
 func NewQuaternion(x, y, z, w float64) *Quaternion {
-	return &Quaternion{x, y, z, w, nil}
+	return &Quaternion{x, y, z, w, func() {}}
 }
 
 // Quaternion :
@@ -34,12 +34,12 @@ type Quaternion struct {
 }
 
 // SlerpQuaternions :
-func (q Quaternion) SlerpQuaternions(qa, qb, qm Quaternion, t float64) *Quaternion {
+func (q *Quaternion) SlerpQuaternions(qa, qb, qm Quaternion, t float64) *Quaternion {
 	return qm.Copy(qa).Slerp(qb, t)
 }
 
 // SlerpFlat :
-func (q Quaternion) SlerpFlat(
+func (q *Quaternion) SlerpFlat(
 	dst []float64, dstOffset int,
 	src0 []float64, srcOffset0 int,
 	src1 []float64, srcOffset1 int,
@@ -100,7 +100,7 @@ func (q Quaternion) SlerpFlat(
 }
 
 // MultiplyQuaternionsFlat :
-func (q Quaternion) MultiplyQuaternionsFlat(
+func (q *Quaternion) MultiplyQuaternionsFlat(
 	dst []float64, dstOffset int,
 	src0 []float64, srcOffset0 int,
 	src1 []float64, srcOffset1 int) []float64 {
@@ -123,51 +123,51 @@ func (q Quaternion) MultiplyQuaternionsFlat(
 }
 
 // X :
-func (q Quaternion) X() float64 {
+func (q *Quaternion) X() float64 {
 	return q._x
 }
 
 // SetX :
-func (q Quaternion) SetX(val float64) {
+func (q *Quaternion) SetX(val float64) {
 	q._x = val
 	q._onChangeCallback()
 }
 
 // Y :
-func (q Quaternion) Y() float64 {
+func (q *Quaternion) Y() float64 {
 	return q._y
 }
 
 // SetY :
-func (q Quaternion) SetY(val float64) {
+func (q *Quaternion) SetY(val float64) {
 	q._y = val
 	q._onChangeCallback()
 }
 
 // Z :
-func (q Quaternion) Z() float64 {
+func (q *Quaternion) Z() float64 {
 	return q._z
 }
 
 // SetZ :
-func (q Quaternion) SetZ(val float64) {
+func (q *Quaternion) SetZ(val float64) {
 	q._z = val
 	q._onChangeCallback()
 }
 
 // W :
-func (q Quaternion) W() float64 {
+func (q *Quaternion) W() float64 {
 	return q._w
 }
 
 // SetW :
-func (q Quaternion) SetW(val float64) {
+func (q *Quaternion) SetW(val float64) {
 	q._w = val
 	q._onChangeCallback()
 }
 
 // Set :
-func (q Quaternion) Set(x, y, z, w float64) *Quaternion {
+func (q *Quaternion) Set(x, y, z, w float64) *Quaternion {
 	q._x = x
 	q._y = y
 	q._z = z
@@ -175,16 +175,16 @@ func (q Quaternion) Set(x, y, z, w float64) *Quaternion {
 
 	q._onChangeCallback()
 
-	return &q
+	return q
 }
 
 // Clone :
-func (q Quaternion) Clone() *Quaternion {
+func (q *Quaternion) Clone() *Quaternion {
 	return NewQuaternion(q._x, q._y, q._z, q._w)
 }
 
 // Copy :
-func (q Quaternion) Copy(quaternion Quaternion) *Quaternion {
+func (q *Quaternion) Copy(quaternion Quaternion) *Quaternion {
 	q._x = quaternion.X()
 	q._y = quaternion.Y()
 	q._z = quaternion.Z()
@@ -192,11 +192,11 @@ func (q Quaternion) Copy(quaternion Quaternion) *Quaternion {
 
 	q._onChangeCallback()
 
-	return &q
+	return q
 }
 
 // SetFromEuler :
-func (q Quaternion) SetFromEuler(euler Euler, update bool) *Quaternion {
+func (q *Quaternion) SetFromEuler(euler Euler, update bool) *Quaternion {
 	x, y, z, order := euler._x, euler._y, euler._z, euler._order
 
 	// http://www.mathworks.com/matlabcentral/fileexchange/
@@ -252,11 +252,11 @@ func (q Quaternion) SetFromEuler(euler Euler, update bool) *Quaternion {
 		q._onChangeCallback()
 	}
 
-	return &q
+	return q
 }
 
 // SetFromAxisAngle :
-func (q Quaternion) SetFromAxisAngle(axis Vector3, angle float64) *Quaternion {
+func (q *Quaternion) SetFromAxisAngle(axis Vector3, angle float64) *Quaternion {
 	// http://www.euclideanspace.com/maths/geometry/rotations/conversions/angleToQuaternion/index.htm
 
 	// assumes axis is normalized
@@ -270,15 +270,15 @@ func (q Quaternion) SetFromAxisAngle(axis Vector3, angle float64) *Quaternion {
 
 	q._onChangeCallback()
 
-	return &q
+	return q
 }
 
 // SetFromRotationMatrix :
-func (q Quaternion) SetFromRotationMatrix(m Matrix4) *Quaternion {
+func (q *Quaternion) SetFromRotationMatrix(m Matrix4) *Quaternion {
 	// http://www.euclideanspace.com/maths/geometry/rotations/conversions/matrixToQuaternion/index.htm
 
 	// assumes the upper 3x3 of m is a pure rotation matrix (i.e, unscaled)
-	te := m.Elements
+	te := &m.Elements
 
 	m11, m12, m13 := te[0], te[4], te[8]
 	m21, m22, m23 := te[1], te[5], te[9]
@@ -319,11 +319,11 @@ func (q Quaternion) SetFromRotationMatrix(m Matrix4) *Quaternion {
 
 	q._onChangeCallback()
 
-	return &q
+	return q
 }
 
 // SetFromUnitVectors :
-func (q Quaternion) SetFromUnitVectors(vFrom, vTo Vector3) *Quaternion {
+func (q *Quaternion) SetFromUnitVectors(vFrom, vTo Vector3) *Quaternion {
 	// assumes direction vectors vFrom and vTo are normalized
 
 	EPS := 0.000001
@@ -356,59 +356,59 @@ func (q Quaternion) SetFromUnitVectors(vFrom, vTo Vector3) *Quaternion {
 }
 
 // AngleTo :
-func (q Quaternion) AngleTo(q1 Quaternion) float64 {
+func (q *Quaternion) AngleTo(q1 Quaternion) float64 {
 	return 2 * math.Acos(math.Abs(Clamp(q.Dot(q1), -1, 1)))
 }
 
 // RotateTowards :
-func (q Quaternion) RotateTowards(q1 Quaternion, step float64) *Quaternion {
+func (q *Quaternion) RotateTowards(q1 Quaternion, step float64) *Quaternion {
 	angle := q.AngleTo(q1)
 
 	if angle == 0 {
-		return &q
+		return q
 	}
 
 	t := math.Min(1, step/angle)
 
 	q.Slerp(q1, t)
 
-	return &q
+	return q
 }
 
 // Inverse :
-func (q Quaternion) Inverse() *Quaternion {
+func (q *Quaternion) Inverse() *Quaternion {
 	// quaternion is assumed to have unit length
 	return q.Conjugate()
 }
 
 // Conjugate :
-func (q Quaternion) Conjugate() *Quaternion {
+func (q *Quaternion) Conjugate() *Quaternion {
 	q._x *= -1
 	q._y *= -1
 	q._z *= -1
 
 	q._onChangeCallback()
 
-	return &q
+	return q
 }
 
 // Dot :
-func (q Quaternion) Dot(v Quaternion) float64 {
+func (q *Quaternion) Dot(v Quaternion) float64 {
 	return q._x*v._x + q._y*v._y + q._z*v._z + q._w*v._w
 }
 
 // LengthSq :
-func (q Quaternion) LengthSq() float64 {
+func (q *Quaternion) LengthSq() float64 {
 	return q._x*q._x + q._y*q._y + q._z*q._z + q._w*q._w
 }
 
 // Length :
-func (q Quaternion) Length() float64 {
+func (q *Quaternion) Length() float64 {
 	return math.Sqrt(q._x*q._x + q._y*q._y + q._z*q._z + q._w*q._w)
 }
 
 // Normalize :
-func (q Quaternion) Normalize() *Quaternion {
+func (q *Quaternion) Normalize() *Quaternion {
 	l := q.Length()
 
 	if l == 0 {
@@ -427,21 +427,21 @@ func (q Quaternion) Normalize() *Quaternion {
 
 	q._onChangeCallback()
 
-	return &q
+	return q
 }
 
 // Multiply :
-func (q Quaternion) Multiply(q1 Quaternion) *Quaternion {
-	return q.MultiplyQuaternions(q, q1)
+func (q *Quaternion) Multiply(q1 Quaternion) *Quaternion {
+	return q.MultiplyQuaternions(*q, q1)
 }
 
 // Premultiply :
-func (q Quaternion) Premultiply(q1 Quaternion) *Quaternion {
-	return q.MultiplyQuaternions(q1, q)
+func (q *Quaternion) Premultiply(q1 Quaternion) *Quaternion {
+	return q.MultiplyQuaternions(q1, *q)
 }
 
 // MultiplyQuaternions :
-func (q Quaternion) MultiplyQuaternions(a, b Quaternion) *Quaternion {
+func (q *Quaternion) MultiplyQuaternions(a, b Quaternion) *Quaternion {
 	// from http://www.euclideanspace.com/maths/algebra/realNormedAlgebra/quaternions/code/index.htm
 	qax, qay, qaz, qaw := a._x, a._y, a._z, a._w
 	qbx, qby, qbz, qbw := b._x, b._y, b._z, b._w
@@ -453,13 +453,13 @@ func (q Quaternion) MultiplyQuaternions(a, b Quaternion) *Quaternion {
 
 	q._onChangeCallback()
 
-	return &q
+	return q
 }
 
 // Slerp :
-func (q Quaternion) Slerp(qb Quaternion, t float64) *Quaternion {
+func (q *Quaternion) Slerp(qb Quaternion, t float64) *Quaternion {
 	if t == 0 {
-		return &q
+		return q
 	}
 	if t == 1 {
 		return q.Copy(qb)
@@ -488,7 +488,7 @@ func (q Quaternion) Slerp(qb Quaternion, t float64) *Quaternion {
 		q._y = y
 		q._z = z
 
-		return &q
+		return q
 	}
 
 	sqrSinHalfTheta := 1.0 - cosHalfTheta*cosHalfTheta
@@ -503,7 +503,7 @@ func (q Quaternion) Slerp(qb Quaternion, t float64) *Quaternion {
 		q.Normalize()
 		q._onChangeCallback()
 
-		return &q
+		return q
 	}
 
 	sinHalfTheta := math.Sqrt(sqrSinHalfTheta)
@@ -518,18 +518,18 @@ func (q Quaternion) Slerp(qb Quaternion, t float64) *Quaternion {
 
 	q._onChangeCallback()
 
-	return &q
+	return q
 }
 
 // Equals :
-func (q Quaternion) Equals(quaternion Quaternion) bool {
+func (q *Quaternion) Equals(quaternion Quaternion) bool {
 	return quaternion._x == q._x &&
 		quaternion._y == q._y && quaternion._z == q._z &&
 		quaternion._w == q._w
 }
 
 // FromArray :
-func (q Quaternion) FromArray(array []float64, offset int) *Quaternion {
+func (q *Quaternion) FromArray(array []float64, offset int) *Quaternion {
 	if len(array) < offset+4 {
 		panic("array length should be greater than offset+4")
 	}
@@ -540,11 +540,11 @@ func (q Quaternion) FromArray(array []float64, offset int) *Quaternion {
 
 	q._onChangeCallback()
 
-	return &q
+	return q
 }
 
 // ToArray :
-func (q Quaternion) ToArray(array []float64, offset int) []float64 {
+func (q *Quaternion) ToArray(array []float64, offset int) []float64 {
 	if len(array) < offset+4 {
 		panic("array length should be greater than offset+4")
 	}
@@ -556,8 +556,8 @@ func (q Quaternion) ToArray(array []float64, offset int) []float64 {
 }
 
 // _OnChange :
-func (q Quaternion) _OnChange(callback onChangeCallback) *Quaternion {
+func (q *Quaternion) _OnChange(callback onChangeCallback) *Quaternion {
 	q._onChangeCallback = callback
 
-	return &q
+	return q
 }
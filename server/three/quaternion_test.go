@@ -0,0 +1,47 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package three
+
+import (
+	"math"
+	"testing"
+)
+
+func TestQuaternionMultiply(t *testing.T) {
+	axis := *NewVector3(0, 0, 1)
+
+	// two quarter turns are a half turn
+	q := NewQuaternion(0, 0, 0, 1).SetFromAxisAngle(axis, math.Pi/2)
+	if result := q.Multiply(*q); result != q {
+		t.Errorf("expect the receiver to be returned")
+	}
+	if !near(q.Z(), 1) || !near(q.W(), 0) {
+		t.Errorf("expect (0, 0, 1, 0), got %v %v %v %v", q.X(), q.Y(), q.Z(), q.W())
+	}
+
+	q.Premultiply(*NewQuaternion(0, 0, 0, 1).SetFromAxisAngle(axis, math.Pi/2))
+	v := NewVector3(1, 0, 0).ApplyQuaternion(*q)
+	if !near(v.X, 0) || !near(v.Y, -1) {
+		t.Errorf("expect (0, -1, 0), got %v", v)
+	}
+}
+
+func TestQuaternionOnChange(t *testing.T) {
+	euler := NewEuler(0, 0, 0, "XYZ")
+	q := NewQuaternion(0, 0, 0, 1)
+
+	changed := 0
+	q._OnChange(func() {
+		changed++
+	})
+	q.SetFromEuler(*euler, true)
+	q.Multiply(*q)
+	if changed != 2 {
+		t.Errorf("expect 2 changes, got %v", changed)
+	}
+}
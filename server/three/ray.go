@@ -35,43 +35,43 @@ type Ray struct {
 }
 
 // Set :
-func (r Ray) Set(origin, direction Vector3) *Ray {
+func (r *Ray) Set(origin, direction Vector3) *Ray {
 	r.Origin.Copy(origin)
 	r.Direction.Copy(direction)
-	return &r
+	return r
 }
 
 // Clone :
-func (r Ray) Clone() *Ray {
-	return NewRay(r.Origin, r.Direction).Copy(r)
+func (r *Ray) Clone() *Ray {
+	return NewRay(r.Origin, r.Direction).Copy(*r)
 }
 
 // Copy :
-func (r Ray) Copy(ray Ray) *Ray {
+func (r *Ray) Copy(ray Ray) *Ray {
 	r.Origin.Copy(ray.Origin)
 	r.Direction.Copy(ray.Direction)
-	return &r
+	return r
 }
 
 // At :
-func (r Ray) At(t float64, target Vector3) *Vector3 {
+func (r *Ray) At(t float64, target Vector3) *Vector3 {
 	return target.Copy(r.Direction).MultiplyScalar(t).Add(r.Origin)
 }
 
 // LookAt :
-func (r Ray) LookAt(v Vector3) *Ray {
+func (r *Ray) LookAt(v Vector3) *Ray {
 	r.Direction.Copy(v).Sub(r.Origin).Normalize()
-	return &r
+	return r
 }
 
 // Recast :
-func (r Ray) Recast(t float64) *Ray {
+func (r *Ray) Recast(t float64) *Ray {
 	r.Origin.Copy(*r.At(t, _vectorRay))
-	return &r
+	return r
 }
 
 // ClosestPointToPoint :
-func (r Ray) ClosestPointToPoint(point, target Vector3) *Vector3 {
+func (r *Ray) ClosestPointToPoint(point, target Vector3) *Vector3 {
 	target.SubVectors(point, r.Origin)
 
 	directionDistance := target.Dot(r.Direction)
@@ -82,12 +82,12 @@ func (r Ray) ClosestPointToPoint(point, target Vector3) *Vector3 {
 }
 
 // DistanceToPoint :
-func (r Ray) DistanceToPoint(point Vector3) float64 {
+func (r *Ray) DistanceToPoint(point Vector3) float64 {
 	return math.Sqrt(r.DistanceSqToPoint(point))
 }
 
 // DistanceSqToPoint :
-func (r Ray) DistanceSqToPoint(point Vector3) float64 {
+func (r *Ray) DistanceSqToPoint(point Vector3) float64 {
 	directionDistance := _vectorRay.SubVectors(point, r.Origin).Dot(r.Direction)
 	// point behind the ray
 	if directionDistance < 0 {
@@ -98,7 +98,7 @@ func (r Ray) DistanceSqToPoint(point Vector3) float64 {
 }
 
 // DistanceSqToSegment :
-func (r Ray) DistanceSqToSegment(v0, v1 Vector3, closestPointOnRay, closestPointOnSegment *Vector3) float64 {
+func (r *Ray) DistanceSqToSegment(v0, v1 Vector3, closestPointOnRay, closestPointOnSegment *Vector3) float64 {
 	// from http://www.geometrictools.com/GTEngine/Include/Mathematics/GteDistRaySegment.h
 	// It returns the min distance between the ray and the segment
 	// defined by v0 and v1
@@ -189,7 +189,7 @@ func (r Ray) DistanceSqToSegment(v0, v1 Vector3, closestPointOnRay, closestPoint
 }
 
 // IntersectSphere :
-func (r Ray) IntersectSphere(sphere Sphere, target Vector3) *Vector3 {
+func (r *Ray) IntersectSphere(sphere Sphere, target Vector3) *Vector3 {
 	_vectorRay.SubVectors(sphere.Center, r.Origin)
 
 	tca := _vectorRay.Dot(r.Direction)
@@ -219,12 +219,12 @@ func (r Ray) IntersectSphere(sphere Sphere, target Vector3) *Vector3 {
 }
 
 // IntersectsSphere :
-func (r Ray) IntersectsSphere(sphere Sphere) bool {
+func (r *Ray) IntersectsSphere(sphere Sphere) bool {
 	return r.DistanceSqToPoint(sphere.Center) <= (sphere.Radius * sphere.Radius)
 }
 
 // DistanceToPlane :
-func (r Ray) DistanceToPlane(plane Plane) float64 {
+func (r *Ray) DistanceToPlane(plane Plane) float64 {
 	denominator := plane.Normal.Dot(r.Direction)
 	if denominator == 0 {
 		// line is coplanar, return origin
@@ -243,7 +243,7 @@ func (r Ray) DistanceToPlane(plane Plane) float64 {
 }
 
 // IntersectPlane :
-func (r Ray) IntersectPlane(plane Plane, target Vector3) *Vector3 {
+func (r *Ray) IntersectPlane(plane Plane, target Vector3) *Vector3 {
 	t := r.DistanceToPlane(plane)
 	if math.IsInf(t, 1) {
 		return nil
@@ -252,7 +252,7 @@ func (r Ray) IntersectPlane(plane Plane, target Vector3) *Vector3 {
 }
 
 // IntersectsPlane :
-func (r Ray) IntersectsPlane(plane Plane) bool {
+func (r *Ray) IntersectsPlane(plane Plane) bool {
 	// check if the ray lies on the plane first
 	distToPoint := plane.DistanceToPoint(r.Origin)
 	if distToPoint == 0 {
@@ -267,7 +267,7 @@ func (r Ray) IntersectsPlane(plane Plane) bool {
 }
 
 // IntersectBox :
-func (r Ray) IntersectBox(box Box3, target Vector3) *Vector3 {
+func (r *Ray) IntersectBox(box Box3, target Vector3) *Vector3 {
 	var tmin, tmax, tymin, tymax, tzmin, tzmax float64
 
 	invdirx, invdiry, invdirz := 1/r.Direction.X, 1/r.Direction.Y, 1/r.Direction.Z
@@ -328,12 +328,12 @@ func (r Ray) IntersectBox(box Box3, target Vector3) *Vector3 {
 }
 
 // IntersectsBox :
-func (r Ray) IntersectsBox(box Box3) bool {
+func (r *Ray) IntersectsBox(box Box3) bool {
 	return r.IntersectBox(box, _vectorRay) != nil
 }
 
 // IntersectTriangle :
-func (r Ray) IntersectTriangle(a, b, c Vector3, backfaceCulling bool, target Vector3) *Vector3 {
+func (r *Ray) IntersectTriangle(a, b, c Vector3, backfaceCulling bool, target Vector3) *Vector3 {
 	// Compute the offset origin, edges, and normal.
 	// from http://www.geometrictools.com/GTEngine/Include/Mathematics/GteIntrRay3Triangle3.h
 	_edge1.SubVectors(b, a)
@@ -390,13 +390,13 @@ func (r Ray) IntersectTriangle(a, b, c Vector3, backfaceCulling bool, target Vec
 }
 
 // ApplyMatrix4 :
-func (r Ray) ApplyMatrix4(matrix4 Matrix4) *Ray {
+func (r *Ray) ApplyMatrix4(matrix4 Matrix4) *Ray {
 	r.Origin.ApplyMatrix4(matrix4)
 	r.Direction.TransformDirection(matrix4)
-	return &r
+	return r
 }
 
 // Equals :
-func (r Ray) Equals(ray Ray) bool {
+func (r *Ray) Equals(ray Ray) bool {
 	return ray.Origin.Equals(r.Origin) && ray.Direction.Equals(r.Direction)
 }
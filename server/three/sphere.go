@@ -24,14 +24,14 @@ type Sphere struct {
 }
 
 // Set :
-func (s Sphere) Set(center Vector3, radius float64) *Sphere {
+func (s *Sphere) Set(center Vector3, radius float64) *Sphere {
 	s.Center.Copy(center)
 	s.Radius = radius
-	return &s
+	return s
 }
 
 // SetFromPoints :
-func (s Sphere) SetFromPoints(points []Vector3, optionalCenter Vector3) *Sphere {
+func (s *Sphere) SetFromPoints(points []Vector3, optionalCenter Vector3) *Sphere {
 	center := s.Center
 	center.Copy(optionalCenter)
 
@@ -42,61 +42,61 @@ func (s Sphere) SetFromPoints(points []Vector3, optionalCenter Vector3) *Sphere
 	}
 
 	s.Radius = math.Sqrt(maxRadiusSq)
-	return &s
+	return s
 }
 
 // Clone :
-func (s Sphere) Clone() *Sphere {
-	return NewSphere(s.Center, s.Radius).Copy(s)
+func (s *Sphere) Clone() *Sphere {
+	return NewSphere(s.Center, s.Radius).Copy(*s)
 }
 
 // Copy :
-func (s Sphere) Copy(sphere Sphere) *Sphere {
+func (s *Sphere) Copy(sphere Sphere) *Sphere {
 	s.Center.Copy(sphere.Center)
 	s.Radius = sphere.Radius
-	return &s
+	return s
 }
 
 // IsEmpty :
-func (s Sphere) IsEmpty() bool {
+func (s *Sphere) IsEmpty() bool {
 	return s.Radius < 0
 }
 
 // MakeEmpty :
-func (s Sphere) MakeEmpty() *Sphere {
+func (s *Sphere) MakeEmpty() *Sphere {
 	s.Center.Set(0, 0, 0)
 	s.Radius = -1
-	return &s
+	return s
 }
 
 // ContainsPoint :
-func (s Sphere) ContainsPoint(point Vector3) bool {
+func (s *Sphere) ContainsPoint(point Vector3) bool {
 	return point.DistanceToSquared(s.Center) <= s.Radius*s.Radius
 }
 
 // DistanceToPoint :
-func (s Sphere) DistanceToPoint(point Vector3) float64 {
+func (s *Sphere) DistanceToPoint(point Vector3) float64 {
 	return point.DistanceTo(s.Center) - s.Radius
 }
 
 // IntersectsSphere :
-func (s Sphere) IntersectsSphere(sphere Sphere) bool {
+func (s *Sphere) IntersectsSphere(sphere Sphere) bool {
 	radiusSum := s.Radius + sphere.Radius
 	return sphere.Center.DistanceToSquared(s.Center) <= radiusSum*radiusSum
 }
 
 // IntersectsBox :
-func (s Sphere) IntersectsBox(box Box3) bool {
-	return box.IntersectsSphere(s)
+func (s *Sphere) IntersectsBox(box Box3) bool {
+	return box.IntersectsSphere(*s)
 }
 
 // IntersectsPlane :
-func (s Sphere) IntersectsPlane(plane Plane) bool {
+func (s *Sphere) IntersectsPlane(plane Plane) bool {
 	return math.Abs(plane.DistanceToPoint(s.Center)) <= s.Radius
 }
 
 // ClampPoint :
-func (s Sphere) ClampPoint(point, target Vector3) *Vector3 {
+func (s *Sphere) ClampPoint(point, target Vector3) *Vector3 {
 	deltaLengthSq := s.Center.DistanceToSquared(point)
 	target.Copy(point)
 
@@ -109,7 +109,7 @@ func (s Sphere) ClampPoint(point, target Vector3) *Vector3 {
 }
 
 // GetBoundingBox :
-func (s Sphere) GetBoundingBox(target Box3) *Box3 {
+func (s *Sphere) GetBoundingBox(target Box3) *Box3 {
 	if s.IsEmpty() {
 		// Empty sphere produces empty bounding box
 		target.MakeEmpty()
@@ -123,19 +123,19 @@ func (s Sphere) GetBoundingBox(target Box3) *Box3 {
 }
 
 // ApplyMatrix4 :
-func (s Sphere) ApplyMatrix4(matrix Matrix4) *Sphere {
+func (s *Sphere) ApplyMatrix4(matrix Matrix4) *Sphere {
 	s.Center.ApplyMatrix4(matrix)
 	s.Radius = s.Radius * matrix.GetMaxScaleOnAxis()
-	return &s
+	return s
 }
 
 // Translate :
-func (s Sphere) Translate(offset Vector3) *Sphere {
+func (s *Sphere) Translate(offset Vector3) *Sphere {
 	s.Center.Add(offset)
-	return &s
+	return s
 }
 
 // Equals :
-func (s Sphere) Equals(sphere Sphere) bool {
+func (s *Sphere) Equals(sphere Sphere) bool {
 	return sphere.Center.Equals(s.Center) && sphere.Radius == s.Radius
 }
@@ -28,40 +28,40 @@ type Spherical struct {
 }
 
 // Set :
-func (s Spherical) Set(radius, phi, theta float64) *Spherical {
+func (s *Spherical) Set(radius, phi, theta float64) *Spherical {
 	s.Radius = radius
 	s.Phi = phi
 	s.Theta = theta
-	return &s
+	return s
 }
 
 // Clone :
-func (s Spherical) Clone() *Spherical {
-	return NewSpherical(0, 0, 0).Copy(s)
+func (s *Spherical) Clone() *Spherical {
+	return NewSpherical(0, 0, 0).Copy(*s)
 }
 
 // Copy :
-func (s Spherical) Copy(other Spherical) *Spherical {
+func (s *Spherical) Copy(other Spherical) *Spherical {
 	s.Radius = other.Radius
 	s.Phi = other.Phi
 	s.Theta = other.Theta
-	return &s
+	return s
 }
 
 // MakeSafe restrict phi to be betwee EPS and PI-EPS
-func (s Spherical) MakeSafe() *Spherical {
+func (s *Spherical) MakeSafe() *Spherical {
 	EPS := 0.000001
 	s.Phi = math.Max(EPS, math.Min(math.Pi-EPS, s.Phi))
-	return &s
+	return s
 }
 
 // SetFromVector3 :
-func (s Spherical) SetFromVector3(v Vector3) *Spherical {
+func (s *Spherical) SetFromVector3(v Vector3) *Spherical {
 	return s.SetFromCartesianCoords(v.X, v.Y, v.Z)
 }
 
 // SetFromCartesianCoords :
-func (s Spherical) SetFromCartesianCoords(x, y, z float64) *Spherical {
+func (s *Spherical) SetFromCartesianCoords(x, y, z float64) *Spherical {
 	s.Radius = math.Sqrt(x*x + y*y + z*z)
 
 	if s.Radius == 0 {
@@ -72,5 +72,5 @@ func (s Spherical) SetFromCartesianCoords(x, y, z float64) *Spherical {
 		s.Phi = math.Acos(Clamp(y/s.Radius, -1, 1))
 	}
 
-	return &s
+	return s
 }
@@ -30,24 +30,24 @@ type SphericalHarmonics3 struct {
 }
 
 // Set :
-func (s SphericalHarmonics3) Set(coefficients [9]Vector3) *SphericalHarmonics3 {
+func (s *SphericalHarmonics3) Set(coefficients [9]Vector3) *SphericalHarmonics3 {
 	for i := 0; i < 9; i++ {
 		s.Coefficients[i].Copy(coefficients[i])
 	}
-	return &s
+	return s
 }
 
 // Zero :
-func (s SphericalHarmonics3) Zero() *SphericalHarmonics3 {
+func (s *SphericalHarmonics3) Zero() *SphericalHarmonics3 {
 	for i := 0; i < 9; i++ {
 		s.Coefficients[i].Set(0, 0, 0)
 	}
-	return &s
+	return s
 }
 
 // GetAt get the radiance in the direction of the normal
 // target is a Vector3
-func (s SphericalHarmonics3) GetAt(normal, target Vector3) *Vector3 {
+func (s *SphericalHarmonics3) GetAt(normal, target Vector3) *Vector3 {
 	// normal is assumed to be unit length
 	x, y, z := normal.X, normal.Y, normal.Z
 	coeff := s.Coefficients
@@ -69,7 +69,7 @@ func (s SphericalHarmonics3) GetAt(normal, target Vector3) *Vector3 {
 // GetIrradianceAt get the irradiance (radiance convolved with cosine lobe) in the direction of the normal
 // target is a Vector3
 // https://graphics.stanford.edu/papers/envmap/envmap.pdf
-func (s SphericalHarmonics3) GetIrradianceAt(normal, target Vector3) *Vector3 {
+func (s *SphericalHarmonics3) GetIrradianceAt(normal, target Vector3) *Vector3 {
 	// normal is assumed to be unit length
 	x, y, z := normal.X, normal.Y, normal.Z
 	var coeff = s.Coefficients
@@ -89,39 +89,39 @@ func (s SphericalHarmonics3) GetIrradianceAt(normal, target Vector3) *Vector3 {
 }
 
 // Add :
-func (s SphericalHarmonics3) Add(sh SphericalHarmonics3) *SphericalHarmonics3 {
+func (s *SphericalHarmonics3) Add(sh SphericalHarmonics3) *SphericalHarmonics3 {
 	for i := 0; i < 9; i++ {
 		s.Coefficients[i].Add(sh.Coefficients[i])
 	}
-	return &s
+	return s
 }
 
 // AddScaledSH :
-func (s SphericalHarmonics3) AddScaledSH(sh SphericalHarmonics3, t float64) *SphericalHarmonics3 {
+func (s *SphericalHarmonics3) AddScaledSH(sh SphericalHarmonics3, t float64) *SphericalHarmonics3 {
 	for i := 0; i < 9; i++ {
 		s.Coefficients[i].AddScaledVector(sh.Coefficients[i], t)
 	}
-	return &s
+	return s
 }
 
 // Scale :
-func (s SphericalHarmonics3) Scale(t float64) *SphericalHarmonics3 {
+func (s *SphericalHarmonics3) Scale(t float64) *SphericalHarmonics3 {
 	for i := 0; i < 9; i++ {
 		s.Coefficients[i].MultiplyScalar(t)
 	}
-	return &s
+	return s
 }
 
 // Lerp :
-func (s SphericalHarmonics3) Lerp(sh SphericalHarmonics3, alpha float64) *SphericalHarmonics3 {
+func (s *SphericalHarmonics3) Lerp(sh SphericalHarmonics3, alpha float64) *SphericalHarmonics3 {
 	for i := 0; i < 9; i++ {
 		s.Coefficients[i].Lerp(sh.Coefficients[i], alpha)
 	}
-	return &s
+	return s
 }
 
 // Equals :
-func (s SphericalHarmonics3) Equals(sh SphericalHarmonics3) bool {
+func (s *SphericalHarmonics3) Equals(sh SphericalHarmonics3) bool {
 	for i := 0; i < 9; i++ {
 		if !s.Coefficients[i].Equals(sh.Coefficients[i]) {
 			return false
@@ -131,17 +131,17 @@ func (s SphericalHarmonics3) Equals(sh SphericalHarmonics3) bool {
 }
 
 // Copy :
-func (s SphericalHarmonics3) Copy(sh SphericalHarmonics3) *SphericalHarmonics3 {
+func (s *SphericalHarmonics3) Copy(sh SphericalHarmonics3) *SphericalHarmonics3 {
 	return s.Set(sh.Coefficients)
 }
 
 // Clone :
-func (s SphericalHarmonics3) Clone() *SphericalHarmonics3 {
-	return NewSphericalHarmonics3().Copy(s)
+func (s *SphericalHarmonics3) Clone() *SphericalHarmonics3 {
+	return NewSphericalHarmonics3().Copy(*s)
 }
 
 // FromArray :
-func (s SphericalHarmonics3) FromArray(array []float64, offset int) *SphericalHarmonics3 {
+func (s *SphericalHarmonics3) FromArray(array []float64, offset int) *SphericalHarmonics3 {
 	if len(array) < offset+27 {
 		panic("array length should be greater than offset+27")
 	}
@@ -149,11 +149,11 @@ func (s SphericalHarmonics3) FromArray(array []float64, offset int) *SphericalHa
 	for i := 0; i < 9; i++ {
 		coefficients[i].FromArray(array, offset+(i*3))
 	}
-	return &s
+	return s
 }
 
 // ToArray :
-func (s SphericalHarmonics3) ToArray(array []float64, offset int) []float64 {
+func (s *SphericalHarmonics3) ToArray(array []float64, offset int) []float64 {
 	if len(array) < offset+27 {
 		panic("array length should be greater than offset+27")
 	}
@@ -108,83 +108,83 @@ func IsFrontFacing(a, b, c, direction Vector3) bool {
 }
 
 // Set :
-func (t Triangle) Set(a, b, c Vector3) *Triangle {
+func (t *Triangle) Set(a, b, c Vector3) *Triangle {
 	t.A.Copy(a)
 	t.B.Copy(b)
 	t.C.Copy(c)
-	return &t
+	return t
 }
 
 // SetFromPointsAndIndices :
-func (t Triangle) SetFromPointsAndIndices(points []Vector3, i0, i1, i2 int) *Triangle {
+func (t *Triangle) SetFromPointsAndIndices(points []Vector3, i0, i1, i2 int) *Triangle {
 	t.A.Copy(points[i0])
 	t.B.Copy(points[i1])
 	t.C.Copy(points[i2])
-	return &t
+	return t
 }
 
 // Clone :
-func (t Triangle) Clone() *Triangle {
-	return NewTriangle(t.A, t.B, t.C).Copy(t)
+func (t *Triangle) Clone() *Triangle {
+	return NewTriangle(t.A, t.B, t.C).Copy(*t)
 }
 
 // Copy :
-func (t Triangle) Copy(triangle Triangle) *Triangle {
+func (t *Triangle) Copy(triangle Triangle) *Triangle {
 	t.A.Copy(triangle.A)
 	t.B.Copy(triangle.B)
 	t.C.Copy(triangle.C)
-	return &t
+	return t
 }
 
 // GetArea :
-func (t Triangle) GetArea() float64 {
+func (t *Triangle) GetArea() float64 {
 	_v0Triangle.SubVectors(t.C, t.B)
 	_v1Triangle.SubVectors(t.A, t.B)
 	return _v0Triangle.Cross(_v1Triangle).Length() * 0.5
 }
 
 // GetMidpoint :
-func (t Triangle) GetMidpoint(target Vector3) *Vector3 {
+func (t *Triangle) GetMidpoint(target Vector3) *Vector3 {
 	return target.AddVectors(t.A, t.B).Add(t.C).MultiplyScalar(1 / 3)
 }
 
 // GetNormal :
-func (t Triangle) GetNormal(target Vector3) *Vector3 {
+func (t *Triangle) GetNormal(target Vector3) *Vector3 {
 	return GetNormal(t.A, t.B, t.C, target)
 }
 
 // GetPlane :
-func (t Triangle) GetPlane(target Plane) *Plane {
+func (t *Triangle) GetPlane(target Plane) *Plane {
 	return target.SetFromCoplanarPoints(t.A, t.B, t.C)
 }
 
 // GetBarycoord :
-func (t Triangle) GetBarycoord(point, target Vector3) *Vector3 {
+func (t *Triangle) GetBarycoord(point, target Vector3) *Vector3 {
 	return GetBarycoord(point, t.A, t.B, t.C, target)
 }
 
 // GetUV :
-func (t Triangle) GetUV(point Vector3, uv1, uv2, uv3, target Vector2) *Vector2 {
+func (t *Triangle) GetUV(point Vector3, uv1, uv2, uv3, target Vector2) *Vector2 {
 	return GetUV(point, t.A, t.B, t.C, uv1, uv2, uv3, target)
 }
 
 // ContainsPoint :
-func (t Triangle) ContainsPoint(point Vector3) bool {
+func (t *Triangle) ContainsPoint(point Vector3) bool {
 	return ContainsPoint(point, t.A, t.B, t.C)
 }
 
 // IsFrontFacing :
-func (t Triangle) IsFrontFacing(direction Vector3) bool {
+func (t *Triangle) IsFrontFacing(direction Vector3) bool {
 	return IsFrontFacing(t.A, t.B, t.C, direction)
 }
 
 // IntersectsBox :
-func (t Triangle) IntersectsBox(box Box3) bool {
-	return box.IntersectsTriangle(t)
+func (t *Triangle) IntersectsBox(box Box3) bool {
+	return box.IntersectsTriangle(*t)
 }
 
 // ClosestPointToPoint :
-func (t Triangle) ClosestPointToPoint(p, target Vector3) *Vector3 {
+func (t *Triangle) ClosestPointToPoint(p, target Vector3) *Vector3 {
 	a, b, c := t.A, t.B, t.C
 	var v, w float64
 
@@ -251,6 +251,6 @@ func (t Triangle) ClosestPointToPoint(p, target Vector3) *Vector3 {
 }
 
 // Equals :
-func (t Triangle) Equals(triangle Triangle) bool {
+func (t *Triangle) Equals(triangle Triangle) bool {
 	return triangle.A.Equals(t.A) && triangle.B.Equals(t.B) && triangle.C.Equals(t.C)
 }
@@ -33,46 +33,46 @@ var _vector3 = NewVector3(0, 0, 0)
 var _quaternionV3 = NewQuaternion(0, 0, 0, 1)
 
 // Set :
-func (v Vector3) Set(x, y, z float64) *Vector3 {
+func (v *Vector3) Set(x, y, z float64) *Vector3 {
 	v.X = x
 	v.Y = y
 	v.Z = z
 
-	return &v
+	return v
 }
 
 // SetScalar :
-func (v Vector3) SetScalar(scalar float64) *Vector3 {
+func (v *Vector3) SetScalar(scalar float64) *Vector3 {
 	v.X = scalar
 	v.Y = scalar
 	v.Z = scalar
 
-	return &v
+	return v
 }
 
 // SetX :
-func (v Vector3) SetX(x float64) *Vector3 {
+func (v *Vector3) SetX(x float64) *Vector3 {
 	v.X = x
 
-	return &v
+	return v
 }
 
 // SetY :
-func (v Vector3) SetY(y float64) *Vector3 {
+func (v *Vector3) SetY(y float64) *Vector3 {
 	v.Y = y
 
-	return &v
+	return v
 }
 
 // SetZ :
-func (v Vector3) SetZ(z float64) *Vector3 {
+func (v *Vector3) SetZ(z float64) *Vector3 {
 	v.Z = z
 
-	return &v
+	return v
 }
 
 // SetComponent :
-func (v Vector3) SetComponent(index int, value float64) *Vector3 {
+func (v *Vector3) SetComponent(index int, value float64) *Vector3 {
 	switch index {
 	default:
 		panic("index is out of range: " + strconv.Itoa(index))
@@ -84,11 +84,11 @@ func (v Vector3) SetComponent(index int, value float64) *Vector3 {
 		v.Z = value
 	}
 
-	return &v
+	return v
 }
 
 // GetComponent :
-func (v Vector3) GetComponent(index int) float64 {
+func (v *Vector3) GetComponent(index int) float64 {
 	switch index {
 	default:
 		panic("index is out of range: " + strconv.Itoa(index))
@@ -102,121 +102,121 @@ func (v Vector3) GetComponent(index int) float64 {
 }
 
 // Clone :
-func (v Vector3) Clone() *Vector3 {
+func (v *Vector3) Clone() *Vector3 {
 	return NewVector3(v.X, v.Y, v.Z)
 }
 
 // Copy :
-func (v Vector3) Copy(w Vector3) *Vector3 {
+func (v *Vector3) Copy(w Vector3) *Vector3 {
 	v.X = w.X
 	v.Y = w.Y
 	v.Z = w.Z
 
-	return &v
+	return v
 }
 
 // Add :
-func (v Vector3) Add(w Vector3) *Vector3 {
+func (v *Vector3) Add(w Vector3) *Vector3 {
 	v.X += w.X
 	v.Y += w.Y
 	v.Z += w.Z
 
-	return &v
+	return v
 }
 
 // AddScalar :
-func (v Vector3) AddScalar(s float64) *Vector3 {
+func (v *Vector3) AddScalar(s float64) *Vector3 {
 	v.X += s
 	v.Y += s
 	v.Z += s
 
-	return &v
+	return v
 }
 
 // AddVectors :
-func (v Vector3) AddVectors(a, b Vector3) *Vector3 {
+func (v *Vector3) AddVectors(a, b Vector3) *Vector3 {
 	v.X = a.X + b.X
 	v.Y = a.Y + b.Y
 	v.Z = a.Z + b.Z
 
-	return &v
+	return v
 }
 
 // AddScaledVector :
-func (v Vector3) AddScaledVector(w Vector3, s float64) *Vector3 {
+func (v *Vector3) AddScaledVector(w Vector3, s float64) *Vector3 {
 	v.X += w.X * s
 	v.Y += w.Y * s
 	v.Z += w.Z * s
 
-	return &v
+	return v
 }
 
 // Sub :
-func (v Vector3) Sub(w Vector3) *Vector3 {
+func (v *Vector3) Sub(w Vector3) *Vector3 {
 	v.X -= w.X
 	v.Y -= w.Y
 	v.Z -= w.Z
 
-	return &v
+	return v
 }
 
 // SubScalar :
-func (v Vector3) SubScalar(s float64) *Vector3 {
+func (v *Vector3) SubScalar(s float64) *Vector3 {
 	v.X -= s
 	v.Y -= s
 	v.Z -= s
 
-	return &v
+	return v
 }
 
 // SubVectors :
-func (v Vector3) SubVectors(a, b Vector3) *Vector3 {
+func (v *Vector3) SubVectors(a, b Vector3) *Vector3 {
 	v.X = a.X - b.X
 	v.Y = a.Y - b.Y
 	v.Z = a.Z - b.Z
 
-	return &v
+	return v
 }
 
 // Multiply :
-func (v Vector3) Multiply(w Vector3) *Vector3 {
+func (v *Vector3) Multiply(w Vector3) *Vector3 {
 	v.X *= w.X
 	v.Y *= w.Y
 	v.Z *= w.Z
 
-	return &v
+	return v
 }
 
 // MultiplyScalar :
-func (v Vector3) MultiplyScalar(scalar float64) *Vector3 {
+func (v *Vector3) MultiplyScalar(scalar float64) *Vector3 {
 	v.X *= scalar
 	v.Y *= scalar
 	v.Z *= scalar
 
-	return &v
+	return v
 }
 
 // MultiplyVectors :
-func (v Vector3) MultiplyVectors(a, b Vector3) *Vector3 {
+func (v *Vector3) MultiplyVectors(a, b Vector3) *Vector3 {
 	v.X = a.X * b.X
 	v.Y = a.Y * b.Y
 	v.Z = a.Z * b.Z
 
-	return &v
+	return v
 }
 
 // ApplyEuler :
-func (v Vector3) ApplyEuler(euler Euler) *Vector3 {
+func (v *Vector3) ApplyEuler(euler Euler) *Vector3 {
 	return v.ApplyQuaternion(*_quaternionV3.SetFromEuler(euler, true))
 }
 
 // ApplyAxisAngle :
-func (v Vector3) ApplyAxisAngle(axis Vector3, angle float64) *Vector3 {
+func (v *Vector3) ApplyAxisAngle(axis Vector3, angle float64) *Vector3 {
 	return v.ApplyQuaternion(*_quaternionV3.SetFromAxisAngle(axis, angle))
 }
 
 // ApplyMatrix3 :
-func (v Vector3) ApplyMatrix3(m Matrix3) *Vector3 {
+func (v *Vector3) ApplyMatrix3(m Matrix3) *Vector3 {
 	x, y, z := v.X, v.Y, v.Z
 	me := m.Elements
 
@@ -224,16 +224,16 @@ func (v Vector3) ApplyMatrix3(m Matrix3) *Vector3 {
 	v.Y = me[1]*x + me[4]*y + me[7]*z
 	v.Z = me[2]*x + me[5]*y + me[8]*z
 
-	return &v
+	return v
 }
 
 // ApplyNormalMatrix :
-func (v Vector3) ApplyNormalMatrix(m Matrix3) *Vector3 {
+func (v *Vector3) ApplyNormalMatrix(m Matrix3) *Vector3 {
 	return v.ApplyMatrix3(m).Normalize()
 }
 
 // ApplyMatrix4 :
-func (v Vector3) ApplyMatrix4(m Matrix4) *Vector3 {
+func (v *Vector3) ApplyMatrix4(m Matrix4) *Vector3 {
 	x, y, z := v.X, v.Y, v.Z
 	e := m.Elements
 
@@ -243,11 +243,11 @@ func (v Vector3) ApplyMatrix4(m Matrix4) *Vector3 {
 	v.Y = (e[1]*x + e[5]*y + e[9]*z + e[13]) * w
 	v.Z = (e[2]*x + e[6]*y + e[10]*z + e[14]) * w
 
-	return &v
+	return v
 }
 
 // ApplyQuaternion :
-func (v Vector3) ApplyQuaternion(q Quaternion) *Vector3 {
+func (v *Vector3) ApplyQuaternion(q Quaternion) *Vector3 {
 	x, y, z := v.X, v.Y, v.Z
 	qx, qy, qz, qw := q.X(), q.Y(), q.Z(), q.W()
 
@@ -264,21 +264,21 @@ func (v Vector3) ApplyQuaternion(q Quaternion) *Vector3 {
 	v.Y = iy*qw + iw*-qy + iz*-qx - ix*-qz
 	v.Z = iz*qw + iw*-qz + ix*-qy - iy*-qx
 
-	return &v
+	return v
 }
 
 // Project :
-func (v Vector3) Project(matrixWorldInverse, projectionMatrix Matrix4) *Vector3 {
+func (v *Vector3) Project(matrixWorldInverse, projectionMatrix Matrix4) *Vector3 {
 	return v.ApplyMatrix4(matrixWorldInverse).ApplyMatrix4(projectionMatrix)
 }
 
 // Unproject :
-func (v Vector3) Unproject(projectionMatrixInverse, matrixWorld Matrix4) *Vector3 {
+func (v *Vector3) Unproject(projectionMatrixInverse, matrixWorld Matrix4) *Vector3 {
 	return v.ApplyMatrix4(projectionMatrixInverse).ApplyMatrix4(matrixWorld)
 }
 
 // TransformDirection :
-func (v Vector3) TransformDirection(m Matrix4) *Vector3 {
+func (v *Vector3) TransformDirection(m Matrix4) *Vector3 {
 	// input: THREE.Matrix4 affine matrix
 	// vector interpreted as a direction
 
@@ -293,59 +293,59 @@ func (v Vector3) TransformDirection(m Matrix4) *Vector3 {
 }
 
 // Divide :
-func (v Vector3) Divide(w Vector3) *Vector3 {
+func (v *Vector3) Divide(w Vector3) *Vector3 {
 	v.X /= w.X
 	v.Y /= w.Y
 	v.Z /= w.Z
 
-	return &v
+	return v
 }
 
 // DivideScalar :
-func (v Vector3) DivideScalar(scalar float64) *Vector3 {
+func (v *Vector3) DivideScalar(scalar float64) *Vector3 {
 	return v.MultiplyScalar(1 / scalar)
 }
 
 // Min :
-func (v Vector3) Min(w Vector3) *Vector3 {
+func (v *Vector3) Min(w Vector3) *Vector3 {
 	v.X = math.Min(v.X, w.X)
 	v.Y = math.Min(v.Y, w.Y)
 	v.Z = math.Min(v.Z, w.Z)
 
-	return &v
+	return v
 }
 
 // Max :
-func (v Vector3) Max(w Vector3) *Vector3 {
+func (v *Vector3) Max(w Vector3) *Vector3 {
 	v.X = math.Max(v.X, w.X)
 	v.Y = math.Max(v.Y, w.Y)
 	v.Z = math.Max(v.Z, w.Z)
 
-	return &v
+	return v
 }
 
 // Clamp :
-func (v Vector3) Clamp(min, max Vector3) *Vector3 {
+func (v *Vector3) Clamp(min, max Vector3) *Vector3 {
 	// assumes min < max, componentwise
 
 	v.X = math.Max(min.X, math.Min(max.X, v.X))
 	v.Y = math.Max(min.Y, math.Min(max.Y, v.Y))
 	v.Z = math.Max(min.Z, math.Min(max.Z, v.Z))
 
-	return &v
+	return v
 }
 
 // ClampScalar :
-func (v Vector3) ClampScalar(minVal, maxVal float64) *Vector3 {
+func (v *Vector3) ClampScalar(minVal, maxVal float64) *Vector3 {
 	v.X = math.Max(minVal, math.Min(maxVal, v.X))
 	v.Y = math.Max(minVal, math.Min(maxVal, v.Y))
 	v.Z = math.Max(minVal, math.Min(maxVal, v.Z))
 
-	return &v
+	return v
 }
 
 // ClampLength :
-func (v Vector3) ClampLength(min, max float64) *Vector3 {
+func (v *Vector3) ClampLength(min, max float64) *Vector3 {
 	length := v.Length()
 	if length == 0 {
 		length = 1
@@ -355,34 +355,34 @@ func (v Vector3) ClampLength(min, max float64) *Vector3 {
 }
 
 // Floor :
-func (v Vector3) Floor() *Vector3 {
+func (v *Vector3) Floor() *Vector3 {
 	v.X = math.Floor(v.X)
 	v.Y = math.Floor(v.Y)
 	v.Z = math.Floor(v.Z)
 
-	return &v
+	return v
 }
 
 // Ceil :
-func (v Vector3) Ceil() *Vector3 {
+func (v *Vector3) Ceil() *Vector3 {
 	v.X = math.Ceil(v.X)
 	v.Y = math.Ceil(v.Y)
 	v.Z = math.Ceil(v.Z)
 
-	return &v
+	return v
 }
 
 // Round :
-func (v Vector3) Round() *Vector3 {
+func (v *Vector3) Round() *Vector3 {
 	v.X = math.Round(v.X)
 	v.Y = math.Round(v.Y)
 	v.Z = math.Round(v.Z)
 
-	return &v
+	return v
 }
 
 // RoundToZero :
-func (v Vector3) RoundToZero() *Vector3 {
+func (v *Vector3) RoundToZero() *Vector3 {
 	if v.X < 0 {
 		v.X = math.Ceil(v.X)
 	} else {
@@ -399,40 +399,40 @@ func (v Vector3) RoundToZero() *Vector3 {
 		v.Z = math.Floor(v.Z)
 	}
 
-	return &v
+	return v
 }
 
 // Negate ：
-func (v Vector3) Negate() *Vector3 {
+func (v *Vector3) Negate() *Vector3 {
 	v.X = -v.X
 	v.Y = -v.Y
 	v.Z = -v.Z
 
-	return &v
+	return v
 }
 
 // Dot ：
-func (v Vector3) Dot(w Vector3) float64 {
+func (v *Vector3) Dot(w Vector3) float64 {
 	return v.X*w.X + v.Y*w.Y + v.Z*w.Z
 }
 
 // LengthSq ：
-func (v Vector3) LengthSq() float64 {
+func (v *Vector3) LengthSq() float64 {
 	return v.X*v.X + v.Y*v.Y + v.Z*v.Z
 }
 
 // Length ：
-func (v Vector3) Length() float64 {
+func (v *Vector3) Length() float64 {
 	return math.Sqrt(v.X*v.X + v.Y*v.Y + v.Z*v.Z)
 }
 
 // ManhattanLength ：
-func (v Vector3) ManhattanLength() float64 {
+func (v *Vector3) ManhattanLength() float64 {
 	return math.Abs(v.X) + math.Abs(v.Y) + math.Abs(v.Z)
 }
 
 // Normalize ：
-func (v Vector3) Normalize() *Vector3 {
+func (v *Vector3) Normalize() *Vector3 {
 	length := v.Length()
 	if length == 0 {
 		length = 1
@@ -441,35 +441,35 @@ func (v Vector3) Normalize() *Vector3 {
 }
 
 // SetLength ：
-func (v Vector3) SetLength(length float64) *Vector3 {
+func (v *Vector3) SetLength(length float64) *Vector3 {
 	return v.Normalize().MultiplyScalar(length)
 }
 
 // Lerp :
-func (v Vector3) Lerp(w Vector3, alpha float64) *Vector3 {
+func (v *Vector3) Lerp(w Vector3, alpha float64) *Vector3 {
 	v.X += (w.X - v.X) * alpha
 	v.Y += (w.Y - v.Y) * alpha
 	v.Z += (w.Z - v.Z) * alpha
 
-	return &v
+	return v
 }
 
 // LerpVectors :
-func (v Vector3) LerpVectors(v1, v2 Vector3, alpha float64) *Vector3 {
+func (v *Vector3) LerpVectors(v1, v2 Vector3, alpha float64) *Vector3 {
 	v.X = v1.X + (v2.X-v1.X)*alpha
 	v.Y = v1.Y + (v2.Y-v1.Y)*alpha
 	v.Z = v1.Z + (v2.Z-v1.Z)*alpha
 
-	return &v
+	return v
 }
 
 // Cross :
-func (v Vector3) Cross(a Vector3) *Vector3 { // bug?
-	return v.CrossVectors(v, a)
+func (v *Vector3) Cross(a Vector3) *Vector3 { // bug?
+	return v.CrossVectors(*v, a)
 }
 
 // CrossVectors :
-func (v Vector3) CrossVectors(a, b Vector3) *Vector3 {
+func (v *Vector3) CrossVectors(a, b Vector3) *Vector3 {
 	ax, ay, az := a.X, a.Y, a.Z
 	bx, by, bz := b.X, b.Y, b.Z
 
@@ -477,31 +477,31 @@ func (v Vector3) CrossVectors(a, b Vector3) *Vector3 {
 	v.Y = az*bx - ax*bz
 	v.Z = ax*by - ay*bx
 
-	return &v
+	return v
 }
 
 // ProjectOnVector :
-func (v Vector3) ProjectOnVector(w Vector3) *Vector3 {
+func (v *Vector3) ProjectOnVector(w Vector3) *Vector3 {
 	denominator := w.LengthSq()
 
 	if denominator == 0 {
 		return v.Set(0, 0, 0)
 	}
 
-	scalar := w.Dot(v) / denominator
+	scalar := w.Dot(*v) / denominator
 
 	return v.Copy(w).MultiplyScalar(scalar)
 }
 
 // ProjectOnPlane :
-func (v Vector3) ProjectOnPlane(planeNormal Vector3) *Vector3 {
-	_vector3.Copy(v).ProjectOnVector(planeNormal)
+func (v *Vector3) ProjectOnPlane(planeNormal Vector3) *Vector3 {
+	_vector3.Copy(*v).ProjectOnVector(planeNormal)
 
 	return v.Sub(*_vector3)
 }
 
 // Reflect :
-func (v Vector3) Reflect(normal Vector3) *Vector3 {
+func (v *Vector3) Reflect(normal Vector3) *Vector3 {
 	// reflect incident vector off plane orthogonal to normal
 	// normal is assumed to have unit length
 
@@ -509,14 +509,14 @@ func (v Vector3) Reflect(normal Vector3) *Vector3 {
 }
 
 // AngleTo :
-func (v Vector3) AngleTo(w Vector3) float64 {
+func (v *Vector3) AngleTo(w Vector3) float64 {
 	denominator := math.Sqrt(w.LengthSq() * w.LengthSq())
 
 	if denominator == 0 {
 		return math.Pi / 2
 	}
 
-	theta := v.Dot(v) / denominator
+	theta := v.Dot(*v) / denominator
 
 	// clamp, to handle numerical problems
 
@@ -524,65 +524,65 @@ func (v Vector3) AngleTo(w Vector3) float64 {
 }
 
 // DistanceTo :
-func (v Vector3) DistanceTo(w Vector3) float64 {
+func (v *Vector3) DistanceTo(w Vector3) float64 {
 	return math.Sqrt(v.DistanceToSquared(w))
 }
 
 // DistanceToSquared :
-func (v Vector3) DistanceToSquared(w Vector3) float64 {
+func (v *Vector3) DistanceToSquared(w Vector3) float64 {
 	dx, dy, dz := v.X-w.X, v.Y-w.Y, v.Z-w.Z
 
 	return dx*dx + dy*dy + dz*dz
 }
 
 // ManhattanDistanceTo :
-func (v Vector3) ManhattanDistanceTo(w Vector3) float64 {
+func (v *Vector3) ManhattanDistanceTo(w Vector3) float64 {
 	return math.Abs(v.X-w.X) + math.Abs(v.Y-w.Y) + math.Abs(v.Z-w.Z)
 }
 
 // SetFromSpherical :
-func (v Vector3) SetFromSpherical(s Spherical) *Vector3 {
+func (v *Vector3) SetFromSpherical(s Spherical) *Vector3 {
 	return v.SetFromSphericalCoords(s.Radius, s.Phi, s.Theta)
 }
 
 // SetFromSphericalCoords :
-func (v Vector3) SetFromSphericalCoords(radius, phi, theta float64) *Vector3 {
+func (v *Vector3) SetFromSphericalCoords(radius, phi, theta float64) *Vector3 {
 	sinPhiRadius := math.Sin(phi) * radius
 
 	v.X = sinPhiRadius * math.Sin(theta)
 	v.Y = math.Cos(phi) * radius
 	v.Z = sinPhiRadius * math.Cos(theta)
 
-	return &v
+	return v
 }
 
 // SetFromCylindrical :
-func (v Vector3) SetFromCylindrical(c Cylindrical) *Vector3 {
+func (v *Vector3) SetFromCylindrical(c Cylindrical) *Vector3 {
 	return v.SetFromCylindricalCoords(c.Radius, c.Theta, c.Y)
 }
 
 // SetFromCylindricalCoords :
-func (v Vector3) SetFromCylindricalCoords(radius, theta, y float64) *Vector3 {
+func (v *Vector3) SetFromCylindricalCoords(radius, theta, y float64) *Vector3 {
 	v.X = radius * math.Sin(theta)
 	v.Y = y
 	v.Z = radius * math.Cos(theta)
 
-	return &v
+	return v
 }
 
 // SetFromMatrixPosition :
-func (v Vector3) SetFromMatrixPosition(m Matrix4) *Vector3 {
+func (v *Vector3) SetFromMatrixPosition(m Matrix4) *Vector3 {
 	e := m.Elements
 
 	v.X = e[12]
 	v.Y = e[13]
 	v.Z = e[14]
 
-	return &v
+	return v
 }
 
 // SetFromMatrixScale :
-func (v Vector3) SetFromMatrixScale(m Matrix4) *Vector3 {
+func (v *Vector3) SetFromMatrixScale(m Matrix4) *Vector3 {
 	sx := v.SetFromMatrixColumn(m, 0).Length()
 	sy := v.SetFromMatrixColumn(m, 1).Length()
 	sz := v.SetFromMatrixColumn(m, 2).Length()
@@ -591,11 +591,11 @@ func (v Vector3) SetFromMatrixScale(m Matrix4) *Vector3 {
 	v.Y = sy
 	v.Z = sz
 
-	return &v
+	return v
 }
 
 // SetFromMatrixColumn :
-func (v Vector3) SetFromMatrixColumn(m Matrix4, index int) *Vector3 {
+func (v *Vector3) SetFromMatrixColumn(m Matrix4, index int) *Vector3 {
 	elems := []float64{}
 	for i := 0; i < 3; i++ {
 		elems = append(elems, m.Elements[index*4+i])
@@ -604,7 +604,7 @@ func (v Vector3) SetFromMatrixColumn(m Matrix4, index int) *Vector3 {
 }
 
 // SetFromMatrix3Column :
-func (v Vector3) SetFromMatrix3Column(m Matrix3, index int) *Vector3 {
+func (v *Vector3) SetFromMatrix3Column(m Matrix3, index int) *Vector3 {
 	elems := []float64{}
 	for i := 0; i < 3; i++ {
 		elems = append(elems, m.Elements[index*3+i])
@@ -613,23 +613,23 @@ func (v Vector3) SetFromMatrix3Column(m Matrix3, index int) *Vector3 {
 }
 
 // Equals :
-func (v Vector3) Equals(w Vector3) bool {
+func (v *Vector3) Equals(w Vector3) bool {
 	return w.X == v.X && w.Y == v.Y && w.Z == v.Z
 }
 
 // FromArray :
-func (v Vector3) FromArray(array []float64, offset int) *Vector3 {
+func (v *Vector3) FromArray(array []float64, offset int) *Vector3 {
 	if len(array) < offset+3 {
 		panic("array length should be greater than offset+3")
 	}
 	v.X = array[offset]
 	v.Y = array[offset+1]
 	v.Z = array[offset+2]
-	return &v
+	return v
 }
 
 // ToArray :
-func (v Vector3) ToArray(array []float64, offset int) []float64 {
+func (v *Vector3) ToArray(array []float64, offset int) []float64 {
 	if len(array) < offset+3 {
 		panic("array length should be greater than offset+3")
 	}
@@ -641,10 +641,10 @@ func (v Vector3) ToArray(array []float64, offset int) []float64 {
 }
 
 // Random :
-func (v Vector3) Random() *Vector3 {
+func (v *Vector3) Random() *Vector3 {
 	v.X = rand.Float64()
 	v.Y = rand.Float64()
 	v.Z = rand.Float64()
 
-	return &v
+	return v
 }
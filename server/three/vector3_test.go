@@ -0,0 +1,42 @@
+// Copyright 2017-2020 The ShadowEditor Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+//
+// For more information, please visit: https://github.com/tengge1/ShadowEditor
+// You can also visit: https://gitee.com/tengge1/ShadowEditor
+
+package three
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVector3ApplyMatrix4(t *testing.T) {
+	m := NewMatrix4().MakeRotationZ(math.Pi/2).SetPosition(1, 0, 0)
+
+	v := NewVector3(1, 0, 0)
+	if result := v.ApplyMatrix4(*m); result != v {
+		t.Errorf("expect the receiver to be returned")
+	}
+	if !near(v.X, 1) || !near(v.Y, 1) || !near(v.Z, 0) {
+		t.Errorf("expect (1, 1, 0), got %v", v)
+	}
+
+	// the translation applies to each call
+	v.ApplyMatrix4(*m).ApplyMatrix4(*m)
+	if !near(v.X, 0) || !near(v.Y, 0) {
+		t.Errorf("expect (0, 0, 0), got %v", v)
+	}
+}
+
+func TestVector3Cross(t *testing.T) {
+	v := NewVector3(1, 0, 0)
+	v.Cross(*NewVector3(0, 1, 0))
+	if !v.Equals(*NewVector3(0, 0, 1)) {
+		t.Errorf("expect (0, 0, 1), got %v", v)
+	}
+	if v.Cross(*v); !v.Equals(*NewVector3(0, 0, 0)) {
+		t.Errorf("expect zero, got %v", v)
+	}
+}
@@ -30,71 +30,71 @@ type Vector4 struct {
 }
 
 // Width :
-func (v Vector4) Width() float64 {
+func (v *Vector4) Width() float64 {
 	return v.Z
 }
 
 // SetWidth :
-func (v Vector4) SetWidth(value float64) *Vector4 {
+func (v *Vector4) SetWidth(value float64) *Vector4 {
 	v.Z = value
-	return &v
+	return v
 }
 
 // Height :
-func (v Vector4) Height() float64 {
+func (v *Vector4) Height() float64 {
 	return v.W
 }
 
 // SetHeight :
-func (v Vector4) SetHeight(value float64) *Vector4 {
+func (v *Vector4) SetHeight(value float64) *Vector4 {
 	v.W = value
-	return &v
+	return v
 }
 
 // Set :
-func (v Vector4) Set(x, y, z, w float64) *Vector4 {
+func (v *Vector4) Set(x, y, z, w float64) *Vector4 {
 	v.X = x
 	v.Y = y
 	v.Z = z
 	v.W = w
-	return &v
+	return v
 }
 
 // SetScalar :
-func (v Vector4) SetScalar(scalar float64) *Vector4 {
+func (v *Vector4) SetScalar(scalar float64) *Vector4 {
 	v.X = scalar
 	v.Y = scalar
 	v.Z = scalar
 	v.W = scalar
-	return &v
+	return v
 }
 
 // SetX :
-func (v Vector4) SetX(x float64) *Vector4 {
+func (v *Vector4) SetX(x float64) *Vector4 {
 	v.X = x
-	return &v
+	return v
 }
 
 // SetY :
-func (v Vector4) SetY(y float64) *Vector4 {
+func (v *Vector4) SetY(y float64) *Vector4 {
 	v.Y = y
-	return &v
+	return v
 }
 
 // SetZ :
-func (v Vector4) SetZ(z float64) *Vector4 {
+func (v *Vector4) SetZ(z float64) *Vector4 {
 	v.Z = z
-	return &v
+	return v
 }
 
 // SetW :
-func (v Vector4) SetW(w float64) *Vector4 {
+func (v *Vector4) SetW(w float64) *Vector4 {
 	v.W = w
-	return &v
+	return v
 }
 
 // SetComponent :
-func (v Vector4) SetComponent(index int, value float64) *Vector4 {
+func (v *Vector4) SetComponent(index int, value float64) *Vector4 {
 	switch index {
 	default:
 		panic("index is out of range: " + strconv.Itoa(index))
@@ -107,11 +107,11 @@ func (v Vector4) SetComponent(index int, value float64) *Vector4 {
 	case 3:
 		v.W = value
 	}
-	return &v
+	return v
 }
 
 // GetComponent :
-func (v Vector4) GetComponent(index int) float64 {
+func (v *Vector4) GetComponent(index int) float64 {
 	switch index {
 	default:
 		panic("index is out of range: " + strconv.Itoa(index))
@@ -127,93 +127,93 @@ func (v Vector4) GetComponent(index int) float64 {
 }
 
 // Clone :
-func (v Vector4) Clone() *Vector4 {
+func (v *Vector4) Clone() *Vector4 {
 	return NewVector4(v.X, v.Y, v.Z, v.W)
 }
 
 // Copy :
-func (v Vector4) Copy(v1 Vector4) *Vector4 {
+func (v *Vector4) Copy(v1 Vector4) *Vector4 {
 	v.X = v1.X
 	v.Y = v1.Y
 	v.Z = v1.Z
 	v.W = v1.W
-	return &v
+	return v
 }
 
 // Add :
-func (v Vector4) Add(v1 Vector4) *Vector4 {
+func (v *Vector4) Add(v1 Vector4) *Vector4 {
 	v.X += v1.X
 	v.Y += v1.Y
 	v.Z += v1.Z
 	v.W += v1.W
-	return &v
+	return v
 }
 
 // AddScalar :
-func (v Vector4) AddScalar(s float64) *Vector4 {
+func (v *Vector4) AddScalar(s float64) *Vector4 {
 	v.X += s
 	v.Y += s
 	v.Z += s
 	v.W += s
-	return &v
+	return v
 }
 
 // AddVectors :
-func (v Vector4) AddVectors(a, b Vector4) *Vector4 {
+func (v *Vector4) AddVectors(a, b Vector4) *Vector4 {
 	v.X = a.X + b.X
 	v.Y = a.Y + b.Y
 	v.Z = a.Z + b.Z
 	v.W = a.W + b.W
-	return &v
+	return v
 }
 
 // AddScaledVector :
-func (v Vector4) AddScaledVector(v1 Vector4, s float64) *Vector4 {
+func (v *Vector4) AddScaledVector(v1 Vector4, s float64) *Vector4 {
 	v.X += v1.X * s
 	v.Y += v1.Y * s
 	v.Z += v1.Z * s
 	v.W += v1.W * s
-	return &v
+	return v
 }
 
 // Sub :
-func (v Vector4) Sub(v1 Vector4) *Vector4 {
+func (v *Vector4) Sub(v1 Vector4) *Vector4 {
 	v.X -= v1.X
 	v.Y -= v1.Y
 	v.Z -= v1.Z
 	v.W -= v1.W
-	return &v
+	return v
 }
 
 // SubScalar :
-func (v Vector4) SubScalar(s float64) *Vector4 {
+func (v *Vector4) SubScalar(s float64) *Vector4 {
 	v.X -= s
 	v.Y -= s
 	v.Z -= s
 	v.W -= s
-	return &v
+	return v
 }
 
 // SubVectors :
-func (v Vector4) SubVectors(a, b Vector4) *Vector4 {
+func (v *Vector4) SubVectors(a, b Vector4) *Vector4 {
 	v.X = a.X - b.X
 	v.Y = a.Y - b.Y
 	v.Z = a.Z - b.Z
 	v.W = a.W - b.W
-	return &v
+	return v
 }
 
 // MultiplyScalar :
-func (v Vector4) MultiplyScalar(scalar float64) *Vector4 {
+func (v *Vector4) MultiplyScalar(scalar float64) *Vector4 {
 	v.X *= scalar
 	v.Y *= scalar
 	v.Z *= scalar
 	v.W *= scalar
-	return &v
+	return v
 }
 
 // ApplyMatrix4 :
-func (v Vector4) ApplyMatrix4(m Matrix4) *Vector4 {
+func (v *Vector4) ApplyMatrix4(m Matrix4) *Vector4 {
 	x, y, z, w := v.X, v.Y, v.Z, v.W
 	e := m.Elements
 
@@ -221,16 +221,16 @@ func (v Vector4) ApplyMatrix4(m Matrix4) *Vector4 {
 	v.Y = e[1]*x + e[5]*y + e[9]*z + e[13]*w
 	v.Z = e[2]*x + e[6]*y + e[10]*z + e[14]*w
 	v.W = e[3]*x + e[7]*y + e[11]*z + e[15]*w
-	return &v
+	return v
 }
 
 // DivideScalar :
-func (v Vector4) DivideScalar(scalar float64) *Vector4 {
+func (v *Vector4) DivideScalar(scalar float64) *Vector4 {
 	return v.MultiplyScalar(1 / scalar)
 }
 
 // SetAxisAngleFromQuaternion :
-func (v Vector4) SetAxisAngleFromQuaternion(q Quaternion) *Vector4 {
+func (v *Vector4) SetAxisAngleFromQuaternion(q Quaternion) *Vector4 {
 	// http://www.euclideanspace.com/maths/geometry/rotations/conversions/quaternionToAngle/index.htm
 	// q is assumed to be normalized
 	v.W = 2 * math.Acos(q.W())
@@ -244,17 +244,17 @@ func (v Vector4) SetAxisAngleFromQuaternion(q Quaternion) *Vector4 {
 		v.Y = q.Y() / s
 		v.Z = q.Z() / s
 	}
-	return &v
+	return v
 }
 
 // SetAxisAngleFromRotationMatrix :
-func (v Vector4) SetAxisAngleFromRotationMatrix(m Matrix4) *Vector4 {
+func (v *Vector4) SetAxisAngleFromRotationMatrix(m Matrix4) *Vector4 {
 	// http://www.euclideanspace.com/maths/geometry/rotations/conversions/matrixToAngle/index.htm
 	// assumes the upper 3x3 of m is a pure rotation matrix (i.e, unscaled)
 	var angle, x, y, z float64 // variables for result
 	epsilon := 0.01            // margin to allow for rounding errors
 	epsilon2 := 0.1            // margin to distinguish between 0 and 180 degrees
-	te := m.Elements
+	te := &m.Elements
 
 	m11, m12, m13 := te[0], te[4], te[8]
 	m21, m22, m23 := te[1], te[5], te[9]
@@ -272,7 +272,7 @@ func (v Vector4) SetAxisAngleFromRotationMatrix(m Matrix4) *Vector4 {
 			(math.Abs(m11+m22+m33-3) < epsilon2) {
 			// v singularity is identity matrix so angle = 0
 			v.Set(1, 0, 0, 0)
-			return &v // zero angle, arbitrary axis
+			return v // zero angle, arbitrary axis
 		}
 		// otherwise v singularity is angle = 180
 		angle = math.Pi
@@ -318,7 +318,7 @@ func (v Vector4) SetAxisAngleFromRotationMatrix(m Matrix4) *Vector4 {
 		}
 
 		v.Set(x, y, z, angle)
-		return &v // return 180 deg rotation
+		return v // return 180 deg rotation
 	}
 
 	// as we have reached here there are no singularities so we can handle normally
@@ -335,48 +335,48 @@ func (v Vector4) SetAxisAngleFromRotationMatrix(m Matrix4) *Vector4 {
 	v.Y = (m13 - m31) / s
 	v.Z = (m21 - m12) / s
 	v.W = math.Acos((m11 + m22 + m33 - 1) / 2)
-	return &v
+	return v
 }
 
 // Min :
-func (v Vector4) Min(v1 Vector4) *Vector4 {
+func (v *Vector4) Min(v1 Vector4) *Vector4 {
 	v.X = math.Min(v.X, v1.X)
 	v.Y = math.Min(v.Y, v1.Y)
 	v.Z = math.Min(v.Z, v1.Z)
 	v.W = math.Min(v.W, v1.W)
-	return &v
+	return v
 }
 
 // Max :
-func (v Vector4) Max(v1 Vector4) *Vector4 {
+func (v *Vector4) Max(v1 Vector4) *Vector4 {
 	v.X = math.Max(v.X, v1.X)
 	v.Y = math.Max(v.Y, v1.Y)
 	v.Z = math.Max(v.Z, v1.Z)
 	v.W = math.Max(v.W, v1.W)
-	return &v
+	return v
 }
 
 // Clamp :
-func (v Vector4) Clamp(min, max Vector4) *Vector4 {
+func (v *Vector4) Clamp(min, max Vector4) *Vector4 {
 	// assumes min < max, componentwise
 	v.X = math.Max(min.X, math.Min(max.X, v.X))
 	v.Y = math.Max(min.Y, math.Min(max.Y, v.Y))
 	v.Z = math.Max(min.Z, math.Min(max.Z, v.Z))
 	v.W = math.Max(min.W, math.Min(max.W, v.W))
-	return &v
+	return v
 }
 
 // ClampScalar :
-func (v Vector4) ClampScalar(minVal, maxVal float64) *Vector4 {
+func (v *Vector4) ClampScalar(minVal, maxVal float64) *Vector4 {
 	v.X = math.Max(minVal, math.Min(maxVal, v.X))
 	v.Y = math.Max(minVal, math.Min(maxVal, v.Y))
 	v.Z = math.Max(minVal, math.Min(maxVal, v.Z))
 	v.W = math.Max(minVal, math.Min(maxVal, v.W))
-	return &v
+	return v
 }
 
 // ClampLength :
-func (v Vector4) ClampLength(min, max float64) *Vector4 {
+func (v *Vector4) ClampLength(min, max float64) *Vector4 {
 	length := v.Length()
 	if length == 0 {
 		length = 1
@@ -385,34 +385,34 @@ func (v Vector4) ClampLength(min, max float64) *Vector4 {
 }
 
 // Floor :
-func (v Vector4) Floor() *Vector4 {
+func (v *Vector4) Floor() *Vector4 {
 	v.X = math.Floor(v.X)
 	v.Y = math.Floor(v.Y)
 	v.Z = math.Floor(v.Z)
 	v.W = math.Floor(v.W)
-	return &v
+	return v
 }
 
 // Ceil :
-func (v Vector4) Ceil() *Vector4 {
+func (v *Vector4) Ceil() *Vector4 {
 	v.X = math.Ceil(v.X)
 	v.Y = math.Ceil(v.Y)
 	v.Z = math.Ceil(v.Z)
 	v.W = math.Ceil(v.W)
-	return &v
+	return v
 }
 
 // Round :
-func (v Vector4) Round() *Vector4 {
+func (v *Vector4) Round() *Vector4 {
 	v.X = math.Round(v.X)
 	v.Y = math.Round(v.Y)
 	v.Z = math.Round(v.Z)
 	v.W = math.Round(v.W)
-	return &v
+	return v
 }
 
 // RoundToZero :
-func (v Vector4) RoundToZero() *Vector4 {
+func (v *Vector4) RoundToZero() *Vector4 {
 	if v.X < 0 {
 		v.X = math.Ceil(v.X)
 	} else {
@@ -433,40 +433,40 @@ func (v Vector4) RoundToZero() *Vector4 {
 	} else {
 		v.W = math.Floor(v.W)
 	}
-	return &v
+	return v
 }
 
 // Negate :
-func (v Vector4) Negate() *Vector4 {
+func (v *Vector4) Negate() *Vector4 {
 	v.X = -v.X
 	v.Y = -v.Y
 	v.Z = -v.Z
 	v.W = -v.W
-	return &v
+	return v
 }
 
 // Dot :
-func (v Vector4) Dot(v1 Vector4) float64 {
+func (v *Vector4) Dot(v1 Vector4) float64 {
 	return v.X*v1.X + v.Y*v1.Y + v.Z*v1.Z + v.W*v1.W
 }
 
 // LengthSq :
-func (v Vector4) LengthSq() float64 {
+func (v *Vector4) LengthSq() float64 {
 	return v.X*v.X + v.Y*v.Y + v.Z*v.Z + v.W*v.W
 }
 
 // Length :
-func (v Vector4) Length() float64 {
+func (v *Vector4) Length() float64 {
 	return math.Sqrt(v.X*v.X + v.Y*v.Y + v.Z*v.Z + v.W*v.W)
 }
 
 // ManhattanLength :
-func (v Vector4) ManhattanLength() float64 {
+func (v *Vector4) ManhattanLength() float64 {
 	return math.Abs(v.X) + math.Abs(v.Y) + math.Abs(v.Z) + math.Abs(v.W)
 }
 
 // Normalize :
-func (v Vector4) Normalize() *Vector4 {
+func (v *Vector4) Normalize() *Vector4 {
 	length := v.Length()
 	if length == 0 {
 		length = 1
@@ -475,35 +475,35 @@ func (v Vector4) Normalize() *Vector4 {
 }
 
 // SetLength :
-func (v Vector4) SetLength(length float64) *Vector4 {
+func (v *Vector4) SetLength(length float64) *Vector4 {
 	return v.Normalize().MultiplyScalar(length)
 }
 
 // Lerp :
-func (v Vector4) Lerp(v1 Vector4, alpha float64) *Vector4 {
+func (v *Vector4) Lerp(v1 Vector4, alpha float64) *Vector4 {
 	v.X += (v1.X - v.X) * alpha
 	v.Y += (v1.Y - v.Y) * alpha
 	v.Z += (v1.Z - v.Z) * alpha
 	v.W += (v1.W - v.W) * alpha
-	return &v
+	return v
 }
 
 // LerpVectors :
-func (v Vector4) LerpVectors(v1, v2 Vector4, alpha float64) *Vector4 {
+func (v *Vector4) LerpVectors(v1, v2 Vector4, alpha float64) *Vector4 {
 	v.X = v1.X + (v2.X-v1.X)*alpha
 	v.Y = v1.Y + (v2.Y-v1.Y)*alpha
 	v.Z = v1.Z + (v2.Z-v1.Z)*alpha
 	v.W = v1.W + (v2.W-v1.W)*alpha
-	return &v
+	return v
 }
 
 // Equals :
-func (v Vector4) Equals(v1 Vector4) bool {
+func (v *Vector4) Equals(v1 Vector4) bool {
 	return ((v1.X == v.X) && (v1.Y == v.Y) && (v1.Z == v.Z) && (v1.W == v.W))
 }
 
 // FromArray :
-func (v Vector4) FromArray(array []float64, offset int) *Vector4 {
+func (v *Vector4) FromArray(array []float64, offset int) *Vector4 {
 	if len(array) < offset+4 {
 		panic("array length should be greater than offset+4")
 	}
@@ -511,11 +511,11 @@ func (v Vector4) FromArray(array []float64, offset int) *Vector4 {
 	v.Y = array[offset+1]
 	v.Z = array[offset+2]
 	v.W = array[offset+3]
-	return &v
+	return v
 }
 
 // ToArray :
-func (v Vector4) ToArray(array []float64, offset int) []float64 {
+func (v *Vector4) ToArray(array []float64, offset int) []float64 {
 	if len(array) < offset+4 {
 		panic("array length should be greater than offset+4")
 	}
@@ -527,10 +527,10 @@ func (v Vector4) ToArray(array []float64, offset int) []float64 {
 }
 
 // Random :
-func (v Vector4) Random() *Vector4 {
+func (v *Vector4) Random() *Vector4 {
 	v.X = rand.Float64()
 	v.Y = rand.Float64()
 	v.Z = rand.Float64()
 	v.W = rand.Float64()
-	return &v
+	return v
 }